@@ -0,0 +1,64 @@
+package main
+
+import (
+	"book-management-system/internal/events"
+	"book-management-system/internal/repositories"
+	"book-management-system/internal/services"
+	"fmt"
+	"os"
+)
+
+// app сервисы, которыми пользуются команды. Собираются так же, как в routes.NewServices,
+// чтобы команда вела себя как соответствующий вызов API
+type app struct {
+	userService    *services.UserService
+	bookService    *services.BookService
+	reviewService  *services.ReviewService
+	catalogService *services.CatalogService
+	outboxService  *services.OutboxService
+
+	userRepo         *repositories.UserRepository
+	bookRepo         *repositories.BookRepository
+	refreshTokenRepo *repositories.RefreshTokenRepository
+}
+
+// newApp подключается к хранилищам и собирает сервисы. События, которые публикуют сервисы,
+// сохраняются в уведомления и очередь вебхуков — отправит их уже воркер сервера
+func newApp() *app {
+	connect()
+
+	userRepo := repositories.NewUserRepository()
+	refreshTokenRepo := repositories.NewRefreshTokenRepository()
+	bookRepo := repositories.NewBookRepository()
+	bookAuthorRepo := repositories.NewBookAuthorRepository()
+	authorRepo := repositories.NewAuthorRepository()
+	reviewRepo := repositories.NewReviewRepository()
+	outboxRepo := repositories.NewOutboxRepository()
+
+	reputationService := services.NewReputationService(repositories.NewUserReputationRepository())
+	activityService := services.NewActivityService(repositories.NewActivityEventRepository(), bookAuthorRepo)
+	bookService := services.NewBookService(bookRepo, bookAuthorRepo, authorRepo, reputationService, activityService)
+	reviewService := services.NewReviewService(reviewRepo, bookRepo, outboxRepo, reputationService, activityService)
+
+	bus := events.GetBus()
+	bus.Subscribe(services.NewNotificationService(repositories.NewNotificationRepository()).HandleEvent)
+	bus.Subscribe(services.NewWebhookService(repositories.NewWebhookRepository()).HandleEvent)
+
+	return &app{
+		userService:    services.NewUserService(userRepo, refreshTokenRepo, reputationService),
+		bookService:    bookService,
+		reviewService:  reviewService,
+		catalogService: services.NewCatalogService(bookRepo, bookAuthorRepo, authorRepo, bookService),
+		outboxService:  services.NewOutboxService(outboxRepo, reviewRepo, reviewService),
+
+		userRepo:         userRepo,
+		bookRepo:         bookRepo,
+		refreshTokenRepo: refreshTokenRepo,
+	}
+}
+
+// fail печатает ошибку команды в stderr
+func fail(format string, args ...interface{}) int {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	return exitError
+}
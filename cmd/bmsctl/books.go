@@ -0,0 +1,71 @@
+package main
+
+import (
+	"github.com/google/uuid"
+)
+
+// runBook подтверждает или удаляет книгу
+func runBook(args []string) int {
+	if len(args) != 2 {
+		return usageError("ожидается book confirm|delete <bookID>")
+	}
+
+	bookID, err := uuid.Parse(args[1])
+	if err != nil {
+		return usageError("неверный ID книги " + args[1])
+	}
+
+	switch args[0] {
+	case "confirm":
+		if err := newApp().bookService.ConfirmBook(bookID, uuid.Nil); err != nil {
+			return fail("Ошибка подтверждения книги: %v", err)
+		}
+		return printJSON(map[string]string{"book_id": bookID.String(), "status": "confirmed"})
+
+	case "delete":
+		// Отзывы книги удалит воркер outbox сервера
+		if err := newApp().bookService.DeleteBook(bookID); err != nil {
+			return fail("Ошибка удаления книги: %v", err)
+		}
+		return printJSON(map[string]string{"book_id": bookID.String(), "status": "deleted"})
+	}
+	return usageError("неизвестная команда book " + args[0])
+}
+
+// ratingsRebuildResult итог пересчета рейтингов
+type ratingsRebuildResult struct {
+	Books  int      `json:"books"`
+	Failed []string `json:"failed"`
+}
+
+// runRatings пересчитывает рейтинги всех книг через ReviewService.RecalculateBookRatings:
+// априорное среднее считается один раз на весь пересчет
+func runRatings(args []string) int {
+	if len(args) != 1 || args[0] != "rebuild" {
+		return usageError("ожидается ratings rebuild")
+	}
+
+	app := newApp()
+	bookIDs, err := app.bookRepo.GetAllBookIDs()
+	if err != nil {
+		return fail("Ошибка получения книг: %v", err)
+	}
+
+	failed, err := app.reviewService.RecalculateBookRatings(bookIDs)
+	if err != nil {
+		return fail("Ошибка пересчета рейтингов: %v", err)
+	}
+
+	result := ratingsRebuildResult{Books: len(bookIDs), Failed: make([]string, len(failed))}
+	for i, bookID := range failed {
+		result.Failed[i] = bookID.String()
+	}
+
+	if code := printJSON(result); code != 0 {
+		return code
+	}
+	if len(result.Failed) > 0 {
+		return exitError
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"book-management-system/internal/dto"
+	"encoding/json"
+	"flag"
+	"os"
+)
+
+// runCatalog выгружает подтвержденный каталог в JSON или загружает его из файла
+func runCatalog(args []string) int {
+	if len(args) == 0 {
+		return usageError("ожидается catalog export|import")
+	}
+
+	flags := flag.NewFlagSet("catalog "+args[0], flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	file := flags.String("file", "", "файл каталога (для import обязателен; для export по умолчанию stdout)")
+	as := flags.String("as", "", "email пользователя, от имени которого создаются книги (для import)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "export":
+		catalog, err := newApp().catalogService.ExportCatalog()
+		if err != nil {
+			return fail("Ошибка выгрузки каталога: %v", err)
+		}
+		if *file == "" {
+			return printJSON(catalog)
+		}
+
+		out, err := os.Create(*file)
+		if err != nil {
+			return fail("Ошибка создания файла: %v", err)
+		}
+		defer out.Close()
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(catalog); err != nil {
+			return fail("Ошибка записи каталога: %v", err)
+		}
+		return printJSON(map[string]interface{}{"file": *file, "books": len(catalog)})
+
+	case "import":
+		if *file == "" || *as == "" {
+			return usageError("для import нужны --file и --as")
+		}
+
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return fail("Ошибка чтения файла: %v", err)
+		}
+		var catalog []dto.CatalogBook
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return fail("Файл не похож на выгрузку каталога: %v", err)
+		}
+
+		app := newApp()
+		importer, err := app.userRepo.GetUserByEmail(*as)
+		if err != nil {
+			return fail("Пользователь %s: %v", *as, err)
+		}
+
+		result := app.catalogService.ImportCatalog(catalog, importer)
+		if code := printJSON(result); code != 0 {
+			return code
+		}
+		if len(result.Failed) > 0 {
+			return exitError
+		}
+		return 0
+	}
+	return usageError("неизвестная команда catalog " + args[0])
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"book-management-system/internal/repositories"
+	"book-management-system/internal/services"
+	"flag"
+	"os"
+)
+
+// runConsistency проверяет согласованность хранилищ и в режиме --apply исправляет найденное
+func runConsistency(args []string) int {
+	flags := flag.NewFlagSet("consistency", flag.ContinueOnError)
+	apply := flags.Bool("apply", false, "исправить найденные расхождения (по умолчанию только отчет)")
+	flags.SetOutput(os.Stderr)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	connect()
+
+	service := services.NewConsistencyService(
+		repositories.NewConsistencyRepository(),
+		repositories.NewReviewRepository(),
+		repositories.NewOutboxRepository(),
+	)
+	report := service.Check(*apply)
+
+	if code := printJSON(report); code != 0 {
+		return code
+	}
+	for _, check := range report.Checks {
+		if check.Error != "" {
+			return exitError
+		}
+	}
+	if !*apply && report.Found > 0 {
+		return exitDrift
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+// bmsctl — консольная утилита обслуживания Book Management System.
+// Результат команд печатается в stdout в JSON, логи пишутся в stderr
+package main
+
+import (
+	"book-management-system/config"
+	"book-management-system/internal/database"
+	"book-management-system/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const (
+	exitError = 1
+	exitUsage = 2
+	exitDrift = 3 // проверка нашла расхождения
+)
+
+const usage = `Использование: bmsctl <команда> [флаги]
+
+Команды:
+  migrate up             применить все непримененные миграции PostgreSQL и MongoDB
+  migrate down [--store postgres|mongo] [--steps N]
+                         откатить N последних миграций хранилища (по умолчанию одну миграцию PostgreSQL)
+  migrate status         показать миграции и отметку, применены ли они
+  consistency [--apply]  найти рассинхронизацию PostgreSQL и MongoDB; с --apply исправить ее.
+                         Код выхода 3, если в режиме dry-run найдены расхождения
+  user create --email E --username U [--role R] [--password-stdin]
+                         создать пользователя; пароль берется из BMSCTL_PASSWORD или первой строки stdin
+  user set-role --email E --role R
+                         сменить роль (user, moderator, admin) и отозвать refresh-токены пользователя
+  book confirm <bookID>  подтвердить книгу
+  book delete <bookID>   удалить книгу
+  ratings rebuild        пересчитать рейтинги всех книг
+  tokens revoke --email E | --all
+                         отозвать refresh-токены пользователя или всех пользователей
+  cleanup                удалить устаревшие refresh-токены и обработанные сообщения outbox
+  catalog export [--file F]
+                         выгрузить подтвержденные книги с авторами в JSON
+  catalog import --file F --as E
+                         загрузить книги из выгрузки от имени пользователя E; дубликаты пропускаются
+`
+
+func main() {
+	logger.InitCLILogger()
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitUsage)
+	}
+
+	var code int
+	switch os.Args[1] {
+	case "migrate":
+		code = runMigrate(os.Args[2:])
+	case "consistency":
+		code = runConsistency(os.Args[2:])
+	case "user":
+		code = runUser(os.Args[2:])
+	case "book":
+		code = runBook(os.Args[2:])
+	case "ratings":
+		code = runRatings(os.Args[2:])
+	case "tokens":
+		code = runTokens(os.Args[2:])
+	case "cleanup":
+		code = runCleanup(os.Args[2:])
+	case "catalog":
+		code = runCatalog(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "Неизвестная команда %q\n\n%s", os.Args[1], usage)
+		code = exitUsage
+	}
+	os.Exit(code)
+}
+
+// connect загружает конфигурацию и подключается к обоим хранилищам
+func connect() {
+	config.Load()
+	database.InitPostgres()
+	database.InitMongoDB()
+}
+
+// usageError сообщает о неверном вызове команды
+func usageError(message string) int {
+	fmt.Fprintf(os.Stderr, "%s\n\n%s", message, usage)
+	return exitUsage
+}
+
+// printJSON печатает результат команды в stdout
+func printJSON(value interface{}) int {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка вывода результата: %v\n", err)
+		return exitError
+	}
+	return 0
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"book-management-system/internal/migrations"
+	"context"
+	"flag"
+	"os"
+)
+
+// runMigrate применяет, откатывает миграции или показывает их состояние
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		return usageError("не указана команда migrate")
+	}
+
+	flags := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	store := flags.String("store", string(migrations.StorePostgres), "хранилище для отката: postgres или mongo")
+	steps := flags.Int("steps", 1, "сколько последних миграций откатить")
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	command := args[0]
+	if command != "up" && command != "down" && command != "status" {
+		return usageError("неизвестная команда migrate " + command)
+	}
+	if command == "down" {
+		if *store != string(migrations.StorePostgres) && *store != string(migrations.StoreMongo) {
+			return usageError("неизвестное хранилище " + *store + ": ожидается postgres или mongo")
+		}
+		if *steps <= 0 {
+			return usageError("--steps должен быть положительным")
+		}
+	}
+
+	connect()
+
+	migrator, err := migrations.NewDefaultMigrator()
+	if err != nil {
+		return fail("Ошибка загрузки миграций: %v", err)
+	}
+
+	var result []migrations.MigrationStatus
+	switch command {
+	case "up":
+		result, err = migrator.Up(context.Background())
+	case "down":
+		result, err = migrator.Down(context.Background(), migrations.Store(*store), *steps)
+	case "status":
+		result, err = migrator.Status(context.Background())
+	}
+
+	// Даже при ошибке печатаем то, что успели применить или откатить
+	if result == nil {
+		result = []migrations.MigrationStatus{}
+	}
+	code := printJSON(result)
+	if err != nil {
+		return fail("Ошибка миграций: %v", err)
+	}
+	return code
+}
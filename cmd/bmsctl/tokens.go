@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"os"
+)
+
+// runTokens отзывает refresh-токены пользователя или всех пользователей
+func runTokens(args []string) int {
+	if len(args) == 0 || args[0] != "revoke" {
+		return usageError("ожидается tokens revoke --email <email> | --all")
+	}
+
+	flags := flag.NewFlagSet("tokens revoke", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	email := flags.String("email", "", "email пользователя")
+	all := flags.Bool("all", false, "отозвать токены всех пользователей")
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if (*email == "") == !*all {
+		return usageError("укажите ровно одно из --email и --all")
+	}
+
+	app := newApp()
+	if *all {
+		revoked, err := app.refreshTokenRepo.DeleteAllTokens()
+		if err != nil {
+			return fail("Ошибка отзыва токенов: %v", err)
+		}
+		return printJSON(map[string]int64{"revoked": revoked})
+	}
+
+	user, err := app.userService.RevokeUserTokens(*email)
+	if err != nil {
+		return fail("Ошибка отзыва токенов: %v", err)
+	}
+	return printJSON(map[string]string{"user_id": user.ID.String(), "status": "revoked"})
+}
+
+// cleanupResult итог фоновых очисток
+type cleanupResult struct {
+	ExpiredTokens int64 `json:"expired_tokens"`
+	Outbox        bool  `json:"outbox"`
+}
+
+// runCleanup запускает очистки, которые сервер выполняет по расписанию
+func runCleanup(args []string) int {
+	if len(args) != 0 {
+		return usageError("cleanup не принимает аргументов")
+	}
+
+	app := newApp()
+	expired, err := app.refreshTokenRepo.CleanupExpiredTokens()
+	if err != nil {
+		return fail("Ошибка удаления устаревших refresh-токенов: %v", err)
+	}
+	if err := app.outboxService.CleanupProcessed(); err != nil {
+		return fail("Ошибка очистки outbox: %v", err)
+	}
+	return printJSON(cleanupResult{ExpiredTokens: expired, Outbox: true})
+}
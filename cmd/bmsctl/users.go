@@ -0,0 +1,96 @@
+package main
+
+import (
+	"book-management-system/internal/models"
+	"bufio"
+	"errors"
+	"flag"
+	"os"
+	"strings"
+)
+
+// minPasswordLength то же ограничение, что и при регистрации через API
+const minPasswordLength = 6
+
+// userOutput пользователь в выводе команд
+type userOutput struct {
+	ID       string `json:"id"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func toUserOutput(user *models.User) userOutput {
+	return userOutput{ID: user.ID.String(), Email: user.Email, Username: user.Username, Role: user.Role}
+}
+
+// runUser создает пользователей и меняет им роль
+func runUser(args []string) int {
+	if len(args) == 0 {
+		return usageError("не указана команда user")
+	}
+
+	flags := flag.NewFlagSet("user "+args[0], flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	email := flags.String("email", "", "email пользователя")
+	username := flags.String("username", "", "имя пользователя (для create)")
+	role := flags.String("role", "", "роль: user, moderator или admin (для create по умолчанию user)")
+	passwordStdin := flags.Bool("password-stdin", false, "прочитать пароль из первой строки stdin вместо BMSCTL_PASSWORD")
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if *email == "" {
+		return usageError("не указан --email")
+	}
+
+	switch args[0] {
+	case "create":
+		if *username == "" {
+			return usageError("не указан --username")
+		}
+		// Пароль не принимаем флагом, чтобы он не попал в историю shell и список процессов
+		password, err := readPassword(*passwordStdin)
+		if err != nil {
+			return usageError(err.Error())
+		}
+
+		if *role == "" {
+			*role = models.RoleUser
+		}
+		user, err := newApp().userService.CreateUser(*email, *username, password, *role)
+		if err != nil {
+			return fail("Ошибка создания пользователя: %v", err)
+		}
+		return printJSON(toUserOutput(user))
+
+	case "set-role":
+		if *role == "" {
+			return usageError("не указана --role")
+		}
+		user, err := newApp().userService.SetUserRole(*email, *role)
+		if err != nil {
+			return fail("Ошибка смены роли: %v", err)
+		}
+		return printJSON(toUserOutput(user))
+	}
+	return usageError("неизвестная команда user " + args[0])
+}
+
+func readPassword(fromStdin bool) (string, error) {
+	password := os.Getenv("BMSCTL_PASSWORD")
+	if fromStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", errors.New("не удалось прочитать пароль из stdin")
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	if password == "" {
+		return "", errors.New("пароль не задан: передайте его в BMSCTL_PASSWORD или через --password-stdin")
+	}
+	if len(password) < minPasswordLength {
+		return "", errors.New("пароль короче 6 символов")
+	}
+	return password, nil
+}
@@ -4,10 +4,23 @@ import (
 	"book-management-system/config"
 	_ "book-management-system/docs"
 	"book-management-system/internal/database"
+	"book-management-system/internal/migrations"
+	"book-management-system/internal/moderation"
+	"book-management-system/internal/repositories"
 	"book-management-system/internal/routes"
 	"book-management-system/pkg/logger"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
+// shutdownTimeout сколько сервер ждет завершения текущих запросов после сигнала остановки
+const shutdownTimeout = 30 * time.Second
+
 // @title						Book Management API
 // @version					1.0
 // @description				API для управления книгами, пользователями и отзывами.
@@ -23,22 +36,65 @@ func main() {
 	log.Info("Загрузка конфигурации...")
 	config.Load()
 
+	log.Info("Загрузка словарей модерации...")
+	moderation.GetFilter()
+
 	log.Info("Подключение постргри...")
 	database.InitPostgres()
 
 	log.Info("Подключение монги...")
 	database.InitMongoDB()
 
+	// Несколько экземпляров могут стартовать одновременно: миграции сериализуются advisory lock
+	if config.GetEnv("MIGRATE_ON_START", "true") == "true" {
+		log.Info("Применение миграций...")
+		if err := applyMigrations(); err != nil {
+			log.Fatalf("Ошибка применения миграций: %v", err)
+		}
+	}
+
+	// Фоновые воркеры и сервер останавливаются по SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	services := routes.NewServices()
+
+	log.Info("Запуск фоновых воркеров...")
+	services.Webhook.StartWorker(ctx)
+	// Воркер outbox доводит до MongoDB удаления книг и повторяет неудавшиеся пересчеты рейтинга
+	services.Outbox.StartWorker(ctx)
+	services.Import.StartStaleJobSweeper(ctx)
+	repositories.StartTokenCleanupTask(services.RefreshTokenRepo)
+
 	port := config.GetEnv("SERVER_PORT", "8080")
 
 	log.Info("Инициализация маршрутизатора...")
-	router := routes.InitRouter()
+	server := &http.Server{Addr: ":" + port, Handler: routes.InitRouter(services)}
+	// Shutdown ждет завершения активных запросов, а SSE-потоки уведомлений сами не заканчиваются
+	server.RegisterOnShutdown(services.Notification.CloseStreams)
 
-	log.Infof("Запуск сервера на порту %s...", port)
-	err := router.Run(":" + port)
+	go func() {
+		log.Infof("Запуск сервера на порту %s...", port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
 
-	if err != nil {
-		log.Fatal(err)
+	<-ctx.Done()
+	log.Info("Остановка сервера...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Warnf("Ошибка остановки сервера: %v", err)
 	}
+}
 
+// applyMigrations применяет непримененные миграции PostgreSQL и MongoDB
+func applyMigrations() error {
+	migrator, err := migrations.NewDefaultMigrator()
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(context.Background())
+	return err
 }
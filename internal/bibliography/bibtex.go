@@ -0,0 +1,132 @@
+package bibliography
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// bibtexEscaper экранирует символы, которые BibTeX и LaTeX трактуют как разметку
+var bibtexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// bibtexTranslit транслитерация кириллицы для ключей записей: ключи BibTeX должны быть в ASCII
+var bibtexTranslit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z", 'и': "i",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// bibtexWriter пишет записи @book; ключ — фамилия первого автора и год, при совпадении с суффиксом a, b, ...
+type bibtexWriter struct {
+	w    *bufio.Writer
+	keys map[string]int
+}
+
+func newBibTeXWriter(w io.Writer) *bibtexWriter {
+	return &bibtexWriter{w: bufio.NewWriter(w), keys: make(map[string]int)}
+}
+
+func (w *bibtexWriter) Begin() error {
+	return nil
+}
+
+func (w *bibtexWriter) Write(record Record) error {
+	fields := [][2]string{}
+	if len(record.Authors) > 0 {
+		names := parseNames(record.Authors)
+		inverted := make([]string, len(names))
+		for i, name := range names {
+			inverted[i] = bibtexEscaper.Replace(name.Inverted())
+		}
+		fields = append(fields, [2]string{"author", strings.Join(inverted, " and ")})
+	}
+	// Двойные скобки сохраняют регистр названия в стилях, которые его меняют
+	fields = append(fields, [2]string{"title", "{" + bibtexEscaper.Replace(record.Title) + "}"})
+	if record.Publisher != "" {
+		fields = append(fields, [2]string{"publisher", bibtexEscaper.Replace(record.Publisher)})
+	}
+	if record.Year > 0 {
+		fields = append(fields, [2]string{"year", strconv.Itoa(record.Year)})
+	}
+	if record.ISBN != "" {
+		fields = append(fields, [2]string{"isbn", record.ISBN})
+	}
+	if record.PageCount > 0 {
+		fields = append(fields, [2]string{"pagetotal", strconv.Itoa(record.PageCount)})
+	}
+
+	if _, err := fmt.Fprintf(w.w, "@book{%s,\n", w.key(record)); err != nil {
+		return err
+	}
+	for i, field := range fields {
+		separator := ","
+		if i == len(fields)-1 {
+			separator = ""
+		}
+		if _, err := fmt.Fprintf(w.w, "  %s = {%s}%s\n", field[0], field[1], separator); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w.w, "}\n\n")
+	return err
+}
+
+func (w *bibtexWriter) Flush() error {
+	return w.w.Flush()
+}
+
+func (w *bibtexWriter) End() error {
+	return w.w.Flush()
+}
+
+func (w *bibtexWriter) key(record Record) string {
+	base := "book"
+	if names := parseNames(record.Authors); len(names) > 0 {
+		if family := bibtexKeyPart(names[0].Family); family != "" {
+			base = family
+		}
+	}
+	if record.Year > 0 {
+		base += strconv.Itoa(record.Year)
+	}
+
+	count := w.keys[base]
+	w.keys[base]++
+	if count == 0 {
+		return base
+	}
+	// Суффиксы a, b, ... после 26 совпадений переходят в номер
+	if count <= 26 {
+		return base + string(rune('a'+count-1))
+	}
+	return base + "-" + strconv.Itoa(count)
+}
+
+// bibtexKeyPart оставляет от фамилии латинские буквы и цифры, кириллицу транслитерирует
+func bibtexKeyPart(family string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(family) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		default:
+			b.WriteString(bibtexTranslit[r])
+		}
+	}
+	return b.String()
+}
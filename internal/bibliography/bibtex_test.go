@@ -0,0 +1,135 @@
+package bibliography
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBibTeXEscaper(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Война и мир", "Война и мир"},
+		{"C&A {x}_100%", `C\&A \{x\}\_100\%`},
+		{`a\b~^$#`, `a\textbackslash{}b\textasciitilde{}\textasciicircum{}\$\#`},
+	}
+
+	for _, tt := range tests {
+		if got := bibtexEscaper.Replace(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBibTeXKeyPart(t *testing.T) {
+	tests := []struct {
+		family, want string
+	}{
+		{"Толстой", "tolstoy"},
+		{"Щедрин", "shchedrin"},
+		{"Салтыков-Щедрин", "saltykovshchedrin"},
+		{"O'Brien", "obrien"},
+		{"Čapek", "apek"},
+		{"李", ""},
+	}
+
+	for _, tt := range tests {
+		if got := bibtexKeyPart(tt.family); got != tt.want {
+			t.Errorf("bibtexKeyPart(%q) = %q, want %q", tt.family, got, tt.want)
+		}
+	}
+}
+
+func TestBibTeXKeys(t *testing.T) {
+	w := newBibTeXWriter(&bytes.Buffer{})
+	tolstoy := Record{Authors: []string{"Лев Толстой"}, Year: 1869}
+
+	tests := []struct {
+		record Record
+		want   string
+	}{
+		{tolstoy, "tolstoy1869"},
+		{tolstoy, "tolstoy1869a"},
+		{tolstoy, "tolstoy1869b"},
+		{Record{Authors: []string{"Толстой, Лев"}}, "tolstoy"},
+		{Record{Authors: []string{"李"}, Year: 2000}, "book2000"},
+		{Record{}, "book"},
+		{Record{}, "booka"},
+	}
+
+	for i, tt := range tests {
+		if got := w.key(tt.record); got != tt.want {
+			t.Errorf("ключ %d = %q, want %q", i+1, got, tt.want)
+		}
+	}
+
+	// После 26 буквенных суффиксов идут номера
+	for i := 0; i < 24; i++ {
+		w.key(tolstoy)
+	}
+	if got := w.key(tolstoy); got != "tolstoy1869-27" {
+		t.Errorf("28-й ключ = %q, want tolstoy1869-27", got)
+	}
+}
+
+func TestBibTeXWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		record Record
+		want   string
+	}{
+		{
+			name: "all fields",
+			record: Record{
+				Title:     "C&A {x}_100%",
+				Authors:   []string{"Лев Николаевич Толстой", "Ann Smith"},
+				Publisher: "O'Reilly & Sons",
+				Year:      2015,
+				ISBN:      "9785170906307",
+				PageCount: 1225,
+			},
+			want: "@book{tolstoy2015,\n" +
+				"  author = {Толстой, Лев Николаевич and Smith, Ann},\n" +
+				"  title = {{C\\&A \\{x\\}\\_100\\%}},\n" +
+				"  publisher = {O'Reilly \\& Sons},\n" +
+				"  year = {2015},\n" +
+				"  isbn = {9785170906307},\n" +
+				"  pagetotal = {1225}\n" +
+				"}\n\n",
+		},
+		{
+			name:   "title only",
+			record: Record{Title: "Война и мир"},
+			want:   "@book{book,\n  title = {{Война и мир}}\n}\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := newBibTeXWriter(&buf)
+			if err := w.Write(tt.record); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.End(); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBibTeXWriterEscapesAuthors(t *testing.T) {
+	var buf bytes.Buffer
+	w := newBibTeXWriter(&buf)
+	if err := w.Write(Record{Title: "T", Authors: []string{"Ann_Marie Smith"}}); err != nil {
+		t.Fatal(err)
+	}
+	w.End()
+	if !strings.Contains(buf.String(), `author = {Smith, Ann\_Marie}`) {
+		t.Errorf("автор не экранирован:\n%s", buf.String())
+	}
+}
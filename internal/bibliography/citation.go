@@ -0,0 +1,168 @@
+package bibliography
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Style стиль библиографической ссылки
+type Style string
+
+const (
+	StyleAPA  Style = "apa"  // APA, 7-е издание
+	StyleMLA  Style = "mla"  // MLA, 9-е издание
+	StyleGOST Style = "gost" // ГОСТ Р 7.0.100-2018
+)
+
+// apaMaxAuthors после стольких авторов APA перечисляет первых 19, многоточие и последнего
+const apaMaxAuthors = 20
+
+// gostMaxResponsibility сколько авторов ГОСТ перечисляет в сведениях об ответственности перед «[и др.]»
+const gostMaxResponsibility = 3
+
+// ParseStyle проверяет стиль ссылки
+func ParseStyle(style string) (Style, error) {
+	switch Style(style) {
+	case StyleAPA, StyleMLA, StyleGOST:
+		return Style(style), nil
+	default:
+		return "", fmt.Errorf("неподдерживаемый стиль ссылки: %s", style)
+	}
+}
+
+// Cite формирует ссылку на книгу обычным текстом: курсив и другое оформление стилей не передаются
+func Cite(record Record, style Style) string {
+	names := parseNames(record.Authors)
+	switch style {
+	case StyleMLA:
+		return citeMLA(record, names)
+	case StyleGOST:
+		return citeGOST(record, names)
+	default:
+		return citeAPA(record, names)
+	}
+}
+
+// citeAPA: «Толстой, Л. Н. (2015). Война и мир. АСТ.»
+func citeAPA(record Record, names []Name) string {
+	authors := make([]string, len(names))
+	for i, name := range names {
+		authors[i] = name.Family
+		if initials := name.Initials(); initials != "" {
+			authors[i] += ", " + initials
+		}
+	}
+
+	var byline string
+	switch {
+	case len(authors) > apaMaxAuthors:
+		byline = strings.Join(authors[:apaMaxAuthors-1], ", ") + ", . . . " + authors[len(authors)-1]
+	case len(authors) == 2:
+		byline = authors[0] + ", & " + authors[1]
+	default:
+		byline = joinNatural(authors, ", ", ", & ")
+	}
+
+	year := "n.d."
+	if record.Year > 0 {
+		year = strconv.Itoa(record.Year)
+	}
+
+	parts := make([]string, 0, 3)
+	if byline != "" {
+		parts = append(parts, sentence(byline+" ("+year+")"), sentence(record.Title))
+	} else {
+		// Без автора на его место встает название
+		parts = append(parts, sentence(record.Title), "("+year+").")
+	}
+	if record.Publisher != "" {
+		parts = append(parts, sentence(record.Publisher))
+	}
+	return strings.Join(parts, " ")
+}
+
+// citeMLA: «Толстой, Лев Николаевич. Война и мир. АСТ, 2015.»
+func citeMLA(record Record, names []Name) string {
+	var byline string
+	switch len(names) {
+	case 0:
+	case 1:
+		byline = names[0].Inverted()
+	case 2:
+		byline = names[0].Inverted() + ", and " + names[1].Natural()
+	default:
+		byline = names[0].Inverted() + ", et al"
+	}
+
+	parts := make([]string, 0, 3)
+	if byline != "" {
+		parts = append(parts, sentence(byline))
+	}
+	parts = append(parts, sentence(record.Title))
+
+	var publication []string
+	if record.Publisher != "" {
+		publication = append(publication, record.Publisher)
+	}
+	if record.Year > 0 {
+		publication = append(publication, strconv.Itoa(record.Year))
+	}
+	if len(publication) > 0 {
+		parts = append(parts, sentence(strings.Join(publication, ", ")))
+	}
+	return strings.Join(parts, " ")
+}
+
+// citeGOST: «Толстой, Л. Н. Война и мир / Л. Н. Толстой. – АСТ, 2015. – 1225 с. – ISBN 9785170906307.»
+// При четырех и более авторах описание начинается с заглавия; место издания в каталоге не хранится и опускается
+func citeGOST(record Record, names []Name) string {
+	var heading string
+	if len(names) > 0 && len(names) <= gostMaxResponsibility {
+		heading = names[0].Family
+		if initials := names[0].Initials(); initials != "" {
+			heading += ", " + initials
+		}
+		heading += " "
+	}
+
+	responsibility := make([]string, 0, gostMaxResponsibility)
+	for _, name := range names[:min(len(names), gostMaxResponsibility)] {
+		responsibility = append(responsibility, strings.TrimSpace(name.Initials()+" "+name.Family))
+	}
+	first := heading + strings.TrimRight(record.Title, ".")
+	if len(responsibility) > 0 {
+		first += " / " + strings.Join(responsibility, ", ")
+		if len(names) > gostMaxResponsibility {
+			first += " [и др.]"
+		}
+	}
+
+	areas := []string{strings.TrimRight(first, ".")}
+	var publication []string
+	if record.Publisher != "" {
+		publication = append(publication, record.Publisher)
+	}
+	if record.Year > 0 {
+		publication = append(publication, strconv.Itoa(record.Year))
+	}
+	if len(publication) > 0 {
+		areas = append(areas, strings.Join(publication, ", "))
+	}
+	if record.PageCount > 0 {
+		areas = append(areas, fmt.Sprintf("%d с", record.PageCount))
+	}
+	if record.ISBN != "" {
+		areas = append(areas, "ISBN "+record.ISBN)
+	}
+	return strings.Join(areas, ". – ") + "."
+}
+
+// sentence завершает фрагмент точкой, если он еще не кончается знаком препинания
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsAny(s[len(s)-1:], ".?!") {
+		return s
+	}
+	return s + "."
+}
@@ -0,0 +1,90 @@
+package bibliography
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCite(t *testing.T) {
+	warAndPeace := Record{
+		Title:     "Война и мир",
+		Authors:   []string{"Лев Николаевич Толстой"},
+		Publisher: "АСТ",
+		Year:      2015,
+		PageCount: 1225,
+		ISBN:      "9785170906307",
+	}
+	roadside := Record{Title: "Пикник на обочине", Authors: []string{"Аркадий Стругацкий", "Стругацкий, Борис"}}
+	three := Record{Title: "Go?", Authors: []string{"Ann Smith", "Bob Jones", "Carl Brown"}, Year: 2020}
+	four := Record{Title: "Сборник.", Authors: []string{"Ann Smith", "Bob Jones", "Carl Brown", "Dan White"}, Publisher: "Наука"}
+	anonymous := Record{Title: "Слово о полку Игореве", Publisher: "Детская литература"}
+
+	tests := []struct {
+		name   string
+		record Record
+		style  Style
+		want   string
+	}{
+		{"APA one author", warAndPeace, StyleAPA, "Толстой, Л. Н. (2015). Война и мир. АСТ."},
+		{"APA two authors without year", roadside, StyleAPA, "Стругацкий, А., & Стругацкий, Б. (n.d.). Пикник на обочине."},
+		{"APA three authors", three, StyleAPA, "Smith, A., Jones, B., & Brown, C. (2020). Go?"},
+		{"APA without author", anonymous, StyleAPA, "Слово о полку Игореве. (n.d.). Детская литература."},
+
+		{"MLA one author", warAndPeace, StyleMLA, "Толстой, Лев Николаевич. Война и мир. АСТ, 2015."},
+		{"MLA two authors", roadside, StyleMLA, "Стругацкий, Аркадий, and Борис Стругацкий. Пикник на обочине."},
+		{"MLA three authors", three, StyleMLA, "Smith, Ann, et al. Go? 2020."},
+		{"MLA without author", anonymous, StyleMLA, "Слово о полку Игореве. Детская литература."},
+
+		{"GOST one author", warAndPeace, StyleGOST, "Толстой, Л. Н. Война и мир / Л. Н. Толстой. – АСТ, 2015. – 1225 с. – ISBN 9785170906307."},
+		{"GOST two authors", roadside, StyleGOST, "Стругацкий, А. Пикник на обочине / А. Стругацкий, Б. Стругацкий."},
+		{"GOST four authors start with the title", four, StyleGOST, "Сборник / A. Smith, B. Jones, C. Brown [и др.]. – Наука."},
+		{"GOST without author", anonymous, StyleGOST, "Слово о полку Игореве. – Детская литература."},
+	}
+
+	for _, tt := range tests {
+		if got := Cite(tt.record, tt.style); got != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCiteAPAManyAuthors(t *testing.T) {
+	record := Record{Title: "Отчет"}
+	for i := 1; i <= apaMaxAuthors+1; i++ {
+		record.Authors = append(record.Authors, fmt.Sprintf("Ann Author%02d", i))
+	}
+
+	got := Cite(record, StyleAPA)
+	if !strings.Contains(got, "Author19, A., . . . Author21, A. (n.d.)") || strings.Contains(got, "Author20") {
+		t.Errorf("Cite = %q", got)
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	for _, style := range []string{"apa", "mla", "gost"} {
+		if got, err := ParseStyle(style); err != nil || string(got) != style {
+			t.Errorf("ParseStyle(%q) = %q, %v", style, got, err)
+		}
+	}
+	if _, err := ParseStyle("chicago"); err == nil {
+		t.Error("ParseStyle(chicago): нет ошибки")
+	}
+}
+
+func TestSentence(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{" Война и мир ", "Война и мир."},
+		{"Что делать?", "Что делать?"},
+		{"Т. 1.", "Т. 1."},
+	}
+
+	for _, tt := range tests {
+		if got := sentence(tt.in); got != tt.want {
+			t.Errorf("sentence(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package bibliography
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// cslItem элемент CSL-JSON — формата, который понимают Zotero, Mendeley и citeproc
+type cslItem struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	Title         string    `json:"title"`
+	Author        []cslName `json:"author,omitempty"`
+	Publisher     string    `json:"publisher,omitempty"`
+	Issued        *cslDate  `json:"issued,omitempty"`
+	ISBN          string    `json:"ISBN,omitempty"`
+	NumberOfPages string    `json:"number-of-pages,omitempty"`
+	Abstract      string    `json:"abstract,omitempty"`
+}
+
+type cslName struct {
+	Family string `json:"family"`
+	Given  string `json:"given,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+func cslFromRecord(record Record) cslItem {
+	item := cslItem{
+		ID:        record.ID.String(),
+		Type:      "book",
+		Title:     record.Title,
+		Publisher: record.Publisher,
+		ISBN:      record.ISBN,
+		Abstract:  record.Description,
+	}
+	for _, name := range parseNames(record.Authors) {
+		item.Author = append(item.Author, cslName{Family: name.Family, Given: name.Given})
+	}
+	if record.Year > 0 {
+		item.Issued = &cslDate{DateParts: [][]int{{record.Year}}}
+	}
+	if record.PageCount > 0 {
+		item.NumberOfPages = strconv.Itoa(record.PageCount)
+	}
+	return item
+}
+
+// cslJSONWriter пишет JSON-массив элементов CSL поэлементно
+type cslJSONWriter struct {
+	w       io.Writer
+	written int
+}
+
+func (w *cslJSONWriter) Begin() error {
+	_, err := io.WriteString(w.w, "[")
+	return err
+}
+
+func (w *cslJSONWriter) Write(record Record) error {
+	if w.written > 0 {
+		if _, err := io.WriteString(w.w, ","); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(cslFromRecord(record))
+	if err != nil {
+		return err
+	}
+
+	w.written++
+	_, err = w.w.Write(data)
+	return err
+}
+
+func (w *cslJSONWriter) Flush() error {
+	return nil
+}
+
+func (w *cslJSONWriter) End() error {
+	_, err := io.WriteString(w.w, "]")
+	return err
+}
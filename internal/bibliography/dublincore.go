@@ -0,0 +1,87 @@
+package bibliography
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	dcNamespace    = "http://purl.org/dc/elements/1.1/"
+	oaiDCNamespace = "http://www.openarchives.org/OAI/2.0/oai_dc/"
+)
+
+// dcRecord запись oai_dc:dc — простой Dublin Core, как его отдают OAI-PMH репозитории
+type dcRecord struct {
+	XMLName     xml.Name `xml:"oai_dc:dc"`
+	Titles      []string `xml:"dc:title"`
+	Creators    []string `xml:"dc:creator"`
+	Publishers  []string `xml:"dc:publisher"`
+	Dates       []string `xml:"dc:date"`
+	Types       []string `xml:"dc:type"`
+	Formats     []string `xml:"dc:format"`
+	Identifiers []string `xml:"dc:identifier"`
+	Description []string `xml:"dc:description"`
+}
+
+// dublinCoreWriter пишет записи oai_dc:dc внутри корневого элемента records, где объявлены пространства имен
+type dublinCoreWriter struct {
+	w       *bufio.Writer
+	encoder *xml.Encoder
+}
+
+func newDublinCoreWriter(w io.Writer) *dublinCoreWriter {
+	buffered := bufio.NewWriter(w)
+	return &dublinCoreWriter{w: buffered, encoder: xml.NewEncoder(buffered)}
+}
+
+func (w *dublinCoreWriter) Begin() error {
+	_, err := fmt.Fprintf(w.w, "%s<records xmlns:oai_dc=%q xmlns:dc=%q>", xml.Header, oaiDCNamespace, dcNamespace)
+	return err
+}
+
+func (w *dublinCoreWriter) Write(record Record) error {
+	dc := dcRecord{
+		Titles:      []string{record.Title},
+		Types:       []string{"Text"},
+		Identifiers: []string{"urn:uuid:" + record.ID.String()},
+	}
+	for _, name := range parseNames(record.Authors) {
+		dc.Creators = append(dc.Creators, name.Inverted())
+	}
+	if record.Publisher != "" {
+		dc.Publishers = []string{record.Publisher}
+	}
+	if record.Year > 0 {
+		dc.Dates = []string{strconv.Itoa(record.Year)}
+	}
+	if record.PageCount > 0 {
+		dc.Formats = []string{fmt.Sprintf("%d pages", record.PageCount)}
+	}
+	if record.ISBN != "" {
+		dc.Identifiers = append(dc.Identifiers, "urn:isbn:"+record.ISBN)
+	}
+	if record.Description != "" {
+		dc.Description = []string{record.Description}
+	}
+	return w.encoder.Encode(dc)
+}
+
+func (w *dublinCoreWriter) Flush() error {
+	if err := w.encoder.Flush(); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+func (w *dublinCoreWriter) End() error {
+	if err := w.encoder.Flush(); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w.w, "</records>\n"); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
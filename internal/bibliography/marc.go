@@ -0,0 +1,165 @@
+package bibliography
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const marcNamespace = "http://www.loc.gov/MARC21/slim"
+
+// marcLeader: новая запись (n), текстовый материал (a), монография (m), кодировка UCS/Unicode (a),
+// описание по ISBD (i). Длина записи и базовый адрес в MARCXML не вычисляются и остаются нулями
+const marcLeader = "00000nam a2200000 i 4500"
+
+type marcRecord struct {
+	XMLName       xml.Name           `xml:"record"`
+	Leader        string             `xml:"leader"`
+	ControlFields []marcControlField `xml:"controlfield"`
+	DataFields    []marcDataField    `xml:"datafield"`
+}
+
+type marcControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type marcDataField struct {
+	Tag       string         `xml:"tag,attr"`
+	Ind1      string         `xml:"ind1,attr"`
+	Ind2      string         `xml:"ind2,attr"`
+	Subfields []marcSubfield `xml:"subfield"`
+}
+
+type marcSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// marcWriter пишет MARCXML (MARC 21 slim): записи внутри одного элемента collection
+type marcWriter struct {
+	w       *bufio.Writer
+	encoder *xml.Encoder
+}
+
+func newMARCWriter(w io.Writer) *marcWriter {
+	buffered := bufio.NewWriter(w)
+	return &marcWriter{w: buffered, encoder: xml.NewEncoder(buffered)}
+}
+
+func (w *marcWriter) Begin() error {
+	_, err := fmt.Fprintf(w.w, "%s<collection xmlns=%q>", xml.Header, marcNamespace)
+	return err
+}
+
+func (w *marcWriter) Write(record Record) error {
+	return w.encoder.Encode(marcFromRecord(record))
+}
+
+func (w *marcWriter) Flush() error {
+	if err := w.encoder.Flush(); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+func (w *marcWriter) End() error {
+	if err := w.encoder.Flush(); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w.w, "</collection>\n"); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+func marcFromRecord(record Record) marcRecord {
+	marc := marcRecord{
+		Leader: marcLeader,
+		ControlFields: []marcControlField{
+			{Tag: "001", Value: record.ID.String()},
+			{Tag: "008", Value: marcFixedData(record)},
+		},
+	}
+
+	if record.ISBN != "" {
+		marc.DataFields = append(marc.DataFields, dataField("020", " ", " ", "a", record.ISBN))
+	}
+
+	names := parseNames(record.Authors)
+	// 245 ind1: 1 — есть основная точка доступа (100), 0 — запись описывается под заглавием
+	titleIndicator := "0"
+	if len(names) > 0 {
+		marc.DataFields = append(marc.DataFields, dataField("100", "1", " ", "a", names[0].Inverted(), "e", "author."))
+		titleIndicator = "1"
+	}
+
+	title := dataField("245", titleIndicator, "0", "a", record.Title)
+	if len(names) > 0 {
+		statement := make([]string, len(names))
+		for i, name := range names {
+			statement[i] = name.Natural()
+		}
+		title.Subfields[0].Value += " /"
+		title.Subfields = append(title.Subfields, marcSubfield{Code: "c", Value: joinNatural(statement, ", ", " and ") + "."})
+	}
+	marc.DataFields = append(marc.DataFields, title)
+
+	if record.Publisher != "" || record.Year > 0 {
+		publication := marcDataField{Tag: "264", Ind1: " ", Ind2: "1"}
+		if record.Publisher != "" {
+			publication.Subfields = append(publication.Subfields, marcSubfield{Code: "b", Value: record.Publisher})
+		}
+		if record.Year > 0 {
+			publication.Subfields = append(publication.Subfields, marcSubfield{Code: "c", Value: strconv.Itoa(record.Year)})
+		}
+		marc.DataFields = append(marc.DataFields, publication)
+	}
+
+	if record.PageCount > 0 {
+		marc.DataFields = append(marc.DataFields, dataField("300", " ", " ", "a", fmt.Sprintf("%d pages", record.PageCount)))
+	}
+	if record.Description != "" {
+		marc.DataFields = append(marc.DataFields, dataField("520", " ", " ", "a", record.Description))
+	}
+	for _, name := range names[min(1, len(names)):] {
+		marc.DataFields = append(marc.DataFields, dataField("700", "1", " ", "a", name.Inverted(), "e", "author."))
+	}
+
+	return marc
+}
+
+// marcFixedData собирает поле 008 (40 позиций): дата создания записи, тип даты и год издания;
+// место издания, язык и специфичные для книг позиции не известны и заполнены знаками «не кодируется»
+func marcFixedData(record Record) string {
+	dateType, year := "n", "uuuu"
+	if record.Year > 0 && record.Year <= 9999 {
+		dateType, year = "s", fmt.Sprintf("%04d", record.Year)
+	}
+	return record.CreatedAt.UTC().Format("060102") + dateType + year + "    " + "xx " + "|||||||||||||||||" + "und" + "|" + "d"
+}
+
+func dataField(tag, ind1, ind2 string, codeValues ...string) marcDataField {
+	field := marcDataField{Tag: tag, Ind1: ind1, Ind2: ind2}
+	for i := 0; i+1 < len(codeValues); i += 2 {
+		field.Subfields = append(field.Subfields, marcSubfield{Code: codeValues[i], Value: codeValues[i+1]})
+	}
+	return field
+}
+
+// joinNatural перечисляет элементы через sep, а последний — через last: «A, B and C»
+func joinNatural(items []string, sep, last string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+	result := items[0]
+	for _, item := range items[1 : len(items)-1] {
+		result += sep + item
+	}
+	return result + last + items[len(items)-1]
+}
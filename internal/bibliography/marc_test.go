@@ -0,0 +1,129 @@
+package bibliography
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestMARCFromRecord(t *testing.T) {
+	id := uuid.MustParse("0b6f3c1e-8d1a-4c3b-9a57-2f0c7c1d5e11")
+	createdAt := time.Date(2024, 3, 5, 23, 30, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	tests := []struct {
+		name   string
+		record Record
+		fixed  string
+		fields []marcDataField
+	}{
+		{
+			name: "all fields",
+			record: Record{
+				ID:          id,
+				Title:       "Пикник на обочине",
+				Authors:     []string{"Аркадий Натанович Стругацкий", "Стругацкий, Борис"},
+				ISBN:        "9785170903344",
+				Publisher:   "АСТ",
+				Year:        2015,
+				PageCount:   256,
+				Description: "Повесть",
+				CreatedAt:   createdAt,
+			},
+			fixed: "240305s2015    xx |||||||||||||||||und|d",
+			fields: []marcDataField{
+				dataField("020", " ", " ", "a", "9785170903344"),
+				dataField("100", "1", " ", "a", "Стругацкий, Аркадий Натанович", "e", "author."),
+				dataField("245", "1", "0", "a", "Пикник на обочине /", "c", "Аркадий Натанович Стругацкий and Борис Стругацкий."),
+				dataField("264", " ", "1", "b", "АСТ", "c", "2015"),
+				dataField("300", " ", " ", "a", "256 pages"),
+				dataField("520", " ", " ", "a", "Повесть"),
+				dataField("700", "1", " ", "a", "Стругацкий, Борис", "e", "author."),
+			},
+		},
+		{
+			name:   "title only",
+			record: Record{ID: id, Title: "Слово о полку Игореве", CreatedAt: createdAt},
+			fixed:  "240305nuuuu    xx |||||||||||||||||und|d",
+			fields: []marcDataField{
+				dataField("245", "0", "0", "a", "Слово о полку Игореве"),
+			},
+		},
+		{
+			name:   "year without publisher",
+			record: Record{ID: id, Title: "T", Year: 1869, CreatedAt: createdAt},
+			fixed:  "240305s1869    xx |||||||||||||||||und|d",
+			fields: []marcDataField{
+				dataField("245", "0", "0", "a", "T"),
+				dataField("264", " ", "1", "c", "1869"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marc := marcFromRecord(tt.record)
+			if marc.Leader != marcLeader || len(marc.Leader) != 24 {
+				t.Errorf("leader = %q", marc.Leader)
+			}
+			if len(marc.ControlFields) != 2 || marc.ControlFields[0] != (marcControlField{Tag: "001", Value: id.String()}) {
+				t.Fatalf("controlfields = %+v", marc.ControlFields)
+			}
+			if fixed := marc.ControlFields[1]; fixed.Tag != "008" || fixed.Value != tt.fixed || len(fixed.Value) != 40 {
+				t.Errorf("008 = %q (%d), want %q", fixed.Value, len(fixed.Value), tt.fixed)
+			}
+
+			if len(marc.DataFields) != len(tt.fields) {
+				t.Fatalf("datafields = %+v, want %+v", marc.DataFields, tt.fields)
+			}
+			for i, want := range tt.fields {
+				got := marc.DataFields[i]
+				if got.Tag != want.Tag || got.Ind1 != want.Ind1 || got.Ind2 != want.Ind2 || len(got.Subfields) != len(want.Subfields) {
+					t.Errorf("datafield %d = %+v, want %+v", i, got, want)
+					continue
+				}
+				for j := range want.Subfields {
+					if got.Subfields[j] != want.Subfields[j] {
+						t.Errorf("datafield %s subfield %d = %+v, want %+v", want.Tag, j, got.Subfields[j], want.Subfields[j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMARCWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newMARCWriter(&buf)
+	records := []Record{
+		{ID: uuid.New(), Title: "Первая <книга> & продолжение"},
+		{ID: uuid.New(), Title: "Вторая"},
+	}
+
+	if err := w.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		if err := w.Write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.End(); err != nil {
+		t.Fatal(err)
+	}
+
+	var collection struct {
+		XMLName xml.Name     `xml:"http://www.loc.gov/MARC21/slim collection"`
+		Records []marcRecord `xml:"record"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &collection); err != nil {
+		t.Fatalf("некорректный MARCXML: %v\n%s", err, buf.String())
+	}
+	if len(collection.Records) != len(records) {
+		t.Fatalf("записей %d, want %d", len(collection.Records), len(records))
+	}
+	if title := collection.Records[0].DataFields[0].Subfields[0].Value; title != records[0].Title {
+		t.Errorf("245$a = %q, want %q", title, records[0].Title)
+	}
+}
@@ -0,0 +1,132 @@
+// Package bibliography строит библиографические записи книг: выгрузки каталога в MARCXML, Dublin Core,
+// BibTeX и CSL-JSON и ссылки на книгу по стилям APA, MLA и ГОСТ Р 7.0.100-2018
+package bibliography
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Record книга со всем, что нужно для библиографического описания
+type Record struct {
+	ID          uuid.UUID
+	Title       string
+	Authors     []string // имена как в каталоге: «Имя Фамилия» или «Фамилия, Имя»
+	ISBN        string   // ISBN-13 без дефисов
+	Publisher   string
+	Year        int // 0 — год неизвестен
+	PageCount   int // 0 — число страниц неизвестно
+	Description string
+	CreatedAt   time.Time
+}
+
+// Format формат выгрузки каталога
+type Format string
+
+const (
+	FormatMARCXML    Format = "marcxml"
+	FormatDublinCore Format = "dc"
+	FormatBibTeX     Format = "bibtex"
+	FormatCSLJSON    Format = "csl-json"
+)
+
+// ParseFormat проверяет формат выгрузки каталога
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case FormatMARCXML, FormatDublinCore, FormatBibTeX, FormatCSLJSON:
+		return Format(format), nil
+	default:
+		return "", fmt.Errorf("неподдерживаемый формат выгрузки каталога: %s", format)
+	}
+}
+
+// ContentType возвращает MIME-тип файла выгрузки
+func (f Format) ContentType() string {
+	switch f {
+	case FormatMARCXML:
+		return "application/marcxml+xml; charset=utf-8"
+	case FormatDublinCore:
+		return "application/xml; charset=utf-8"
+	case FormatBibTeX:
+		return "application/x-bibtex; charset=utf-8"
+	default:
+		return "application/vnd.citationstyles.csl+json; charset=utf-8"
+	}
+}
+
+// FileName возвращает имя файла выгрузки
+func (f Format) FileName() string {
+	switch f {
+	case FormatMARCXML:
+		return "catalog.marc.xml"
+	case FormatDublinCore:
+		return "catalog.dc.xml"
+	case FormatBibTeX:
+		return "catalog.bib"
+	default:
+		return "catalog.csl.json"
+	}
+}
+
+// Name имя автора, разделенное на фамилию и имя для стилей, которые их переставляют
+type Name struct {
+	Family string
+	Given  string
+}
+
+// ParseName разбирает имя автора: «Фамилия, Имя Отчество» или «Имя Отчество Фамилия».
+// Имя из одного слова (псевдоним, организация) целиком считается фамилией
+func ParseName(full string) Name {
+	full = strings.Join(strings.Fields(full), " ")
+	if family, given, ok := strings.Cut(full, ","); ok {
+		return Name{Family: strings.TrimSpace(family), Given: strings.TrimSpace(given)}
+	}
+
+	i := strings.LastIndex(full, " ")
+	if i < 0 {
+		return Name{Family: full}
+	}
+	return Name{Family: full[i+1:], Given: full[:i]}
+}
+
+// Initials возвращает инициалы имени: «Лев Николаевич» → «Л. Н.», «Жан-Поль» → «Ж.-П.»
+func (n Name) Initials() string {
+	parts := strings.Fields(n.Given)
+	for i, part := range parts {
+		pieces := strings.Split(part, "-")
+		for j, piece := range pieces {
+			for _, r := range piece {
+				pieces[j] = string(unicode.ToUpper(r)) + "."
+				break
+			}
+		}
+		parts[i] = strings.Join(pieces, "-")
+	}
+	return strings.Join(parts, " ")
+}
+
+// Inverted возвращает «Фамилия, Имя», как принято в заголовках записей
+func (n Name) Inverted() string {
+	if n.Given == "" {
+		return n.Family
+	}
+	return n.Family + ", " + n.Given
+}
+
+// Natural возвращает «Имя Фамилия», как имя пишется в сведениях об ответственности
+func (n Name) Natural() string {
+	return strings.TrimSpace(n.Given + " " + n.Family)
+}
+
+func parseNames(authors []string) []Name {
+	names := make([]Name, 0, len(authors))
+	for _, author := range authors {
+		if name := ParseName(author); name.Family != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
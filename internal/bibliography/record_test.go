@@ -0,0 +1,78 @@
+package bibliography
+
+import "testing"
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		full                    string
+		family, given, initials string
+	}{
+		{"Лев Николаевич Толстой", "Толстой", "Лев Николаевич", "Л. Н."},
+		{"Толстой, Лев Николаевич", "Толстой", "Лев Николаевич", "Л. Н."},
+		{"  Жан-Поль   Сартр ", "Сартр", "Жан-Поль", "Ж.-П."},
+		{"ursula k. le guin", "guin", "ursula k. le", "U. K. L."},
+		{"Гомер", "Гомер", "", ""},
+		{"Le Guin, Ursula", "Le Guin", "Ursula", "U."},
+		{"", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		name := ParseName(tt.full)
+		if name.Family != tt.family || name.Given != tt.given {
+			t.Errorf("ParseName(%q) = %+v, want {%s %s}", tt.full, name, tt.family, tt.given)
+		}
+		if got := name.Initials(); got != tt.initials {
+			t.Errorf("ParseName(%q).Initials() = %q, want %q", tt.full, got, tt.initials)
+		}
+	}
+}
+
+func TestNameForms(t *testing.T) {
+	tests := []struct {
+		name              Name
+		inverted, natural string
+	}{
+		{Name{Family: "Толстой", Given: "Лев"}, "Толстой, Лев", "Лев Толстой"},
+		{Name{Family: "Гомер"}, "Гомер", "Гомер"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.name.Inverted(); got != tt.inverted {
+			t.Errorf("%+v.Inverted() = %q, want %q", tt.name, got, tt.inverted)
+		}
+		if got := tt.name.Natural(); got != tt.natural {
+			t.Errorf("%+v.Natural() = %q, want %q", tt.name, got, tt.natural)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range []string{"marcxml", "dc", "bibtex", "csl-json"} {
+		if got, err := ParseFormat(format); err != nil || string(got) != format {
+			t.Errorf("ParseFormat(%q) = %q, %v", format, got, err)
+		}
+	}
+	for _, format := range []string{"", "MARCXML", "ris"} {
+		if _, err := ParseFormat(format); err == nil {
+			t.Errorf("ParseFormat(%q): нет ошибки", format)
+		}
+	}
+}
+
+func TestJoinNatural(t *testing.T) {
+	tests := []struct {
+		items []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{"A"}, "A"},
+		{[]string{"A", "B"}, "A and B"},
+		{[]string{"A", "B", "C"}, "A, B and C"},
+	}
+
+	for _, tt := range tests {
+		if got := joinNatural(tt.items, ", ", " and "); got != tt.want {
+			t.Errorf("joinNatural(%q) = %q, want %q", tt.items, got, tt.want)
+		}
+	}
+}
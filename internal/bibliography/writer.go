@@ -0,0 +1,26 @@
+package bibliography
+
+import "io"
+
+// Writer пишет записи выгрузки каталога в конкретном формате. Записи пишутся по одной,
+// поэтому каталог выгружается потоком, не собираясь целиком в памяти
+type Writer interface {
+	Begin() error
+	Write(record Record) error
+	Flush() error
+	End() error
+}
+
+// NewWriter создает Writer формата поверх w
+func NewWriter(format Format, w io.Writer) Writer {
+	switch format {
+	case FormatMARCXML:
+		return newMARCWriter(w)
+	case FormatDublinCore:
+		return newDublinCoreWriter(w)
+	case FormatBibTeX:
+		return newBibTeXWriter(w)
+	default:
+		return &cslJSONWriter{w: w}
+	}
+}
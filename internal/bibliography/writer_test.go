@@ -0,0 +1,137 @@
+package bibliography
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/google/uuid"
+	"strings"
+	"testing"
+)
+
+func TestWriters(t *testing.T) {
+	records := []Record{
+		{
+			ID:          uuid.MustParse("0b6f3c1e-8d1a-4c3b-9a57-2f0c7c1d5e11"),
+			Title:       "Война и мир",
+			Authors:     []string{"Лев Толстой"},
+			ISBN:        "9785170906307",
+			Publisher:   "АСТ",
+			Year:        2015,
+			PageCount:   1225,
+			Description: "Роман-эпопея <1869>",
+		},
+		{ID: uuid.MustParse("6f0e7a52-3b1d-4d7e-8c4a-1f2b3c4d5e6f"), Title: "Слово о полку Игореве"},
+	}
+
+	tests := []struct {
+		format Format
+		check  func(t *testing.T, output []byte)
+	}{
+		{FormatMARCXML, func(t *testing.T, output []byte) {
+			if count := bytes.Count(output, []byte("<record>")); count != 2 {
+				t.Errorf("записей record: %d", count)
+			}
+		}},
+		{FormatDublinCore, func(t *testing.T, output []byte) {
+			var root struct {
+				Records []struct {
+					Titles      []string `xml:"title"`
+					Creators    []string `xml:"creator"`
+					Dates       []string `xml:"date"`
+					Identifiers []string `xml:"identifier"`
+					Description []string `xml:"description"`
+				} `xml:"dc"`
+			}
+			if err := xml.Unmarshal(output, &root); err != nil {
+				t.Fatalf("некорректный XML: %v", err)
+			}
+			if len(root.Records) != 2 {
+				t.Fatalf("записей %d", len(root.Records))
+			}
+			first := root.Records[0]
+			want := []string{"urn:uuid:0b6f3c1e-8d1a-4c3b-9a57-2f0c7c1d5e11", "urn:isbn:9785170906307"}
+			if strings.Join(first.Identifiers, " ") != strings.Join(want, " ") || first.Creators[0] != "Толстой, Лев" ||
+				first.Dates[0] != "2015" || first.Description[0] != "Роман-эпопея <1869>" {
+				t.Errorf("запись = %+v", first)
+			}
+			if len(root.Records[1].Creators) != 0 || len(root.Records[1].Dates) != 0 {
+				t.Errorf("лишние поля у записи без автора и года: %+v", root.Records[1])
+			}
+		}},
+		{FormatBibTeX, func(t *testing.T, output []byte) {
+			if !bytes.HasPrefix(output, []byte("@book{tolstoy2015,\n")) || bytes.Count(output, []byte("@book{")) != 2 {
+				t.Errorf("выгрузка:\n%s", output)
+			}
+		}},
+		{FormatCSLJSON, func(t *testing.T, output []byte) {
+			var items []cslItem
+			if err := json.Unmarshal(output, &items); err != nil {
+				t.Fatalf("некорректный JSON: %v\n%s", err, output)
+			}
+			if len(items) != 2 {
+				t.Fatalf("элементов %d", len(items))
+			}
+			first := items[0]
+			if first.ID != "0b6f3c1e-8d1a-4c3b-9a57-2f0c7c1d5e11" || first.Type != "book" || first.Title != "Война и мир" ||
+				len(first.Author) != 1 || first.Author[0] != (cslName{Family: "Толстой", Given: "Лев"}) ||
+				first.Issued == nil || first.Issued.DateParts[0][0] != 2015 || first.NumberOfPages != "1225" ||
+				first.ISBN != "9785170906307" || first.Publisher != "АСТ" || first.Abstract != "Роман-эпопея <1869>" {
+				t.Errorf("элемент = %+v", first)
+			}
+			if !bytes.Contains(output, []byte(`"number-of-pages":"1225"`)) {
+				t.Errorf("нет number-of-pages:\n%s", output)
+			}
+			if items[1].Issued != nil || items[1].Author != nil {
+				t.Errorf("лишние поля у книги без автора и года: %+v", items[1])
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewWriter(tt.format, &buf)
+			if err := w.Begin(); err != nil {
+				t.Fatal(err)
+			}
+			for _, record := range records {
+				if err := w.Write(record); err != nil {
+					t.Fatal(err)
+				}
+				if err := w.Flush(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.End(); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, buf.Bytes())
+		})
+	}
+}
+
+func TestWriterEmptyCatalog(t *testing.T) {
+	for _, format := range []Format{FormatMARCXML, FormatDublinCore, FormatCSLJSON} {
+		var buf bytes.Buffer
+		w := NewWriter(format, &buf)
+		if err := w.Begin(); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.End(); err != nil {
+			t.Fatal(err)
+		}
+
+		var err error
+		if format == FormatCSLJSON {
+			var items []interface{}
+			err = json.Unmarshal(buf.Bytes(), &items)
+		} else {
+			var root struct{}
+			err = xml.Unmarshal(buf.Bytes(), &root)
+		}
+		if err != nil {
+			t.Errorf("%s: пустая выгрузка некорректна: %v\n%s", format, err, buf.String())
+		}
+	}
+}
@@ -2,7 +2,6 @@ package database
 
 import (
 	"book-management-system/config"
-	"book-management-system/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"log"
@@ -10,7 +9,7 @@ import (
 
 var DB *gorm.DB
 
-// InitDatabase инициализирует подключение к PostgreSQL
+// InitPostgres инициализирует подключение к PostgreSQL. Схему ведут миграции из internal/migrations
 func InitPostgres() {
 	dsn := config.GetEnv("DATABASE_URL", "host=localhost user=postgres password=secret dbname=books port=5432 sslmode=disable")
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
@@ -20,18 +19,5 @@ func InitPostgres() {
 
 	log.Println("База данных успешно подключена")
 
-	// Автомиграция моделей
-	db.AutoMigrate(
-		&models.Author{},
-		&models.Book{},
-		&models.BookRating{},
-		&models.BookAuthor{},
-		&models.ModeratorAction{},
-		&models.ReadingProgress{},
-		&models.RefreshToken{},
-		&models.User{},
-		&models.UserBook{},
-	)
-
 	DB = db
 }
@@ -1,12 +1,16 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 // CreateBookRequest тело запроса на создание книги
 type CreateBookRequest struct {
 	Title       string      `json:"title"`
 	Description string      `json:"description"`
 	CoverImage  string      `json:"cover_image"`
+	PageCount   int         `json:"page_count"`
 	AuthorIDs   []uuid.UUID `json:"author_ids"`
 }
 
@@ -36,12 +40,48 @@ type BookResponse struct {
 	// Example: "/uploads/123e4567-e89b-12d3-a456-426614174000.jpg"
 	CoverImage string `json:"cover_image"`
 
-	// Средний рейтинг книги (из 10)
+	// Количество страниц (0 — неизвестно)
+	// Example: 352
+	PageCount int `json:"page_count"`
+
+	// ISBN-13 без дефисов, если известен
+	// Example: "9785170906307"
+	ISBN string `json:"isbn,omitempty"`
+
+	// Издательство
+	// Example: "АСТ"
+	Publisher string `json:"publisher,omitempty"`
+
+	// Год издания (0 — неизвестен)
+	// Example: 2015
+	PublishedYear int `json:"published_year,omitempty"`
+
+	// Средний рейтинг книги по отзывам (из 10), простое среднее
 	// Example: 8.5
 	AverageRating float64 `json:"average_rating"`
 
+	// Байесовский рейтинг (из 10): учитывает число отзывов и их полезность, по нему строится топ
+	// Example: 7.9
+	BayesianRating float64 `json:"bayesian_rating"`
+
+	// Количество отзывов, учтенных в рейтинге
+	// Example: 42
+	RatingsCount int `json:"ratings_count"`
+
 	// Авторы книги (массив объектов)
 	Authors []AuthorByBookResponse `json:"authors"`
+
+	// Когда книга добавлена в каталог
+	// Example: "2024-03-01T12:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+
+	// Когда книга подтверждена и появилась в каталоге
+	// Example: "2024-03-02T09:00:00Z"
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+
+	// Когда карточка книги последний раз менялась
+	// Example: "2024-03-05T08:30:00Z"
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // BookListResponse DTO для списка книг с пагинацией
@@ -55,6 +95,12 @@ type PaginatedBooksResponse struct {
 	NextCursor *uuid.UUID `json:"next_cursor,omitempty"`
 }
 
+// TopRatedBooksResponse DTO для списка книг с лучшим рейтингом
+// @Description Книги по убыванию байесовского рейтинга
+type TopRatedBooksResponse struct {
+	Books []BookResponse `json:"books"`
+}
+
 // BookDeletionResponse DTO для списка книг с пагинацией
 // @Description Ответ API со списком книг
 type BookDeletionResponse struct {
@@ -0,0 +1,74 @@
+package dto
+
+// BookJSONLD разметка книги по schema.org в JSON-LD для поисковиков
+// @Description Объект schema.org Book; поля без значения не выводятся
+type BookJSONLD struct {
+	// Example: "https://schema.org"
+	Context string `json:"@context"`
+
+	// Example: "Book"
+	Type string `json:"@type"`
+
+	// Адрес страницы книги, он же идентификатор
+	// Example: "https://books.example.com/share/books/123e4567-e89b-12d3-a456-426614174000"
+	ID  string `json:"@id"`
+	URL string `json:"url"`
+
+	// Example: "Война и мир"
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// ISBN-13 без дефисов
+	// Example: "9785170906307"
+	ISBN string `json:"isbn,omitempty"`
+
+	// Абсолютный адрес обложки
+	// Example: "https://books.example.com/uploads/123e4567-e89b-12d3-a456-426614174000.jpg"
+	Image         string `json:"image,omitempty"`
+	NumberOfPages int    `json:"numberOfPages,omitempty"`
+
+	// Год издания
+	// Example: "2015"
+	DatePublished string `json:"datePublished,omitempty"`
+
+	Publisher       *SchemaOrganization    `json:"publisher,omitempty"`
+	Author          []SchemaPerson         `json:"author,omitempty"`
+	AggregateRating *SchemaAggregateRating `json:"aggregateRating,omitempty"`
+}
+
+// SchemaPerson автор книги по schema.org
+type SchemaPerson struct {
+	// Example: "Person"
+	Type string `json:"@type"`
+
+	// Example: "Лев Толстой"
+	Name string `json:"name"`
+}
+
+// SchemaOrganization издательство по schema.org
+type SchemaOrganization struct {
+	// Example: "Organization"
+	Type string `json:"@type"`
+
+	// Example: "АСТ"
+	Name string `json:"name"`
+}
+
+// SchemaAggregateRating рейтинг книги по видимым отзывам, шкала от 1 до 10
+type SchemaAggregateRating struct {
+	// Example: "AggregateRating"
+	Type string `json:"@type"`
+
+	// Простое среднее оценок, округленное до десятых
+	// Example: 8.5
+	RatingValue float64 `json:"ratingValue"`
+
+	// Example: 10
+	BestRating int `json:"bestRating"`
+
+	// Example: 1
+	WorstRating int `json:"worstRating"`
+
+	// Example: 42
+	RatingCount int `json:"ratingCount"`
+}
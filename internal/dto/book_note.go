@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// BookNoteRequest тело запроса на создание/обновление заметки к книге
+// @Description Заметка, выделение или цитата к книге из списка пользователя
+type BookNoteRequest struct {
+	// Тип записи: note, highlight, quote
+	// Required: true
+	// Example: "quote"
+	Kind string `json:"kind" binding:"required"`
+
+	// Текст заметки или цитаты
+	// Required: true
+	// Example: "Рукописи не горят"
+	Text string `json:"text" binding:"required"`
+
+	// Страница (для бумажных книг)
+	// Example: 245
+	Page *int `json:"page,omitempty"`
+
+	// Позиция в электронной книге (локация, CFI и т.п.)
+	// Example: "epubcfi(/6/4!/4/2/1:0)"
+	Location *string `json:"location,omitempty"`
+
+	// Видимость: private (по умолчанию) или public
+	// Example: "public"
+	Visibility string `json:"visibility"`
+}
+
+// BookNoteResponse DTO заметки пользователя
+type BookNoteResponse struct {
+	ID         uuid.UUID `json:"id"`
+	BookID     uuid.UUID `json:"book_id"`
+	Kind       string    `json:"kind"`
+	Text       string    `json:"text"`
+	Page       *int      `json:"page,omitempty"`
+	Location   *string   `json:"location,omitempty"`
+	Visibility string    `json:"visibility"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// QuoteResponse DTO публичной цитаты из книги
+// @Description Публичная цитата со счетчиками голосов
+type QuoteResponse struct {
+	ID       uuid.UUID `json:"id"`
+	BookID   uuid.UUID `json:"book_id"`
+	UserID   uuid.UUID `json:"user_id"`
+	Text     string    `json:"text"`
+	Page     *int      `json:"page,omitempty"`
+	Location *string   `json:"location,omitempty"`
+
+	// Лайки
+	// Example: 10
+	Likes int `json:"likes"`
+
+	// Дизлайки
+	// Example: 2
+	Dislikes int `json:"dislikes"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PaginatedQuotesResponse DTO списка цитат с маркерной пагинацией
+type PaginatedQuotesResponse struct {
+	Quotes []QuoteResponse `json:"quotes"`
+
+	// Следующий маркер для пагинации (если есть)
+	// Example: "123e4567-e89b-12d3-a456-426614174002"
+	NextCursor *uuid.UUID `json:"next_cursor,omitempty"`
+}
+
+// VoteNoteRequest DTO для голосования за цитату
+// @Description Запрос API с голосованием (1 -1 0)
+type VoteNoteRequest struct {
+	Vote int `json:"vote"` // 1 - лайк, -1 - дизлайк, 0 - удалить голос
+}
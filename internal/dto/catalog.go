@@ -0,0 +1,49 @@
+package dto
+
+import "github.com/google/uuid"
+
+// CatalogBook книга в переносимом формате каталога: авторы указаны именами, чтобы каталог
+// можно было загрузить в другую базу с другими идентификаторами
+type CatalogBook struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	CoverImage  string   `json:"cover_image,omitempty"`
+	PageCount   int      `json:"page_count,omitempty"`
+	Authors     []string `json:"authors"`
+}
+
+// CatalogImportResult итог загрузки каталога
+type CatalogImportResult struct {
+	// Создано книг
+	Created int `json:"created"`
+
+	// Пропущено книг, которые уже есть в каталоге (то же название и те же авторы)
+	Skipped int `json:"skipped"`
+
+	// Создано новых авторов
+	AuthorsCreated int `json:"authors_created"`
+
+	// Книги, которые не удалось загрузить
+	Failed []CatalogImportError `json:"failed"`
+}
+
+// CatalogImportError ошибка загрузки одной книги
+type CatalogImportError struct {
+	// Номер книги во входных данных, с нуля
+	Index int    `json:"index"`
+	Title string `json:"title"`
+	Error string `json:"error"`
+}
+
+// BookCitationResponse библиографическая ссылка на книгу
+// @Description Ссылка обычным текстом, без курсива и другого оформления
+type BookCitationResponse struct {
+	BookID uuid.UUID `json:"book_id"`
+
+	// Стиль: apa, mla или gost
+	// Example: "gost"
+	Style string `json:"style"`
+
+	// Example: "Толстой, Л. Н. Война и мир / Л. Н. Толстой. – АСТ, 2015. – 1225 с."
+	Citation string `json:"citation"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+// ConsistencyReport DTO отчета о согласованности PostgreSQL и MongoDB
+// @Description Результат проверки: по каждой проверке число найденных расхождений и, в режиме apply, исправленных
+type ConsistencyReport struct {
+	// Режим запуска: dry-run только ищет расхождения, apply еще и исправляет
+	// Example: dry-run
+	Mode string `json:"mode"`
+
+	// Время проверки
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Всего найдено расхождений
+	// Example: 12
+	Found int `json:"found"`
+
+	// Всего исправлено расхождений
+	// Example: 0
+	Repaired int `json:"repaired"`
+
+	// Результаты отдельных проверок
+	Checks []ConsistencyCheckResult `json:"checks"`
+}
+
+// ConsistencyCheckResult DTO результата одной проверки
+type ConsistencyCheckResult struct {
+	// Код проверки
+	// Example: orphan_reviews
+	Name string `json:"name"`
+
+	// Что проверяется и как исправляется
+	Description string `json:"description"`
+
+	// Найдено расхождений
+	// Example: 10
+	Found int `json:"found"`
+
+	// Исправлено расхождений
+	// Example: 0
+	Repaired int `json:"repaired"`
+
+	// Примеры затронутых идентификаторов (не больше 20)
+	Sample []string `json:"sample,omitempty"`
+
+	// Ошибка, из-за которой проверка не завершилась; остальные проверки выполняются
+	Error string `json:"error,omitempty"`
+}
@@ -32,6 +32,8 @@ type FeedbackResponse struct {
 	Text      string             `json:"text"`
 	Rating    int                `json:"rating"`
 	Checked   bool               `json:"checked"`
+	Flagged   bool               `json:"flagged"`
+	AutoFlags []string           `json:"auto_flags,omitempty"`
 	CreatedAt time.Time          `json:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at,omitempty"`
 }
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// FollowResponse DTO подписки
+// @Description Пользователь или автор, на которого подписан текущий пользователь
+type FollowResponse struct {
+	// На кого подписка: user или author
+	// Example: "author"
+	TargetType string `json:"target_type"`
+
+	// Example: "123e4567-e89b-12d3-a456-426614174000"
+	TargetID uuid.UUID `json:"target_id"`
+
+	// Когда оформлена подписка
+	// Example: "2024-03-01T12:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FeedEventResponse DTO события ленты
+// @Description Действие пользователя или автора, на которого подписан читатель
+type FeedEventResponse struct {
+	// Example: "123e4567-e89b-12d3-a456-426614174003"
+	ID uuid.UUID `json:"id"`
+
+	// Вид события: book_added, book_finished, review_posted, author_new_book
+	// Example: "review_posted"
+	Type string `json:"type"`
+
+	// Кто совершил действие: user или author
+	// Example: "user"
+	ActorType string `json:"actor_type"`
+
+	// Example: "123e4567-e89b-12d3-a456-426614174000"
+	ActorID uuid.UUID `json:"actor_id"`
+
+	// Имя пользователя или автора
+	// Example: "bookworm"
+	ActorName string `json:"actor_name"`
+
+	// Example: "123e4567-e89b-12d3-a456-426614174001"
+	BookID uuid.UUID `json:"book_id"`
+
+	// Example: "Мастер и Маргарита"
+	BookTitle string `json:"book_title"`
+
+	// ObjectID отзыва для review_posted
+	// Example: "65f1c2a4e13b5a0d9c8b4567"
+	ReviewID string `json:"review_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FeedResponse DTO ленты с маркерной пагинацией
+type FeedResponse struct {
+	Events []FeedEventResponse `json:"events"`
+
+	// Следующий маркер для пагинации (если есть)
+	// Example: "123e4567-e89b-12d3-a456-426614174003"
+	NextCursor *uuid.UUID `json:"next_cursor,omitempty"`
+}
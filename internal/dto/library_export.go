@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// LibraryExportEntry DTO одной записи выгрузки библиотеки пользователя
+// @Description Книга из списка пользователя вместе с оценкой и отзывом
+type LibraryExportEntry struct {
+	// Уникальный идентификатор книги (UUID)
+	// Example: "123e4567-e89b-12d3-a456-426614174000"
+	BookID uuid.UUID `json:"book_id"`
+
+	// Название книги
+	// Example: "Гарри Поттер и философский камень"
+	Title string `json:"title"`
+
+	// Имена авторов книги
+	Authors []string `json:"authors"`
+
+	// Статус чтения (reading, completed, dropped)
+	// Example: "completed"
+	Status string `json:"status"`
+
+	// Количество прочитанных страниц
+	// Example: 120
+	PagesRead int `json:"pages_read"`
+
+	// Количество завершенных прочтений
+	// Example: 2
+	ReadCount int `json:"read_count"`
+
+	// Оценка пользователя (от 1 до 10), если есть
+	// Example: 9
+	Rating *int `json:"rating,omitempty"`
+
+	// Текст отзыва пользователя, если есть
+	// Example: "Отличная книга, советую!"
+	Review *string `json:"review,omitempty"`
+
+	// Дата добавления книги в список
+	AddedAt time.Time `json:"added_at"`
+
+	// Дата последнего обновления прогресса
+	UpdatedAt time.Time `json:"updated_at"`
+}
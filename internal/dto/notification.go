@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// NotificationResponse DTO уведомления
+// @Description Уведомление пользователя
+type NotificationResponse struct {
+	// Example: "123e4567-e89b-12d3-a456-426614174005"
+	ID uuid.UUID `json:"id"`
+
+	// Вид уведомления: book_confirmed, review_replied, comment_replied, review_liked, review_moderated
+	// Example: "book_confirmed"
+	Type string `json:"type"`
+
+	// Example: "Ваша книга «Мастер и Маргарита» подтверждена и опубликована"
+	Message string `json:"message"`
+
+	// Кто вызвал уведомление
+	// Example: "123e4567-e89b-12d3-a456-426614174000"
+	ActorID *uuid.UUID `json:"actor_id,omitempty"`
+
+	// Example: "123e4567-e89b-12d3-a456-426614174001"
+	BookID *uuid.UUID `json:"book_id,omitempty"`
+
+	// Example: "65f1c2a4e13b5a0d9c8b4567"
+	ReviewID string `json:"review_id,omitempty"`
+
+	// Example: "65f1c2a4e13b5a0d9c8b4568"
+	CommentID string `json:"comment_id,omitempty"`
+
+	// Прочитано ли уведомление
+	// Example: false
+	Read bool `json:"read"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NotificationsResponse DTO списка уведомлений с маркерной пагинацией
+type NotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+
+	// Всего непрочитанных уведомлений
+	// Example: 3
+	UnreadCount int64 `json:"unread_count"`
+
+	// Следующий маркер для пагинации (если есть)
+	// Example: "123e4567-e89b-12d3-a456-426614174005"
+	NextCursor *uuid.UUID `json:"next_cursor,omitempty"`
+}
+
+// NotificationPreferenceResponse DTO настройки вида уведомлений
+type NotificationPreferenceResponse struct {
+	// Example: "review_liked"
+	Type string `json:"type"`
+
+	// Example: true
+	Enabled bool `json:"enabled"`
+}
+
+// UpdateNotificationPreferencesRequest DTO изменения настроек уведомлений
+// @Description Виды уведомлений и включены ли они; не указанные виды не меняются
+type UpdateNotificationPreferencesRequest struct {
+	// Example: {"review_liked": false}
+	Preferences map[string]bool `json:"preferences" binding:"required"`
+}
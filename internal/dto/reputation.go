@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// ReputationResponse DTO репутации пользователя
+// @Description Очки репутации и события, из которых они сложились
+type ReputationResponse struct {
+	// Итоговые очки
+	// Example: 124
+	Score int `json:"score"`
+
+	// Лайки, полученные на отзывы
+	// Example: 80
+	ReviewLikes int `json:"review_likes"`
+
+	// Дизлайки, полученные на отзывы
+	// Example: 6
+	ReviewDislikes int `json:"review_dislikes"`
+
+	// Добавленные пользователем и подтвержденные книги
+	// Example: 7
+	ConfirmedBooks int `json:"confirmed_books"`
+
+	// Отзывы, скрытые или удаленные модератором
+	// Example: 0
+	ModerationPenalties int `json:"moderation_penalties"`
+
+	// Доверенный пользователь: его книги публикуются без подтверждения модератором
+	// Example: true
+	Trusted bool `json:"trusted"`
+}
+
+// PublicProfileResponse DTO публичного профиля пользователя
+// @Description Открытые данные пользователя и его репутация
+type PublicProfileResponse struct {
+	// Example: "550e8400-e29b-41d4-a716-446655440000"
+	ID string `json:"id"`
+
+	// Example: "bookworm"
+	Username string `json:"username"`
+
+	Reputation ReputationResponse `json:"reputation"`
+
+	// Дата регистрации
+	// Example: "2024-02-01T12:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -7,10 +7,24 @@ type BaseReviewRequest struct {
 	// Example: "123e4567-e89b-12d3-a456-426614174000"
 	BookID string `json:"book_id" binding:"required"`
 
-	// Текст отзыва
+	// Текст отзыва, не длиннее 10000 символов
 	// Required: true
 	// Example: "Отличная книга, советую!"
-	Text string `json:"text" binding:"required"`
+	Text string `json:"text" binding:"required,max=10000"`
+
+	// Оценка (от 1 до 10)
+	// Required: true
+	// Example: 9
+	Rating int `json:"rating" binding:"required,min=1,max=10"`
+}
+
+// UpsertReviewRequest DTO для создания или обновления своего отзыва на книгу
+// @Description Текст и оценка отзыва текущего пользователя; книга берется из пути
+type UpsertReviewRequest struct {
+	// Текст отзыва, не длиннее 10000 символов
+	// Required: true
+	// Example: "Отличная книга, советую!"
+	Text string `json:"text" binding:"required,max=10000"`
 
 	// Оценка (от 1 до 10)
 	// Required: true
@@ -19,14 +33,32 @@ type BaseReviewRequest struct {
 }
 
 // ReviewResponse DTO для ответа API с информацией об отзыве
-// @Description Ответ API с информацией об отзыве
+// @Description Ответ API с информацией об отзыве. В зависимости от text_format содержит исходный текст, HTML или оба
 type ReviewResponse struct {
 
 	// Уникальный идентификатор отзыва (ObjectID в MongoDB)
 	// Example: "60c72b2f5f1b2c001f6f1b20"
 	ID string `json:"id"`
 
-	BaseReviewRequest
+	// ID книги (UUID)
+	// Example: "123e4567-e89b-12d3-a456-426614174000"
+	BookID string `json:"book_id"`
+
+	// Исходный текст отзыва в ограниченном Markdown
+	// Example: "Отличная книга, но ||в конце все умирают||"
+	Text *string `json:"text,omitempty"`
+
+	// Текст отзыва в безопасном HTML
+	// Example: "<p>Отличная книга, но <span class=\"spoiler\">в конце все умирают</span></p>"
+	TextHTML *string `json:"text_html,omitempty"`
+
+	// Есть ли в тексте спойлеры (клиенту стоит их размыть)
+	// Example: true
+	HasSpoilers bool `json:"has_spoilers"`
+
+	// Оценка (от 1 до 10)
+	// Example: 9
+	Rating int `json:"rating"`
 
 	// ID автора отзыва (UUID)
 	// Example: "550e8400-e29b-41d4-a716-446655440000"
@@ -40,6 +72,14 @@ type ReviewResponse struct {
 	// Example: 2
 	Dislikes int `json:"dislikes"`
 
+	// Количество комментариев
+	// Example: 4
+	CommentsCount int `json:"comments_count"`
+
+	// Отзыв скрыт до решения модератора (например, его отправил на проверку автоматический фильтр)
+	// Example: false
+	UnderModeration bool `json:"under_moderation,omitempty"`
+
 	// Дата создания
 	// Example: "2024-02-01T12:00:00Z"
 	CreatedAt string `json:"created_at"`
@@ -61,3 +101,57 @@ type ReviewListResponse struct {
 type VoteReviewRequest struct {
 	Vote int `json:"vote"` // 1 - лайк, -1 - дизлайк, 0 - удалить голос
 }
+
+// ReviewDiffLine DTO для строки diff'а между версиями отзыва
+// @Description Строка текста с пометкой: equal — без изменений, insert — добавлена, delete — удалена
+type ReviewDiffLine struct {
+	// Вид изменения
+	// Example: "insert"
+	Op string `json:"op"`
+
+	// Текст строки
+	// Example: "Перечитал — понравилось еще больше"
+	Text string `json:"text"`
+}
+
+// ReviewVersionResponse DTO для версии отзыва
+// @Description Версия отзыва и ее отличия от предыдущей
+type ReviewVersionResponse struct {
+	// Номер версии, начиная с 1; последняя версия — текущий текст отзыва
+	// Example: 2
+	Number int `json:"number"`
+
+	// Текст версии
+	// Example: "Отличная книга, советую!"
+	Text string `json:"text"`
+
+	// Оценка в этой версии (не указана у версий, сохраненных до учета оценки)
+	// Example: 9
+	Rating *int `json:"rating,omitempty"`
+
+	// Кто внес эту редакцию (UUID)
+	// Example: "550e8400-e29b-41d4-a716-446655440000"
+	EditedBy string `json:"edited_by"`
+
+	// Когда внесена эта редакция
+	// Example: "2024-02-02T14:30:00Z"
+	EditedAt string `json:"edited_at"`
+
+	// Является ли версия текущей
+	// Example: false
+	Current bool `json:"current"`
+
+	// Построчные отличия от предыдущей версии (у первой версии — весь текст как добавленный)
+	Diff []ReviewDiffLine `json:"diff"`
+}
+
+// ReviewVersionsResponse DTO для истории правок отзыва
+// @Description История версий отзыва от первой к текущей
+type ReviewVersionsResponse struct {
+	// ID отзыва
+	// Example: "60c72b2f5f1b2c001f6f1b20"
+	ReviewID string `json:"review_id"`
+
+	// Версии от старых к новым
+	Versions []ReviewVersionResponse `json:"versions"`
+}
@@ -0,0 +1,67 @@
+package dto
+
+import "time"
+
+// CreateReviewCommentRequest DTO для создания комментария к отзыву
+type CreateReviewCommentRequest struct {
+	// Текст комментария
+	// Required: true
+	// Example: "Согласен, концовка слабая"
+	Text string `json:"text" binding:"required"`
+
+	// ObjectID комментария, на который отвечаем (пусто — комментарий верхнего уровня)
+	// Example: "60c72b2f5f1b2c001f6f1b21"
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// UpdateReviewCommentRequest DTO для редактирования комментария
+type UpdateReviewCommentRequest struct {
+	// Новый текст комментария
+	// Required: true
+	// Example: "Согласен, хотя концовка спорная"
+	Text string `json:"text" binding:"required"`
+}
+
+// ReviewCommentResponse DTO комментария к отзыву
+// @Description Комментарий к отзыву с количеством ответов
+type ReviewCommentResponse struct {
+	// Example: "60c72b2f5f1b2c001f6f1b21"
+	ID string `json:"id"`
+
+	// Example: "60c72b2f5f1b2c001f6f1b20"
+	ReviewID string `json:"review_id"`
+
+	// ObjectID родительского комментария (нет у комментариев верхнего уровня)
+	ParentID *string `json:"parent_id,omitempty"`
+
+	// ID автора комментария (UUID)
+	// Example: "550e8400-e29b-41d4-a716-446655440000"
+	UserID string `json:"user_id"`
+
+	// Текст комментария (пустой у удаленных)
+	Text string `json:"text"`
+
+	// Уровень вложенности, 0 — верхний уровень
+	// Example: 1
+	Depth int `json:"depth"`
+
+	// Количество прямых ответов
+	// Example: 3
+	RepliesCount int `json:"replies_count"`
+
+	// Комментарий удален, но оставлен ради ответов на него
+	Deleted bool `json:"deleted"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PaginatedReviewCommentsResponse DTO для списка комментариев с пагинацией
+// @Description Ответ API со списком комментариев к отзыву
+type PaginatedReviewCommentsResponse struct {
+	Comments []ReviewCommentResponse `json:"comments"`
+
+	// Маркер для следующей страницы (если есть)
+	// Example: "60c72b2f5f1b2c001f6f1b29"
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,95 @@
+package dto
+
+import "time"
+
+// ReportReviewRequest DTO жалобы на отзыв
+type ReportReviewRequest struct {
+	// Причина жалобы: spam, spoilers или abuse
+	// Required: true
+	// Example: "spoilers"
+	Reason string `json:"reason" binding:"required"`
+
+	// Необязательное пояснение
+	// Example: "Раскрыта концовка в первом абзаце"
+	Comment string `json:"comment,omitempty"`
+}
+
+// ModerationDecisionRequest DTO решения модератора по отзыву
+type ModerationDecisionRequest struct {
+	// Комментарий модератора для журнала действий
+	// Example: "Спойлер убран автором"
+	Comment string `json:"comment,omitempty"`
+}
+
+// ModerationReviewResponse DTO отзыва в очереди модерации
+// @Description Отзыв с состоянием модерации и открытыми жалобами по причинам
+type ModerationReviewResponse struct {
+	// Example: "60c72b2f5f1b2c001f6f1b20"
+	ID string `json:"id"`
+
+	// Example: "123e4567-e89b-12d3-a456-426614174000"
+	BookID string `json:"book_id"`
+
+	// Example: "550e8400-e29b-41d4-a716-446655440000"
+	UserID string `json:"user_id"`
+
+	Text   string `json:"text"`
+	Rating int    `json:"rating"`
+
+	// Скрыт ли отзыв от читателей
+	Hidden bool `json:"hidden"`
+
+	// Статус модерации: pending, approved, hidden, deleted
+	// Example: "pending"
+	ModerationStatus string `json:"moderation_status"`
+
+	// Количество нерассмотренных жалоб
+	// Example: 3
+	ReportsCount int `json:"reports_count"`
+
+	// Нерассмотренные жалобы по причинам
+	ReportReasons map[string]int `json:"report_reasons"`
+
+	// Категории, по которым отзыв отправил на модерацию автоматический фильтр
+	// Example: ["insult"]
+	AutoFlags []string `json:"auto_flags,omitempty"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// PaginatedModerationReviewsResponse DTO очереди модерации с пагинацией
+// @Description Ответ API с очередью отзывов на модерацию
+type PaginatedModerationReviewsResponse struct {
+	Reviews []ModerationReviewResponse `json:"reviews"`
+
+	// Маркер для следующей страницы (если есть)
+	// Example: "60c72b2f5f1b2c001f6f1b29"
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// ReviewReportResponse DTO жалобы на отзыв
+type ReviewReportResponse struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Reason    string    `json:"reason"`
+	Comment   string    `json:"comment,omitempty"`
+	Resolved  bool      `json:"resolved"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ModeratorActionResponse DTO записи журнала действий модераторов
+type ModeratorActionResponse struct {
+	ModeratorID string    `json:"moderator_id"`
+	Action      string    `json:"action"`
+	Comment     string    `json:"comment,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ReviewModerationDetailsResponse DTO карточки отзыва для модератора
+// @Description Отзыв со всеми жалобами и историей решений модераторов
+type ReviewModerationDetailsResponse struct {
+	Review  ModerationReviewResponse  `json:"review"`
+	Reports []ReviewReportResponse    `json:"reports"`
+	Actions []ModeratorActionResponse `json:"actions"`
+}
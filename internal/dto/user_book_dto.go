@@ -11,14 +11,103 @@ type AddBookRequest struct {
 }
 
 type UpdateReadingProgressRequest struct {
-	Status    string `json:"status"`
-	PagesRead int    `json:"pages_read"`
+	// Статус чтения (reading, completed, dropped)
+	// Example: "reading"
+	Status string `json:"status"`
+
+	// Единица прогресса: pages (по умолчанию), percent или location
+	// Example: "pages"
+	Unit string `json:"unit,omitempty"`
+
+	// Прочитано страниц, для unit=pages
+	// Example: 120
+	PagesRead int `json:"pages_read"`
+
+	// Прочитано процентов (0-100), для unit=percent
+	// Example: 42.5
+	Percent *float64 `json:"percent,omitempty"`
+
+	// Позиция в электронной книге, для unit=location
+	// Example: 1530
+	Location *int `json:"location,omitempty"`
+
+	// Всего позиций в электронной книге; можно не передавать, если уже сохранено
+	// Example: 4210
+	TotalLocations *int `json:"total_locations,omitempty"`
+
+	// Оценка текущего прочтения (от 1 до 10)
+	// Example: 9
+	Rating *int `json:"rating,omitempty"`
 }
 
 type UserBookResponse struct {
 	BookID    *uuid.UUID `json:"book_id"`
 	Status    string     `json:"status"`
 	PagesRead int        `json:"pages_read"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Единица, в которой отмечается прогресс
+	// Example: "pages"
+	ProgressUnit string `json:"progress_unit"`
+
+	// Процент прочитанного; отсутствует, если у книги неизвестно число страниц
+	// Example: 37.5
+	ProgressPercent *float64 `json:"progress_percent,omitempty"`
+
+	Location       *int `json:"location,omitempty"`
+	TotalLocations *int `json:"total_locations,omitempty"`
+
+	// Количество завершенных прочтений
+	// Example: 2
+	ReadCount int       `json:"read_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReadThroughResponse DTO одного прочтения книги
+// @Description Прочтение книги с датами, статусом и оценкой
+type ReadThroughResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	BookID     uuid.UUID  `json:"book_id"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Rating     *int       `json:"rating,omitempty"`
+}
+
+// StartRereadRequest DTO для начала повторного прочтения
+type StartRereadRequest struct {
+	// Дата начала прочтения (по умолчанию — сейчас)
+	// Example: "2024-02-01T12:00:00Z"
+	StartedAt *time.Time `json:"started_at,omitempty"`
+}
+
+// UpdateReadThroughRequest DTO для правки прочтения из истории
+type UpdateReadThroughRequest struct {
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	// Оценка прочтения (от 1 до 10)
+	// Example: 8
+	Rating *int `json:"rating,omitempty"`
+}
+
+// UserBookStatsResponse DTO статистики чтения пользователя
+// @Description Статистика по списку книг и повторным прочтениям
+type UserBookStatsResponse struct {
+	TotalBooks int `json:"total_books"`
+	Reading    int `json:"reading"`
+	Completed  int `json:"completed"`
+	Dropped    int `json:"dropped"`
+
+	// Всего завершенных прочтений, включая повторные
+	// Example: 14
+	TotalReads int `json:"total_reads"`
+
+	// Количество повторных прочтений
+	// Example: 3
+	Rereads int `json:"rereads"`
+
+	// Количество книг, прочитанных больше одного раза
+	// Example: 2
+	RereadBooks int `json:"reread_books"`
 }
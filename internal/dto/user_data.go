@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// UserProfileExport DTO профиля пользователя в архиве персональных данных (без хеша пароля)
+type UserProfileExport struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RefreshTokenExport DTO сессии пользователя в архиве персональных данных (без самого токена)
+type RefreshTokenExport struct {
+	ID        uuid.UUID `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -48,7 +48,8 @@ type AuthResponse struct {
 }
 
 type UserResponse struct {
-	ID    string `json:"access_token"`
-	Email string `json:"refresh_token"`
-	Role  string `json:"role"`
+	ID         string              `json:"access_token"`
+	Email      string              `json:"refresh_token"`
+	Role       string              `json:"role"`
+	Reputation *ReputationResponse `json:"reputation,omitempty"`
 }
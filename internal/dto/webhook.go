@@ -0,0 +1,129 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// CreateWebhookRequest DTO регистрации вебхука
+// @Description Адрес, на который отправляются события, и список событий
+type CreateWebhookRequest struct {
+	// Example: "https://search.example.com/hooks/books"
+	URL string `json:"url" binding:"required"`
+
+	// Ключ HMAC-подписи; если не указан, будет сгенерирован и возвращен один раз в ответе
+	// Example: "s3cr3t"
+	Secret string `json:"secret"`
+
+	// События: book.created, book.confirmed, book.updated, book.deleted, review.created
+	// Example: ["book.created", "book.deleted"]
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+}
+
+// UpdateWebhookRequest DTO изменения вебхука
+type UpdateWebhookRequest struct {
+	// Example: "https://search.example.com/hooks/books"
+	URL string `json:"url" binding:"required"`
+
+	// Example: ["book.created", "book.confirmed"]
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+
+	// Отключенный вебхук не получает новых событий; если не указано — не меняется
+	// Example: true
+	Active *bool `json:"active"`
+}
+
+// WebhookResponse DTO вебхука
+type WebhookResponse struct {
+	// Example: "123e4567-e89b-12d3-a456-426614174010"
+	ID uuid.UUID `json:"id"`
+
+	// Example: "https://search.example.com/hooks/books"
+	URL string `json:"url"`
+
+	// Example: ["book.created", "book.deleted"]
+	EventTypes []string `json:"event_types"`
+
+	// Example: true
+	Active bool `json:"active"`
+
+	// Ключ подписи; возвращается только при регистрации
+	// Example: "9f86d081884c7d659a2feaa0c55ad015"
+	Secret string `json:"secret,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse DTO доставки вебхука
+type WebhookDeliveryResponse struct {
+	// Example: "123e4567-e89b-12d3-a456-426614174011"
+	ID uuid.UUID `json:"id"`
+
+	// Example: "book.created"
+	EventType string `json:"event_type"`
+
+	// Отправленное тело запроса
+	Payload string `json:"payload"`
+
+	// pending, succeeded или failed
+	// Example: "succeeded"
+	Status string `json:"status"`
+
+	// Example: 1
+	Attempts int `json:"attempts"`
+
+	// Когда будет следующая попытка
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+
+	// HTTP-статус последнего ответа получателя
+	// Example: 200
+	LastStatusCode int `json:"last_status_code,omitempty"`
+
+	// Ошибка последней попытки
+	// Example: "context deadline exceeded"
+	LastError string `json:"last_error,omitempty"`
+
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// PaginatedWebhookDeliveriesResponse DTO журнала доставок с маркерной пагинацией
+type PaginatedWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+
+	// Следующий маркер для пагинации (если есть)
+	// Example: "123e4567-e89b-12d3-a456-426614174011"
+	NextCursor *uuid.UUID `json:"next_cursor,omitempty"`
+}
+
+// WebhookEventPayload тело запроса, которое получает вебхук.
+// Подпись: заголовок X-Webhook-Signature = "sha256=" + hex(HMAC-SHA256(secret, X-Webhook-Timestamp + "." + тело))
+type WebhookEventPayload struct {
+	// ID события: повторные попытки доставки одного события приходят с тем же ID
+	// Example: "123e4567-e89b-12d3-a456-426614174012"
+	ID uuid.UUID `json:"id"`
+
+	// Example: "book.confirmed"
+	Event string `json:"event"`
+
+	OccurredAt time.Time `json:"occurred_at"`
+
+	Data WebhookEventData `json:"data"`
+}
+
+// WebhookEventData данные события; получатель при необходимости запрашивает подробности через API
+type WebhookEventData struct {
+	// Example: "123e4567-e89b-12d3-a456-426614174001"
+	BookID *uuid.UUID `json:"book_id,omitempty"`
+
+	// Example: "Мастер и Маргарита"
+	BookTitle string `json:"book_title,omitempty"`
+
+	// Example: "65f1c2a4e13b5a0d9c8b4567"
+	ReviewID string `json:"review_id,omitempty"`
+
+	// Пользователь, который вызвал событие
+	// Example: "123e4567-e89b-12d3-a456-426614174000"
+	ActorID *uuid.UUID `json:"actor_id,omitempty"`
+}
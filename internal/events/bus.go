@@ -0,0 +1,99 @@
+// Package events внутренняя шина доменных событий: сервисы сообщают о том, что произошло,
+// а уведомления, вебхуки и другие подписчики решают, что с этим делать.
+package events
+
+import (
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+	"sync"
+	"time"
+)
+
+// Type вид события
+type Type string
+
+const (
+	BookCreated     Type = "book_created"     // книга добавлена в каталог
+	BookConfirmed   Type = "book_confirmed"   // книга подтверждена и опубликована
+	BookUpdated     Type = "book_updated"     // данные книги изменены
+	BookDeleted     Type = "book_deleted"     // книга удалена из каталога
+	ReviewCreated   Type = "review_created"   // опубликован отзыв
+	ReviewReplied   Type = "review_replied"   // отзыв пользователя прокомментировали
+	CommentReplied  Type = "comment_replied"  // на комментарий пользователя ответили
+	ReviewLiked     Type = "review_liked"     // отзыву пользователя поставили лайк
+	ReviewModerated Type = "review_moderated" // модератор скрыл или удалил отзыв пользователя
+)
+
+// Event доменное событие
+type Event struct {
+	Type Type `json:"type"`
+	// UserID кого касается событие, например автор книги или отзыва; uuid.Nil — событие никому не адресовано
+	UserID uuid.UUID `json:"user_id"`
+	// ActorID кто вызвал событие; uuid.Nil — система или неизвестно
+	ActorID    uuid.UUID  `json:"actor_id"`
+	BookID     *uuid.UUID `json:"book_id,omitempty"`
+	BookTitle  string     `json:"book_title,omitempty"`
+	ReviewID   string     `json:"review_id,omitempty"`
+	CommentID  string     `json:"comment_id,omitempty"`
+	OccurredAt time.Time  `json:"occurred_at"`
+}
+
+// Handler обработчик событий
+type Handler func(Event)
+
+// Bus синхронная шина: Publish вызывает подписчиков по очереди в горутине публикующего,
+// поэтому обработчики должны быть быстрыми, а долгую работу уносить в фон
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+	log      *logger.Logger
+}
+
+// NewBus создает пустую шину
+func NewBus() *Bus {
+	return &Bus{log: logger.GetLogger()}
+}
+
+// Subscribe добавляет обработчик всех событий
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish передает событие всем подписчикам. Паника подписчика не мешает остальным и публикующему
+func (b *Bus) Publish(event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		b.dispatch(handler, event)
+	}
+}
+
+func (b *Bus) dispatch(handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.Errorf("Паника в обработчике события %s: %v", event.Type, r)
+		}
+	}()
+	handler(event)
+}
+
+var (
+	bus     *Bus
+	busOnce sync.Once
+)
+
+// GetBus возвращает общую шину приложения
+func GetBus() *Bus {
+	busOnce.Do(func() {
+		bus = NewBus()
+	})
+	return bus
+}
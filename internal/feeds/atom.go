@@ -0,0 +1,166 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	AtomType        = "application/atom+xml"
+	AtomContentType = AtomType + "; charset=utf-8"
+
+	atomNamespace       = "http://www.w3.org/2005/Atom"
+	dcTermsNamespace    = "http://purl.org/dc/terms/"
+	openSearchNamespace = "http://a9.com/-/spec/opensearch/1.1/"
+)
+
+type atomFeed struct {
+	XMLName      xml.Name    `xml:"feed"`
+	Namespace    string      `xml:"xmlns,attr"`
+	DCNamespace  string      `xml:"xmlns:dc,attr,omitempty"`
+	OSNamespace  string      `xml:"xmlns:opensearch,attr,omitempty"`
+	ID           string      `xml:"id"`
+	Title        string      `xml:"title"`
+	Updated      string      `xml:"updated"`
+	Author       *atomPerson `xml:"author,omitempty"`
+	Links        []atomLink  `xml:"link"`
+	TotalResults *int        `xml:"opensearch:totalResults,omitempty"`
+	ItemsPerPage *int        `xml:"opensearch:itemsPerPage,omitempty"`
+	StartIndex   *int        `xml:"opensearch:startIndex,omitempty"`
+	Entries      []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomLink struct {
+	Rel   string `xml:"rel,attr,omitempty"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Authors    []atomPerson   `xml:"author"`
+	Identifier string         `xml:"dc:identifier,omitempty"`
+	Publisher  string         `xml:"dc:publisher,omitempty"`
+	Issued     string         `xml:"dc:issued,omitempty"`
+	Extent     string         `xml:"dc:extent,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+	Links      []atomLink     `xml:"link"`
+}
+
+// WriteAtom пишет ленту в формате Atom (RFC 4287). Каталоги OPDS 1.2 — тоже Atom,
+// их отличают только типы ссылок, которые выставляет вызывающий
+func WriteAtom(w io.Writer, feed *Feed) error {
+	doc := atomFeed{
+		Namespace: atomNamespace,
+		ID:        feed.ID,
+		Title:     feed.Title,
+		Updated:   atomTime(feed.Updated),
+		Links:     atomLinks(feed.Links),
+		Entries:   make([]atomEntry, len(feed.Entries)),
+	}
+	if feed.Author != nil {
+		doc.Author = &atomPerson{Name: feed.Author.Name, URI: feed.Author.URI}
+	}
+	if paging := feed.Paging; paging != nil {
+		doc.OSNamespace = openSearchNamespace
+		doc.ItemsPerPage = &paging.ItemsPerPage
+		doc.StartIndex = &paging.StartIndex
+		if paging.TotalResults >= 0 {
+			doc.TotalResults = &paging.TotalResults
+		}
+	}
+
+	for i, entry := range feed.Entries {
+		doc.Entries[i] = atomEntryFrom(entry)
+		if entry.Publication != nil {
+			doc.DCNamespace = dcTermsNamespace
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func atomEntryFrom(entry Entry) atomEntry {
+	result := atomEntry{
+		ID:      entry.ID,
+		Title:   entry.Title,
+		Updated: atomTime(entry.Updated),
+		Links:   atomLinks(entry.Links),
+	}
+	if !entry.Published.IsZero() {
+		result.Published = atomTime(entry.Published)
+	}
+	for _, author := range entry.Authors {
+		result.Authors = append(result.Authors, atomPerson{Name: author.Name, URI: author.URI})
+	}
+	for _, category := range entry.Categories {
+		result.Categories = append(result.Categories, atomCategory{Term: category.Term, Label: category.Label})
+	}
+	if entry.Summary != "" {
+		result.Summary = &atomText{Type: "text", Body: entry.Summary}
+	}
+	if entry.Content != "" {
+		result.Content = &atomText{Type: "text", Body: entry.Content}
+	}
+
+	if publication := entry.Publication; publication != nil {
+		if publication.ISBN != "" {
+			result.Identifier = "urn:isbn:" + publication.ISBN
+		}
+		result.Publisher = publication.Publisher
+		if publication.Year > 0 {
+			result.Issued = strconv.Itoa(publication.Year)
+		}
+		if publication.PageCount > 0 {
+			result.Extent = strconv.Itoa(publication.PageCount) + " pages"
+		}
+	}
+	return result
+}
+
+func atomLinks(links []Link) []atomLink {
+	result := make([]atomLink, 0, len(links))
+	for _, link := range links {
+		if link.Templated {
+			continue // шаблонные ссылки есть только в OPDS 2.0, в Atom поиск описывает OpenSearch
+		}
+		result = append(result, atomLink{Rel: link.Rel, Href: link.Href, Type: link.Type, Title: link.Title})
+	}
+	return result
+}
+
+// atomTime форматирует время по RFC 3339, как требует Atom
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
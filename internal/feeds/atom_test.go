@@ -0,0 +1,78 @@
+package feeds
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteAtom(t *testing.T) {
+	tests := []struct {
+		name string
+		feed *Feed
+		want string
+	}{
+		{
+			name: "minimal feed",
+			feed: &Feed{ID: "urn:uuid:feed", Title: "Отзывы", Updated: testTime},
+			want: `<feed xmlns="http://www.w3.org/2005/Atom"><id>urn:uuid:feed</id><title>Отзывы</title>` +
+				`<updated>2024-03-05T09:00:00Z</updated></feed>`,
+		},
+		{
+			name: "escaping, paging without total and skipped templated link",
+			feed: &Feed{
+				ID:      "urn:uuid:feed",
+				Title:   "Новинки & <книги>",
+				Updated: testTime,
+				Author:  &Person{Name: "Каталог", URI: "https://books.example"},
+				Links: []Link{
+					{Rel: "self", Href: "https://books.example/feed?a=1&b=2", Type: AtomType},
+					{Rel: "search", Href: "https://books.example/search{?query}", Templated: true},
+				},
+				Paging:  &Paging{TotalResults: -1, ItemsPerPage: 20, StartIndex: 21},
+				Entries: []Entry{testNavigationEntry()},
+			},
+			want: `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">` +
+				`<id>urn:uuid:feed</id><title>Новинки &amp; &lt;книги&gt;</title><updated>2024-03-05T09:00:00Z</updated>` +
+				`<author><name>Каталог</name><uri>https://books.example</uri></author>` +
+				`<link rel="self" href="https://books.example/feed?a=1&amp;b=2" type="application/atom+xml"></link>` +
+				`<opensearch:itemsPerPage>20</opensearch:itemsPerPage><opensearch:startIndex>21</opensearch:startIndex>` +
+				`<entry><id>urn:uuid:nav</id><title>Жанры</title><updated>2024-03-05T09:00:00Z</updated>` +
+				`<link rel="subsection" href="https://books.example/opds/genres" type="application/atom+xml;profile=opds-catalog;kind=navigation"></link></entry>` +
+				`</feed>`,
+		},
+		{
+			name: "book entry with publication and total results",
+			feed: &Feed{
+				ID:      "urn:uuid:feed",
+				Title:   "Поиск",
+				Updated: testTime,
+				Paging:  &Paging{TotalResults: 0, ItemsPerPage: 20, StartIndex: 1},
+				Entries: []Entry{testBookEntry()},
+			},
+			want: `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:dc="http://purl.org/dc/terms/" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">` +
+				`<id>urn:uuid:feed</id><title>Поиск</title><updated>2024-03-05T09:00:00Z</updated>` +
+				`<opensearch:totalResults>0</opensearch:totalResults><opensearch:itemsPerPage>20</opensearch:itemsPerPage><opensearch:startIndex>1</opensearch:startIndex>` +
+				`<entry><id>urn:uuid:1</id><title>Война и мир</title><updated>2024-03-05T09:00:00Z</updated><published>2024-03-05T08:00:00Z</published>` +
+				`<author><name>Лев Толстой</name></author>` +
+				`<dc:identifier>urn:isbn:9785170906307</dc:identifier><dc:publisher>АСТ</dc:publisher><dc:issued>2015</dc:issued><dc:extent>1225 pages</dc:extent>` +
+				`<category term="g1" label="Роман"></category><summary type="text">Кратко</summary><content type="text">Полно</content>` +
+				`<link rel="http://opds-spec.org/image" href="https://books.example/c.jpg" type="image/jpeg"></link>` +
+				`<link rel="http://opds-spec.org/image/thumbnail" href="https://books.example/c.jpg" type="image/jpeg"></link>` +
+				`<link rel="alternate" href="https://books.example/api/v1/books/1" type="application/json" title="API"></link></entry>` +
+				`</feed>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteAtom(&buf, tt.feed); err != nil {
+				t.Fatal(err)
+			}
+			want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + tt.want + "\n"
+			if got := buf.String(); got != want {
+				t.Errorf("\n got %s\nwant %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Package feeds строит ленты каталога: Atom, каталоги OPDS 1.2 (Atom) и OPDS 2.0 (JSON) и описание поиска OpenSearch.
+// Сервисы собирают ленту в нейтральной модели Feed, а пакет только сериализует ее в нужный формат
+package feeds
+
+import "time"
+
+// Link ссылка ленты или записи
+type Link struct {
+	Rel       string
+	Href      string
+	Type      string
+	Title     string
+	Templated bool // href — шаблон URI (RFC 6570), только для OPDS 2.0
+}
+
+// Person автор ленты или записи
+type Person struct {
+	Name string
+	URI  string
+}
+
+// Category рубрика записи, например жанр книги
+type Category struct {
+	Term  string
+	Label string
+}
+
+// Publication выходные данные книги; есть только у записей-книг
+type Publication struct {
+	ISBN      string // ISBN-13 без дефисов
+	Publisher string
+	Year      int // 0 — год неизвестен
+	PageCount int // 0 — число страниц неизвестно
+}
+
+// Entry запись ленты: книга, отзыв или пункт навигации каталога
+type Entry struct {
+	ID         string
+	Title      string
+	Updated    time.Time
+	Published  time.Time // нулевое значение не выводится
+	Authors    []Person
+	Summary    string // краткое описание, простой текст
+	Content    string // полный текст, простой текст
+	Categories []Category
+	Links      []Link
+
+	// Publication заполнен у записей-книг; в OPDS 2.0 такие записи становятся publications, остальные — navigation
+	Publication *Publication
+}
+
+// Feed лента
+type Feed struct {
+	ID      string
+	Title   string
+	Updated time.Time
+	Author  *Person // автор всей ленты; Atom требует автора у ленты или у каждой записи
+	Links   []Link
+	Entries []Entry
+
+	// Paging заполняется у результатов поиска
+	Paging *Paging
+}
+
+// Paging сведения о странице результатов поиска по OpenSearch 1.1
+type Paging struct {
+	TotalResults int // -1 — общее число неизвестно
+	ItemsPerPage int
+	StartIndex   int // номер первой записи страницы, с единицы
+}
+
+// LatestUpdate возвращает самое позднее время обновления записей или fallback, если записей нет
+func LatestUpdate(entries []Entry, fallback time.Time) time.Time {
+	var latest time.Time
+	for _, entry := range entries {
+		if entry.Updated.After(latest) {
+			latest = entry.Updated
+		}
+	}
+	if latest.IsZero() {
+		return fallback
+	}
+	return latest
+}
@@ -0,0 +1,59 @@
+package feeds
+
+import (
+	"testing"
+	"time"
+)
+
+// testTime время в часовом поясе не UTC: ленты обязаны выводить его в UTC
+var testTime = time.Date(2024, 3, 5, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+// testBookEntry запись-книга со всеми полями
+func testBookEntry() Entry {
+	return Entry{
+		ID:         "urn:uuid:1",
+		Title:      "Война и мир",
+		Updated:    testTime,
+		Published:  testTime.Add(-time.Hour),
+		Authors:    []Person{{Name: "Лев Толстой"}},
+		Summary:    "Кратко",
+		Content:    "Полно",
+		Categories: []Category{{Term: "g1", Label: "Роман"}},
+		Links: []Link{
+			{Rel: RelImage, Href: "https://books.example/c.jpg", Type: "image/jpeg"},
+			{Rel: RelThumbnail, Href: "https://books.example/c.jpg", Type: "image/jpeg"},
+			{Rel: "alternate", Href: "https://books.example/api/v1/books/1", Type: "application/json", Title: "API"},
+		},
+		Publication: &Publication{ISBN: "9785170906307", Publisher: "АСТ", Year: 2015, PageCount: 1225},
+	}
+}
+
+// testNavigationEntry пункт навигации каталога
+func testNavigationEntry() Entry {
+	return Entry{
+		ID:      "urn:uuid:nav",
+		Title:   "Жанры",
+		Updated: testTime,
+		Links:   []Link{{Rel: "subsection", Href: "https://books.example/opds/genres", Type: opds1NavigationType}},
+	}
+}
+
+func TestLatestUpdate(t *testing.T) {
+	fallback := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		entries []Entry
+		want    time.Time
+	}{
+		{"no entries", nil, fallback},
+		{"zero times", []Entry{{}, {}}, fallback},
+		{"latest wins", []Entry{{Updated: testTime}, {Updated: testTime.Add(time.Hour)}, {Updated: testTime.Add(-time.Hour)}}, testTime.Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		if got := LatestUpdate(tt.entries, fallback); !got.Equal(tt.want) {
+			t.Errorf("%s: LatestUpdate = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package feeds
+
+import (
+	"book-management-system/pkg/utils"
+	"encoding/json"
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+)
+
+// Отношения ссылок OPDS и типы документов
+const (
+	RelImage       = "http://opds-spec.org/image"
+	RelThumbnail   = "http://opds-spec.org/image/thumbnail"
+	RelSortNew     = "http://opds-spec.org/sort/new"
+	RelSortPopular = "http://opds-spec.org/sort/popular"
+
+	OpenSearchContentType = "application/opensearchdescription+xml; charset=utf-8"
+
+	opds1NavigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	opds1AcquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	opds2Type            = "application/opds+json"
+	openSearchType       = "application/opensearchdescription+xml"
+)
+
+// OPDSVersion версия каталога OPDS
+type OPDSVersion int
+
+const (
+	OPDS1 OPDSVersion = 1 // OPDS 1.2, Atom
+	OPDS2 OPDSVersion = 2 // OPDS 2.0, JSON
+)
+
+// Kind вид ленты каталога: навигация по разделам или список книг
+type Kind int
+
+const (
+	KindNavigation Kind = iota
+	KindAcquisition
+)
+
+// Catalog адреса одного представления каталога OPDS. Ленты обеих версий строятся одним кодом,
+// а Catalog подставляет адреса и типы ссылок нужной версии
+type Catalog struct {
+	Version OPDSVersion
+	Site    string // абсолютный адрес сайта без завершающего слеша, от него считаются обложки и ссылки API
+	Root    string // путь корня каталога на сайте: /opds или /opds/v2
+}
+
+// URL возвращает абсолютный адрес раздела каталога; пустой section — корень
+func (c Catalog) URL(section string, query url.Values) string {
+	href := c.Site + c.Root + "/"
+	if section != "" {
+		href = c.Site + path.Join(c.Root, section)
+	}
+	if len(query) > 0 {
+		href += "?" + query.Encode()
+	}
+	return href
+}
+
+// SiteURL делает абсолютным адрес на сайте, например путь обложки /uploads/<id>.jpg; абсолютные адреса не меняются
+func (c Catalog) SiteURL(href string) string {
+	return utils.AbsoluteURL(c.Site, href)
+}
+
+// Type возвращает тип документа ленты данного вида
+func (c Catalog) Type(kind Kind) string {
+	switch {
+	case c.Version == OPDS2:
+		return opds2Type
+	case kind == KindNavigation:
+		return opds1NavigationType
+	default:
+		return opds1AcquisitionType
+	}
+}
+
+// Link ссылка на раздел каталога
+func (c Catalog) Link(rel, section string, query url.Values, kind Kind, title string) Link {
+	return Link{Rel: rel, Href: c.URL(section, query), Type: c.Type(kind), Title: title}
+}
+
+// SearchLink ссылка на поиск: в OPDS 1.2 — на описание OpenSearch, в OPDS 2.0 — шаблон адреса поиска
+func (c Catalog) SearchLink() Link {
+	if c.Version == OPDS2 {
+		return Link{Rel: "search", Href: c.URL("search", nil) + "{?query}", Type: opds2Type, Templated: true}
+	}
+	return Link{Rel: "search", Href: c.URL("opensearch.xml", nil), Type: openSearchType}
+}
+
+// ContentType возвращает заголовок Content-Type лент каталога
+func (c Catalog) ContentType(kind Kind) string {
+	if c.Version == OPDS2 {
+		return opds2Type + "; charset=utf-8"
+	}
+	return c.Type(kind) + ";charset=utf-8"
+}
+
+// Write пишет ленту каталога в формате своей версии
+func (c Catalog) Write(w io.Writer, feed *Feed) error {
+	if c.Version == OPDS2 {
+		return WriteOPDS2(w, feed)
+	}
+	return WriteAtom(w, feed)
+}
+
+type opds2Feed struct {
+	Metadata     opds2FeedMetadata  `json:"metadata"`
+	Links        []opds2Link        `json:"links"`
+	Navigation   []opds2Link        `json:"navigation,omitempty"`
+	Publications []opds2Publication `json:"publications,omitempty"`
+}
+
+type opds2FeedMetadata struct {
+	Title         string `json:"title"`
+	Modified      string `json:"modified"`
+	NumberOfItems *int   `json:"numberOfItems,omitempty"`
+	ItemsPerPage  *int   `json:"itemsPerPage,omitempty"`
+	CurrentPage   *int   `json:"currentPage,omitempty"`
+}
+
+type opds2Link struct {
+	Rel       string `json:"rel,omitempty"`
+	Href      string `json:"href"`
+	Type      string `json:"type,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Templated bool   `json:"templated,omitempty"`
+}
+
+type opds2Publication struct {
+	Metadata opds2PublicationMetadata `json:"metadata"`
+	Links    []opds2Link              `json:"links"`
+	Images   []opds2Link              `json:"images,omitempty"`
+}
+
+type opds2PublicationMetadata struct {
+	Type          string         `json:"@type"`
+	Identifier    string         `json:"identifier"`
+	Title         string         `json:"title"`
+	Author        []opds2Contrib `json:"author,omitempty"`
+	Publisher     string         `json:"publisher,omitempty"`
+	Published     string         `json:"published,omitempty"`
+	Modified      string         `json:"modified"`
+	Description   string         `json:"description,omitempty"`
+	NumberOfPages int            `json:"numberOfPages,omitempty"`
+	Subject       []opds2Contrib `json:"subject,omitempty"`
+}
+
+type opds2Contrib struct {
+	Name string `json:"name"`
+}
+
+// WriteOPDS2 пишет ленту в формате OPDS 2.0. Записи с Publication становятся публикациями,
+// остальные — пунктами навигации по своей первой ссылке
+func WriteOPDS2(w io.Writer, feed *Feed) error {
+	doc := opds2Feed{
+		Metadata: opds2FeedMetadata{Title: feed.Title, Modified: atomTime(feed.Updated)},
+		Links:    opds2Links(feed.Links),
+	}
+	if paging := feed.Paging; paging != nil && paging.ItemsPerPage > 0 {
+		page := (paging.StartIndex-1)/paging.ItemsPerPage + 1
+		doc.Metadata.ItemsPerPage = &paging.ItemsPerPage
+		doc.Metadata.CurrentPage = &page
+		if paging.TotalResults >= 0 {
+			doc.Metadata.NumberOfItems = &paging.TotalResults
+		}
+	}
+
+	for _, entry := range feed.Entries {
+		if entry.Publication == nil {
+			if len(entry.Links) > 0 {
+				link := entry.Links[0]
+				doc.Navigation = append(doc.Navigation, opds2Link{Href: link.Href, Type: link.Type, Title: entry.Title})
+			}
+			continue
+		}
+		doc.Publications = append(doc.Publications, opds2PublicationFrom(entry))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(doc)
+}
+
+func opds2PublicationFrom(entry Entry) opds2Publication {
+	publication := entry.Publication
+	metadata := opds2PublicationMetadata{
+		Type:          "http://schema.org/Book",
+		Identifier:    entry.ID,
+		Title:         entry.Title,
+		Publisher:     publication.Publisher,
+		Modified:      atomTime(entry.Updated),
+		Description:   entry.Summary,
+		NumberOfPages: publication.PageCount,
+	}
+	if publication.ISBN != "" {
+		metadata.Identifier = "urn:isbn:" + publication.ISBN
+	}
+	if publication.Year > 0 {
+		metadata.Published = strconv.Itoa(publication.Year)
+	}
+	for _, author := range entry.Authors {
+		metadata.Author = append(metadata.Author, opds2Contrib{Name: author.Name})
+	}
+	for _, category := range entry.Categories {
+		metadata.Subject = append(metadata.Subject, opds2Contrib{Name: category.Label})
+	}
+
+	result := opds2Publication{Metadata: metadata, Links: []opds2Link{}}
+	for _, link := range entry.Links {
+		switch link.Rel {
+		case RelImage, RelThumbnail:
+			// В OPDS 2.0 обложки лежат в images; миниатюра совпадает с обложкой, второй раз ее не пишем
+			if link.Rel == RelImage {
+				result.Images = append(result.Images, opds2Link{Href: link.Href, Type: link.Type})
+			}
+		default:
+			result.Links = append(result.Links, opds2Link{Rel: link.Rel, Href: link.Href, Type: link.Type, Title: link.Title})
+		}
+	}
+	return result
+}
+
+func opds2Links(links []Link) []opds2Link {
+	result := make([]opds2Link, len(links))
+	for i, link := range links {
+		result[i] = opds2Link{Rel: link.Rel, Href: link.Href, Type: link.Type, Title: link.Title, Templated: link.Templated}
+	}
+	return result
+}
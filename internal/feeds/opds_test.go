@@ -0,0 +1,146 @@
+package feeds
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	opds1 := Catalog{Version: OPDS1, Site: "https://books.example", Root: "/opds"}
+	opds2 := Catalog{Version: OPDS2, Site: "https://books.example", Root: "/opds/v2"}
+
+	urls := []struct {
+		catalog Catalog
+		section string
+		query   url.Values
+		want    string
+	}{
+		{opds1, "", nil, "https://books.example/opds/"},
+		{opds1, "new", nil, "https://books.example/opds/new"},
+		{opds1, "search", url.Values{"q": {"война и мир"}, "page": {"2"}}, "https://books.example/opds/search?page=2&q=%D0%B2%D0%BE%D0%B9%D0%BD%D0%B0+%D0%B8+%D0%BC%D0%B8%D1%80"},
+		{opds2, "", nil, "https://books.example/opds/v2/"},
+		{opds2, "genres/g1", nil, "https://books.example/opds/v2/genres/g1"},
+	}
+	for _, tt := range urls {
+		if got := tt.catalog.URL(tt.section, tt.query); got != tt.want {
+			t.Errorf("URL(%q, %v) = %q, want %q", tt.section, tt.query, got, tt.want)
+		}
+	}
+
+	types := []struct {
+		catalog          Catalog
+		kind             Kind
+		typ, contentType string
+	}{
+		{opds1, KindNavigation, opds1NavigationType, opds1NavigationType + ";charset=utf-8"},
+		{opds1, KindAcquisition, opds1AcquisitionType, opds1AcquisitionType + ";charset=utf-8"},
+		{opds2, KindNavigation, opds2Type, opds2Type + "; charset=utf-8"},
+		{opds2, KindAcquisition, opds2Type, opds2Type + "; charset=utf-8"},
+	}
+	for _, tt := range types {
+		if got := tt.catalog.Type(tt.kind); got != tt.typ {
+			t.Errorf("OPDS%d Type(%d) = %q, want %q", tt.catalog.Version, tt.kind, got, tt.typ)
+		}
+		if got := tt.catalog.ContentType(tt.kind); got != tt.contentType {
+			t.Errorf("OPDS%d ContentType(%d) = %q, want %q", tt.catalog.Version, tt.kind, got, tt.contentType)
+		}
+	}
+
+	searchLinks := []struct {
+		catalog Catalog
+		want    Link
+	}{
+		{opds1, Link{Rel: "search", Href: "https://books.example/opds/opensearch.xml", Type: openSearchType}},
+		{opds2, Link{Rel: "search", Href: "https://books.example/opds/v2/search{?query}", Type: opds2Type, Templated: true}},
+	}
+	for _, tt := range searchLinks {
+		if got := tt.catalog.SearchLink(); got != tt.want {
+			t.Errorf("OPDS%d SearchLink = %+v, want %+v", tt.catalog.Version, got, tt.want)
+		}
+	}
+
+	if got := opds1.SiteURL("/uploads/1.jpg"); got != "https://books.example/uploads/1.jpg" {
+		t.Errorf("SiteURL = %q", got)
+	}
+	if got := opds1.SiteURL("https://cdn.example/1.jpg"); got != "https://cdn.example/1.jpg" {
+		t.Errorf("SiteURL absolute = %q", got)
+	}
+}
+
+func TestWriteOPDS2(t *testing.T) {
+	tests := []struct {
+		name string
+		feed *Feed
+		want string
+	}{
+		{
+			name: "empty feed",
+			feed: &Feed{Title: "Каталог", Updated: testTime},
+			want: `{"metadata":{"title":"Каталог","modified":"2024-03-05T09:00:00Z"},"links":[]}`,
+		},
+		{
+			name: "navigation, publication and paging",
+			feed: &Feed{
+				Title:   "Новинки & <книги>",
+				Updated: testTime,
+				Links: []Link{
+					{Rel: "self", Href: "https://books.example/opds/v2/new?a=1&b=2", Type: opds2Type},
+					{Rel: "search", Href: "https://books.example/opds/v2/search{?query}", Type: opds2Type, Templated: true},
+				},
+				Paging:  &Paging{TotalResults: 45, ItemsPerPage: 20, StartIndex: 21},
+				Entries: []Entry{testNavigationEntry(), {Title: "Без ссылки"}, testBookEntry()},
+			},
+			want: `{"metadata":{"title":"Новинки & <книги>","modified":"2024-03-05T09:00:00Z","numberOfItems":45,"itemsPerPage":20,"currentPage":2},` +
+				`"links":[{"rel":"self","href":"https://books.example/opds/v2/new?a=1&b=2","type":"application/opds+json"},` +
+				`{"rel":"search","href":"https://books.example/opds/v2/search{?query}","type":"application/opds+json","templated":true}],` +
+				`"navigation":[{"href":"https://books.example/opds/genres","type":"application/atom+xml;profile=opds-catalog;kind=navigation","title":"Жанры"}],` +
+				`"publications":[{"metadata":{"@type":"http://schema.org/Book","identifier":"urn:isbn:9785170906307","title":"Война и мир",` +
+				`"author":[{"name":"Лев Толстой"}],"publisher":"АСТ","published":"2015","modified":"2024-03-05T09:00:00Z","description":"Кратко",` +
+				`"numberOfPages":1225,"subject":[{"name":"Роман"}]},` +
+				`"links":[{"rel":"alternate","href":"https://books.example/api/v1/books/1","type":"application/json","title":"API"}],` +
+				`"images":[{"href":"https://books.example/c.jpg","type":"image/jpeg"}]}]}`,
+		},
+		{
+			name: "publication without ISBN keeps the entry ID",
+			feed: &Feed{
+				Title:   "Книга",
+				Updated: testTime,
+				Entries: []Entry{{ID: "urn:uuid:2", Title: "Солярис", Updated: testTime, Publication: &Publication{}}},
+			},
+			want: `{"metadata":{"title":"Книга","modified":"2024-03-05T09:00:00Z"},"links":[],` +
+				`"publications":[{"metadata":{"@type":"http://schema.org/Book","identifier":"urn:uuid:2","title":"Солярис",` +
+				`"modified":"2024-03-05T09:00:00Z"},"links":[]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteOPDS2(&buf, tt.feed); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want+"\n" {
+				t.Errorf("\n got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteOpenSearch(t *testing.T) {
+	catalog := Catalog{Version: OPDS1, Site: "https://books.example", Root: "/opds"}
+
+	var buf bytes.Buffer
+	if err := WriteOpenSearch(&buf, catalog, "Книги", "Поиск по каталогу"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<OpenSearchDescription xmlns="http://a9.com/-/spec/opensearch/1.1/"><ShortName>Книги</ShortName>` +
+		`<Description>Поиск по каталогу</Description><InputEncoding>UTF-8</InputEncoding><OutputEncoding>UTF-8</OutputEncoding>` +
+		`<Url type="application/atom+xml;profile=opds-catalog;kind=acquisition" template="https://books.example/opds/search?q={searchTerms}&amp;page={startPage?}"></Url>` +
+		`</OpenSearchDescription>` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type openSearchDescription struct {
+	XMLName        xml.Name        `xml:"OpenSearchDescription"`
+	Namespace      string          `xml:"xmlns,attr"`
+	ShortName      string          `xml:"ShortName"`
+	Description    string          `xml:"Description"`
+	InputEncoding  string          `xml:"InputEncoding"`
+	OutputEncoding string          `xml:"OutputEncoding"`
+	URLs           []openSearchURL `xml:"Url"`
+}
+
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// WriteOpenSearch пишет описание поиска OpenSearch 1.1 для каталога OPDS 1.2.
+// Шаблон поиска принимает параметры q ({searchTerms}) и page ({startPage})
+func WriteOpenSearch(w io.Writer, catalog Catalog, shortName, description string) error {
+	doc := openSearchDescription{
+		Namespace:      openSearchNamespace,
+		ShortName:      shortName,
+		Description:    description,
+		InputEncoding:  "UTF-8",
+		OutputEncoding: "UTF-8",
+		URLs: []openSearchURL{{
+			Type:     catalog.Type(KindAcquisition),
+			Template: catalog.URL("search", nil) + "?q={searchTerms}&page={startPage?}",
+		}},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	if err := xml.NewEncoder(w).Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
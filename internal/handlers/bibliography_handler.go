@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"book-management-system/internal/bibliography"
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type BibliographyHandler struct {
+	service *services.CatalogService
+	log     *logger.Logger
+}
+
+// NewBibliographyHandler создает обработчик библиографических выгрузок и ссылок
+func NewBibliographyHandler(service *services.CatalogService) *BibliographyHandler {
+	return &BibliographyHandler{
+		service: service,
+		log:     logger.GetLogger(),
+	}
+}
+
+// ExportCatalog выгружает подтвержденный каталог библиографическими записями
+//
+//	@Summary		Выгрузить каталог
+//	@Description	Потоково отдает все подтвержденные книги в MARCXML (MARC 21 slim), Dublin Core (oai_dc), BibTeX или CSL-JSON
+//	@Tags			Books
+//	@Produce		xml
+//	@Produce		json
+//	@Param			format	query		string	false	"Формат: marcxml (по умолчанию), dc, bibtex, csl-json"
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	map[string]string	"Неверный формат выгрузки"
+//	@Router			/books/export [get]
+func (h *BibliographyHandler) ExportCatalog(c *gin.Context) {
+	format, err := bibliography.ParseFormat(c.DefaultQuery("format", string(bibliography.FormatMARCXML)))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга формата выгрузки каталога: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Допустимые форматы: marcxml, dc, bibtex, csl-json"})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", format.FileName()))
+	c.Status(http.StatusOK)
+
+	// Заголовки уже отправлены, поэтому ошибку посреди потока можно только залогировать
+	if err := h.service.ExportBibliography(format, c.Writer); err != nil {
+		h.log.Warnf("Ошибка выгрузки каталога: %v", err)
+		c.Abort()
+	}
+}
+
+// GetBookCitation формирует библиографическую ссылку на книгу
+//
+//	@Summary		Ссылка на книгу
+//	@Description	Библиографическая ссылка на подтвержденную книгу по стилю APA, MLA или ГОСТ Р 7.0.100-2018
+//	@Tags			Books
+//	@Produce		json
+//	@Param			bookID	path		string	true	"UUID книги"
+//	@Param			style	query		string	false	"Стиль: apa (по умолчанию), mla, gost"
+//	@Success		200		{object}	dto.BookCitationResponse
+//	@Failure		400		{object}	map[string]string	"Неверный ID или стиль"
+//	@Failure		404		{object}	map[string]string	"Книга не найдена"
+//	@Router			/books/{bookID}/citation [get]
+func (h *BibliographyHandler) GetBookCitation(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("bookID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID книги"})
+		return
+	}
+
+	style, err := bibliography.ParseStyle(c.DefaultQuery("style", string(bibliography.StyleAPA)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Допустимые стили: apa, mla, gost"})
+		return
+	}
+
+	citation, err := h.service.CiteBook(bookID, style)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Книга не найдена"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при формировании ссылки"})
+		return
+	}
+
+	c.JSON(http.StatusOK, citation)
+}
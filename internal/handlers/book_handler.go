@@ -5,9 +5,11 @@ import (
 	"book-management-system/internal/services"
 	"book-management-system/pkg/logger"
 	"book-management-system/pkg/utils"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"net/http"
 	"path/filepath"
 	"strconv"
@@ -48,11 +50,21 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 		return
 	}
 
-	// Получаем роль пользователя из миддлвари
+	// Получаем роль и ID пользователя из миддлвари
 	role, _ := c.Get("role")
+	creatorID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка получения userID: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
 
 	// Создаем книгу через сервис
-	book, err := h.service.CreateBook(req.Title, req.Description, req.CoverImage, req.AuthorIDs, role.(string))
+	book, err := h.service.CreateBook(req.Title, req.Description, req.CoverImage, req.PageCount, req.AuthorIDs, role.(string), creatorID)
+	if errors.Is(err, services.ErrInvalidPageCount) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		h.log.Warnf("Ошибка создания книги: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании книги"})
@@ -62,6 +74,37 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 	c.JSON(http.StatusCreated, book)
 }
 
+// GetTopRatedBooks получает книги с лучшим рейтингом
+//
+//	@Summary		Книги с лучшим рейтингом
+//	@Description	Подтвержденные книги по убыванию байесовского рейтинга: книга с парой восторженных отзывов не обгонит классику с тысячами оценок
+//	@Tags			Books
+//	@Produce		json
+//	@Param			limit	query		int	false	"Количество книг (по умолчанию 10, не больше 100)"
+//	@Success		200		{object}	dto.TopRatedBooksResponse
+//	@Failure		500		{object}	map[string]string	"Internal server error"
+//	@Router			/books/top-rated [get]
+func (h *BookHandler) GetTopRatedBooks(c *gin.Context) {
+	queryLimit := c.Query("limit")
+	limitInt, err := strconv.Atoi(queryLimit)
+	if err != nil {
+		h.log.Warnf("ошибка конвертации query limit=%s : %v", queryLimit, err)
+		limitInt = 10
+	}
+	if limitInt > 100 {
+		limitInt = 100
+	}
+
+	books, err := h.service.GetTopRatedBooks(limitInt)
+	if err != nil {
+		h.log.Warnf("Ошибка получения книг с лучшим рейтингом: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось получить книги"})
+		return
+	}
+
+	c.JSON(http.StatusOK, books)
+}
+
 // GetBookByID получает книгу по ID
 //
 //	@Summary		получить книгу по ID
@@ -145,7 +188,11 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.UpdateBook(bookID, req.Title, req.Description, req.CoverImage, req.AuthorIDs); err != nil {
+	if err := h.service.UpdateBook(bookID, req.Title, req.Description, req.CoverImage, req.PageCount, req.AuthorIDs); err != nil {
+		if errors.Is(err, services.ErrInvalidPageCount) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.log.Warnf("Ошибка обновления книги: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении книги"})
 		return
@@ -233,6 +280,7 @@ func (h *BookHandler) GetBooksPaginated(c *gin.Context) {
 //	@Param			bookID	path		string				true	"UUID книги"
 //	@Success		200		{object}	map[string]string	"Book confirmed"
 //	@Failure		400		{object}	map[string]string	"Invalid data"
+//	@Failure		401		{object}	map[string]string	"Пользователь не аутентифицирован"
 //	@Failure		404		{object}	map[string]string	"Book not found"
 //	@Router			/books/{bookID}/confirm [put]
 func (h *BookHandler) ConfirmBook(c *gin.Context) {
@@ -243,7 +291,18 @@ func (h *BookHandler) ConfirmBook(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.ConfirmBook(bookID); err != nil {
+	moderatorID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка получения userID: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
+
+	if err := h.service.ConfirmBook(bookID, moderatorID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Книга не найдена"})
+			return
+		}
 		h.log.Warnf("Ошибка подтверждения книги: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при подтверждении книги"})
 		return
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"html/template"
+	"net/http"
+)
+
+// sharePageTemplate страница книги для превью ссылок в соцсетях и мессенджерах. html/template экранирует
+// значения по контексту: в атрибутах — как HTML, а JSON-LD внутри script сериализует в JSON с экранированием «<»
+var sharePageTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="ru" prefix="og: https://ogp.me/ns# book: https://ogp.me/ns/book#">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="canonical" href="{{.URL}}">
+{{- with .Description}}
+<meta name="description" content="{{.}}">
+{{- end}}
+<meta property="og:type" content="book">
+<meta property="og:site_name" content="{{.SiteName}}">
+<meta property="og:title" content="{{.Title}}">
+<meta property="og:url" content="{{.URL}}">
+{{- with .Description}}
+<meta property="og:description" content="{{.}}">
+{{- end}}
+{{- with .Image}}
+<meta property="og:image" content="{{.}}">
+{{- end}}
+{{- with .JSONLD.ISBN}}
+<meta property="book:isbn" content="{{.}}">
+{{- end}}
+<meta name="twitter:card" content="{{if .Image}}summary_large_image{{else}}summary{{end}}">
+<meta name="twitter:title" content="{{.Title}}">
+{{- with .Description}}
+<meta name="twitter:description" content="{{.}}">
+{{- end}}
+{{- with .Image}}
+<meta name="twitter:image" content="{{.}}">
+{{- end}}
+<script type="application/ld+json">{{.JSONLD}}</script>
+</head>
+<body>
+<h1>{{.BookTitle}}</h1>
+{{- with .Authors}}
+<p>{{.}}</p>
+{{- end}}
+{{- with .Image}}
+<img src="{{.}}" alt="{{$.BookTitle}}">
+{{- end}}
+{{- with .JSONLD.Description}}
+<p>{{.}}</p>
+{{- end}}
+</body>
+</html>
+`))
+
+type BookMetadataHandler struct {
+	service *services.BookMetadataService
+	site    PublicSite
+	log     *logger.Logger
+}
+
+// NewBookMetadataHandler создает обработчик метаданных книги для поисковиков и соцсетей
+func NewBookMetadataHandler(service *services.BookMetadataService, site PublicSite) *BookMetadataHandler {
+	return &BookMetadataHandler{
+		service: service,
+		site:    site,
+		log:     logger.GetLogger(),
+	}
+}
+
+// GetBookJSONLD отдает разметку книги schema.org в JSON-LD
+//
+//	@Summary		Разметка книги schema.org
+//	@Description	Объект schema.org Book с авторами, ISBN, обложкой и рейтингом по отзывам для SEO. Поддерживает ETag/If-None-Match и Last-Modified/If-Modified-Since
+//	@Tags			Books
+//	@Produce		json
+//	@Param			bookID	path		string	true	"UUID книги"
+//	@Success		200		{object}	dto.BookJSONLD
+//	@Success		304		"Книга не изменилась"
+//	@Failure		400		{object}	map[string]string	"Неверный ID книги"
+//	@Failure		404		{object}	map[string]string	"Книга не найдена"
+//	@Router			/books/{bookID}/jsonld [get]
+func (h *BookMetadataHandler) GetBookJSONLD(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("bookID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID книги"})
+		return
+	}
+
+	jsonld, updatedAt, err := h.service.BookJSONLD(h.site.URL(c), bookID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Книга не найдена"})
+		return
+	}
+	if err != nil {
+		h.log.Warnf("Ошибка получения разметки книги %s: %v", bookID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении книги"})
+		return
+	}
+
+	body, err := json.Marshal(jsonld)
+	if err != nil {
+		h.log.Warnf("Ошибка сериализации разметки книги %s: %v", bookID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении книги"})
+		return
+	}
+	writeConditional(c, "application/ld+json; charset=utf-8", body, updatedAt)
+}
+
+// GetBookSharePage отдает HTML-страницу книги с тегами Open Graph и Twitter
+//
+//	@Summary		Страница книги для соцсетей
+//	@Description	HTML-страница подтвержденной книги с тегами Open Graph, Twitter Cards и разметкой JSON-LD для превью ссылок
+//	@Tags			Books
+//	@Produce		html
+//	@Param			bookID	path	string	true	"UUID книги"
+//	@Success		200		{string}	string	"HTML-страница"
+//	@Success		304		"Книга не изменилась"
+//	@Failure		400		{string}	string	"Неверный ID книги"
+//	@Failure		404		{string}	string	"Книга не найдена"
+//	@Router			/share/books/{bookID} [get]
+func (h *BookMetadataHandler) GetBookSharePage(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("bookID"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Неверный ID книги")
+		return
+	}
+
+	page, err := h.service.SharePage(h.site.URL(c), bookID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.String(http.StatusNotFound, "Книга не найдена")
+		return
+	}
+	if err != nil {
+		h.log.Warnf("Ошибка получения страницы книги %s: %v", bookID, err)
+		c.String(http.StatusInternalServerError, "Ошибка при получении книги")
+		return
+	}
+
+	var body bytes.Buffer
+	if err := sharePageTemplate.Execute(&body, page); err != nil {
+		h.log.Warnf("Ошибка отрисовки страницы книги %s: %v", bookID, err)
+		c.String(http.StatusInternalServerError, "Ошибка при получении книги")
+		return
+	}
+	writeConditional(c, "text/html; charset=utf-8", body.Bytes(), page.UpdatedAt)
+}
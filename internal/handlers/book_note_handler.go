@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+type BookNoteHandler struct {
+	service *services.BookNoteService
+	log     *logger.Logger
+}
+
+// NewBookNoteHandler создает обработчик заметок к книгам
+func NewBookNoteHandler(service *services.BookNoteService) *BookNoteHandler {
+	return &BookNoteHandler{
+		service: service,
+		log:     logger.GetLogger(),
+	}
+}
+
+// GetNotes получает заметки пользователя к книге
+//
+//	@Summary		Получить заметки к книге
+//	@Description	Возвращает заметки, выделения и цитаты пользователя к книге из его списка
+//	@Tags			BookNotes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			bookID	path		string	true	"UUID книги"
+//	@Success		200		{array}		dto.BookNoteResponse
+//	@Failure		400		{object}	map[string]string	"Неверный идентификатор книги"
+//	@Failure		500		{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me/books/{bookID}/notes [get]
+func (h *BookNoteHandler) GetNotes(c *gin.Context) {
+	userID, bookID, ok := h.parseUserBook(c)
+	if !ok {
+		return
+	}
+
+	notes, err := h.service.GetNotes(userID, bookID)
+	if err != nil {
+		h.log.Warnf("Ошибка получения заметок: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении заметок"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notes)
+}
+
+// CreateNote создает заметку к книге
+//
+//	@Summary		Создать заметку к книге
+//	@Description	Добавляет заметку, выделение или цитату к книге из списка пользователя
+//	@Tags			BookNotes
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			bookID	path		string				true	"UUID книги"
+//	@Param			note	body		dto.BookNoteRequest	true	"Данные заметки"
+//	@Success		201		{object}	dto.BookNoteResponse
+//	@Failure		400		{object}	map[string]string	"Неверный формат запроса"
+//	@Failure		404		{object}	map[string]string	"Книги нет в списке пользователя"
+//	@Failure		500		{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me/books/{bookID}/notes [post]
+func (h *BookNoteHandler) CreateNote(c *gin.Context) {
+	userID, bookID, ok := h.parseUserBook(c)
+	if !ok {
+		return
+	}
+
+	var req dto.BookNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warnf("Ошибка привязки JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	note, err := h.service.CreateNote(userID, bookID, req)
+	if err != nil {
+		h.log.Warnf("Ошибка создания заметки: %v", err)
+		h.respondNoteError(c, err, "Книги нет в списке пользователя")
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// UpdateNote обновляет заметку к книге
+//
+//	@Summary		Обновить заметку к книге
+//	@Description	Редактирует текст, позицию, тип или видимость заметки
+//	@Tags			BookNotes
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			bookID	path		string				true	"UUID книги"
+//	@Param			noteID	path		string				true	"UUID заметки"
+//	@Param			note	body		dto.BookNoteRequest	true	"Данные заметки"
+//	@Success		200		{object}	dto.BookNoteResponse
+//	@Failure		400		{object}	map[string]string	"Неверный формат запроса"
+//	@Failure		404		{object}	map[string]string	"Заметка не найдена"
+//	@Failure		500		{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me/books/{bookID}/notes/{noteID} [put]
+func (h *BookNoteHandler) UpdateNote(c *gin.Context) {
+	userID, bookID, ok := h.parseUserBook(c)
+	if !ok {
+		return
+	}
+
+	noteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга noteID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор заметки"})
+		return
+	}
+
+	var req dto.BookNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warnf("Ошибка привязки JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	note, err := h.service.UpdateNote(userID, bookID, noteID, req)
+	if err != nil {
+		h.log.Warnf("Ошибка обновления заметки: %v", err)
+		h.respondNoteError(c, err, "Заметка не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+// DeleteNote удаляет заметку к книге
+//
+//	@Summary		Удалить заметку к книге
+//	@Description	Удаляет заметку пользователя вместе с голосами за нее
+//	@Tags			BookNotes
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			bookID	path		string				true	"UUID книги"
+//	@Param			noteID	path		string				true	"UUID заметки"
+//	@Success		200		{object}	map[string]string	"message: Заметка удалена"
+//	@Failure		400		{object}	map[string]string	"Неверный формат запроса"
+//	@Failure		404		{object}	map[string]string	"Заметка не найдена"
+//	@Failure		500		{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me/books/{bookID}/notes/{noteID} [delete]
+func (h *BookNoteHandler) DeleteNote(c *gin.Context) {
+	userID, bookID, ok := h.parseUserBook(c)
+	if !ok {
+		return
+	}
+
+	noteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга noteID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор заметки"})
+		return
+	}
+
+	if err := h.service.DeleteNote(userID, bookID, noteID); err != nil {
+		h.log.Warnf("Ошибка удаления заметки: %v", err)
+		h.respondNoteError(c, err, "Заметка не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Заметка удалена"})
+}
+
+// GetPublicQuotes возвращает публичные цитаты книги
+//
+//	@Summary		Цитаты из книги
+//	@Description	Публичные цитаты пользователей с количеством голосов и маркерной пагинацией
+//	@Tags			BookNotes
+//	@Produce		json
+//	@Param			bookID		path		string	true	"UUID книги"
+//	@Param			after_id	query		string	false	"UUID последней цитаты (для пагинации)"
+//	@Param			limit		query		int		false	"Количество цитат на страницу (по умолчанию 10)"
+//	@Success		200			{object}	dto.PaginatedQuotesResponse
+//	@Failure		400			{object}	map[string]string	"Invalid data"
+//	@Failure		500			{object}	map[string]string	"Internal server error"
+//	@Router			/books/{bookID}/quotes [get]
+func (h *BookNoteHandler) GetPublicQuotes(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("bookID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга bookID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор книги"})
+		return
+	}
+
+	queryLimit := c.Query("limit")
+	limitInt, err := strconv.Atoi(queryLimit)
+	if err != nil {
+		h.log.Warnf("ошибка конвертации query limit=%s : %v", queryLimit, err)
+		limitInt = 10
+	}
+
+	var afterUUID *uuid.UUID
+	if queryAfterId := c.Query("after_id"); queryAfterId != "" {
+		parsedID, err := uuid.Parse(queryAfterId)
+		if err != nil {
+			h.log.Warnf("Ошибка парсинга after_id: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный параметр after_id"})
+			return
+		}
+		afterUUID = &parsedID
+	}
+
+	quotes, err := h.service.GetPublicQuotes(bookID, limitInt, afterUUID)
+	if err != nil {
+		h.log.Warnf("Ошибка получения цитат: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении цитат"})
+		return
+	}
+
+	c.JSON(http.StatusOK, quotes)
+}
+
+// VoteQuote голосует за публичную цитату
+//
+//	@Summary		Проголосовать за цитату
+//	@Description	Проголосовать за публичную цитату +1 -1 0
+//	@Tags			BookNotes
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			bookID	path		string				true	"UUID книги"
+//	@Param			noteID	path		string				true	"UUID цитаты"
+//	@Param			vote	body		dto.VoteNoteRequest	true	"Данные для голосования"
+//	@Success		200		{object}	map[string]string	"message: Голос успешно учтен"
+//	@Failure		400		{object}	map[string]string	"Неверный формат запроса"
+//	@Failure		404		{object}	map[string]string	"Цитата не найдена"
+//	@Router			/books/{bookID}/quotes/{noteID}/vote [post]
+func (h *BookNoteHandler) VoteQuote(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка идентификации пользователя: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
+
+	bookID, err := uuid.Parse(c.Param("bookID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга bookID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор книги"})
+		return
+	}
+
+	noteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга noteID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор цитаты"})
+		return
+	}
+
+	var body dto.VoteNoteRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.log.Warnf("Ошибка привязки JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	if !slices.Contains([]int{-1, 0, 1}, body.Vote) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Недопустимое значение голоса"})
+		return
+	}
+
+	if err := h.service.VoteQuote(bookID, noteID, userID, body.Vote); err != nil {
+		h.log.Warnf("Ошибка голосования за цитату: %v", err)
+		h.respondNoteError(c, err, "Цитата не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Голос успешно учтен"})
+}
+
+// parseUserBook достает пользователя из токена и книгу из пути
+func (h *BookNoteHandler) parseUserBook(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка идентификации пользователя: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	bookID, err := uuid.Parse(c.Param("bookID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга bookID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор книги"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, bookID, true
+}
+
+// respondNoteError переводит ошибку сервиса заметок в HTTP-ответ
+func (h *BookNoteHandler) respondNoteError(c *gin.Context, err error, notFoundMessage string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFoundMessage})
+	case errors.Is(err, services.ErrInvalidNoteKind),
+		errors.Is(err, services.ErrInvalidNoteVisibility),
+		errors.Is(err, services.ErrInvalidNotePage),
+		errors.Is(err, services.ErrEmptyNoteText):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка обработки заметки"})
+	}
+}
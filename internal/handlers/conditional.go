@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// publicCacheControl сколько клиент может не перезапрашивать открытый ответ; после этого он приходит с If-None-Match
+	publicCacheControl = "public, max-age=300"
+	// requestSiteCacheControl то же для ответа с адресом сайта из заголовков запроса: без public,
+	// чтобы общий кеш не раздал ссылки на подставленный клиентом хост
+	requestSiteCacheControl = "max-age=300"
+)
+
+// writeConditional отдает тело с ETag и Last-Modified или 304, если у клиента та же версия.
+// ETag считается по телу, поэтому меняется при любом изменении ответа, даже если время изменения осталось прежним
+// (например, отзыв скрыли модераторы); If-Modified-Since проверяется, только если клиент не прислал If-None-Match.
+// Ответ кешируется как public, только если адрес сайта в нем не взят из запроса (см. PublicSite)
+func writeConditional(c *gin.Context, contentType string, body []byte, modified time.Time) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	modified = modified.UTC().Truncate(time.Second)
+
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", modified.Format(http.TimeFormat))
+	if c.GetBool(siteFromRequestKey) {
+		c.Header("Cache-Control", requestSiteCacheControl)
+	} else {
+		c.Header("Cache-Control", publicCacheControl)
+	}
+
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		if etagMatches(ifNoneMatch, etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	} else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !modified.After(since) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// etagMatches проверяет If-None-Match: список ETag через запятую или *; слабые ETag сравниваются как сильные
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
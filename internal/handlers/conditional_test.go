@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEtagMatches(t *testing.T) {
+	const etag = `"abc"`
+
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"x", "abc"`, true},
+		{` "x" ,W/"abc" `, true},
+		{`*`, true},
+		{`"abcd"`, false},
+		{`abc`, false},
+		{`"x", "y"`, false},
+		{``, false},
+	}
+
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, etag); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestWriteConditional(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := []byte(`<feed/>`)
+	modified := time.Date(2024, 3, 5, 12, 0, 30, 500_000_000, time.FixedZone("MSK", 3*60*60))
+	const lastModified = "Tue, 05 Mar 2024 09:00:30 GMT"
+
+	// ETag ответа берется из первого запроса без условий
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/feeds/books.atom", nil)
+	writeConditional(c, "application/atom+xml", body, modified)
+	etag := recorder.Header().Get("ETag")
+	if etag == "" || etag[0] != '"' {
+		t.Fatalf("ETag = %q", etag)
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"no conditions", nil, http.StatusOK},
+		{"same ETag", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"weak ETag in a list", map[string]string{"If-None-Match": `"old", W/` + etag}, http.StatusNotModified},
+		{"other ETag", map[string]string{"If-None-Match": `"old"`}, http.StatusOK},
+		{"If-None-Match wins over If-Modified-Since", map[string]string{"If-None-Match": `"old"`, "If-Modified-Since": lastModified}, http.StatusOK},
+		{"not modified since", map[string]string{"If-Modified-Since": lastModified}, http.StatusNotModified},
+		{"modified later", map[string]string{"If-Modified-Since": "Tue, 05 Mar 2024 09:00:29 GMT"}, http.StatusOK},
+		{"invalid date", map[string]string{"If-Modified-Since": "вчера"}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(http.MethodGet, "/feeds/books.atom", nil)
+			for name, value := range tt.headers {
+				c.Request.Header.Set(name, value)
+			}
+
+			writeConditional(c, "application/atom+xml", body, modified)
+			c.Writer.WriteHeaderNow()
+
+			if recorder.Code != tt.want {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.want)
+			}
+			if got := recorder.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q", got, etag)
+			}
+			if got := recorder.Header().Get("Last-Modified"); got != lastModified {
+				t.Errorf("Last-Modified = %q, want %q", got, lastModified)
+			}
+
+			wantBody := string(body)
+			if tt.want == http.StatusNotModified {
+				wantBody = ""
+			}
+			if recorder.Body.String() != wantBody {
+				t.Errorf("body = %q, want %q", recorder.Body.String(), wantBody)
+			}
+		})
+	}
+
+	// Другое тело — другой ETag
+	recorder = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/feeds/books.atom", nil)
+	writeConditional(c, "application/atom+xml", []byte(`<feed></feed>`), modified)
+	if recorder.Header().Get("ETag") == etag {
+		t.Error("ETag не зависит от тела")
+	}
+}
+
+func TestWriteConditionalCacheControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		baseURL      string
+		cacheControl string
+		vary         string
+	}{
+		{"configured site", "https://books.example", publicCacheControl, ""},
+		{"site from request", "", requestSiteCacheControl, "Host, X-Forwarded-Proto"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(http.MethodGet, "/share/books/1", nil)
+			c.Request.Host = "evil.example"
+
+			site := NewPublicSite(tt.baseURL).URL(c)
+			writeConditional(c, "text/html", []byte(site), time.Now())
+
+			if got := recorder.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cacheControl)
+			}
+			if got := recorder.Header().Get("Vary"); got != tt.vary {
+				t.Errorf("Vary = %q, want %q", got, tt.vary)
+			}
+		})
+	}
+}
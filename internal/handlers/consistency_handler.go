@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type ConsistencyHandler struct {
+	service *services.ConsistencyService
+	log     *logger.Logger
+}
+
+// NewConsistencyHandler создает обработчик проверки согласованности хранилищ
+func NewConsistencyHandler(service *services.ConsistencyService) *ConsistencyHandler {
+	return &ConsistencyHandler{
+		service: service,
+		log:     logger.GetLogger(),
+	}
+}
+
+// CheckConsistency ищет рассинхронизацию PostgreSQL и MongoDB без изменений
+//
+//	@Summary		Проверить согласованность хранилищ
+//	@Description	Ищет отзывы на удаленные книги, висячие связи книг с авторами и записи библиотек, устаревшие рейтинги и голоса за удаленные отзывы. Ничего не меняет
+//	@Tags			Admin
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	dto.ConsistencyReport
+//	@Router			/admin/consistency [get]
+func (h *ConsistencyHandler) CheckConsistency(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.Check(false))
+}
+
+// RepairConsistency ищет и исправляет рассинхронизацию PostgreSQL и MongoDB
+//
+//	@Summary		Исправить рассинхронизацию хранилищ
+//	@Description	Выполняет те же проверки и исправляет найденное: удаляет осиротевшие записи, пересчет рейтингов ставит в outbox
+//	@Tags			Admin
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	dto.ConsistencyReport
+//	@Router			/admin/consistency/repair [post]
+func (h *ConsistencyHandler) RepairConsistency(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.Check(true))
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"strings"
+)
+
+// currentUserID достает ID пользователя, который AuthMiddleware кладет в контекст строкой
+func currentUserID(c *gin.Context) (uuid.UUID, error) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		return uuid.Nil, errors.New("пользователь не аутентифицирован")
+	}
+
+	stringUserID, ok := userID.(string)
+	if !ok {
+		return uuid.Nil, errors.New("некорректный тип userID в контексте")
+	}
+
+	return uuid.Parse(stringUserID)
+}
+
+// siteFromRequestKey ключ контекста: адрес сайта в ответе собран из заголовков запроса
+const siteFromRequestKey = "siteFromRequest"
+
+// PublicSite адрес сайта для абсолютных ссылок в лентах, каталоге OPDS, разметке JSON-LD и на страницах для соцсетей.
+// Его задает PUBLIC_BASE_URL; если переменная пуста, адрес собирается из схемы и хоста запроса
+// с учетом X-Forwarded-Proto от обратного прокси. Такой ответ зависит от заголовков, которые присылает клиент,
+// поэтому он помечается Vary и не отдается общим кешам как public (см. writeConditional)
+type PublicSite struct {
+	baseURL string
+}
+
+// NewPublicSite создает адрес сайта из значения PUBLIC_BASE_URL
+func NewPublicSite(baseURL string) PublicSite {
+	return PublicSite{baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// URL возвращает адрес сайта без завершающего слеша
+func (s PublicSite) URL(c *gin.Context) string {
+	if s.baseURL != "" {
+		return s.baseURL
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	c.Writer.Header().Add("Vary", "Host, X-Forwarded-Proto")
+	c.Set(siteFromRequestKey, true)
+	return scheme + "://" + c.Request.Host
+}
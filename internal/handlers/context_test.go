@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"crypto/tls"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPublicSiteURL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		baseURL string
+		host    string
+		tls     bool
+		proto   string
+		want    string
+	}{
+		{"configured", "https://books.example/", "evil.example", false, "http", "https://books.example"},
+		{"request host", "", "localhost:8080", false, "", "http://localhost:8080"},
+		{"TLS", "", "books.example", true, "", "https://books.example"},
+		{"proxy HTTPS", "", "books.example", false, "https", "https://books.example"},
+		{"proxy HTTP over TLS", "", "books.example", true, "http", "http://books.example"},
+		{"unknown proto ignored", "", "books.example", false, "javascript", "http://books.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/opds/", nil)
+			c.Request.Host = tt.host
+			if tt.tls {
+				c.Request.TLS = &tls.ConnectionState{}
+			}
+			if tt.proto != "" {
+				c.Request.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+
+			if got := NewPublicSite(tt.baseURL).URL(c); got != tt.want {
+				t.Errorf("URL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"book-management-system/internal/feeds"
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"strings"
+)
+
+type FeedHandler struct {
+	service *services.FeedService
+	site    PublicSite
+	log     *logger.Logger
+}
+
+// NewFeedHandler создает обработчик лент Atom
+func NewFeedHandler(service *services.FeedService, site PublicSite) *FeedHandler {
+	return &FeedHandler{
+		service: service,
+		site:    site,
+		log:     logger.GetLogger(),
+	}
+}
+
+// GetNewBooksFeed отдает ленту новых книг
+//
+//	@Summary		Лента новых книг
+//	@Description	Atom-лента 50 последних подтвержденных книг. Поддерживает ETag/If-None-Match и Last-Modified/If-Modified-Since
+//	@Tags			Feeds
+//	@Produce		xml
+//	@Param			If-None-Match		header	string	false	"ETag из прошлого ответа"
+//	@Param			If-Modified-Since	header	string	false	"Last-Modified из прошлого ответа"
+//	@Success		200					{file}	file
+//	@Success		304					"Лента не изменилась"
+//	@Router			/feeds/books.atom [get]
+func (h *FeedHandler) GetNewBooksFeed(c *gin.Context) {
+	feed, err := h.service.NewBooks(h.site.URL(c))
+	h.respond(c, feed, err)
+}
+
+// GetAuthorFeed отдает ленту новых книг автора
+//
+//	@Summary		Лента книг автора
+//	@Description	Atom-лента подтвержденных книг автора от новых к старым. Поддерживает ETag/If-None-Match и Last-Modified/If-Modified-Since
+//	@Tags			Feeds
+//	@Produce		xml
+//	@Param			authorID			path	string	true	"UUID автора"
+//	@Param			If-None-Match		header	string	false	"ETag из прошлого ответа"
+//	@Param			If-Modified-Since	header	string	false	"Last-Modified из прошлого ответа"
+//	@Success		200					{file}	file
+//	@Success		304					"Лента не изменилась"
+//	@Failure		400					{object}	map[string]string	"Неверный ID автора"
+//	@Failure		404					{object}	map[string]string	"Автор не найден"
+//	@Router			/feeds/authors/{authorID}.atom [get]
+func (h *FeedHandler) GetAuthorFeed(c *gin.Context) {
+	// gin не разбирает параметр с суффиксом в одном сегменте, поэтому .atom отрезаем сами
+	authorFile := c.Param("authorFile")
+	if !strings.HasSuffix(authorFile, ".atom") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Лента не найдена"})
+		return
+	}
+	authorID, err := uuid.Parse(strings.TrimSuffix(authorFile, ".atom"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID автора"})
+		return
+	}
+
+	feed, err := h.service.AuthorBooks(h.site.URL(c), authorID)
+	h.respond(c, feed, err)
+}
+
+// GetBookReviewsFeed отдает ленту отзывов на книгу
+//
+//	@Summary		Лента отзывов на книгу
+//	@Description	Atom-лента 50 последних видимых отзывов на подтвержденную книгу; спойлеры скрыты. Поддерживает ETag/If-None-Match и Last-Modified/If-Modified-Since
+//	@Tags			Feeds
+//	@Produce		xml
+//	@Param			bookID				path	string	true	"UUID книги"
+//	@Param			If-None-Match		header	string	false	"ETag из прошлого ответа"
+//	@Param			If-Modified-Since	header	string	false	"Last-Modified из прошлого ответа"
+//	@Success		200					{file}	file
+//	@Success		304					"Лента не изменилась"
+//	@Failure		400					{object}	map[string]string	"Неверный ID книги"
+//	@Failure		404					{object}	map[string]string	"Книга не найдена"
+//	@Router			/feeds/books/{bookID}/reviews.atom [get]
+func (h *FeedHandler) GetBookReviewsFeed(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("bookID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID книги"})
+		return
+	}
+
+	feed, err := h.service.BookReviews(h.site.URL(c), bookID)
+	h.respond(c, feed, err)
+}
+
+func (h *FeedHandler) respond(c *gin.Context, feed *feeds.Feed, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Лента не найдена"})
+		return
+	}
+	if err != nil {
+		h.log.Warnf("Ошибка построения ленты: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при построении ленты"})
+		return
+	}
+
+	var body bytes.Buffer
+	if err := feeds.WriteAtom(&body, feed); err != nil {
+		h.log.Warnf("Ошибка записи ленты: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при построении ленты"})
+		return
+	}
+
+	writeConditional(c, feeds.AtomContentType, body.Bytes(), feed.Updated)
+}
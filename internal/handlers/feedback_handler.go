@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"book-management-system/internal/dto"
+	"book-management-system/internal/moderation"
 	"book-management-system/internal/services"
 	"book-management-system/pkg/logger"
 	"book-management-system/pkg/utils"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
@@ -33,7 +35,7 @@ func NewFeedbackHandler(service *services.FeedbackService) *FeedbackHandler {
 //	@Produce		json
 //	@Param			review	body		dto.BaseFeedbackRequest	true	"Данные для создания отзыва"
 //	@Success		201		{object}	dto.CreatedFeedbackResponse
-//	@Failure		400		{object}	map[string]string	"Неверный формат запроса"
+//	@Failure		400		{object}	map[string]string	"Неверный формат запроса или текст отклонен фильтром"
 //	@Failure		500		{object}	map[string]string	"Ошибка сервера"
 //	@Router			/feedbacks [post]
 func (h *FeedbackHandler) CreateFeedback(c *gin.Context) {
@@ -58,6 +60,10 @@ func (h *FeedbackHandler) CreateFeedback(c *gin.Context) {
 	createdFeedbackId, err := h.service.CreateFeedback(feedback, stringUserID)
 	if err != nil {
 		h.log.Warnf("Ошибка создания отзыва: %v", err)
+		if errors.Is(err, moderation.ErrRejected) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось создать отзыв"})
 		return
 	}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"book-management-system/internal/models"
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+)
+
+type FollowHandler struct {
+	followService   *services.FollowService
+	activityService *services.ActivityService
+	log             *logger.Logger
+}
+
+// NewFollowHandler создает обработчик подписок и ленты
+func NewFollowHandler(followService *services.FollowService, activityService *services.ActivityService) *FollowHandler {
+	return &FollowHandler{
+		followService:   followService,
+		activityService: activityService,
+		log:             logger.GetLogger(),
+	}
+}
+
+// FollowUser подписывает текущего пользователя на другого пользователя
+//
+//	@Summary		Подписаться на пользователя
+//	@Description	Книги, которые пользователь добавляет и дочитывает, и его отзывы попадут в ленту
+//	@Tags			Follows
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			userID	path		string				true	"UUID пользователя"
+//	@Success		200		{object}	map[string]string	"Подписка оформлена"
+//	@Failure		400		{object}	map[string]string	"Неверный ID или подписка на себя"
+//	@Failure		404		{object}	map[string]string	"Пользователь не найден"
+//	@Router			/users/{userID}/follow [post]
+func (h *FollowHandler) FollowUser(c *gin.Context) {
+	h.changeFollow(c, models.FollowTargetUser, "userID", true)
+}
+
+// UnfollowUser отменяет подписку на пользователя
+//
+//	@Summary		Отписаться от пользователя
+//	@Tags			Follows
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			userID	path		string				true	"UUID пользователя"
+//	@Success		200		{object}	map[string]string	"Подписка отменена"
+//	@Failure		400		{object}	map[string]string	"Неверный ID"
+//	@Failure		404		{object}	map[string]string	"Подписки нет"
+//	@Router			/users/{userID}/follow [delete]
+func (h *FollowHandler) UnfollowUser(c *gin.Context) {
+	h.changeFollow(c, models.FollowTargetUser, "userID", false)
+}
+
+// FollowAuthor подписывает текущего пользователя на автора
+//
+//	@Summary		Подписаться на автора
+//	@Description	Новые подтвержденные книги автора попадут в ленту
+//	@Tags			Follows
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			authorID	path		string				true	"UUID автора"
+//	@Success		200			{object}	map[string]string	"Подписка оформлена"
+//	@Failure		400			{object}	map[string]string	"Неверный ID"
+//	@Failure		404			{object}	map[string]string	"Автор не найден"
+//	@Router			/authors/{authorID}/follow [post]
+func (h *FollowHandler) FollowAuthor(c *gin.Context) {
+	h.changeFollow(c, models.FollowTargetAuthor, "authorID", true)
+}
+
+// UnfollowAuthor отменяет подписку на автора
+//
+//	@Summary		Отписаться от автора
+//	@Tags			Follows
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			authorID	path		string				true	"UUID автора"
+//	@Success		200			{object}	map[string]string	"Подписка отменена"
+//	@Failure		400			{object}	map[string]string	"Неверный ID"
+//	@Failure		404			{object}	map[string]string	"Подписки нет"
+//	@Router			/authors/{authorID}/follow [delete]
+func (h *FollowHandler) UnfollowAuthor(c *gin.Context) {
+	h.changeFollow(c, models.FollowTargetAuthor, "authorID", false)
+}
+
+// GetFollowing получает подписки текущего пользователя
+//
+//	@Summary		Мои подписки
+//	@Tags			Follows
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{array}		dto.FollowResponse
+//	@Failure		401	{object}	map[string]string	"Пользователь не аутентифицирован"
+//	@Failure		500	{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me/following [get]
+func (h *FollowHandler) GetFollowing(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка получения userID: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
+
+	following, err := h.followService.GetFollowing(userID)
+	if err != nil {
+		h.log.Warnf("Ошибка получения подписок: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении подписок"})
+		return
+	}
+
+	c.JSON(http.StatusOK, following)
+}
+
+// GetFeed получает ленту активности по подпискам
+//
+//	@Summary		Лента активности
+//	@Description	События пользователей и авторов, на которых подписан текущий пользователь, новые первыми
+//	@Tags			Follows
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			after_id	query		string	false	"UUID последнего события (для пагинации)"
+//	@Param			limit		query		int		false	"Количество событий на страницу (по умолчанию 10)"
+//	@Success		200			{object}	dto.FeedResponse
+//	@Failure		400			{object}	map[string]string	"Invalid data"
+//	@Failure		401			{object}	map[string]string	"Пользователь не аутентифицирован"
+//	@Failure		500			{object}	map[string]string	"Internal server error"
+//	@Router			/users/me/feed [get]
+func (h *FollowHandler) GetFeed(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка получения userID: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
+
+	queryLimit := c.Query("limit")
+	limitInt, err := strconv.Atoi(queryLimit)
+	if err != nil {
+		h.log.Warnf("ошибка конвертации query limit=%s : %v", queryLimit, err)
+		limitInt = 10
+	}
+
+	var afterUUID *uuid.UUID
+	if queryAfterId := c.Query("after_id"); queryAfterId != "" {
+		parsedID, err := uuid.Parse(queryAfterId)
+		if err != nil {
+			h.log.Warnf("Ошибка парсинга after_id: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный параметр after_id"})
+			return
+		}
+		afterUUID = &parsedID
+	}
+
+	feed, err := h.activityService.GetFeed(userID, limitInt, afterUUID)
+	if err != nil {
+		h.log.Warnf("Ошибка получения ленты: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении ленты"})
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+func (h *FollowHandler) changeFollow(c *gin.Context, targetType models.FollowTargetType, param string, follow bool) {
+	followerID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка получения userID: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга %s: %v", param, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор"})
+		return
+	}
+
+	if follow {
+		err = h.followService.Follow(followerID, targetType, targetID)
+	} else {
+		err = h.followService.Unfollow(followerID, targetType, targetID)
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, services.ErrCannotFollowSelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if follow {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Не найден тот, на кого вы подписываетесь"})
+		} else {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Подписка не найдена"})
+		}
+		return
+	default:
+		h.log.Warnf("Ошибка изменения подписки на %s %s: %v", targetType, targetID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении подписки"})
+		return
+	}
+
+	if follow {
+		c.JSON(http.StatusOK, gin.H{"message": "Подписка оформлена"})
+	} else {
+		c.JSON(http.StatusOK, gin.H{"message": "Подписка отменена"})
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"book-management-system/internal/importer"
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// maxImportFileSize максимальный размер файла массовой загрузки
+const maxImportFileSize = 50 << 20
+
+type ImportHandler struct {
+	service *services.ImportService
+	log     *logger.Logger
+}
+
+// NewImportHandler создает обработчик массовой загрузки книг
+func NewImportHandler(service *services.ImportService) *ImportHandler {
+	return &ImportHandler{
+		service: service,
+		log:     logger.GetLogger(),
+	}
+}
+
+// StartBookImport принимает файл каталога и запускает его загрузку в фоне
+//
+//	@Summary		Массовая загрузка книг
+//	@Description	Принимает CSV (колонки title, authors через «;», isbn, description, page_count, publisher, published_year, cover_image), JSON Lines с теми же полями или ONIX 3.0 XML. Авторы ищутся по имени и создаются при необходимости. Книга пропускается, если есть книга с тем же ISBN или с тем же названием и авторами, когда у одной из них ISBN не указан. Загрузка идет в фоне, прогресс и ошибки записей — в GET /admin/import/jobs/{jobID}
+//	@Tags			Admin
+//	@Security		BearerAuth
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			file	formData	file	true	"Файл каталога, до 50 MB"
+//	@Param			format	formData	string	false	"Формат: csv, jsonl или onix; по умолчанию определяется по расширению файла"
+//	@Success		202		{object}	models.ImportJob
+//	@Failure		400		{object}	map[string]string	"Нет файла, неизвестный формат или файл слишком большой"
+//	@Failure		500		{object}	map[string]string	"Ошибка сервера"
+//	@Router			/admin/import/books [post]
+func (h *ImportHandler) StartBookImport(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		h.log.Warnf("Ошибка получения файла загрузки: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Файл обязателен"})
+		return
+	}
+	if file.Size > maxImportFileSize {
+		h.log.Warnf("Файл загрузки слишком большой: %d MB", file.Size>>20)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Файл слишком большой, максимум 50 MB"})
+		return
+	}
+
+	format, err := importer.ParseFormat(c.PostForm("format"), file.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		h.log.Warnf("Ошибка открытия файла загрузки: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка обработки файла"})
+		return
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		h.log.Warnf("Ошибка чтения файла загрузки: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка обработки файла"})
+		return
+	}
+
+	job, err := h.service.StartImport(data, format, file.Filename, userID)
+	if errors.Is(err, services.ErrEmptyImportFile) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании задачи загрузки"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetImportJob возвращает прогресс задачи загрузки
+//
+//	@Summary		Прогресс массовой загрузки
+//	@Description	Статус задачи (pending, running, completed, failed), счетчики обработанных, созданных, пропущенных и ошибочных записей и первые 1000 ошибок записей
+//	@Tags			Admin
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			jobID	path		string	true	"UUID задачи"
+//	@Success		200		{object}	models.ImportJob
+//	@Failure		400		{object}	map[string]string	"Неверный ID"
+//	@Failure		404		{object}	map[string]string	"Задача не найдена"
+//	@Router			/admin/import/jobs/{jobID} [get]
+func (h *ImportHandler) GetImportJob(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("jobID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID задачи"})
+		return
+	}
+
+	job, err := h.service.GetJob(jobID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Задача не найдена"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении задачи"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetImportJobs возвращает последние задачи загрузки
+//
+//	@Summary		Последние массовые загрузки
+//	@Description	Задачи загрузки, новые первыми
+//	@Tags			Admin
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			limit	query		int	false	"Количество задач (по умолчанию 10)"
+//	@Success		200		{array}		models.ImportJob
+//	@Failure		500		{object}	map[string]string	"Ошибка сервера"
+//	@Router			/admin/import/jobs [get]
+func (h *ImportHandler) GetImportJobs(c *gin.Context) {
+	queryLimit := c.Query("limit")
+	limitInt, err := strconv.Atoi(queryLimit)
+	if err != nil || limitInt <= 0 {
+		h.log.Warnf("ошибка конвертации query limit=%s : %v", queryLimit, err)
+		limitInt = 10
+	}
+
+	jobs, err := h.service.GetRecentJobs(limitInt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении задач"})
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type LibraryExportHandler struct {
+	service *services.LibraryExportService
+	log     *logger.Logger
+}
+
+// NewLibraryExportHandler создает обработчик выгрузки библиотеки
+func NewLibraryExportHandler(service *services.LibraryExportService) *LibraryExportHandler {
+	return &LibraryExportHandler{
+		service: service,
+		log:     logger.GetLogger(),
+	}
+}
+
+// ExportLibrary выгружает книги пользователя вместе с оценками и отзывами
+//
+//	@Summary		Выгрузить библиотеку пользователя
+//	@Description	Потоково отдает список книг пользователя с оценками и отзывами в CSV, JSON или CSV для импорта в Goodreads
+//	@Tags			UserBooks
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Produce		text/csv
+//	@Param			format	query		string	false	"Формат выгрузки: csv (по умолчанию), json, goodreads"
+//	@Success		200		{array}		dto.LibraryExportEntry
+//	@Failure		400		{object}	map[string]string	"Неверный формат выгрузки"
+//	@Failure		401		{object}	map[string]string	"Пользователь не аутентифицирован"
+//	@Router			/users/me/export [get]
+func (h *LibraryExportHandler) ExportLibrary(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка идентификации пользователя: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
+
+	format, err := services.ParseLibraryExportFormat(c.DefaultQuery("format", string(services.LibraryExportCSV)))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга формата выгрузки: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Допустимые форматы: csv, json, goodreads"})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", format.FileName()))
+	c.Status(http.StatusOK)
+
+	// Заголовки уже отправлены, поэтому ошибку посреди потока можно только залогировать
+	if err := h.service.ExportLibrary(userID, format, c.Writer); err != nil {
+		h.log.Warnf("Ошибка выгрузки библиотеки: %v", err)
+		c.Abort()
+	}
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// notificationHeartbeat как часто слать комментарий в SSE-поток, чтобы прокси не закрывали простаивающее соединение
+const notificationHeartbeat = 30 * time.Second
+
+type NotificationHandler struct {
+	service *services.NotificationService
+	log     *logger.Logger
+}
+
+// NewNotificationHandler создает обработчик уведомлений
+func NewNotificationHandler(service *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		service: service,
+		log:     logger.GetLogger(),
+	}
+}
+
+// GetNotifications получает уведомления текущего пользователя
+//
+//	@Summary		Мои уведомления
+//	@Description	Уведомления новые первыми и число непрочитанных
+//	@Tags			Notifications
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			unread		query		bool	false	"Только непрочитанные"
+//	@Param			after_id	query		string	false	"UUID последнего уведомления (для пагинации)"
+//	@Param			limit		query		int		false	"Количество уведомлений на страницу (по умолчанию 10)"
+//	@Success		200			{object}	dto.NotificationsResponse
+//	@Failure		400			{object}	map[string]string	"Invalid data"
+//	@Failure		401			{object}	map[string]string	"Пользователь не аутентифицирован"
+//	@Failure		500			{object}	map[string]string	"Internal server error"
+//	@Router			/users/me/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	queryLimit := c.Query("limit")
+	limitInt, err := strconv.Atoi(queryLimit)
+	if err != nil {
+		h.log.Warnf("ошибка конвертации query limit=%s : %v", queryLimit, err)
+		limitInt = 10
+	}
+
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+
+	var afterUUID *uuid.UUID
+	if queryAfterId := c.Query("after_id"); queryAfterId != "" {
+		parsedID, err := uuid.Parse(queryAfterId)
+		if err != nil {
+			h.log.Warnf("Ошибка парсинга after_id: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный параметр after_id"})
+			return
+		}
+		afterUUID = &parsedID
+	}
+
+	notifications, err := h.service.GetNotifications(userID, unreadOnly, limitInt, afterUUID)
+	if err != nil {
+		h.log.Warnf("Ошибка получения уведомлений: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении уведомлений"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// MarkNotificationRead отмечает уведомление прочитанным
+//
+//	@Summary		Прочитать уведомление
+//	@Tags			Notifications
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			notificationID	path		string				true	"UUID уведомления"
+//	@Success		200				{object}	map[string]string	"Уведомление прочитано"
+//	@Failure		400				{object}	map[string]string	"Неверный ID"
+//	@Failure		404				{object}	map[string]string	"Уведомление не найдено"
+//	@Router			/users/me/notifications/{notificationID}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	notificationID, err := uuid.Parse(c.Param("notificationID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга notificationID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор уведомления"})
+		return
+	}
+
+	if err := h.service.MarkRead(userID, notificationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Уведомление не найдено"})
+			return
+		}
+		h.log.Warnf("Ошибка отметки уведомления прочитанным: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при отметке уведомления"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Уведомление прочитано"})
+}
+
+// MarkAllNotificationsRead отмечает прочитанными все уведомления
+//
+//	@Summary		Прочитать все уведомления
+//	@Tags			Notifications
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	map[string]int64	"Сколько уведомлений отмечено"
+//	@Failure		500	{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	marked, err := h.service.MarkAllRead(userID)
+	if err != nil {
+		h.log.Warnf("Ошибка отметки уведомлений прочитанными: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при отметке уведомлений"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// GetNotificationPreferences получает настройки уведомлений
+//
+//	@Summary		Настройки уведомлений
+//	@Description	Все виды уведомлений и включены ли они
+//	@Tags			Notifications
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{array}		dto.NotificationPreferenceResponse
+//	@Failure		500	{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me/notifications/preferences [get]
+func (h *NotificationHandler) GetNotificationPreferences(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.service.GetPreferences(userID)
+	if err != nil {
+		h.log.Warnf("Ошибка получения настроек уведомлений: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении настроек уведомлений"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// UpdateNotificationPreferences включает и отключает виды уведомлений
+//
+//	@Summary		Изменить настройки уведомлений
+//	@Tags			Notifications
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			preferences	body		dto.UpdateNotificationPreferencesRequest	true	"Виды уведомлений"
+//	@Success		200			{array}		dto.NotificationPreferenceResponse
+//	@Failure		400			{object}	map[string]string	"Неизвестный вид уведомлений"
+//	@Failure		500			{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me/notifications/preferences [put]
+func (h *NotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warnf("Ошибка привязки JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	preferences, err := h.service.UpdatePreferences(userID, req.Preferences)
+	if errors.Is(err, services.ErrUnknownNotificationType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.log.Warnf("Ошибка сохранения настроек уведомлений: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении настроек уведомлений"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// StreamNotifications отправляет новые уведомления в реальном времени
+//
+//	@Summary		Поток уведомлений
+//	@Description	Server-Sent Events: каждое новое уведомление приходит событием notification с dto.NotificationResponse в data.
+//	@Description	Токен передается в заголовке Authorization, поэтому нужен клиент SSE с поддержкой заголовков
+//	@Tags			Notifications
+//	@Security		BearerAuth
+//	@Produce		text/event-stream
+//	@Success		200	{object}	dto.NotificationResponse
+//	@Failure		401	{object}	map[string]string	"Пользователь не аутентифицирован"
+//	@Router			/users/me/notifications/stream [get]
+func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	stream, unsubscribe := h.service.Subscribe(userID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx не должен буферизовать поток
+
+	heartbeat := time.NewTicker(notificationHeartbeat)
+	defer heartbeat.Stop()
+
+	// Сразу отправляем заголовки, чтобы клиент знал, что подписка установлена
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case notification, ok := <-stream:
+			// Поток закрывается при остановке сервера
+			if !ok {
+				return false
+			}
+			c.SSEvent("notification", notification)
+			return true
+		case <-heartbeat.C:
+			_, err := fmt.Fprint(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
+
+func (h *NotificationHandler) currentUser(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка получения userID: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
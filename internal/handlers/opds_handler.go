@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"book-management-system/internal/feeds"
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+)
+
+// OPDSHandler отдает каталог OPDS одной версии; для 1.2 и 2.0 создаются отдельные обработчики
+type OPDSHandler struct {
+	service *services.OPDSService
+	version feeds.OPDSVersion
+	root    string // путь корня каталога: /opds или /opds/v2
+	site    PublicSite
+	log     *logger.Logger
+}
+
+// NewOPDSHandler создает обработчик каталога OPDS версии version с корнем root
+func NewOPDSHandler(service *services.OPDSService, version feeds.OPDSVersion, root string, site PublicSite) *OPDSHandler {
+	return &OPDSHandler{
+		service: service,
+		version: version,
+		root:    root,
+		site:    site,
+		log:     logger.GetLogger(),
+	}
+}
+
+// GetRoot отдает корень каталога
+//
+//	@Summary		Каталог OPDS
+//	@Description	Корень каталога для читалок: разделы новинок, лучших книг, всех книг, авторов и жанров. /opds — OPDS 1.2 (Atom), /opds/v2 — OPDS 2.0 (JSON)
+//	@Tags			OPDS
+//	@Produce		xml
+//	@Produce		json
+//	@Success		200	{file}	file
+//	@Router			/opds/ [get]
+//	@Router			/opds/v2/ [get]
+func (h *OPDSHandler) GetRoot(c *gin.Context) {
+	catalog := h.catalog(c)
+	h.writeFeed(c, catalog, feeds.KindNavigation, h.service.Root(catalog))
+}
+
+// GetNewestBooks отдает новинки каталога
+//
+//	@Summary		Новинки OPDS
+//	@Description	Подтвержденные книги от новых к старым, по 50 на страницу
+//	@Tags			OPDS
+//	@Produce		xml
+//	@Produce		json
+//	@Param			after_id	query		string	false	"ID последней книги предыдущей страницы"
+//	@Success		200			{file}		file
+//	@Failure		400			{object}	map[string]string	"Неверный параметр after_id"
+//	@Router			/opds/new [get]
+//	@Router			/opds/v2/new [get]
+func (h *OPDSHandler) GetNewestBooks(c *gin.Context) {
+	afterID, ok := h.afterID(c)
+	if !ok {
+		return
+	}
+
+	catalog := h.catalog(c)
+	feed, err := h.service.NewestBooks(catalog, afterID)
+	h.respond(c, catalog, feeds.KindAcquisition, feed, err)
+}
+
+// GetAllBooks отдает весь каталог
+//
+//	@Summary		Все книги OPDS
+//	@Description	Подтвержденные книги в порядке добавления, по 50 на страницу
+//	@Tags			OPDS
+//	@Produce		xml
+//	@Produce		json
+//	@Param			after_id	query		string	false	"ID последней книги предыдущей страницы"
+//	@Success		200			{file}		file
+//	@Failure		400			{object}	map[string]string	"Неверный параметр after_id"
+//	@Router			/opds/books [get]
+//	@Router			/opds/v2/books [get]
+func (h *OPDSHandler) GetAllBooks(c *gin.Context) {
+	afterID, ok := h.afterID(c)
+	if !ok {
+		return
+	}
+
+	catalog := h.catalog(c)
+	feed, err := h.service.AllBooks(catalog, afterID)
+	h.respond(c, catalog, feeds.KindAcquisition, feed, err)
+}
+
+// GetTopRatedBooks отдает лучшие книги
+//
+//	@Summary		Лучшие книги OPDS
+//	@Description	50 подтвержденных книг с лучшим байесовским рейтингом
+//	@Tags			OPDS
+//	@Produce		xml
+//	@Produce		json
+//	@Success		200	{file}	file
+//	@Router			/opds/top [get]
+//	@Router			/opds/v2/top [get]
+func (h *OPDSHandler) GetTopRatedBooks(c *gin.Context) {
+	catalog := h.catalog(c)
+	feed, err := h.service.TopRatedBooks(catalog)
+	h.respond(c, catalog, feeds.KindAcquisition, feed, err)
+}
+
+// GetAuthors отдает список авторов
+//
+//	@Summary		Авторы OPDS
+//	@Description	Авторы каталога в порядке добавления, по 50 на страницу
+//	@Tags			OPDS
+//	@Produce		xml
+//	@Produce		json
+//	@Param			after_id	query		string	false	"ID последнего автора предыдущей страницы"
+//	@Success		200			{file}		file
+//	@Failure		400			{object}	map[string]string	"Неверный параметр after_id"
+//	@Router			/opds/authors [get]
+//	@Router			/opds/v2/authors [get]
+func (h *OPDSHandler) GetAuthors(c *gin.Context) {
+	afterID, ok := h.afterID(c)
+	if !ok {
+		return
+	}
+
+	catalog := h.catalog(c)
+	feed, err := h.service.Authors(catalog, afterID)
+	h.respond(c, catalog, feeds.KindNavigation, feed, err)
+}
+
+// GetAuthorBooks отдает книги автора
+//
+//	@Summary		Книги автора OPDS
+//	@Description	Подтвержденные книги автора по названию
+//	@Tags			OPDS
+//	@Produce		xml
+//	@Produce		json
+//	@Param			authorID	path		string	true	"UUID автора"
+//	@Success		200			{file}		file
+//	@Failure		400			{object}	map[string]string	"Неверный ID автора"
+//	@Failure		404			{object}	map[string]string	"Автор не найден"
+//	@Router			/opds/authors/{authorID} [get]
+//	@Router			/opds/v2/authors/{authorID} [get]
+func (h *OPDSHandler) GetAuthorBooks(c *gin.Context) {
+	authorID, err := uuid.Parse(c.Param("authorID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID автора"})
+		return
+	}
+
+	catalog := h.catalog(c)
+	feed, err := h.service.AuthorBooks(catalog, authorID)
+	h.respond(c, catalog, feeds.KindAcquisition, feed, err)
+}
+
+// GetGenres отдает список жанров
+//
+//	@Summary		Жанры OPDS
+//	@Description	Жанры, в которых есть подтвержденные книги, с числом книг
+//	@Tags			OPDS
+//	@Produce		xml
+//	@Produce		json
+//	@Success		200	{file}	file
+//	@Router			/opds/genres [get]
+//	@Router			/opds/v2/genres [get]
+func (h *OPDSHandler) GetGenres(c *gin.Context) {
+	catalog := h.catalog(c)
+	feed, err := h.service.Genres(catalog)
+	h.respond(c, catalog, feeds.KindNavigation, feed, err)
+}
+
+// GetGenreBooks отдает книги жанра
+//
+//	@Summary		Книги жанра OPDS
+//	@Description	Подтвержденные книги жанра от новых к старым, по 50 на страницу
+//	@Tags			OPDS
+//	@Produce		xml
+//	@Produce		json
+//	@Param			genreID		path		string	true	"UUID жанра"
+//	@Param			after_id	query		string	false	"ID последней книги предыдущей страницы"
+//	@Success		200			{file}		file
+//	@Failure		400			{object}	map[string]string	"Неверный ID жанра или after_id"
+//	@Failure		404			{object}	map[string]string	"Жанр не найден"
+//	@Router			/opds/genres/{genreID} [get]
+//	@Router			/opds/v2/genres/{genreID} [get]
+func (h *OPDSHandler) GetGenreBooks(c *gin.Context) {
+	genreID, err := uuid.Parse(c.Param("genreID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID жанра"})
+		return
+	}
+	afterID, ok := h.afterID(c)
+	if !ok {
+		return
+	}
+
+	catalog := h.catalog(c)
+	feed, err := h.service.GenreBooks(catalog, genreID, afterID)
+	h.respond(c, catalog, feeds.KindAcquisition, feed, err)
+}
+
+// Search ищет книги
+//
+//	@Summary		Поиск OPDS
+//	@Description	Поиск подтвержденных книг по части названия или имени автора, по 50 на страницу
+//	@Tags			OPDS
+//	@Produce		xml
+//	@Produce		json
+//	@Param			q		query		string	true	"Поисковый запрос (в OPDS 2.0 можно передавать как query)"
+//	@Param			page	query		int		false	"Номер страницы с единицы"
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	map[string]string	"Пустой поисковый запрос"
+//	@Router			/opds/search [get]
+//	@Router			/opds/v2/search [get]
+func (h *OPDSHandler) Search(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		query = c.Query("query")
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		page = 1
+	}
+
+	catalog := h.catalog(c)
+	feed, err := h.service.Search(catalog, query, page)
+	h.respond(c, catalog, feeds.KindAcquisition, feed, err)
+}
+
+// GetOpenSearchDescription отдает описание поиска OpenSearch для каталога OPDS 1.2
+//
+//	@Summary		Описание поиска OpenSearch
+//	@Description	Шаблон поиска по каталогу OPDS 1.2 для читалок
+//	@Tags			OPDS
+//	@Produce		xml
+//	@Success		200	{file}	file
+//	@Router			/opds/opensearch.xml [get]
+func (h *OPDSHandler) GetOpenSearchDescription(c *gin.Context) {
+	c.Header("Content-Type", feeds.OpenSearchContentType)
+	c.Status(http.StatusOK)
+	if err := feeds.WriteOpenSearch(c.Writer, h.catalog(c), "Книги", "Поиск книг по названию и автору"); err != nil {
+		h.log.Warnf("Ошибка записи описания OpenSearch: %v", err)
+		c.Abort()
+	}
+}
+
+func (h *OPDSHandler) catalog(c *gin.Context) feeds.Catalog {
+	return feeds.Catalog{Version: h.version, Site: h.site.URL(c), Root: h.root}
+}
+
+// afterID разбирает маркер пагинации; при ошибке сам отвечает 400
+func (h *OPDSHandler) afterID(c *gin.Context) (*uuid.UUID, bool) {
+	queryAfterID := c.Query("after_id")
+	if queryAfterID == "" {
+		return nil, true
+	}
+
+	parsedID, err := uuid.Parse(queryAfterID)
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга after_id: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный параметр after_id"})
+		return nil, false
+	}
+	return &parsedID, true
+}
+
+func (h *OPDSHandler) respond(c *gin.Context, catalog feeds.Catalog, kind feeds.Kind, feed *feeds.Feed, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Раздел каталога не найден"})
+	case errors.Is(err, services.ErrEmptySearchQuery):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Пустой поисковый запрос"})
+	case err != nil:
+		h.log.Warnf("Ошибка построения каталога OPDS: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при построении каталога"})
+	default:
+		h.writeFeed(c, catalog, kind, feed)
+	}
+}
+
+func (h *OPDSHandler) writeFeed(c *gin.Context, catalog feeds.Catalog, kind feeds.Kind, feed *feeds.Feed) {
+	c.Header("Content-Type", catalog.ContentType(kind))
+	c.Status(http.StatusOK)
+	if err := catalog.Write(c.Writer, feed); err != nil {
+		h.log.Warnf("Ошибка записи ленты OPDS: %v", err)
+		c.Abort()
+	}
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/models"
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"strconv"
+)
+
+type ReviewCommentHandler struct {
+	service *services.ReviewCommentService
+	log     *logger.Logger
+}
+
+// NewReviewCommentHandler создает обработчик комментариев к отзывам
+func NewReviewCommentHandler(service *services.ReviewCommentService) *ReviewCommentHandler {
+	return &ReviewCommentHandler{
+		service: service,
+		log:     logger.GetLogger(),
+	}
+}
+
+// GetComments получает комментарии к отзыву
+//
+//	@Summary		Получить комментарии к отзыву
+//	@Description	Возвращает комментарии верхнего уровня или, если передан parent_id, ответы на комментарий
+//	@Tags			ReviewComments
+//	@Produce		json
+//	@Param			reviewID	path		string	true	"ObjectID отзыва"
+//	@Param			parent_id	query		string	false	"ObjectID комментария, ответы на который нужны"
+//	@Param			after_id	query		string	false	"ObjectID последнего комментария (для пагинации)"
+//	@Param			limit		query		int		false	"Количество комментариев на страницу (по умолчанию 10)"
+//	@Success		200			{object}	dto.PaginatedReviewCommentsResponse
+//	@Failure		400			{object}	map[string]string	"Неверные параметры"
+//	@Failure		404			{object}	map[string]string	"Отзыв не найден"
+//	@Failure		500			{object}	map[string]string	"Ошибка сервера"
+//	@Router			/reviews/{reviewID}/comments [get]
+func (h *ReviewCommentHandler) GetComments(c *gin.Context) {
+	reviewID, err := primitive.ObjectIDFromHex(c.Param("reviewID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга reviewID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор отзыва"})
+		return
+	}
+
+	queryLimit := c.Query("limit")
+	limitInt, err := strconv.Atoi(queryLimit)
+	if err != nil {
+		h.log.Warnf("ошибка конвертации query limit=%s : %v", queryLimit, err)
+		limitInt = 10
+	}
+
+	var parentID *primitive.ObjectID
+	if queryParentID := c.Query("parent_id"); queryParentID != "" {
+		parsed, err := primitive.ObjectIDFromHex(queryParentID)
+		if err != nil {
+			h.log.Warnf("Ошибка парсинга parent_id: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный параметр parent_id"})
+			return
+		}
+		parentID = &parsed
+	}
+
+	var afterID *primitive.ObjectID
+	if queryAfterID := c.Query("after_id"); queryAfterID != "" {
+		parsed, err := primitive.ObjectIDFromHex(queryAfterID)
+		if err != nil {
+			h.log.Warnf("Ошибка парсинга after_id: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный параметр after_id"})
+			return
+		}
+		afterID = &parsed
+	}
+
+	comments, err := h.service.GetComments(reviewID, parentID, limitInt, afterID)
+	if err != nil {
+		h.log.Warnf("Ошибка получения комментариев: %v", err)
+		h.respondCommentError(c, err, "Отзыв не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+// CreateComment добавляет комментарий к отзыву
+//
+//	@Summary		Прокомментировать отзыв
+//	@Description	Добавляет комментарий к отзыву или ответ на другой комментарий
+//	@Tags			ReviewComments
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			reviewID	path		string							true	"ObjectID отзыва"
+//	@Param			comment		body		dto.CreateReviewCommentRequest	true	"Данные комментария"
+//	@Success		201			{object}	dto.ReviewCommentResponse
+//	@Failure		400			{object}	map[string]string	"Неверный формат запроса"
+//	@Failure		404			{object}	map[string]string	"Отзыв или комментарий не найден"
+//	@Failure		500			{object}	map[string]string	"Ошибка сервера"
+//	@Router			/reviews/{reviewID}/comments [post]
+func (h *ReviewCommentHandler) CreateComment(c *gin.Context) {
+	reviewID, err := primitive.ObjectIDFromHex(c.Param("reviewID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга reviewID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор отзыва"})
+		return
+	}
+
+	var req dto.CreateReviewCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warnf("Ошибка привязки JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	comment, err := h.service.CreateComment(reviewID, req, userID.(string))
+	if err != nil {
+		h.log.Warnf("Ошибка создания комментария: %v", err)
+		h.respondCommentError(c, err, "Отзыв или комментарий не найден")
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// UpdateComment редактирует комментарий
+//
+//	@Summary		Изменить комментарий
+//	@Description	Редактирует текст комментария (только автор, модератор или админ)
+//	@Tags			ReviewComments
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			reviewID	path		string							true	"ObjectID отзыва"
+//	@Param			commentID	path		string							true	"ObjectID комментария"
+//	@Param			comment		body		dto.UpdateReviewCommentRequest	true	"Новый текст"
+//	@Success		200			{object}	dto.ReviewCommentResponse
+//	@Failure		400			{object}	map[string]string	"Неверные параметры"
+//	@Failure		403			{object}	map[string]string	"Нет прав на редактирование"
+//	@Failure		404			{object}	map[string]string	"Комментарий не найден"
+//	@Router			/reviews/{reviewID}/comments/{commentID} [put]
+func (h *ReviewCommentHandler) UpdateComment(c *gin.Context) {
+	comment, ok := h.getOwnComment(c, "Нет прав на редактирование")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateReviewCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warnf("Ошибка привязки JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	updated, err := h.service.UpdateComment(comment, req.Text)
+	if err != nil {
+		h.log.Warnf("Ошибка обновления комментария: %v", err)
+		h.respondCommentError(c, err, "Комментарий не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteComment удаляет комментарий
+//
+//	@Summary		Удалить комментарий
+//	@Description	Удаляет комментарий (только автор, модератор или админ). Комментарий с ответами остается в ветке без текста
+//	@Tags			ReviewComments
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			reviewID	path		string				true	"ObjectID отзыва"
+//	@Param			commentID	path		string				true	"ObjectID комментария"
+//	@Success		200			{object}	map[string]string	"message: Комментарий удален"
+//	@Failure		400			{object}	map[string]string	"Неверные параметры"
+//	@Failure		403			{object}	map[string]string	"Нет прав на удаление"
+//	@Failure		404			{object}	map[string]string	"Комментарий не найден"
+//	@Router			/reviews/{reviewID}/comments/{commentID} [delete]
+func (h *ReviewCommentHandler) DeleteComment(c *gin.Context) {
+	comment, ok := h.getOwnComment(c, "Нет прав на удаление")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteComment(comment); err != nil {
+		h.log.Warnf("Ошибка удаления комментария: %v", err)
+		h.respondCommentError(c, err, "Комментарий не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Комментарий удален"})
+}
+
+// getOwnComment загружает комментарий из пути и проверяет, что его может менять текущий пользователь
+func (h *ReviewCommentHandler) getOwnComment(c *gin.Context, forbiddenMessage string) (*models.ReviewComment, bool) {
+	reviewID, err := primitive.ObjectIDFromHex(c.Param("reviewID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга reviewID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор отзыва"})
+		return nil, false
+	}
+
+	commentID, err := primitive.ObjectIDFromHex(c.Param("commentID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга commentID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор комментария"})
+		return nil, false
+	}
+
+	comment, err := h.service.GetCommentByID(reviewID, commentID)
+	if err != nil {
+		h.log.Warnf("Ошибка получения комментария: %v", err)
+		h.respondCommentError(c, err, "Комментарий не найден")
+		return nil, false
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	if comment.UserID != userID && role != models.RoleModerator && role != models.RoleAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": forbiddenMessage})
+		return nil, false
+	}
+
+	return comment, true
+}
+
+// respondCommentError переводит ошибку сервиса комментариев в HTTP-ответ
+func (h *ReviewCommentHandler) respondCommentError(c *gin.Context, err error, notFoundMessage string) {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFoundMessage})
+	case errors.Is(err, services.ErrEmptyCommentText),
+		errors.Is(err, services.ErrCommentDepthExceeded),
+		errors.Is(err, services.ErrParentCommentMismatch),
+		errors.Is(err, services.ErrInvalidParentCommentID):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCommentDeleted):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка обработки комментария"})
+	}
+}
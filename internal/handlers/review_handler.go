@@ -3,24 +3,30 @@ package handlers
 import (
 	"book-management-system/internal/dto"
 	"book-management-system/internal/models"
+	"book-management-system/internal/moderation"
 	"book-management-system/internal/services"
 	"book-management-system/pkg/logger"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
 	"net/http"
 	"slices"
 )
 
 type ReviewHandler struct {
-	service *services.ReviewService
-	log     *logger.Logger
+	service           *services.ReviewService
+	moderationService *services.ReviewModerationService
+	log               *logger.Logger
 }
 
 // NewReviewHandler создает новый экземпляр ReviewHandler
-func NewReviewHandler(service *services.ReviewService) *ReviewHandler {
+func NewReviewHandler(service *services.ReviewService, moderationService *services.ReviewModerationService) *ReviewHandler {
 	return &ReviewHandler{
-		service: service,
-		log:     logger.GetLogger(),
+		service:           service,
+		moderationService: moderationService,
+		log:               logger.GetLogger(),
 	}
 }
 
@@ -31,6 +37,7 @@ func NewReviewHandler(service *services.ReviewService) *ReviewHandler {
 //	@Tags			Reviews
 //	@Produce		json
 //	@Param			reviewID	path		string	true	"ObjectID отзыва"
+//	@Param			text_format	query		string	false	"Формат текста: source, html или both (по умолчанию)"
 //	@Success		200			{object}	dto.ReviewResponse
 //	@Failure		400			{object}	map[string]string	"Неверный ID"
 //	@Failure		404			{object}	map[string]string	"Отзыв не найден"
@@ -43,6 +50,12 @@ func (h *ReviewHandler) GetReviewByID(c *gin.Context) {
 		return
 	}
 
+	textFormat, err := services.ParseReviewTextFormat(c.Query("text_format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	review, err := h.service.GetReviewById(reviewID)
 	if err != nil {
 		h.log.Warnf("Ошибка получения отзыва: %v", err)
@@ -50,7 +63,7 @@ func (h *ReviewHandler) GetReviewByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, review)
+	c.JSON(http.StatusOK, services.ToReviewResponse(review, textFormat))
 }
 
 // CreateReview создаёт новый отзыв
@@ -64,6 +77,8 @@ func (h *ReviewHandler) GetReviewByID(c *gin.Context) {
 //	@Param			review	body		dto.BaseReviewRequest	true	"Данные для создания отзыва"
 //	@Success		201		{object}	dto.ReviewResponse
 //	@Failure		400		{object}	map[string]string	"Неверный формат запроса"
+//	@Failure		404		{object}	map[string]string	"Книга не найдена или не подтверждена"
+//	@Failure		409		{object}	map[string]string	"Отзыв на эту книгу уже есть"
 //	@Failure		500		{object}	map[string]string	"Ошибка сервера"
 //	@Router			/review [post]
 
@@ -79,16 +94,59 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 	if !userIdExists {
 		h.log.Warnf("Отсутствует создатель ревью")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Отсутствует создатель реаью"})
+		return
 	}
 	stringUserID := userID.(string)
 
-	if err := h.service.CreateReview(review, stringUserID); err != nil {
+	created, err := h.service.CreateReview(review, stringUserID)
+	if err != nil {
 		h.log.Warnf("Ошибка создания отзыва: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось создать отзыв"})
+		h.respondReviewError(c, err, "Не удалось создать отзыв")
+		return
+	}
+
+	c.JSON(http.StatusCreated, services.ToReviewResponse(created, services.ReviewTextBoth))
+}
+
+// UpsertMyReview создает или обновляет отзыв текущего пользователя на книгу
+//
+//	@Summary		Создать или обновить свой отзыв на книгу
+//	@Description	Если отзыва еще нет — создает его (201), иначе обновляет текст и оценку с сохранением версии (200)
+//	@Tags			Reviews
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			bookID	path		string					true	"UUID книги"
+//	@Param			review	body		dto.UpsertReviewRequest	true	"Текст и оценка"
+//	@Success		200		{object}	dto.ReviewResponse
+//	@Success		201		{object}	dto.ReviewResponse
+//	@Failure		400		{object}	map[string]string	"Неверный формат запроса"
+//	@Failure		404		{object}	map[string]string	"Книга не найдена или не подтверждена"
+//	@Failure		409		{object}	map[string]string	"Отзыв скрыт модератором"
+//	@Failure		500		{object}	map[string]string	"Ошибка сервера"
+//	@Router			/books/{bookID}/reviews/me [put]
+func (h *ReviewHandler) UpsertMyReview(c *gin.Context) {
+	var req dto.UpsertReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warnf("Ошибка привязки JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	review, created, err := h.service.UpsertUserReview(c.Param("bookID"), userID.(string), req.Text, req.Rating)
+	if err != nil {
+		h.log.Warnf("Ошибка сохранения отзыва на книгу: %v", err)
+		h.respondReviewError(c, err, "Не удалось сохранить отзыв")
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Отзыв успешно создан"})
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, services.ToReviewResponse(review, services.ReviewTextBoth))
 }
 
 // UpdateReview обновляет существующий отзыв
@@ -120,8 +178,8 @@ func (h *ReviewHandler) UpdateReview(c *gin.Context) {
 	stringifiedUserId := userID.(string)
 
 	var body struct {
-		Text   string `json:"text"`
-		Rating int    `json:"rating"`
+		Text   string `json:"text" binding:"required,max=10000"`
+		Rating int    `json:"rating" binding:"required,min=1,max=10"`
 	}
 	if err := c.ShouldBindJSON(&body); err != nil {
 		h.log.Warnf("Ошибка привязки JSON: %v", err)
@@ -143,13 +201,47 @@ func (h *ReviewHandler) UpdateReview(c *gin.Context) {
 
 	if err := h.service.UpdateReview(reviewID, body.Text, body.Rating, stringifiedUserId); err != nil {
 		h.log.Warnf("Ошибка обновления отзыва: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка обновления отзыва"})
+		h.respondReviewError(c, err, "Ошибка обновления отзыва")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Отзыв успешно обновлен"})
 }
 
+// GetReviewVersions получает историю правок отзыва
+//
+//	@Summary		История правок отзыва
+//	@Description	Возвращает все версии отзыва от первой к текущей с построчными отличиями каждой версии от предыдущей
+//	@Tags			Reviews
+//	@Produce		json
+//	@Param			reviewID	path		string	true	"ObjectID отзыва"
+//	@Success		200			{object}	dto.ReviewVersionsResponse
+//	@Failure		400			{object}	map[string]string	"Неверный ID"
+//	@Failure		404			{object}	map[string]string	"Отзыв не найден"
+//	@Failure		500			{object}	map[string]string	"Ошибка сервера"
+//	@Router			/reviews/{reviewID}/versions [get]
+func (h *ReviewHandler) GetReviewVersions(c *gin.Context) {
+	reviewID, err := primitive.ObjectIDFromHex(c.Param("reviewID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга reviewID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор отзыва"})
+		return
+	}
+
+	versions, err := h.service.GetReviewVersions(reviewID)
+	if err != nil {
+		h.log.Warnf("Ошибка получения истории отзыва: %v", err)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Отзыв не найден"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка получения истории отзыва"})
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
 // VoteReview обрабатывает запрос на добавление отзыва
 //
 //	@Summary		Проголосовать за отзыв
@@ -190,6 +282,10 @@ func (h *ReviewHandler) VoteReview(c *gin.Context) {
 	}
 
 	if err := h.service.VoteReview(reviewID, stringifiedUserId, body.Vote); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Отзыв не найден"})
+			return
+		}
 		h.log.Warnf("Ошибка голосования за отзыв: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка голосования"})
 		return
@@ -234,6 +330,25 @@ func (h *ReviewHandler) DeleteReview(c *gin.Context) {
 		return
 	}
 
+	// Чужой отзыв модератор удаляет мягко и с записью в журнал, чтобы не терять доказательства
+	if review.UserID != userID {
+		moderatorID, err := currentUserID(c)
+		if err != nil {
+			h.log.Warnf("Ошибка идентификации модератора: %v", err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+			return
+		}
+
+		if err := h.moderationService.DeleteReview(reviewID, moderatorID, ""); err != nil {
+			h.log.Warnf("Ошибка удаления отзыва модератором: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления отзыва"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Отзыв успешно удален"})
+		return
+	}
+
 	if err := h.service.DeleteReviewByID(reviewID); err != nil {
 		h.log.Warnf("Ошибка удаления отзыва: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления отзыва"})
@@ -242,3 +357,21 @@ func (h *ReviewHandler) DeleteReview(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Отзыв успешно удален"})
 }
+
+// respondReviewError переводит ошибку сервиса отзывов в HTTP-ответ
+func (h *ReviewHandler) respondReviewError(c *gin.Context, err error, internalMessage string) {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Отзыв не найден"})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Книга не найдена или не подтверждена"})
+	case errors.Is(err, services.ErrInvalidReviewBookID),
+		errors.Is(err, moderation.ErrRejected):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrReviewAlreadyExists),
+		errors.Is(err, services.ErrReviewHiddenByModerator):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": internalMessage})
+	}
+}
@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"strconv"
+)
+
+type ReviewModerationHandler struct {
+	service *services.ReviewModerationService
+	log     *logger.Logger
+}
+
+// NewReviewModerationHandler создает обработчик жалоб и модерации отзывов
+func NewReviewModerationHandler(service *services.ReviewModerationService) *ReviewModerationHandler {
+	return &ReviewModerationHandler{
+		service: service,
+		log:     logger.GetLogger(),
+	}
+}
+
+// ReportReview отправляет жалобу на отзыв
+//
+//	@Summary		Пожаловаться на отзыв
+//	@Description	Отправляет жалобу на отзыв (spam, spoilers, abuse). После порога жалоб отзыв скрывается до решения модератора
+//	@Tags			ReviewModeration
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			reviewID	path		string					true	"ObjectID отзыва"
+//	@Param			report		body		dto.ReportReviewRequest	true	"Причина жалобы"
+//	@Success		201			{object}	map[string]string		"message: Жалоба отправлена"
+//	@Failure		400			{object}	map[string]string		"Неверный формат запроса"
+//	@Failure		404			{object}	map[string]string		"Отзыв не найден"
+//	@Failure		409			{object}	map[string]string		"Жалоба уже отправлена"
+//	@Failure		500			{object}	map[string]string		"Ошибка сервера"
+//	@Router			/reviews/{reviewID}/report [post]
+func (h *ReviewModerationHandler) ReportReview(c *gin.Context) {
+	reviewID, ok := h.parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReportReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warnf("Ошибка привязки JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	if err := h.service.ReportReview(reviewID, userID.(string), req); err != nil {
+		h.log.Warnf("Ошибка отправки жалобы на отзыв: %v", err)
+		h.respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Жалоба отправлена"})
+}
+
+// GetQueue получает очередь отзывов на модерацию
+//
+//	@Summary		Очередь модерации отзывов
+//	@Description	Возвращает отзывы с жалобами; status позволяет посмотреть уже одобренные, скрытые или удаленные
+//	@Tags			ReviewModeration
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			status		query		string	false	"pending (по умолчанию), approved, hidden, deleted"
+//	@Param			after_id	query		string	false	"ObjectID последнего отзыва (для пагинации)"
+//	@Param			limit		query		int		false	"Количество отзывов на страницу (по умолчанию 10)"
+//	@Success		200			{object}	dto.PaginatedModerationReviewsResponse
+//	@Failure		400			{object}	map[string]string	"Неверные параметры"
+//	@Failure		403			{object}	map[string]string	"Доступ запрещен"
+//	@Failure		500			{object}	map[string]string	"Ошибка сервера"
+//	@Router			/moderation/reviews [get]
+func (h *ReviewModerationHandler) GetQueue(c *gin.Context) {
+	queryLimit := c.Query("limit")
+	limitInt, err := strconv.Atoi(queryLimit)
+	if err != nil {
+		h.log.Warnf("ошибка конвертации query limit=%s : %v", queryLimit, err)
+		limitInt = 10
+	}
+
+	var afterID *primitive.ObjectID
+	if queryAfterID := c.Query("after_id"); queryAfterID != "" {
+		parsed, err := primitive.ObjectIDFromHex(queryAfterID)
+		if err != nil {
+			h.log.Warnf("Ошибка парсинга after_id: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный параметр after_id"})
+			return
+		}
+		afterID = &parsed
+	}
+
+	queue, err := h.service.GetQueue(c.Query("status"), limitInt, afterID)
+	if err != nil {
+		h.log.Warnf("Ошибка получения очереди модерации: %v", err)
+		h.respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// GetReviewDetails получает отзыв со всеми жалобами и журналом решений
+//
+//	@Summary		Карточка отзыва для модератора
+//	@Description	Возвращает отзыв (даже скрытый или удаленный), все жалобы на него и историю решений модераторов
+//	@Tags			ReviewModeration
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			reviewID	path		string	true	"ObjectID отзыва"
+//	@Success		200			{object}	dto.ReviewModerationDetailsResponse
+//	@Failure		400			{object}	map[string]string	"Неверный ID"
+//	@Failure		404			{object}	map[string]string	"Отзыв не найден"
+//	@Router			/moderation/reviews/{reviewID} [get]
+func (h *ReviewModerationHandler) GetReviewDetails(c *gin.Context) {
+	reviewID, ok := h.parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	details, err := h.service.GetReviewDetails(reviewID)
+	if err != nil {
+		h.log.Warnf("Ошибка получения карточки модерации отзыва: %v", err)
+		h.respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+// ApproveReview одобряет отзыв
+//
+//	@Summary		Одобрить отзыв
+//	@Description	Делает отзыв снова видимым и закрывает открытые жалобы
+//	@Tags			ReviewModeration
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			reviewID	path		string							true	"ObjectID отзыва"
+//	@Param			decision	body		dto.ModerationDecisionRequest	false	"Комментарий модератора"
+//	@Success		200			{object}	map[string]string				"message: Отзыв одобрен"
+//	@Failure		400			{object}	map[string]string				"Неверные параметры"
+//	@Failure		404			{object}	map[string]string				"Отзыв не найден"
+//	@Router			/moderation/reviews/{reviewID}/approve [post]
+func (h *ReviewModerationHandler) ApproveReview(c *gin.Context) {
+	h.decide(c, h.service.ApproveReview, "Отзыв одобрен")
+}
+
+// HideReview скрывает отзыв
+//
+//	@Summary		Скрыть отзыв
+//	@Description	Скрывает отзыв от читателей и исключает его из рейтинга, сохраняя для истории
+//	@Tags			ReviewModeration
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			reviewID	path		string							true	"ObjectID отзыва"
+//	@Param			decision	body		dto.ModerationDecisionRequest	false	"Комментарий модератора"
+//	@Success		200			{object}	map[string]string				"message: Отзыв скрыт"
+//	@Failure		400			{object}	map[string]string				"Неверные параметры"
+//	@Failure		404			{object}	map[string]string				"Отзыв не найден"
+//	@Router			/moderation/reviews/{reviewID}/hide [post]
+func (h *ReviewModerationHandler) HideReview(c *gin.Context) {
+	h.decide(c, h.service.HideReview, "Отзыв скрыт")
+}
+
+// DeleteReview мягко удаляет отзыв
+//
+//	@Summary		Удалить отзыв (модерация)
+//	@Description	Помечает отзыв удаленным; текст, жалобы и журнал решений сохраняются
+//	@Tags			ReviewModeration
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			reviewID	path		string							true	"ObjectID отзыва"
+//	@Param			decision	body		dto.ModerationDecisionRequest	false	"Комментарий модератора"
+//	@Success		200			{object}	map[string]string				"message: Отзыв удален"
+//	@Failure		400			{object}	map[string]string				"Неверные параметры"
+//	@Failure		404			{object}	map[string]string				"Отзыв не найден"
+//	@Router			/moderation/reviews/{reviewID}/delete [post]
+func (h *ReviewModerationHandler) DeleteReview(c *gin.Context) {
+	h.decide(c, h.service.DeleteReview, "Отзыв удален")
+}
+
+// RestoreReviewVersion откатывает отзыв к одной из прошлых версий
+//
+//	@Summary		Восстановить версию отзыва
+//	@Description	Возвращает отзыву текст и оценку указанной версии. Текущее состояние сохраняется в истории как новая версия
+//	@Tags			ReviewModeration
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			reviewID	path		string							true	"ObjectID отзыва"
+//	@Param			number		path		int								true	"Номер версии из истории правок"
+//	@Param			decision	body		dto.ModerationDecisionRequest	false	"Комментарий модератора"
+//	@Success		200			{object}	map[string]string				"message: Версия отзыва восстановлена"
+//	@Failure		400			{object}	map[string]string				"Неверные параметры"
+//	@Failure		403			{object}	map[string]string				"Доступ запрещен"
+//	@Failure		404			{object}	map[string]string				"Отзыв или версия не найдены"
+//	@Router			/reviews/{reviewID}/versions/{number}/restore [post]
+func (h *ReviewModerationHandler) RestoreReviewVersion(c *gin.Context) {
+	reviewID, ok := h.parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга номера версии: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный номер версии"})
+		return
+	}
+
+	moderatorID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка идентификации модератора: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
+
+	req, ok := h.bindDecision(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RestoreReviewVersion(reviewID, number, moderatorID, req.Comment); err != nil {
+		h.log.Warnf("Ошибка восстановления версии отзыва: %v", err)
+		h.respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Версия отзыва восстановлена"})
+}
+
+// decide разбирает запрос решения модератора и применяет его
+func (h *ReviewModerationHandler) decide(
+	c *gin.Context,
+	apply func(reviewID primitive.ObjectID, moderatorID uuid.UUID, comment string) error,
+	successMessage string,
+) {
+	reviewID, ok := h.parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	moderatorID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка идентификации модератора: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
+
+	req, ok := h.bindDecision(c)
+	if !ok {
+		return
+	}
+
+	if err := apply(reviewID, moderatorID, req.Comment); err != nil {
+		h.log.Warnf("Ошибка применения решения модератора: %v", err)
+		h.respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": successMessage})
+}
+
+// bindDecision читает необязательное тело решения: комментарий модератора можно не оставлять
+func (h *ReviewModerationHandler) bindDecision(c *gin.Context) (dto.ModerationDecisionRequest, bool) {
+	var req dto.ModerationDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.log.Warnf("Ошибка привязки JSON: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+			return req, false
+		}
+	}
+	return req, true
+}
+
+func (h *ReviewModerationHandler) parseReviewID(c *gin.Context) (primitive.ObjectID, bool) {
+	reviewID, err := primitive.ObjectIDFromHex(c.Param("reviewID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга reviewID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор отзыва"})
+		return primitive.NilObjectID, false
+	}
+	return reviewID, true
+}
+
+// respondModerationError переводит ошибку сервиса модерации в HTTP-ответ
+func (h *ReviewModerationHandler) respondModerationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Отзыв не найден"})
+	case errors.Is(err, services.ErrReviewVersionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyReported):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidReportReason),
+		errors.Is(err, services.ErrCannotReportOwnReview),
+		errors.Is(err, services.ErrInvalidModerationStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка модерации отзыва"})
+	}
+}
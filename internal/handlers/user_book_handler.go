@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"book-management-system/internal/dto"
-	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
 	"book-management-system/internal/services"
 	"book-management-system/pkg/logger"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"net/http"
 )
 
@@ -35,7 +37,10 @@ func NewUserBookHandler(service *services.UserBookService) *UserBookHandler {
 //	@Failure		500		{object}	map[string]string	"Ошибка сервера"
 //	@Router			/users/me/books/ [post]
 func (h *UserBookHandler) AddBookToUser(c *gin.Context) {
-	userID, _ := c.Get("userID")
+	userID, ok := userBookUserID(c)
+	if !ok {
+		return
+	}
 	var req dto.AddBookRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -44,7 +49,7 @@ func (h *UserBookHandler) AddBookToUser(c *gin.Context) {
 		return
 	}
 
-	err := h.service.AddBookToUser(userID.(uuid.UUID), req.BookID)
+	err := h.service.AddBookToUser(userID, req.BookID)
 	if err != nil {
 		log.Warnf("Ошибка добавления книги пользователю: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при добавлении книги"})
@@ -57,19 +62,24 @@ func (h *UserBookHandler) AddBookToUser(c *gin.Context) {
 // UpdateReadingProgress обновляет статус и прогресс чтения книги
 //
 //	@Summary		Обновить прогресс чтения
-//	@Description	Обновляет статус и количество прочитанных страниц
+//	@Description	Обновляет статус и прогресс чтения в страницах, процентах или позициях электронной книги.
+//	@Description	Страницы ограничиваются объемом книги, по достижении конца статус меняется на completed
 //	@Tags			UserBooks
 //	@Security		BearerAuth
 //	@Accept			json
 //	@Produce		json
 //	@Param			bookID		path		string								true	"UUID книги"
 //	@Param			progress	body		dto.UpdateReadingProgressRequest	true	"Прогресс чтения"
-//	@Success		200			{object}	map[string]string					"message: Прогресс чтения обновлен"
-//	@Failure		400			{object}	map[string]string					"Неверный формат запроса"
-//	@Failure		500			{object}	map[string]string					"Ошибка сервера"
+//	@Success		200			{object}	dto.UserBookResponse
+//	@Failure		400			{object}	map[string]string	"Неверный формат запроса"
+//	@Failure		404			{object}	map[string]string	"Книги нет в списке пользователя"
+//	@Failure		500			{object}	map[string]string	"Ошибка сервера"
 //	@Router			/users/me/books/{bookID}/progress [put]
 func (h *UserBookHandler) UpdateReadingProgress(c *gin.Context) {
-	userID, _ := c.Get("userID")
+	userID, ok := userBookUserID(c)
+	if !ok {
+		return
+	}
 	bookID, err := uuid.Parse(c.Param("bookID"))
 	if err != nil {
 		log.Warnf("Ошибка парсинга bookID: %v", err)
@@ -85,14 +95,14 @@ func (h *UserBookHandler) UpdateReadingProgress(c *gin.Context) {
 		return
 	}
 
-	err = h.service.UpdateReadingProgress(userID.(uuid.UUID), bookID, models.ReadingStatus(req.Status), req.PagesRead)
+	userBook, err := h.service.UpdateReadingProgress(userID, bookID, req)
 	if err != nil {
 		log.Warnf("Ошибка обновления прогресса чтения: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении прогресса"})
+		respondUserBookError(c, err, "Ошибка при обновлении прогресса")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Прогресс чтения обновлен"})
+	c.JSON(http.StatusOK, userBook)
 }
 
 // RemoveBookFromUser удаляет книгу из списка пользователя
@@ -108,7 +118,10 @@ func (h *UserBookHandler) UpdateReadingProgress(c *gin.Context) {
 //	@Failure		500		{object}	map[string]string	"Ошибка сервера"
 //	@Router			/users/me/books/{bookID} [delete]
 func (h *UserBookHandler) RemoveBookFromUser(c *gin.Context) {
-	userID, _ := c.Get("userID")
+	userID, ok := userBookUserID(c)
+	if !ok {
+		return
+	}
 	bookID, err := uuid.Parse(c.Param("bookID"))
 	if err != nil {
 		log.Warnf("Ошибка парсинга bookID: %v", err)
@@ -116,7 +129,7 @@ func (h *UserBookHandler) RemoveBookFromUser(c *gin.Context) {
 		return
 	}
 
-	err = h.service.RemoveBookFromUser(userID.(uuid.UUID), bookID)
+	err = h.service.RemoveBookFromUser(userID, bookID)
 	if err != nil {
 		log.Warnf("Ошибка удаления книги из списка пользователя: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении книги"})
@@ -137,9 +150,12 @@ func (h *UserBookHandler) RemoveBookFromUser(c *gin.Context) {
 //	@Failure		500	{object}	map[string]string		"Ошибка сервера"
 //	@Router			/users/me/books [get]
 func (h *UserBookHandler) GetUserBooks(c *gin.Context) {
-	userID, _ := c.Get("userID")
+	userID, ok := userBookUserID(c)
+	if !ok {
+		return
+	}
 
-	books, err := h.service.GetUserBooks(userID.(uuid.UUID))
+	books, err := h.service.GetUserBooks(userID)
 	if err != nil {
 		log.Warnf("Ошибка получения списка книг пользователя: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении списка книг"})
@@ -148,3 +164,192 @@ func (h *UserBookHandler) GetUserBooks(c *gin.Context) {
 
 	c.JSON(http.StatusOK, books)
 }
+
+// GetReadingHistory получает историю прочтений книги
+//
+//	@Summary		Получить историю прочтений книги
+//	@Description	Возвращает все прочтения книги пользователем с датами, статусом и оценкой
+//	@Tags			UserBooks
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			bookID	path		string	true	"UUID книги"
+//	@Success		200		{array}		dto.ReadThroughResponse
+//	@Failure		400		{object}	map[string]string	"Неверный идентификатор книги"
+//	@Failure		404		{object}	map[string]string	"Книги нет в списке пользователя"
+//	@Failure		500		{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me/books/{bookID}/history [get]
+func (h *UserBookHandler) GetReadingHistory(c *gin.Context) {
+	userID, ok := userBookUserID(c)
+	if !ok {
+		return
+	}
+	bookID, err := uuid.Parse(c.Param("bookID"))
+	if err != nil {
+		log.Warnf("Ошибка парсинга bookID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор книги"})
+		return
+	}
+
+	history, err := h.service.GetReadingHistory(userID, bookID)
+	if err != nil {
+		log.Warnf("Ошибка получения истории прочтений: %v", err)
+		respondUserBookError(c, err, "Ошибка при получении истории прочтений")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// StartReread начинает повторное прочтение книги
+//
+//	@Summary		Начать повторное прочтение
+//	@Description	Открывает новое прочтение уже прочитанной или брошенной книги
+//	@Tags			UserBooks
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			bookID	path		string					true	"UUID книги"
+//	@Param			reread	body		dto.StartRereadRequest	false	"Дата начала прочтения"
+//	@Success		201		{object}	dto.ReadThroughResponse
+//	@Failure		400		{object}	map[string]string	"Неверный формат запроса"
+//	@Failure		404		{object}	map[string]string	"Книги нет в списке пользователя"
+//	@Failure		409		{object}	map[string]string	"Текущее прочтение еще не завершено"
+//	@Failure		500		{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me/books/{bookID}/reread [post]
+func (h *UserBookHandler) StartReread(c *gin.Context) {
+	userID, ok := userBookUserID(c)
+	if !ok {
+		return
+	}
+	bookID, err := uuid.Parse(c.Param("bookID"))
+	if err != nil {
+		log.Warnf("Ошибка парсинга bookID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор книги"})
+		return
+	}
+
+	var req dto.StartRereadRequest
+	// Тело необязательно: без него прочтение начинается сейчас
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Warnf("Ошибка привязки JSON: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+			return
+		}
+	}
+
+	readThrough, err := h.service.StartReread(userID, bookID, req.StartedAt)
+	if err != nil {
+		log.Warnf("Ошибка начала повторного прочтения: %v", err)
+		respondUserBookError(c, err, "Ошибка при начале повторного прочтения")
+		return
+	}
+
+	c.JSON(http.StatusCreated, readThrough)
+}
+
+// UpdateReadThrough правит прочтение из истории
+//
+//	@Summary		Изменить прочтение
+//	@Description	Исправляет даты начала и окончания или оценку прочтения
+//	@Tags			UserBooks
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			bookID			path		string							true	"UUID книги"
+//	@Param			readThroughID	path		string							true	"UUID прочтения"
+//	@Param			readThrough		body		dto.UpdateReadThroughRequest	true	"Новые данные прочтения"
+//	@Success		200				{object}	dto.ReadThroughResponse
+//	@Failure		400				{object}	map[string]string	"Неверный формат запроса"
+//	@Failure		404				{object}	map[string]string	"Прочтение не найдено"
+//	@Failure		500				{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me/books/{bookID}/history/{readThroughID} [put]
+func (h *UserBookHandler) UpdateReadThrough(c *gin.Context) {
+	userID, ok := userBookUserID(c)
+	if !ok {
+		return
+	}
+	bookID, err := uuid.Parse(c.Param("bookID"))
+	if err != nil {
+		log.Warnf("Ошибка парсинга bookID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор книги"})
+		return
+	}
+	readThroughID, err := uuid.Parse(c.Param("readThroughID"))
+	if err != nil {
+		log.Warnf("Ошибка парсинга readThroughID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор прочтения"})
+		return
+	}
+
+	var req dto.UpdateReadThroughRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnf("Ошибка привязки JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	readThrough, err := h.service.UpdateReadThrough(userID, bookID, readThroughID, req)
+	if err != nil {
+		log.Warnf("Ошибка обновления прочтения: %v", err)
+		respondUserBookError(c, err, "Ошибка при обновлении прочтения")
+		return
+	}
+
+	c.JSON(http.StatusOK, readThrough)
+}
+
+// GetStats получает статистику чтения пользователя
+//
+//	@Summary		Получить статистику чтения
+//	@Description	Возвращает количество книг по статусам, число прочтений и повторных прочтений
+//	@Tags			UserBooks
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	dto.UserBookStatsResponse
+//	@Failure		500	{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me/books/stats [get]
+func (h *UserBookHandler) GetStats(c *gin.Context) {
+	userID, ok := userBookUserID(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.service.GetStats(userID)
+	if err != nil {
+		log.Warnf("Ошибка получения статистики чтения: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении статистики"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// userBookUserID достает ID текущего пользователя и отвечает 401, если его нет
+func userBookUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		log.Warnf("Ошибка идентификации пользователя: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// respondUserBookError переводит ошибку сервиса списка книг в HTTP-ответ
+func respondUserBookError(c *gin.Context, err error, internalMessage string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Книга или прочтение не найдены в списке пользователя"})
+	case errors.Is(err, repositories.ErrReadThroughInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidRating),
+		errors.Is(err, services.ErrInvalidReadThroughDates),
+		errors.Is(err, services.ErrInvalidReadingStatus),
+		errors.Is(err, services.ErrInvalidProgressUnit),
+		errors.Is(err, services.ErrInvalidProgress):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": internalMessage})
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+type UserDataHandler struct {
+	service *services.UserDataService
+	log     *logger.Logger
+}
+
+// NewUserDataHandler создает обработчик запросов на выгрузку и удаление персональных данных
+func NewUserDataHandler(service *services.UserDataService) *UserDataHandler {
+	return &UserDataHandler{
+		service: service,
+		log:     logger.GetLogger(),
+	}
+}
+
+// ExportMyData выгружает все данные текущего пользователя
+//
+//	@Summary		Выгрузить мои данные
+//	@Description	Формирует ZIP-архив со всеми данными пользователя из PostgreSQL и MongoDB
+//	@Tags			Users
+//	@Security		BearerAuth
+//	@Produce		application/zip
+//	@Success		200	{file}		file				"ZIP-архив с данными"
+//	@Failure		401	{object}	map[string]string	"Пользователь не аутентифицирован"
+//	@Failure		404	{object}	map[string]string	"Пользователь не найден"
+//	@Router			/users/me/data-export [post]
+func (h *UserDataHandler) ExportMyData(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка идентификации пользователя: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
+
+	h.exportUserData(c, userID)
+}
+
+// DeleteMe удаляет аккаунт текущего пользователя
+//
+//	@Summary		Удалить мой аккаунт
+//	@Description	Анонимизирует отзывы, удаляет голоса и фидбэк, отзывает токены и удаляет пользователя
+//	@Tags			Users
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	map[string]string	"message: Аккаунт удален"
+//	@Failure		401	{object}	map[string]string	"Пользователь не аутентифицирован"
+//	@Failure		404	{object}	map[string]string	"Пользователь не найден"
+//	@Failure		500	{object}	map[string]string	"Ошибка сервера"
+//	@Router			/users/me [delete]
+func (h *UserDataHandler) DeleteMe(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка идентификации пользователя: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
+
+	h.eraseUser(c, userID)
+}
+
+// AdminExportUserData выгружает данные произвольного пользователя (только для админов)
+//
+//	@Summary		Выгрузить данные пользователя
+//	@Description	Формирует ZIP-архив со всеми данными указанного пользователя
+//	@Tags			Admin
+//	@Security		BearerAuth
+//	@Produce		application/zip
+//	@Param			userID	path		string				true	"UUID пользователя"
+//	@Success		200		{file}		file				"ZIP-архив с данными"
+//	@Failure		400		{object}	map[string]string	"Неверный идентификатор пользователя"
+//	@Failure		404		{object}	map[string]string	"Пользователь не найден"
+//	@Router			/admin/users/{userID}/data-export [post]
+func (h *UserDataHandler) AdminExportUserData(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга userID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор пользователя"})
+		return
+	}
+
+	h.exportUserData(c, userID)
+}
+
+// AdminDeleteUser удаляет аккаунт произвольного пользователя (только для админов)
+//
+//	@Summary		Удалить пользователя
+//	@Description	Анонимизирует отзывы, удаляет голоса и фидбэк, отзывает токены и удаляет указанного пользователя
+//	@Tags			Admin
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			userID	path		string				true	"UUID пользователя"
+//	@Success		200		{object}	map[string]string	"message: Аккаунт удален"
+//	@Failure		400		{object}	map[string]string	"Неверный идентификатор пользователя"
+//	@Failure		404		{object}	map[string]string	"Пользователь не найден"
+//	@Failure		500		{object}	map[string]string	"Ошибка сервера"
+//	@Router			/admin/users/{userID} [delete]
+func (h *UserDataHandler) AdminDeleteUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга userID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор пользователя"})
+		return
+	}
+
+	h.eraseUser(c, userID)
+}
+
+func (h *UserDataHandler) exportUserData(c *gin.Context, userID uuid.UUID) {
+	fileName := fmt.Sprintf("user-data-%s-%s.zip", userID, time.Now().UTC().Format("20060102"))
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+
+	// Архив собирается в буфер ответа: до первой записи еще можно вернуть 404/500
+	if err := h.service.ExportUserData(userID, c.Writer); err != nil {
+		h.log.Warnf("Ошибка выгрузки данных пользователя %s: %v", userID, err)
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+
+		c.Header("Content-Disposition", "")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Пользователь не найден"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка выгрузки данных"})
+	}
+}
+
+func (h *UserDataHandler) eraseUser(c *gin.Context, userID uuid.UUID) {
+	if err := h.service.EraseUser(userID); err != nil {
+		h.log.Warnf("Ошибка удаления пользователя %s: %v", userID, err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Пользователь не найден"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка удаления аккаунта"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Аккаунт удален"})
+}
@@ -120,6 +120,35 @@ func (h *UserHandler) RefreshToken(c *gin.Context) {
 	})
 }
 
+// GetUserProfile получает публичный профиль пользователя
+//
+//	@Summary		Профиль пользователя
+//	@Description	Возвращает имя пользователя, дату регистрации и репутацию
+//	@Tags			Users
+//	@Produce		json
+//	@Param			userID	path		string	true	"UUID пользователя"
+//	@Success		200		{object}	dto.PublicProfileResponse
+//	@Failure		400		{object}	map[string]string	"Неверный ID"
+//	@Failure		404		{object}	map[string]string	"Пользователь не найден"
+//	@Router			/users/{userID}/profile [get]
+func (h *UserHandler) GetUserProfile(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга userID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор пользователя"})
+		return
+	}
+
+	profile, err := h.service.GetPublicProfile(userID)
+	if err != nil {
+		h.log.Warnf("Ошибка получения профиля пользователя %s: %v", userID, err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "Пользователь не найден"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
 // GetCurrentUser получает информацию о текущем пользователе
 //
 //	@Summary		Информация о текущем пользователе
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/services"
+	"book-management-system/pkg/logger"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+)
+
+type WebhookHandler struct {
+	service *services.WebhookService
+	log     *logger.Logger
+}
+
+// NewWebhookHandler создает обработчик вебхуков
+func NewWebhookHandler(service *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+		log:     logger.GetLogger(),
+	}
+}
+
+// CreateWebhook регистрирует вебхук
+//
+//	@Summary		Зарегистрировать вебхук
+//	@Description	События отправляются POST-запросом с подписью X-Webhook-Signature (HMAC-SHA256), неудачные доставки повторяются с экспоненциальной задержкой
+//	@Tags			Webhooks
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			webhook	body		dto.CreateWebhookRequest	true	"Адрес и события"
+//	@Success		201		{object}	dto.WebhookResponse
+//	@Failure		400		{object}	map[string]string	"Неверный адрес или событие"
+//	@Failure		500		{object}	map[string]string	"Ошибка сервера"
+//	@Router			/admin/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	adminID, err := currentUserID(c)
+	if err != nil {
+		h.log.Warnf("Ошибка получения userID: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован"})
+		return
+	}
+
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warnf("Ошибка привязки JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	webhook, err := h.service.CreateWebhook(req, adminID)
+	if err != nil {
+		h.respondWebhookError(c, err, "Ошибка при создании вебхука")
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// GetWebhooks получает все вебхуки
+//
+//	@Summary		Список вебхуков
+//	@Tags			Webhooks
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{array}		dto.WebhookResponse
+//	@Failure		500	{object}	map[string]string	"Ошибка сервера"
+//	@Router			/admin/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	webhooks, err := h.service.GetWebhooks()
+	if err != nil {
+		h.respondWebhookError(c, err, "Ошибка при получении вебхуков")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// GetWebhook получает вебхук
+//
+//	@Summary		Вебхук
+//	@Tags			Webhooks
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			webhookID	path		string	true	"UUID вебхука"
+//	@Success		200			{object}	dto.WebhookResponse
+//	@Failure		400			{object}	map[string]string	"Неверный ID"
+//	@Failure		404			{object}	map[string]string	"Вебхук не найден"
+//	@Router			/admin/webhooks/{webhookID} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	webhookID, ok := h.parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.service.GetWebhook(webhookID)
+	if err != nil {
+		h.respondWebhookError(c, err, "Ошибка при получении вебхука")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// UpdateWebhook меняет вебхук
+//
+//	@Summary		Изменить вебхук
+//	@Tags			Webhooks
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			webhookID	path		string						true	"UUID вебхука"
+//	@Param			webhook		body		dto.UpdateWebhookRequest	true	"Адрес, события и включенность"
+//	@Success		200			{object}	dto.WebhookResponse
+//	@Failure		400			{object}	map[string]string	"Неверный адрес или событие"
+//	@Failure		404			{object}	map[string]string	"Вебхук не найден"
+//	@Router			/admin/webhooks/{webhookID} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	webhookID, ok := h.parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warnf("Ошибка привязки JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат запроса"})
+		return
+	}
+
+	webhook, err := h.service.UpdateWebhook(webhookID, req)
+	if err != nil {
+		h.respondWebhookError(c, err, "Ошибка при обновлении вебхука")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook удаляет вебхук
+//
+//	@Summary		Удалить вебхук
+//	@Description	Удаляет вебхук вместе с журналом доставок
+//	@Tags			Webhooks
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			webhookID	path		string				true	"UUID вебхука"
+//	@Success		200			{object}	map[string]string	"Вебхук удален"
+//	@Failure		400			{object}	map[string]string	"Неверный ID"
+//	@Failure		404			{object}	map[string]string	"Вебхук не найден"
+//	@Router			/admin/webhooks/{webhookID} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	webhookID, ok := h.parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteWebhook(webhookID); err != nil {
+		h.respondWebhookError(c, err, "Ошибка при удалении вебхука")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Вебхук удален"})
+}
+
+// GetWebhookDeliveries получает журнал доставок вебхука
+//
+//	@Summary		Журнал доставок вебхука
+//	@Description	Доставки новые первыми: статус, число попыток, ответ получателя и время следующей попытки
+//	@Tags			Webhooks
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			webhookID	path		string	true	"UUID вебхука"
+//	@Param			after_id	query		string	false	"UUID последней доставки (для пагинации)"
+//	@Param			limit		query		int		false	"Количество доставок на страницу (по умолчанию 10)"
+//	@Success		200			{object}	dto.PaginatedWebhookDeliveriesResponse
+//	@Failure		400			{object}	map[string]string	"Неверный ID"
+//	@Failure		404			{object}	map[string]string	"Вебхук не найден"
+//	@Router			/admin/webhooks/{webhookID}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	webhookID, ok := h.parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	queryLimit := c.Query("limit")
+	limitInt, err := strconv.Atoi(queryLimit)
+	if err != nil {
+		h.log.Warnf("ошибка конвертации query limit=%s : %v", queryLimit, err)
+		limitInt = 10
+	}
+
+	var afterUUID *uuid.UUID
+	if queryAfterId := c.Query("after_id"); queryAfterId != "" {
+		parsedID, err := uuid.Parse(queryAfterId)
+		if err != nil {
+			h.log.Warnf("Ошибка парсинга after_id: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный параметр after_id"})
+			return
+		}
+		afterUUID = &parsedID
+	}
+
+	deliveries, err := h.service.GetDeliveries(webhookID, limitInt, afterUUID)
+	if err != nil {
+		h.respondWebhookError(c, err, "Ошибка при получении журнала доставок")
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// TestWebhook отправляет тестовое событие
+//
+//	@Summary		Проверить вебхук
+//	@Description	Сразу отправляет событие webhook.test без повторов и возвращает результат доставки
+//	@Tags			Webhooks
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			webhookID	path		string	true	"UUID вебхука"
+//	@Success		200			{object}	dto.WebhookDeliveryResponse
+//	@Failure		400			{object}	map[string]string	"Неверный ID"
+//	@Failure		404			{object}	map[string]string	"Вебхук не найден"
+//	@Router			/admin/webhooks/{webhookID}/test [post]
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	webhookID, ok := h.parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.TestWebhook(webhookID)
+	if err != nil {
+		h.respondWebhookError(c, err, "Ошибка при проверке вебхука")
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+func (h *WebhookHandler) parseWebhookID(c *gin.Context) (uuid.UUID, bool) {
+	webhookID, err := uuid.Parse(c.Param("webhookID"))
+	if err != nil {
+		h.log.Warnf("Ошибка парсинга webhookID: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный идентификатор вебхука"})
+		return uuid.Nil, false
+	}
+	return webhookID, true
+}
+
+func (h *WebhookHandler) respondWebhookError(c *gin.Context, err error, internalMessage string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Вебхук не найден"})
+	case errors.Is(err, services.ErrInvalidWebhookURL), errors.Is(err, services.ErrUnknownWebhookEvent),
+		errors.Is(err, services.ErrWebhookPrivateAddress):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.log.Warnf("%s: %v", internalMessage, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": internalMessage})
+	}
+}
@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvColumns колонки CSV; заголовок обязателен, регистр не важен, неизвестные колонки игнорируются.
+// Авторы и жанры перечисляются в одной ячейке через точку с запятой
+var csvColumns = []string{"title", "authors", "isbn", "description", "page_count", "publisher", "published_year", "cover_image", "genres"}
+
+func parseCSV(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("пустой CSV-файл")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать заголовок CSV: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, known := columns[name]; !known {
+			columns[name] = i
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("в заголовке CSV нет колонки title; поддерживаются колонки %s", strings.Join(csvColumns, ", "))
+	}
+
+	var records []Record
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			records = append(records, Record{Number: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		records = append(records, csvRecord(line, row, columns))
+	}
+}
+
+func csvRecord(line int, row []string, columns map[string]int) Record {
+	value := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	record := Record{
+		Number:      line,
+		Title:       value("title"),
+		Description: value("description"),
+		CoverImage:  value("cover_image"),
+		Authors:     splitList(value("authors")),
+		Genres:      splitList(value("genres")),
+		ISBN:        value("isbn"),
+		Publisher:   value("publisher"),
+	}
+
+	var err error
+	if record.PageCount, err = parseNumber("page_count", value("page_count")); err != nil {
+		record.Err = err
+	} else if record.PublishedYear, err = parseNumber("published_year", value("published_year")); err != nil {
+		record.Err = err
+	}
+	return record
+}
+
+// parseNumber разбирает необязательное целое поле; пустое значение — 0
+func parseNumber(field, value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("поле %s должно быть целым числом, получено %q", field, value)
+	}
+	return number, nil
+}
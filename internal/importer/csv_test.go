@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Record
+		wantErr string
+	}{
+		{
+			name: "all columns",
+			input: "\ufeffTitle,Authors,ISBN,Description,Page_Count,Publisher,Published_Year,Cover_Image,Genres\n" +
+				"Пикник на обочине,Аркадий Стругацкий; Борис Стругацкий,978-5-17-090334-4,Повесть,256,АСТ,2015,https://example.com/c.jpg,Фантастика;Повесть\n",
+			want: []Record{{
+				Number:        2,
+				Title:         "Пикник на обочине",
+				Description:   "Повесть",
+				CoverImage:    "https://example.com/c.jpg",
+				PageCount:     256,
+				Authors:       []string{"Аркадий Стругацкий", "Борис Стругацкий"},
+				Genres:        []string{"Фантастика", "Повесть"},
+				ISBN:          "978-5-17-090334-4",
+				Publisher:     "АСТ",
+				PublishedYear: 2015,
+			}},
+		},
+		{
+			name:  "column order, unknown columns and short rows",
+			input: "extra,authors,title\nx,Лем\ny,Лем,Солярис\n",
+			want: []Record{
+				{Number: 2, Authors: []string{"Лем"}},
+				{Number: 3, Title: "Солярис", Authors: []string{"Лем"}},
+			},
+		},
+		{
+			name:  "quoted cell with comma and newline",
+			input: "title,description\n\"Книга, том 1\",\"Первая строка\nвторая\"\nДругая,\n",
+			want: []Record{
+				{Number: 2, Title: "Книга, том 1", Description: "Первая строка\nвторая"},
+				{Number: 4, Title: "Другая"},
+			},
+		},
+		{
+			name:  "invalid numbers are record errors",
+			input: "title,page_count,published_year\nA,много,2000\nB,10,давно\nC,,\n",
+			want: []Record{
+				{Number: 2, Err: errors.New("page_count")},
+				{Number: 3, Err: errors.New("published_year")},
+				{Number: 4, Title: "C"},
+			},
+		},
+		{
+			name:  "broken quotes are record errors",
+			input: "title,authors\nA,\"Лем\"x\nB,Лем\n",
+			want: []Record{
+				{Number: 2, Err: errors.New("quote")},
+				{Number: 3, Title: "B", Authors: []string{"Лем"}},
+			},
+		},
+		{name: "empty file", input: "", wantErr: "пустой CSV-файл"},
+		{name: "no title column", input: "name,authors\nA,B\n", wantErr: "нет колонки title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := Parse(FormatCSV, strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkRecords(t, records, tt.want)
+		})
+	}
+}
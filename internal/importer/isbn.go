@@ -0,0 +1,77 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidISBN = errors.New("некорректный ISBN: нужен ISBN-10 или ISBN-13 с верной контрольной цифрой")
+
+// NormalizeISBN проверяет контрольную цифру и приводит ISBN-10 и ISBN-13 к ISBN-13 без дефисов и пробелов
+func NormalizeISBN(raw string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		switch {
+		case r == '-' || r == ' ':
+			return -1
+		case r == 'x':
+			return 'X'
+		}
+		return r
+	}, strings.TrimPrefix(strings.TrimSpace(raw), "ISBN"))
+	digits = strings.TrimLeft(digits, ":")
+
+	switch len(digits) {
+	case 10:
+		if !validISBN10(digits) {
+			return "", ErrInvalidISBN
+		}
+		isbn := "978" + digits[:9]
+		return isbn + string(isbn13CheckDigit(isbn)), nil
+	case 13:
+		if !allDigits(digits) || (!strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979")) ||
+			isbn13CheckDigit(digits[:12]) != digits[12] {
+			return "", ErrInvalidISBN
+		}
+		return digits, nil
+	}
+	return "", ErrInvalidISBN
+}
+
+func validISBN10(isbn string) bool {
+	sum := 0
+	for i := 0; i < 10; i++ {
+		var value int
+		switch c := isbn[i]; {
+		case c >= '0' && c <= '9':
+			value = int(c - '0')
+		case c == 'X' && i == 9:
+			value = 10
+		default:
+			return false
+		}
+		sum += (10 - i) * value
+	}
+	return sum%11 == 0
+}
+
+// isbn13CheckDigit считает контрольную цифру по первым 12 цифрам ISBN-13
+func isbn13CheckDigit(prefix string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(prefix[i]-'0')
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
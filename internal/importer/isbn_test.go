@@ -0,0 +1,42 @@
+package importer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{raw: "9780306406157", want: "9780306406157"},
+		{raw: "978-0-306-40615-7", want: "9780306406157"},
+		{raw: " ISBN: 978 0 306 40615 7 ", want: "9780306406157"},
+		{raw: "0-306-40615-2", want: "9780306406157"},
+		{raw: "080442957X", want: "9780804429573"},
+		{raw: "080442957x", want: "9780804429573"},
+		{raw: "9791000000008", want: "9791000000008"},
+		{raw: "9780306406158", wantErr: true},
+		{raw: "0-306-40615-3", wantErr: true},
+		{raw: "1234567890128", wantErr: true},
+		{raw: "X804429570", wantErr: true},
+		{raw: "978030640615", wantErr: true},
+		{raw: "97803064061a7", wantErr: true},
+		{raw: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeISBN(tt.raw)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidISBN) {
+				t.Errorf("NormalizeISBN(%q) = %q, %v; want ErrInvalidISBN", tt.raw, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeISBN(%q) = %q, %v; want %q", tt.raw, got, err, tt.want)
+		}
+	}
+}
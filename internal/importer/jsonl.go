@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// jsonlMaxLine максимальная длина строки JSON Lines; описания книг бывают длинными
+const jsonlMaxLine = 1 << 20
+
+// jsonlBook строка JSON Lines: те же поля, что колонки CSV. Авторы и жанры — массив строк или строка через точку с запятой
+type jsonlBook struct {
+	Title         string          `json:"title"`
+	Authors       json.RawMessage `json:"authors"`
+	ISBN          string          `json:"isbn"`
+	Description   string          `json:"description"`
+	PageCount     int             `json:"page_count"`
+	Publisher     string          `json:"publisher"`
+	PublishedYear int             `json:"published_year"`
+	CoverImage    string          `json:"cover_image"`
+	Genres        json.RawMessage `json:"genres"`
+}
+
+func parseJSONL(r io.Reader) ([]Record, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), jsonlMaxLine)
+
+	var records []Record
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		records = append(records, jsonlRecord(line, data))
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("строка %d длиннее %d байт", line+1, jsonlMaxLine)
+		}
+		return nil, err
+	}
+	return records, nil
+}
+
+func jsonlRecord(line int, data []byte) Record {
+	var book jsonlBook
+	if err := json.Unmarshal(data, &book); err != nil {
+		return Record{Number: line, Err: fmt.Errorf("некорректный JSON: %v", err)}
+	}
+
+	record := Record{
+		Number:        line,
+		Title:         book.Title,
+		Description:   book.Description,
+		CoverImage:    book.CoverImage,
+		PageCount:     book.PageCount,
+		ISBN:          book.ISBN,
+		Publisher:     book.Publisher,
+		PublishedYear: book.PublishedYear,
+	}
+
+	var err error
+	if record.Authors, err = jsonlList("authors", book.Authors); err != nil {
+		record.Err = err
+	} else if record.Genres, err = jsonlList("genres", book.Genres); err != nil {
+		record.Err = err
+	}
+	return record
+}
+
+// jsonlList разбирает список имен: массив строк или одну строку через точку с запятой
+func jsonlList(field string, raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var names []string
+	if json.Unmarshal(raw, &names) == nil {
+		var items []string
+		for _, name := range names {
+			if name = strings.TrimSpace(name); name != "" {
+				items = append(items, name)
+			}
+		}
+		return items, nil
+	}
+
+	var joined string
+	if json.Unmarshal(raw, &joined) == nil {
+		return splitList(joined), nil
+	}
+	return nil, fmt.Errorf("поле %s должно быть массивом строк или строкой", field)
+}
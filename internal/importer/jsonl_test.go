@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseJSONL(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Record
+		wantErr string
+	}{
+		{
+			name: "authors as array and as string",
+			input: `{"title":"Солярис","authors":["Станислав Лем"," "],"isbn":"9785170903344","page_count":320,"published_year":1961,"genres":"Фантастика; Философия"}` + "\n" +
+				"\n" +
+				`{"title":"Пикник на обочине","authors":"Аркадий Стругацкий;Борис Стругацкий","publisher":"АСТ","genres":null}` + "\n",
+			want: []Record{
+				{
+					Number:        1,
+					Title:         "Солярис",
+					Authors:       []string{"Станислав Лем"},
+					Genres:        []string{"Фантастика", "Философия"},
+					ISBN:          "9785170903344",
+					PageCount:     320,
+					PublishedYear: 1961,
+				},
+				{
+					Number:    3,
+					Title:     "Пикник на обочине",
+					Authors:   []string{"Аркадий Стругацкий", "Борис Стругацкий"},
+					Publisher: "АСТ",
+				},
+			},
+		},
+		{
+			name:  "invalid lines are record errors",
+			input: "{\"title\":\n{\"title\":\"A\",\"authors\":42}\n{\"title\":\"B\",\"genres\":{}}\n{\"title\":\"C\",\"page_count\":\"10\"}\n",
+			want: []Record{
+				{Number: 1, Err: errors.New("некорректный JSON")},
+				{Number: 2, Err: errors.New("поле authors")},
+				{Number: 3, Err: errors.New("поле genres")},
+				{Number: 4, Err: errors.New("некорректный JSON")},
+			},
+		},
+		{
+			name:    "line too long",
+			input:   `{"title":"A"}` + "\n" + `{"description":"` + strings.Repeat("x", jsonlMaxLine) + `"}` + "\n",
+			wantErr: "строка 2 длиннее",
+		},
+		{name: "empty file", input: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := Parse(FormatJSONL, strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkRecords(t, records, tt.want)
+		})
+	}
+}
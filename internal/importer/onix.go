@@ -0,0 +1,342 @@
+package importer
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Коды списков ONIX 3.0, которые нужны для разбора карточки книги
+const (
+	onixNotificationDelete = "05"  // List 1: удаление записи
+	onixIDTypeISBN10       = "02"  // List 5
+	onixIDTypeGTIN13       = "03"  // List 5
+	onixIDTypeISBN13       = "15"  // List 5
+	onixTitleDistinctive   = "01"  // List 15: основное название
+	onixTitleLevelProduct  = "01"  // List 149: название самого продукта, а не серии
+	onixRoleAuthor         = "A01" // List 17
+	onixExtentUnitPages    = "03"  // List 24
+	onixResourceCover      = "01"  // List 158: лицевая обложка
+	onixResourceLinkable   = "01"  // List 161: ресурс доступен по ссылке
+	onixPublisherRole      = "01"  // List 45: издатель
+	onixDatePublication    = "01"  // List 163: дата выхода
+)
+
+var (
+	// List 23, в порядке предпочтения: основной текст, число страниц содержимого, всего страниц
+	onixPageExtentTypes = []string{"00", "11", "07"}
+	// List 153, в порядке предпочтения: описание, краткое описание
+	onixDescriptionTypes = []string{"03", "02"}
+
+	onixMarkupTag = regexp.MustCompile(`<[^>]*>`)
+)
+
+type onixProduct struct {
+	RecordReference  string `xml:"RecordReference"`
+	NotificationType string `xml:"NotificationType"`
+	Identifiers      []struct {
+		Type  string `xml:"ProductIDType"`
+		Value string `xml:"IDValue"`
+	} `xml:"ProductIdentifier"`
+	Descriptive struct {
+		Titles []struct {
+			Type     string `xml:"TitleType"`
+			Elements []struct {
+				Level         string `xml:"TitleElementLevel"`
+				Text          string `xml:"TitleText"`
+				Prefix        string `xml:"TitlePrefix"`
+				WithoutPrefix string `xml:"TitleWithoutPrefix"`
+				Subtitle      string `xml:"Subtitle"`
+			} `xml:"TitleElement"`
+		} `xml:"TitleDetail"`
+		Contributors []struct {
+			Roles          []string `xml:"ContributorRole"`
+			PersonName     string   `xml:"PersonName"`
+			NamesBeforeKey string   `xml:"NamesBeforeKey"`
+			KeyNames       string   `xml:"KeyNames"`
+			CorporateName  string   `xml:"CorporateName"`
+		} `xml:"Contributor"`
+		Subjects []struct {
+			HeadingTexts []string `xml:"SubjectHeadingText"`
+		} `xml:"Subject"`
+		Extents []struct {
+			Type  string `xml:"ExtentType"`
+			Value string `xml:"ExtentValue"`
+			Unit  string `xml:"ExtentUnit"`
+		} `xml:"Extent"`
+	} `xml:"DescriptiveDetail"`
+	Collateral struct {
+		Texts []struct {
+			Type string `xml:"TextType"`
+			Text struct {
+				Body string `xml:",innerxml"`
+			} `xml:"Text"`
+		} `xml:"TextContent"`
+		Resources []struct {
+			ContentType string `xml:"ResourceContentType"`
+			Versions    []struct {
+				Form string `xml:"ResourceForm"`
+				Link string `xml:"ResourceLink"`
+			} `xml:"ResourceVersion"`
+		} `xml:"SupportingResource"`
+	} `xml:"CollateralDetail"`
+	Publishing struct {
+		Publishers []struct {
+			Role string `xml:"PublishingRole"`
+			Name string `xml:"PublisherName"`
+		} `xml:"Publisher"`
+		Dates []struct {
+			Role string `xml:"PublishingDateRole"`
+			Date string `xml:"Date"`
+		} `xml:"PublishingDate"`
+	} `xml:"PublishingDetail"`
+}
+
+// parseONIX читает ONIX 3.0 с полными (reference) именами тегов. Продукты декодируются по одному,
+// поэтому большой файл не разворачивается в память целиком как дерево
+func parseONIX(r io.Reader) ([]Record, error) {
+	decoder := xml.NewDecoder(r)
+
+	var records []Record
+	rootSeen := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			if !rootSeen {
+				return nil, errors.New("в файле нет элемента ONIXMessage")
+			}
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("некорректный XML (поддерживается только UTF-8): %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if !rootSeen {
+			if err := checkONIXRoot(start); err != nil {
+				return nil, err
+			}
+			rootSeen = true
+			continue
+		}
+
+		if start.Name.Local != "Product" {
+			continue
+		}
+
+		var product onixProduct
+		if err := decoder.DecodeElement(&product, &start); err != nil {
+			return nil, fmt.Errorf("некорректный XML в Product %d: %w", len(records)+1, err)
+		}
+		records = append(records, product.record(len(records)+1))
+	}
+}
+
+func checkONIXRoot(root xml.StartElement) error {
+	switch root.Name.Local {
+	case "ONIXMessage":
+	case "ONIXmessage":
+		return errors.New("ONIX с короткими тегами не поддерживается, нужны полные (reference) имена тегов")
+	default:
+		return fmt.Errorf("ожидался корневой элемент ONIXMessage, получен %s", root.Name.Local)
+	}
+
+	for _, attr := range root.Attr {
+		if attr.Name.Local == "release" && !strings.HasPrefix(attr.Value, "3.") {
+			return fmt.Errorf("поддерживается только ONIX 3.0, в файле release=%q", attr.Value)
+		}
+	}
+	return nil
+}
+
+func (p *onixProduct) record(number int) Record {
+	record := Record{
+		Number:      number,
+		Reference:   strings.TrimSpace(p.RecordReference),
+		Title:       p.title(),
+		Description: p.description(),
+		CoverImage:  p.coverImage(),
+		Authors:     p.authors(),
+		Genres:      p.genres(),
+		ISBN:        p.isbn(),
+		Publisher:   p.publisher(),
+	}
+
+	if strings.TrimSpace(p.NotificationType) == onixNotificationDelete {
+		record.Err = errors.New("уведомления об удалении (NotificationType 05) не поддерживаются")
+		return record
+	}
+
+	var err error
+	if record.PageCount, err = p.pageCount(); err != nil {
+		record.Err = err
+	} else if record.PublishedYear, err = p.publishedYear(); err != nil {
+		record.Err = err
+	}
+	return record
+}
+
+func (p *onixProduct) isbn() string {
+	for _, idType := range []string{onixIDTypeISBN13, onixIDTypeGTIN13, onixIDTypeISBN10} {
+		for _, id := range p.Identifiers {
+			if strings.TrimSpace(id.Type) != idType {
+				continue
+			}
+			value := strings.TrimSpace(id.Value)
+			// GTIN-13 бывает не только у книг: берем его, только если это ISBN в форме «Bookland»
+			if idType == onixIDTypeGTIN13 && !strings.HasPrefix(value, "978") && !strings.HasPrefix(value, "979") {
+				continue
+			}
+			return value
+		}
+	}
+	return ""
+}
+
+func (p *onixProduct) title() string {
+	for _, detail := range p.Descriptive.Titles {
+		if strings.TrimSpace(detail.Type) != onixTitleDistinctive {
+			continue
+		}
+		for _, element := range detail.Elements {
+			if level := strings.TrimSpace(element.Level); level != "" && level != onixTitleLevelProduct {
+				continue
+			}
+
+			title := strings.TrimSpace(element.Text)
+			if title == "" {
+				title = strings.TrimSpace(strings.TrimSpace(element.Prefix) + " " + strings.TrimSpace(element.WithoutPrefix))
+			}
+			if subtitle := strings.TrimSpace(element.Subtitle); subtitle != "" && title != "" {
+				title += ": " + subtitle
+			}
+			return title
+		}
+	}
+	return ""
+}
+
+func (p *onixProduct) authors() []string {
+	var authors []string
+	for _, contributor := range p.Descriptive.Contributors {
+		if !slices.ContainsFunc(contributor.Roles, func(role string) bool { return strings.TrimSpace(role) == onixRoleAuthor }) {
+			continue
+		}
+
+		name := strings.TrimSpace(contributor.PersonName)
+		if name == "" {
+			name = strings.TrimSpace(strings.TrimSpace(contributor.NamesBeforeKey) + " " + strings.TrimSpace(contributor.KeyNames))
+		}
+		if name == "" {
+			name = strings.TrimSpace(contributor.CorporateName)
+		}
+		if name != "" {
+			authors = append(authors, name)
+		}
+	}
+	return authors
+}
+
+// genres берет текстовые рубрики Subject; коды классификаторов (BISAC, Thema) без текста пропускаются,
+// потому что жанры у нас хранятся по названию
+func (p *onixProduct) genres() []string {
+	var genres []string
+	for _, subject := range p.Descriptive.Subjects {
+		for _, heading := range subject.HeadingTexts {
+			for _, genre := range splitList(heading) {
+				if !slices.Contains(genres, genre) {
+					genres = append(genres, genre)
+				}
+			}
+		}
+	}
+	return genres
+}
+
+func (p *onixProduct) pageCount() (int, error) {
+	for _, extentType := range onixPageExtentTypes {
+		for _, extent := range p.Descriptive.Extents {
+			if strings.TrimSpace(extent.Type) != extentType {
+				continue
+			}
+			if unit := strings.TrimSpace(extent.Unit); unit != "" && unit != onixExtentUnitPages {
+				continue
+			}
+			return parseNumber("ExtentValue", strings.TrimSpace(extent.Value))
+		}
+	}
+	return 0, nil
+}
+
+func (p *onixProduct) description() string {
+	for _, textType := range onixDescriptionTypes {
+		for _, content := range p.Collateral.Texts {
+			if strings.TrimSpace(content.Type) == textType {
+				return plainText(content.Text.Body)
+			}
+		}
+	}
+	return ""
+}
+
+func (p *onixProduct) coverImage() string {
+	for _, resource := range p.Collateral.Resources {
+		if strings.TrimSpace(resource.ContentType) != onixResourceCover {
+			continue
+		}
+		for _, version := range resource.Versions {
+			if strings.TrimSpace(version.Form) == onixResourceLinkable && strings.TrimSpace(version.Link) != "" {
+				return strings.TrimSpace(version.Link)
+			}
+		}
+	}
+	return ""
+}
+
+func (p *onixProduct) publisher() string {
+	for _, publisher := range p.Publishing.Publishers {
+		if role := strings.TrimSpace(publisher.Role); role == onixPublisherRole || role == "" {
+			return strings.TrimSpace(publisher.Name)
+		}
+	}
+	return ""
+}
+
+// publishedYear берет год из даты выхода: по умолчанию ONIX пишет даты как YYYYMMDD, но допускает YYYY и YYYYMM
+func (p *onixProduct) publishedYear() (int, error) {
+	for _, date := range p.Publishing.Dates {
+		if strings.TrimSpace(date.Role) != onixDatePublication {
+			continue
+		}
+		value := strings.TrimSpace(date.Date)
+		if value == "" {
+			return 0, nil
+		}
+		year, err := strconv.Atoi(value[:min(4, len(value))])
+		if err != nil || len(value) < 4 {
+			return 0, fmt.Errorf("некорректная дата выхода %q", value)
+		}
+		return year, nil
+	}
+	return 0, nil
+}
+
+// plainText превращает содержимое Text (обычный текст или XHTML) в обычный текст
+func plainText(body string) string {
+	body = strings.TrimSpace(body)
+	if strings.HasPrefix(body, "<![CDATA[") {
+		body = strings.TrimSuffix(strings.TrimPrefix(body, "<![CDATA["), "]]>")
+	}
+	body = onixMarkupTag.ReplaceAllString(body, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(body)), " ")
+}
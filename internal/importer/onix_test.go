@@ -0,0 +1,150 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseONIX(t *testing.T) {
+	onix := func(products string) string {
+		return `<?xml version="1.0" encoding="UTF-8"?>
+<ONIXMessage release="3.0" xmlns="http://ns.editeur.org/onix/3.0/reference">
+<Header><Sender><SenderName>Издательство</SenderName></Sender></Header>` + products + `</ONIXMessage>`
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []Record
+		wantErr string
+	}{
+		{
+			name: "full product",
+			input: onix(`<Product>
+  <RecordReference>ref-1</RecordReference>
+  <NotificationType>03</NotificationType>
+  <ProductIdentifier><ProductIDType>01</ProductIDType><IDValue>internal</IDValue></ProductIdentifier>
+  <ProductIdentifier><ProductIDType>02</ProductIDType><IDValue>0306406152</IDValue></ProductIdentifier>
+  <ProductIdentifier><ProductIDType>15</ProductIDType><IDValue>9780306406157</IDValue></ProductIdentifier>
+  <DescriptiveDetail>
+    <TitleDetail><TitleType>01</TitleType>
+      <TitleElement><TitleElementLevel>02</TitleElementLevel><TitleText>Серия</TitleText></TitleElement>
+      <TitleElement><TitleElementLevel>01</TitleElementLevel><TitlePrefix>The</TitlePrefix><TitleWithoutPrefix>Book</TitleWithoutPrefix><Subtitle>A Novel</Subtitle></TitleElement>
+    </TitleDetail>
+    <Contributor><ContributorRole>A01</ContributorRole><PersonName>Станислав Лем</PersonName></Contributor>
+    <Contributor><ContributorRole>B06</ContributorRole><PersonName>Переводчик</PersonName></Contributor>
+    <Contributor><ContributorRole>A01</ContributorRole><NamesBeforeKey>Борис</NamesBeforeKey><KeyNames>Стругацкий</KeyNames></Contributor>
+    <Extent><ExtentType>07</ExtentType><ExtentValue>400</ExtentValue><ExtentUnit>03</ExtentUnit></Extent>
+    <Extent><ExtentType>00</ExtentType><ExtentValue>5</ExtentValue><ExtentUnit>09</ExtentUnit></Extent>
+    <Extent><ExtentType>11</ExtentType><ExtentValue>384</ExtentValue><ExtentUnit>03</ExtentUnit></Extent>
+    <Subject><SubjectSchemeIdentifier>10</SubjectSchemeIdentifier><SubjectCode>FIC028000</SubjectCode></Subject>
+    <Subject><SubjectHeadingText>Фантастика; Классика</SubjectHeadingText></Subject>
+    <Subject><SubjectHeadingText>Фантастика</SubjectHeadingText></Subject>
+  </DescriptiveDetail>
+  <CollateralDetail>
+    <TextContent><TextType>02</TextType><Text>Кратко</Text></TextContent>
+    <TextContent><TextType>03</TextType><Text textformat="05"><p>Полное <b>описание</b> &amp; еще</p></Text></TextContent>
+    <SupportingResource><ResourceContentType>01</ResourceContentType>
+      <ResourceVersion><ResourceForm>02</ResourceForm><ResourceLink>cover.jpg</ResourceLink></ResourceVersion>
+      <ResourceVersion><ResourceForm>01</ResourceForm><ResourceLink>https://example.com/cover.jpg</ResourceLink></ResourceVersion>
+    </SupportingResource>
+  </CollateralDetail>
+  <PublishingDetail>
+    <Publisher><PublishingRole>02</PublishingRole><PublisherName>Соиздатель</PublisherName></Publisher>
+    <Publisher><PublishingRole>01</PublishingRole><PublisherName>АСТ</PublisherName></Publisher>
+    <PublishingDate><PublishingDateRole>02</PublishingDateRole><Date>20200101</Date></PublishingDate>
+    <PublishingDate><PublishingDateRole>01</PublishingDateRole><Date>20150310</Date></PublishingDate>
+  </PublishingDetail>
+</Product>`),
+			want: []Record{{
+				Number:        1,
+				Reference:     "ref-1",
+				Title:         "The Book: A Novel",
+				Description:   "Полное описание & еще",
+				CoverImage:    "https://example.com/cover.jpg",
+				PageCount:     384,
+				Authors:       []string{"Станислав Лем", "Борис Стругацкий"},
+				Genres:        []string{"Фантастика", "Классика"},
+				ISBN:          "9780306406157",
+				Publisher:     "АСТ",
+				PublishedYear: 2015,
+			}},
+		},
+		{
+			name: "fallback identifiers and plain text",
+			input: onix(`<Product>
+  <ProductIdentifier><ProductIDType>03</ProductIDType><IDValue>4006381333931</IDValue></ProductIdentifier>
+  <ProductIdentifier><ProductIDType>02</ProductIDType><IDValue>0306406152</IDValue></ProductIdentifier>
+  <DescriptiveDetail>
+    <TitleDetail><TitleType>01</TitleType><TitleElement><TitleText>Солярис</TitleText></TitleElement></TitleDetail>
+    <Contributor><ContributorRole>A01</ContributorRole><CorporateName>Коллектив авторов</CorporateName></Contributor>
+  </DescriptiveDetail>
+  <CollateralDetail><TextContent><TextType>02</TextType><Text><![CDATA[Кратко о <i>книге</i>]]></Text></TextContent></CollateralDetail>
+  <PublishingDetail><PublishingDate><PublishingDateRole>01</PublishingDateRole><Date>1961</Date></PublishingDate></PublishingDetail>
+</Product>
+<Product>
+  <ProductIdentifier><ProductIDType>03</ProductIDType><IDValue>9791000000008</IDValue></ProductIdentifier>
+</Product>`),
+			want: []Record{
+				{
+					Number:        1,
+					Title:         "Солярис",
+					Description:   "Кратко о книге",
+					Authors:       []string{"Коллектив авторов"},
+					ISBN:          "0306406152",
+					PublishedYear: 1961,
+				},
+				{Number: 2, ISBN: "9791000000008"},
+			},
+		},
+		{
+			name: "record errors",
+			input: onix(`<Product><RecordReference>del</RecordReference><NotificationType>05</NotificationType></Product>
+<Product><DescriptiveDetail><Extent><ExtentType>00</ExtentType><ExtentValue>много</ExtentValue></Extent></DescriptiveDetail></Product>
+<Product><PublishingDetail><PublishingDate><PublishingDateRole>01</PublishingDateRole><Date>20</Date></PublishingDate></PublishingDetail></Product>`),
+			want: []Record{
+				{Number: 1, Err: errors.New("NotificationType 05")},
+				{Number: 2, Err: errors.New("ExtentValue")},
+				{Number: 3, Err: errors.New("некорректная дата выхода")},
+			},
+		},
+		{name: "short tags", input: `<ONIXmessage release="3.0"></ONIXmessage>`, wantErr: "короткими тегами"},
+		{name: "ONIX 2.1", input: `<ONIXMessage release="2.1"></ONIXMessage>`, wantErr: "только ONIX 3.0"},
+		{name: "wrong root", input: `<catalog></catalog>`, wantErr: "получен catalog"},
+		{name: "no root", input: ``, wantErr: "нет элемента ONIXMessage"},
+		{name: "broken XML", input: onix(`<Product><RecordReference>x</Product>`), wantErr: "некорректный XML"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := Parse(FormatONIX, strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkRecords(t, records, tt.want)
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		body, want string
+	}{
+		{"  Просто   текст\n", "Просто текст"},
+		{"<p>Абзац</p><p>второй&nbsp;абзац</p>", "Абзац второй абзац"},
+		{"<![CDATA[<b>жирный</b> &lt;тег&gt;]]>", "жирный <тег>"},
+	}
+
+	for _, tt := range tests {
+		if got := plainText(tt.body); got != tt.want {
+			t.Errorf("plainText(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package importer разбирает файлы массовой загрузки каталога: CSV, JSON Lines и ONIX 3.0.
+// Парсеры только переводят формат в Record; поиск дублей, авторов и запись в базу — в services.ImportService
+package importer
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Format формат файла загрузки
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+	FormatONIX  Format = "onix"
+)
+
+var ErrUnknownFormat = errors.New("неизвестный формат файла: поддерживаются csv, jsonl и onix")
+
+// Record одна книга из файла загрузки. Если запись не удалось разобрать, Err содержит причину,
+// а остальные поля заполнены настолько, насколько это получилось
+type Record struct {
+	Number        int    // номер строки CSV и JSON Lines или порядковый номер Product в ONIX, с единицы
+	Reference     string // RecordReference из ONIX
+	Title         string
+	Description   string
+	CoverImage    string
+	PageCount     int
+	Authors       []string
+	Genres        []string
+	ISBN          string // как в файле, проверяется и нормализуется NormalizeISBN
+	Publisher     string
+	PublishedYear int
+	Err           error
+}
+
+// ParseFormat разбирает название формата; пустое название определяется по расширению файла
+func ParseFormat(name, fileName string) (Format, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		switch strings.ToLower(filepath.Ext(fileName)) {
+		case ".csv":
+			return FormatCSV, nil
+		case ".jsonl", ".ndjson":
+			return FormatJSONL, nil
+		case ".xml", ".onix":
+			return FormatONIX, nil
+		}
+		return "", ErrUnknownFormat
+	}
+
+	switch format := Format(name); format {
+	case FormatCSV, FormatJSONL, FormatONIX:
+		return format, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// Parse читает все записи файла. Ошибка возвращается, только если файл нельзя разобрать целиком
+// (нет заголовка CSV, сломан XML); ошибки отдельных записей попадают в Record.Err
+func Parse(format Format, r io.Reader) ([]Record, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatJSONL:
+		return parseJSONL(r)
+	case FormatONIX:
+		return parseONIX(r)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// splitList разбирает список авторов или жанров через точку с запятой
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
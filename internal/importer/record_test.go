@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name, fileName string
+		want           Format
+		wantErr        bool
+	}{
+		{name: "CSV", want: FormatCSV},
+		{name: " jsonl ", want: FormatJSONL},
+		{name: "onix", fileName: "catalog.csv", want: FormatONIX},
+		{fileName: "catalog.CSV", want: FormatCSV},
+		{fileName: "catalog.ndjson", want: FormatJSONL},
+		{fileName: "catalog.xml", want: FormatONIX},
+		{fileName: "catalog.xlsx", wantErr: true},
+		{name: "yaml", fileName: "catalog.csv", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.name, tt.fileName)
+		if tt.wantErr {
+			if !errors.Is(err, ErrUnknownFormat) {
+				t.Errorf("ParseFormat(%q, %q) = %q, %v; want ErrUnknownFormat", tt.name, tt.fileName, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseFormat(%q, %q) = %q, %v; want %q", tt.name, tt.fileName, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseUnknownFormat(t *testing.T) {
+	if _, err := Parse("yaml", strings.NewReader("title: x")); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("err = %v, want ErrUnknownFormat", err)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{" ; ;", nil},
+		{"Стругацкий А.", []string{"Стругацкий А."}},
+		{" Аркадий Стругацкий ;Борис Стругацкий; ", []string{"Аркадий Стругацкий", "Борис Стругацкий"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitList(tt.value); !equalStrings(got, tt.want) {
+			t.Errorf("splitList(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkRecords сравнивает разобранные записи с ожидаемыми; у ошибочных записей сверяется только текст ошибки
+func checkRecords(t *testing.T, got, want []Record) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("записей %d, want %d: %+v", len(got), len(want), got)
+	}
+
+	for i := range want {
+		g, w := got[i], want[i]
+		if w.Err != nil {
+			if g.Err == nil || !strings.Contains(g.Err.Error(), w.Err.Error()) {
+				t.Errorf("запись %d: err = %v, want %q", i+1, g.Err, w.Err)
+			}
+			if g.Number != w.Number {
+				t.Errorf("запись %d: Number = %d, want %d", i+1, g.Number, w.Number)
+			}
+			continue
+		}
+		if g.Err != nil {
+			t.Errorf("запись %d: неожиданная ошибка %v", i+1, g.Err)
+			continue
+		}
+
+		if g.Number != w.Number || g.Reference != w.Reference || g.Title != w.Title || g.Description != w.Description ||
+			g.CoverImage != w.CoverImage || g.PageCount != w.PageCount || g.ISBN != w.ISBN ||
+			g.Publisher != w.Publisher || g.PublishedYear != w.PublishedYear ||
+			!equalStrings(g.Authors, w.Authors) || !equalStrings(g.Genres, w.Genres) {
+			t.Errorf("запись %d:\n got %+v\nwant %+v", i+1, g, w)
+		}
+	}
+}
@@ -19,7 +19,8 @@ func RoleMiddleware(allowedRoles ...constants.Role) gin.HandlerFunc {
 			return
 		}
 
-		userRole, ok := role.(constants.Role)
+		// AuthMiddleware кладет роль из JWT строкой
+		stringRole, ok := role.(string)
 		if !ok {
 			log.Warn("Ошибка преобразования роли пользователя")
 			c.JSON(http.StatusForbidden, gin.H{"error": "Ошибка авторизации"})
@@ -27,6 +28,7 @@ func RoleMiddleware(allowedRoles ...constants.Role) gin.HandlerFunc {
 			return
 		}
 
+		userRole := constants.Role(stringRole)
 		for _, allowed := range allowedRoles {
 			if userRole == allowed {
 				c.Next()
@@ -0,0 +1,328 @@
+package migrations
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/pkg/logger"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"io/fs"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Store хранилище, схему которого ведут миграции
+type Store string
+
+const (
+	StorePostgres Store = "postgres"
+	StoreMongo    Store = "mongo"
+)
+
+// migrationLockKey ключ advisory lock PostgreSQL: пока он взят, миграции не запустит другой экземпляр сервиса или bmsctl
+const migrationLockKey int64 = 4_817_220_561_903
+
+//go:embed postgres/*.sql
+var postgresFiles embed.FS
+
+// postgresFileName разбирает имена вида 0002_add_isbn.up.sql
+var postgresFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration одна версия схемы хранилища. Миграции PostgreSQL — пара SQL-файлов, выполняемая в транзакции
+// вместе с записью версии. Миграции MongoDB — функции; транзакций там нет, поэтому они должны быть идемпотентными
+type Migration struct {
+	Store   Store
+	Version int
+	Name    string
+
+	upSQL, downSQL     string
+	upMongo, downMongo func(ctx context.Context, db *mongo.Database) error
+}
+
+// MigrationStatus состояние миграции
+type MigrationStatus struct {
+	Store     Store      `json:"store"`
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Migrator применяет и откатывает миграции PostgreSQL и MongoDB. Примененные версии обоих хранилищ
+// хранятся в таблице schema_migrations, а запуски сериализуются advisory lock
+type Migrator struct {
+	db         *sql.DB
+	mongo      *mongo.Database
+	migrations []Migration
+	log        *logger.Logger
+}
+
+// NewMigrator собирает миграции: SQL-файлы PostgreSQL из embed.FS и функции MongoDB
+func NewMigrator(db *sql.DB, mongoDB *mongo.Database) (*Migrator, error) {
+	migrations, err := loadPostgresMigrations(postgresFiles)
+	if err != nil {
+		return nil, err
+	}
+	migrations = append(migrations, mongoMigrations...)
+
+	return &Migrator{
+		db:         db,
+		mongo:      mongoDB,
+		migrations: migrations,
+		log:        logger.GetLogger(),
+	}, nil
+}
+
+func loadPostgresMigrations(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "postgres")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := postgresFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("некорректное имя файла миграции %s: ожидается <версия>_<название>.(up|down).sql", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Store: StorePostgres, Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("у версии %d миграции PostgreSQL два названия: %s и %s", version, migration.Name, match[2])
+		}
+
+		content, err := fs.ReadFile(files, "postgres/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if match[3] == "up" {
+			migration.upSQL = string(content)
+		} else {
+			migration.downSQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, version := range slices.Sorted(maps.Keys(byVersion)) {
+		migration := byVersion[version]
+		if migration.upSQL == "" {
+			return nil, fmt.Errorf("у миграции PostgreSQL %04d_%s нет up-файла", version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	return migrations, nil
+}
+
+// Up применяет все непримененные миграции: сначала PostgreSQL, затем MongoDB
+func (m *Migrator) Up(ctx context.Context) ([]MigrationStatus, error) {
+	var applied []MigrationStatus
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.key()]; ok {
+				continue
+			}
+
+			m.log.Infof("Применяем миграцию %s %04d_%s", migration.Store, migration.Version, migration.Name)
+			if err := m.apply(ctx, conn, migration, true); err != nil {
+				return fmt.Errorf("миграция %s %04d_%s: %w", migration.Store, migration.Version, migration.Name, err)
+			}
+
+			now := time.Now().UTC()
+			applied = append(applied, migration.status(&now))
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down откатывает steps последних примененных миграций хранилища
+func (m *Migrator) Down(ctx context.Context, store Store, steps int) ([]MigrationStatus, error) {
+	var reverted []MigrationStatus
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Store != store {
+				continue
+			}
+			if _, ok := done[migration.key()]; !ok {
+				continue
+			}
+
+			m.log.Infof("Откатываем миграцию %s %04d_%s", migration.Store, migration.Version, migration.Name)
+			if err := m.apply(ctx, conn, migration, false); err != nil {
+				return fmt.Errorf("откат миграции %s %04d_%s: %w", migration.Store, migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration.status(nil))
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status возвращает все известные миграции с отметкой, применены ли они
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		statuses = make([]MigrationStatus, len(m.migrations))
+		for i, migration := range m.migrations {
+			if appliedAt, ok := done[migration.key()]; ok {
+				statuses[i] = migration.status(&appliedAt)
+			} else {
+				statuses[i] = migration.status(nil)
+			}
+		}
+		return nil
+	})
+
+	return statuses, err
+}
+
+// withLock выполняет fn на выделенном соединении под advisory lock: блокировка сессионная,
+// поэтому и она, и все запросы миграций должны идти через одно соединение
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("не удалось взять блокировку миграций: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			m.log.Warnf("Не удалось снять блокировку миграций: %v", err)
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		store varchar(20) NOT NULL,
+		version bigint NOT NULL,
+		name text NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now(),
+		PRIMARY KEY (store, version)
+	)`)
+	if err != nil {
+		return fmt.Errorf("не удалось создать schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[string]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT store, version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := make(map[string]time.Time)
+	for rows.Next() {
+		var migration Migration
+		var appliedAt time.Time
+		if err := rows.Scan(&migration.Store, &migration.Version, &appliedAt); err != nil {
+			return nil, err
+		}
+		done[migration.key()] = appliedAt.UTC()
+	}
+	return done, rows.Err()
+}
+
+// apply применяет (up) или откатывает миграцию и обновляет schema_migrations
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration, up bool) error {
+	record := func(exec func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)) error {
+		if up {
+			_, err := exec(ctx, "INSERT INTO schema_migrations (store, version, name) VALUES ($1, $2, $3)",
+				migration.Store, migration.Version, migration.Name)
+			return err
+		}
+		_, err := exec(ctx, "DELETE FROM schema_migrations WHERE store = $1 AND version = $2", migration.Store, migration.Version)
+		return err
+	}
+
+	if migration.Store == StoreMongo {
+		run := migration.upMongo
+		if !up {
+			run = migration.downMongo
+		}
+		if err := run(ctx, m.mongo); err != nil {
+			return err
+		}
+		return record(conn.ExecContext)
+	}
+
+	script := migration.upSQL
+	if !up {
+		if migration.downSQL == "" {
+			return fmt.Errorf("нет down-файла")
+		}
+		script = migration.downSQL
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Без аргументов драйвер отправляет скрипт простым протоколом, поэтому в файле может быть несколько команд
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := record(tx.ExecContext); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (migration Migration) key() string {
+	return fmt.Sprintf("%s/%d", migration.Store, migration.Version)
+}
+
+func (migration Migration) status(appliedAt *time.Time) MigrationStatus {
+	return MigrationStatus{
+		Store:     migration.Store,
+		Version:   migration.Version,
+		Name:      migration.Name,
+		Applied:   appliedAt != nil,
+		AppliedAt: appliedAt,
+	}
+}
+
+// NewDefaultMigrator создает мигратор для подключений из пакета database
+func NewDefaultMigrator() (*Migrator, error) {
+	db, err := database.DB.DB()
+	if err != nil {
+		return nil, err
+	}
+	return NewMigrator(db, database.MongoDB.Database("bookstore"))
+}
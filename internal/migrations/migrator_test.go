@@ -0,0 +1,220 @@
+package migrations
+
+import (
+	"book-management-system/pkg/logger"
+	"context"
+	"database/sql"
+	"fmt"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLoadPostgresMigrations(t *testing.T) {
+	file := func(content string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(content)} }
+
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		versions []int
+		wantErr  string
+	}{
+		{
+			name: "sorted by version",
+			files: fstest.MapFS{
+				"postgres/0010_later.up.sql":  file("SELECT 10"),
+				"postgres/0002_second.up.sql": file("SELECT 2"),
+				"postgres/0001_init.up.sql":   file("SELECT 1"),
+				"postgres/0001_init.down.sql": file("SELECT -1"),
+			},
+			versions: []int{1, 2, 10},
+		},
+		{
+			name:    "bad file name",
+			files:   fstest.MapFS{"postgres/init.sql": file("SELECT 1")},
+			wantErr: "некорректное имя файла",
+		},
+		{
+			name: "two names for one version",
+			files: fstest.MapFS{
+				"postgres/0001_init.up.sql":    file("SELECT 1"),
+				"postgres/0001_other.down.sql": file("SELECT -1"),
+			},
+			wantErr: "два названия",
+		},
+		{
+			name:    "down without up",
+			files:   fstest.MapFS{"postgres/0001_init.down.sql": file("SELECT -1")},
+			wantErr: "нет up-файла",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadPostgresMigrations(tt.files)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var versions []int
+			for _, migration := range migrations {
+				versions = append(versions, migration.Version)
+			}
+			if fmt.Sprint(versions) != fmt.Sprint(tt.versions) {
+				t.Fatalf("versions = %v, want %v", versions, tt.versions)
+			}
+		})
+	}
+}
+
+func TestEmbeddedPostgresMigrations(t *testing.T) {
+	migrations, err := loadPostgresMigrations(postgresFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("версия %04d_%s идет %d-й: версии должны идти подряд с 1", migration.Version, migration.Name, i+1)
+		}
+		if migration.downSQL == "" {
+			t.Errorf("у миграции %04d_%s нет down-файла", migration.Version, migration.Name)
+		}
+	}
+}
+
+// TestUpgradeBaseline поднимает до текущей версии базу, которую создал AutoMigrate до перехода на миграции.
+// Нужен PostgreSQL: тест запускается, только если задан TEST_DATABASE_URL, и работает в отдельной схеме
+func TestUpgradeBaseline(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL не задан")
+	}
+
+	ctx := context.Background()
+	db := openTestSchema(t, dsn)
+
+	migrations, err := loadPostgresMigrations(postgresFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// База AutoMigrate: таблицы 0001 с данными, но без schema_migrations
+	mustExec(t, db, migrations[0].upSQL)
+	mustExec(t, db, `INSERT INTO books (title) VALUES ('Пикник на обочине')`)
+	mustExec(t, db, `INSERT INTO user_books (user_id, book_id, status, pages_read)
+		SELECT gen_random_uuid(), id, 'reading', 10 FROM books`)
+	mustExec(t, db, `INSERT INTO moderator_actions (moderator_id, action, target_id, target_type) VALUES (1, 'confirm', 1, 'book')`)
+
+	migrator := &Migrator{db: db, migrations: migrations, log: logger.GetLogger()}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(migrations) {
+		t.Fatalf("применено %d миграций, want %d", len(applied), len(migrations))
+	}
+
+	columns := []struct{ table, column string }{
+		{"books", "page_count"},
+		{"books", "average_rating"},
+		{"books", "bayesian_rating"},
+		{"books", "ratings_count"},
+		{"books", "created_by"},
+		{"books", "isbn"},
+		{"books", "normalized_title"},
+		{"books", "confirmed_at"},
+		{"user_books", "progress_unit"},
+		{"user_books", "progress_percent"},
+		{"user_books", "location"},
+		{"user_books", "total_locations"},
+		{"user_books", "read_count"},
+		{"moderator_actions", "comment"},
+		{"activity_events", "actor_id"},
+		{"outbox_messages", "next_attempt_at"},
+		{"webhook_deliveries", "status"},
+	}
+	for _, c := range columns {
+		var found bool
+		err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = $1 AND column_name = $2)`, c.table, c.column).Scan(&found)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found {
+			t.Errorf("нет колонки %s.%s", c.table, c.column)
+		}
+	}
+
+	for _, index := range []string{"idx_books_created_by", "idx_books_bayesian_rating", "idx_books_normalized_title", "idx_books_confirmed_at"} {
+		var found bool
+		err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_indexes
+			WHERE schemaname = current_schema() AND indexname = $1)`, index).Scan(&found)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found {
+			t.Errorf("нет индекса %s", index)
+		}
+	}
+
+	var normalizedTitle, progressUnit string
+	err = db.QueryRowContext(ctx, `SELECT b.normalized_title, ub.progress_unit
+		FROM books b JOIN user_books ub ON ub.book_id = b.id`).Scan(&normalizedTitle, &progressUnit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normalizedTitle != "пикник на обочине" || progressUnit != "pages" {
+		t.Errorf("normalized_title = %q, progress_unit = %q", normalizedTitle, progressUnit)
+	}
+
+	// Текущая схема должна откатываться до пустой базы и подниматься заново
+	if _, err := migrator.Down(ctx, StorePostgres, len(migrations)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// openTestSchema открывает подключение, все запросы которого идут в новую пустую схему
+func openTestSchema(t *testing.T, dsn string) *sql.DB {
+	t.Helper()
+
+	gormDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gormDB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// search_path задается на соединение, поэтому оно должно быть единственным
+	db.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("migrations_test_%d", time.Now().UnixNano())
+	mustExec(t, db, "CREATE SCHEMA "+schema)
+	mustExec(t, db, "SET search_path TO "+schema)
+	t.Cleanup(func() {
+		db.Exec("DROP SCHEMA " + schema + " CASCADE")
+		db.Close()
+	})
+	return db
+}
+
+func mustExec(t *testing.T, db *sql.DB, query string) {
+	t.Helper()
+	if _, err := db.Exec(query); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+}
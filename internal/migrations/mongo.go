@@ -0,0 +1,71 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoCollections коллекции базы bookstore
+var mongoCollections = []string{"reviews", "review_votes", "review_comments", "review_reports", "feedbacks"}
+
+// mongoMigrations миграции MongoDB по порядку версий. Транзакций нет, поэтому каждая миграция
+// должна спокойно переживать повторный запуск после частичного применения
+var mongoMigrations = []Migration{
+	{
+		Store:   StoreMongo,
+		Version: 1,
+		Name:    "create_collections",
+		upMongo: func(ctx context.Context, db *mongo.Database) error {
+			for _, name := range mongoCollections {
+				if err := db.CreateCollection(ctx, name); err != nil && !isNamespaceExists(err) {
+					return err
+				}
+			}
+			return nil
+		},
+		// Коллекции хранят данные, поэтому откат их не удаляет
+		downMongo: func(ctx context.Context, db *mongo.Database) error {
+			return nil
+		},
+	},
+	{
+		Store:   StoreMongo,
+		Version: 2,
+		Name:    "reviews_book_user_unique",
+		// Один отзыв на книгу от пользователя. Отзывы удаленных пользователей анонимизируются
+		// нулевым UUID — он меньше любого настоящего, поэтому такие отзывы в индекс не попадают.
+		// Если в базе уже есть повторные отзывы, миграция упадет: их нужно разобрать вручную
+		upMongo: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("reviews").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys: bson.D{{Key: "book_id", Value: 1}, {Key: "user_id", Value: 1}},
+				Options: options.Index().
+					SetName("book_id_user_id_unique").
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"user_id": bson.M{"$gt": "00000000-0000-0000-0000-000000000000"}}),
+			})
+			return err
+		},
+		downMongo: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndex(ctx, db.Collection("reviews"), "book_id_user_id_unique")
+		},
+	},
+}
+
+// isNamespaceExists проверяет, что коллекция уже создана
+func isNamespaceExists(err error) bool {
+	var commandErr mongo.CommandError
+	return errors.As(err, &commandErr) && commandErr.Name == "NamespaceExists"
+}
+
+// dropIndex удаляет индекс, если он есть
+func dropIndex(ctx context.Context, collection *mongo.Collection, name string) error {
+	_, err := collection.Indexes().DropOne(ctx, name)
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) && commandErr.Name == "IndexNotFound" {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS user_books;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS reading_progresses;
+DROP TABLE IF EXISTS moderator_actions;
+DROP TABLE IF EXISTS book_authors;
+DROP TABLE IF EXISTS book_ratings;
+DROP TABLE IF EXISTS books;
+DROP TABLE IF EXISTS authors;
@@ -0,0 +1,107 @@
+-- Схема базы, которую вел AutoMigrate до перехода на версионные миграции.
+-- IF NOT EXISTS: в таких базах таблицы уже есть, и миграция только фиксирует версию.
+-- Файл не меняется: все, что появилось в схеме позже, добавляют следующие миграции
+
+CREATE TABLE IF NOT EXISTS authors (
+    id uuid DEFAULT gen_random_uuid(),
+    name text NOT NULL,
+    bio text,
+    deleted_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_authors_deleted_at ON authors (deleted_at);
+
+CREATE TABLE IF NOT EXISTS books (
+    id uuid DEFAULT gen_random_uuid(),
+    title text NOT NULL,
+    description text,
+    cover_image text,
+    confirmed boolean DEFAULT false,
+    deleted_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON books (deleted_at);
+
+CREATE TABLE IF NOT EXISTS book_ratings (
+    user_id uuid,
+    book_id uuid,
+    rating bigint NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id,book_id),
+    CONSTRAINT chk_book_ratings_rating CHECK (rating >= 1 AND rating <= 10)
+);
+CREATE INDEX IF NOT EXISTS idx_book_ratings_book_id ON book_ratings (book_id);
+CREATE INDEX IF NOT EXISTS idx_book_ratings_user_id ON book_ratings (user_id);
+
+CREATE TABLE IF NOT EXISTS book_authors (
+    book_id uuid,
+    author_id uuid
+);
+CREATE INDEX IF NOT EXISTS idx_book_authors_author_id ON book_authors (author_id);
+CREATE INDEX IF NOT EXISTS idx_book_authors_book_id ON book_authors (book_id);
+
+CREATE TABLE IF NOT EXISTS moderator_actions (
+    id bigserial,
+    moderator_id bigint,
+    action text NOT NULL,
+    target_id bigint,
+    target_type text NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_moderator_actions_moderator_id ON moderator_actions (moderator_id);
+CREATE INDEX IF NOT EXISTS idx_moderator_actions_target_id ON moderator_actions (target_id);
+
+CREATE TABLE IF NOT EXISTS reading_progresses (
+    id bigserial,
+    user_id bigint,
+    book_id bigint,
+    status text NOT NULL,
+    pages_read bigint DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_reading_progresses_book_id ON reading_progresses (book_id);
+CREATE INDEX IF NOT EXISTS idx_reading_progresses_user_id ON reading_progresses (user_id);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id text,
+    user_id uuid NOT NULL,
+    token text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS users (
+    id text,
+    username text NOT NULL,
+    email text NOT NULL,
+    password text NOT NULL,
+    role text NOT NULL DEFAULT 'user',
+    deleted_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+
+CREATE TABLE IF NOT EXISTS user_books (
+    user_id uuid,
+    book_id uuid,
+    status varchar(20) NOT NULL,
+    pages_read bigint,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id,book_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_books_book_id ON user_books (book_id);
+CREATE INDEX IF NOT EXISTS idx_user_books_user_id ON user_books (user_id);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS import_jobs;
+
+DROP INDEX IF EXISTS idx_books_normalized_title;
+DROP INDEX IF EXISTS idx_books_isbn;
+
+ALTER TABLE books DROP COLUMN IF EXISTS normalized_title;
+ALTER TABLE books DROP COLUMN IF EXISTS published_year;
+ALTER TABLE books DROP COLUMN IF EXISTS publisher;
+ALTER TABLE books DROP COLUMN IF EXISTS isbn;
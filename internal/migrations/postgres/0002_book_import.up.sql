@@ -0,0 +1,32 @@
+ALTER TABLE books ADD COLUMN isbn varchar(13);
+ALTER TABLE books ADD COLUMN publisher text NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN published_year bigint NOT NULL DEFAULT 0;
+ALTER TABLE books ADD COLUMN normalized_title text NOT NULL DEFAULT '';
+
+-- То же правило, что utils.NormalizeTitle: нижний регистр, все кроме букв и цифр схлопывается в один пробел
+UPDATE books SET normalized_title = btrim(lower(regexp_replace(title, '[^[:alnum:]]+', ' ', 'g')));
+
+CREATE UNIQUE INDEX idx_books_isbn ON books (isbn) WHERE isbn IS NOT NULL AND deleted_at IS NULL;
+CREATE INDEX idx_books_normalized_title ON books (normalized_title);
+
+CREATE TABLE import_jobs (
+    id uuid DEFAULT gen_random_uuid(),
+    format varchar(10) NOT NULL,
+    file_name text NOT NULL DEFAULT '',
+    status varchar(20) NOT NULL,
+    created_by uuid NOT NULL,
+    total bigint NOT NULL DEFAULT 0,
+    processed bigint NOT NULL DEFAULT 0,
+    created bigint NOT NULL DEFAULT 0,
+    skipped bigint NOT NULL DEFAULT 0,
+    failed bigint NOT NULL DEFAULT 0,
+    authors_created bigint NOT NULL DEFAULT 0,
+    errors jsonb NOT NULL DEFAULT '[]',
+    error text NOT NULL DEFAULT '',
+    started_at timestamptz,
+    finished_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_import_jobs_created_at ON import_jobs (created_at);
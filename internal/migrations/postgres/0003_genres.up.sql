@@ -0,0 +1,14 @@
+CREATE TABLE genres (
+    id uuid DEFAULT gen_random_uuid(),
+    name text NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX idx_genres_name ON genres (lower(name));
+
+CREATE TABLE book_genres (
+    book_id uuid NOT NULL,
+    genre_id uuid NOT NULL,
+    PRIMARY KEY (book_id, genre_id)
+);
+CREATE INDEX idx_book_genres_genre_id ON book_genres (genre_id);
@@ -0,0 +1,38 @@
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+DROP TABLE IF EXISTS user_reputations;
+DROP TABLE IF EXISTS read_throughs;
+DROP TABLE IF EXISTS outbox_messages;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS follows;
+DROP TABLE IF EXISTS book_note_votes;
+DROP TABLE IF EXISTS book_notes;
+DROP TABLE IF EXISTS activity_events;
+
+DROP TABLE IF EXISTS moderator_actions;
+CREATE TABLE moderator_actions (
+    id bigserial,
+    moderator_id bigint,
+    action text NOT NULL,
+    target_id bigint,
+    target_type text NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_moderator_actions_moderator_id ON moderator_actions (moderator_id);
+CREATE INDEX idx_moderator_actions_target_id ON moderator_actions (target_id);
+
+ALTER TABLE user_books DROP COLUMN IF EXISTS read_count;
+ALTER TABLE user_books DROP COLUMN IF EXISTS total_locations;
+ALTER TABLE user_books DROP COLUMN IF EXISTS location;
+ALTER TABLE user_books DROP COLUMN IF EXISTS progress_percent;
+ALTER TABLE user_books DROP COLUMN IF EXISTS progress_unit;
+
+DROP INDEX IF EXISTS idx_books_bayesian_rating;
+DROP INDEX IF EXISTS idx_books_created_by;
+ALTER TABLE books DROP COLUMN IF EXISTS ratings_count;
+ALTER TABLE books DROP COLUMN IF EXISTS bayesian_rating;
+ALTER TABLE books DROP COLUMN IF EXISTS average_rating;
+ALTER TABLE books DROP COLUMN IF EXISTS created_by;
+ALTER TABLE books DROP COLUMN IF EXISTS page_count;
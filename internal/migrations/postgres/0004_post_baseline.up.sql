@@ -0,0 +1,182 @@
+-- Все изменения схемы между базой AutoMigrate (0001) и переходом на версионные миграции.
+-- Миграция идемпотентна: в базах, созданных из прежней версии 0001, все это уже есть, и она ничего не меняет
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS page_count bigint NOT NULL DEFAULT 0;
+ALTER TABLE books ADD COLUMN IF NOT EXISTS created_by uuid;
+ALTER TABLE books ADD COLUMN IF NOT EXISTS average_rating decimal NOT NULL DEFAULT 0;
+ALTER TABLE books ADD COLUMN IF NOT EXISTS bayesian_rating decimal NOT NULL DEFAULT 0;
+ALTER TABLE books ADD COLUMN IF NOT EXISTS ratings_count bigint NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_books_created_by ON books (created_by);
+CREATE INDEX IF NOT EXISTS idx_books_bayesian_rating ON books (bayesian_rating);
+
+ALTER TABLE user_books ADD COLUMN IF NOT EXISTS progress_unit varchar(10) NOT NULL DEFAULT 'pages';
+ALTER TABLE user_books ADD COLUMN IF NOT EXISTS progress_percent decimal;
+ALTER TABLE user_books ADD COLUMN IF NOT EXISTS location bigint;
+ALTER TABLE user_books ADD COLUMN IF NOT EXISTS total_locations bigint;
+ALTER TABLE user_books ADD COLUMN IF NOT EXISTS read_count bigint NOT NULL DEFAULT 0;
+
+-- В базе AutoMigrate у moderator_actions числовые id, и таблицу никто не заполнял:
+-- пересоздаем ее с uuid модератора и строковым target_id (UUID из PostgreSQL или ObjectID из MongoDB)
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'moderator_actions'
+          AND column_name = 'id' AND data_type = 'bigint'
+    ) THEN
+        DROP TABLE moderator_actions;
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS moderator_actions (
+    id uuid DEFAULT gen_random_uuid(),
+    moderator_id uuid,
+    action text NOT NULL,
+    target_id text NOT NULL,
+    target_type text NOT NULL,
+    comment text,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_moderator_actions_target_id ON moderator_actions (target_id);
+CREATE INDEX IF NOT EXISTS idx_moderator_actions_moderator_id ON moderator_actions (moderator_id);
+
+CREATE TABLE IF NOT EXISTS activity_events (
+    id uuid DEFAULT gen_random_uuid(),
+    actor_type varchar(10) NOT NULL,
+    actor_id uuid NOT NULL,
+    type varchar(30) NOT NULL,
+    book_id uuid NOT NULL,
+    review_id text,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_activity_actor ON activity_events (actor_type, actor_id, created_at);
+
+CREATE TABLE IF NOT EXISTS book_notes (
+    id uuid DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    book_id uuid NOT NULL,
+    kind varchar(20) NOT NULL,
+    text text NOT NULL,
+    page bigint,
+    location text,
+    visibility varchar(20) NOT NULL DEFAULT 'private',
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_book_notes_book_id ON book_notes (book_id);
+CREATE INDEX IF NOT EXISTS idx_book_notes_user_book ON book_notes (user_id, book_id);
+
+CREATE TABLE IF NOT EXISTS book_note_votes (
+    note_id uuid,
+    user_id uuid,
+    vote bigint NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (note_id,user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_book_note_votes_user_id ON book_note_votes (user_id);
+
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id uuid,
+    target_type varchar(10),
+    target_id uuid,
+    created_at timestamptz,
+    PRIMARY KEY (follower_id,target_type,target_id)
+);
+CREATE INDEX IF NOT EXISTS idx_follows_target_id ON follows (target_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id uuid DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    type varchar(30) NOT NULL,
+    actor_id uuid,
+    book_id uuid,
+    review_id text,
+    comment_id text,
+    message text NOT NULL,
+    read_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id uuid,
+    type varchar(30),
+    enabled boolean NOT NULL,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id,type)
+);
+
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id uuid DEFAULT gen_random_uuid(),
+    type varchar(50) NOT NULL,
+    book_id uuid NOT NULL,
+    attempts bigint NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz,
+    last_error text,
+    processed_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_due ON outbox_messages (next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS read_throughs (
+    id uuid DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    book_id uuid NOT NULL,
+    status varchar(20) NOT NULL,
+    started_at timestamptz NOT NULL,
+    finished_at timestamptz,
+    rating bigint,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_read_throughs_rating CHECK (rating IS NULL OR (rating >= 1 AND rating <= 10))
+);
+CREATE INDEX IF NOT EXISTS idx_read_throughs_user_book ON read_throughs (user_id, book_id);
+
+CREATE TABLE IF NOT EXISTS user_reputations (
+    user_id uuid,
+    score bigint NOT NULL DEFAULT 0,
+    review_likes bigint NOT NULL DEFAULT 0,
+    review_dislikes bigint NOT NULL DEFAULT 0,
+    confirmed_books bigint NOT NULL DEFAULT 0,
+    moderation_penalties bigint NOT NULL DEFAULT 0,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_reputations_score ON user_reputations (score);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id uuid DEFAULT gen_random_uuid(),
+    url text NOT NULL,
+    secret text NOT NULL,
+    event_types jsonb NOT NULL,
+    active boolean NOT NULL DEFAULT true,
+    created_by uuid,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id uuid DEFAULT gen_random_uuid(),
+    webhook_id uuid NOT NULL,
+    event_type varchar(50) NOT NULL,
+    payload text NOT NULL,
+    status varchar(20) NOT NULL,
+    attempts bigint NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz,
+    last_status_code bigint,
+    last_error text,
+    delivered_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
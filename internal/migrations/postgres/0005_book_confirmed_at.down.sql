@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_books_confirmed_at;
+ALTER TABLE books DROP COLUMN IF EXISTS confirmed_at;
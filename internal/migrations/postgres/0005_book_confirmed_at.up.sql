@@ -0,0 +1,4 @@
+ALTER TABLE books ADD COLUMN confirmed_at timestamptz;
+-- Время подтверждения уже подтвержденных книг не сохранялось: берем время добавления, порядок новинок не меняется
+UPDATE books SET confirmed_at = created_at WHERE confirmed;
+CREATE INDEX idx_books_confirmed_at ON books (confirmed_at);
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ActivityEventType вид события в ленте
+type ActivityEventType string
+
+const (
+	ActivityBookAdded     ActivityEventType = "book_added"      // пользователь добавил книгу в свой список
+	ActivityBookFinished  ActivityEventType = "book_finished"   // пользователь дочитал книгу
+	ActivityReviewPosted  ActivityEventType = "review_posted"   // пользователь написал отзыв
+	ActivityAuthorNewBook ActivityEventType = "author_new_book" // у автора появилась подтвержденная книга
+)
+
+// ActivityEvent событие для ленты подписчиков.
+// Лента собирается при чтении (fan-out on read): события хранятся один раз у того, кто их совершил,
+// а подписчики выбирают их по своим подпискам
+type ActivityEvent struct {
+	ID        uuid.UUID         `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ActorType FollowTargetType  `gorm:"type:varchar(10);not null;index:idx_activity_actor,priority:1" json:"actor_type"`
+	ActorID   uuid.UUID         `gorm:"type:uuid;not null;index:idx_activity_actor,priority:2" json:"actor_id"`
+	Type      ActivityEventType `gorm:"type:varchar(30);not null" json:"type"`
+	BookID    uuid.UUID         `gorm:"type:uuid;not null" json:"book_id"`
+	ReviewID  string            `json:"review_id,omitempty"` // ObjectID отзыва в MongoDB для review_posted
+	CreatedAt time.Time         `gorm:"autoCreateTime;index:idx_activity_actor,priority:3" json:"created_at"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"book-management-system/pkg/utils"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"time"
@@ -8,12 +9,35 @@ import (
 
 // Book model
 type Book struct {
-	ID          uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	Title       string         `gorm:"not null" json:"title"`
-	Description string         `json:"description"`
-	CoverImage  string         `json:"cover_image"`
-	Confirmed   bool           `gorm:"default:false" json:"confirmed"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
-	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Title       string     `gorm:"not null" json:"title"`
+	Description string     `json:"description"`
+	CoverImage  string     `json:"cover_image"`
+	PageCount   int        `gorm:"not null;default:0" json:"page_count"` // 0 — количество страниц неизвестно
+	Confirmed   bool       `gorm:"default:false" json:"confirmed"`
+	ConfirmedAt *time.Time `gorm:"index" json:"confirmed_at,omitempty"`         // когда книга появилась в каталоге; по нему сортируются новинки
+	CreatedBy   *uuid.UUID `gorm:"type:uuid;index" json:"created_by,omitempty"` // кто добавил книгу; пусто у книг, добавленных до учета авторства
+
+	// Выходные данные издания, обычно приходят из массовой загрузки каталога
+	ISBN          *string `gorm:"type:varchar(13)" json:"isbn,omitempty"` // ISBN-13 без дефисов, уникален среди неудаленных книг
+	Publisher     string  `gorm:"not null;default:''" json:"publisher,omitempty"`
+	PublishedYear int     `gorm:"not null;default:0" json:"published_year,omitempty"` // 0 — год неизвестен
+
+	// Название для поиска дублей, см. utils.NormalizeTitle; заполняется в BeforeSave
+	NormalizedTitle string `gorm:"not null;default:''" json:"-"`
+
+	// Рейтинг по отзывам: простое среднее и байесовская оценка с учетом веса отзывов, см. ReviewService.RecalculateBookRating
+	AverageRating  float64 `gorm:"not null;default:0" json:"average_rating"`
+	BayesianRating float64 `gorm:"not null;default:0;index" json:"bayesian_rating"`
+	RatingsCount   int     `gorm:"not null;default:0" json:"ratings_count"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// BeforeSave поддерживает нормализованное название в актуальном состоянии при создании и обновлении книги
+func (b *Book) BeforeSave(tx *gorm.DB) error {
+	b.NormalizedTitle = utils.NormalizeTitle(b.Title)
+	return nil
 }
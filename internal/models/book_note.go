@@ -0,0 +1,43 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type NoteKind string
+
+const (
+	NoteKindNote      NoteKind = "note"
+	NoteKindHighlight NoteKind = "highlight"
+	NoteKindQuote     NoteKind = "quote"
+)
+
+type NoteVisibility string
+
+const (
+	NoteVisibilityPrivate NoteVisibility = "private"
+	NoteVisibilityPublic  NoteVisibility = "public"
+)
+
+// BookNote заметка, выделение или цитата пользователя к книге из его списка (UserBook)
+type BookNote struct {
+	ID         uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID     uuid.UUID      `gorm:"type:uuid;index:idx_book_notes_user_book;not null" json:"user_id"`
+	BookID     uuid.UUID      `gorm:"type:uuid;index:idx_book_notes_user_book;index;not null" json:"book_id"`
+	Kind       NoteKind       `gorm:"type:varchar(20);not null" json:"kind"`
+	Text       string         `gorm:"not null" json:"text"`
+	Page       *int           `json:"page,omitempty"`
+	Location   *string        `json:"location,omitempty"`
+	Visibility NoteVisibility `gorm:"type:varchar(20);not null;default:private" json:"visibility"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// BookNoteVote голос пользователя за публичную цитату
+type BookNoteVote struct {
+	NoteID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"note_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	Vote      int       `gorm:"not null" json:"vote"` // 1 - лайк, -1 - дизлайк
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -12,6 +12,8 @@ type Feedback struct {
 	Text      string             `bson:"text" json:"text"`
 	Rating    int                `bson:"rating" json:"rating"`
 	Checked   bool               `bson:"checked" json:"checked"`
+	Flagged   bool               `bson:"flagged" json:"flagged"`                           // фильтр просит модератора посмотреть в первую очередь
+	AutoFlags []string           `bson:"auto_flags,omitempty" json:"auto_flags,omitempty"` // категории, найденные фильтром
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// FollowTargetType на кого подписан пользователь
+type FollowTargetType string
+
+const (
+	FollowTargetUser   FollowTargetType = "user"
+	FollowTargetAuthor FollowTargetType = "author"
+)
+
+// IsValid проверяет, что тип подписки поддерживается
+func (t FollowTargetType) IsValid() bool {
+	return t == FollowTargetUser || t == FollowTargetAuthor
+}
+
+// Follow подписка пользователя на другого пользователя или автора
+type Follow struct {
+	FollowerID uuid.UUID        `gorm:"type:uuid;primaryKey" json:"follower_id"`
+	TargetType FollowTargetType `gorm:"type:varchar(10);primaryKey" json:"target_type"`
+	TargetID   uuid.UUID        `gorm:"type:uuid;primaryKey;index" json:"target_id"`
+	CreatedAt  time.Time        `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Genre жанр каталога; название уникально без учета регистра
+type Genre struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// BookGenre связь книги с жанром
+type BookGenre struct {
+	BookID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"book_id"`
+	GenreID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"genre_id"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ImportJobStatus состояние задачи массовой загрузки книг
+type ImportJobStatus string
+
+const (
+	ImportJobPending   ImportJobStatus = "pending"   // файл принят, обработка еще не началась
+	ImportJobRunning   ImportJobStatus = "running"   // записи обрабатываются, счетчики обновляются после каждой пачки
+	ImportJobCompleted ImportJobStatus = "completed" // все записи обработаны; ошибки отдельных записей — в Errors
+	ImportJobFailed    ImportJobStatus = "failed"    // файл не удалось разобрать или обработка прервалась, причина — в Error
+)
+
+// ImportJob задача массовой загрузки книг из CSV, JSON Lines или ONIX
+type ImportJob struct {
+	ID        uuid.UUID       `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Format    string          `gorm:"type:varchar(10);not null" json:"format"`
+	FileName  string          `gorm:"not null;default:''" json:"file_name"`
+	Status    ImportJobStatus `gorm:"type:varchar(20);not null" json:"status"`
+	CreatedBy uuid.UUID       `gorm:"type:uuid;not null" json:"created_by"`
+
+	// Прогресс: Processed из Total записей, из них создано, пропущено как дубли и не загружено из-за ошибок
+	Total          int `gorm:"not null;default:0" json:"total"`
+	Processed      int `gorm:"not null;default:0" json:"processed"`
+	Created        int `gorm:"not null;default:0" json:"created"`
+	Skipped        int `gorm:"not null;default:0" json:"skipped"`
+	Failed         int `gorm:"not null;default:0" json:"failed"`
+	AuthorsCreated int `gorm:"not null;default:0" json:"authors_created"`
+
+	Errors []ImportRecordError `gorm:"type:jsonb;serializer:json;not null" json:"errors"` // первые ошибки записей, см. ImportService
+	Error  string              `gorm:"not null;default:''" json:"error,omitempty"`
+
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ImportRecordError ошибка загрузки одной записи файла
+type ImportRecordError struct {
+	Record    int    `json:"record"`              // номер строки CSV и JSON Lines или порядковый номер Product в ONIX, с единицы
+	Reference string `json:"reference,omitempty"` // ISBN или RecordReference из ONIX, если есть
+	Title     string `json:"title,omitempty"`
+	Error     string `json:"error"`
+}
@@ -1,13 +1,17 @@
 package models
 
-import "time"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 // Moderator Action model
 type ModeratorAction struct {
-	ID          uint   `gorm:"primaryKey"`
-	ModeratorID uint   `gorm:"index"`
-	Action      string `gorm:"not null"`
-	TargetID    uint   `gorm:"index"`
-	TargetType  string `gorm:"not null"` // book, author, review
-	CreatedAt   time.Time
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ModeratorID uuid.UUID `gorm:"type:uuid;index" json:"moderator_id"`
+	Action      string    `gorm:"not null" json:"action"`
+	TargetID    string    `gorm:"index;not null" json:"target_id"` // UUID из Postgres или ObjectID из Mongo строкой
+	TargetType  string    `gorm:"not null" json:"target_type"`     // book, author, review
+	Comment     string    `json:"comment,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
 }
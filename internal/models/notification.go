@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Notification уведомление пользователя во внутреннем центре уведомлений
+type Notification struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_notifications_user,priority:1" json:"user_id"`
+	Type      string     `gorm:"type:varchar(30);not null" json:"type"`
+	ActorID   *uuid.UUID `gorm:"type:uuid" json:"actor_id,omitempty"`
+	BookID    *uuid.UUID `gorm:"type:uuid" json:"book_id,omitempty"`
+	ReviewID  string     `json:"review_id,omitempty"`  // ObjectID отзыва в MongoDB
+	CommentID string     `json:"comment_id,omitempty"` // ObjectID комментария в MongoDB
+	Message   string     `gorm:"not null" json:"message"`
+	ReadAt    *time.Time `json:"read_at,omitempty"` // nil — не прочитано
+	CreatedAt time.Time  `gorm:"autoCreateTime;index:idx_notifications_user,priority:2" json:"created_at"`
+}
+
+// NotificationPreference включен ли для пользователя вид уведомлений; без записи — включен
+type NotificationPreference struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Type      string    `gorm:"type:varchar(30);primaryKey" json:"type"`
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// OutboxMessageType побочный эффект в MongoDB, который нужно применить после изменения в PostgreSQL
+type OutboxMessageType string
+
+const (
+	OutboxDeleteBookReviews     OutboxMessageType = "delete_book_reviews"     // удалить отзывы удаленной книги с комментариями и голосами
+	OutboxRecalculateBookRating OutboxMessageType = "recalculate_book_rating" // пересчитать рейтинг книги по отзывам
+)
+
+// OutboxMessage запись транзакционного outbox. Пишется в той же транзакции, что и изменение в PostgreSQL,
+// и обрабатывается воркером до успеха, поэтому обработчики должны быть идемпотентными
+type OutboxMessage struct {
+	ID            uuid.UUID         `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Type          OutboxMessageType `gorm:"type:varchar(50);not null" json:"type"`
+	BookID        uuid.UUID         `gorm:"type:uuid;not null" json:"book_id"`
+	Attempts      int               `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt *time.Time        `gorm:"index:idx_outbox_messages_due" json:"next_attempt_at,omitempty"` // nil — сообщение обработано
+	LastError     string            `json:"last_error,omitempty"`
+	ProcessedAt   *time.Time        `json:"processed_at,omitempty"`
+	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
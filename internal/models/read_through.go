@@ -0,0 +1,19 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ReadThrough одно прочтение книги пользователем; UserBook хранит агрегированное состояние по всем прочтениям
+type ReadThrough struct {
+	ID         uuid.UUID     `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID     uuid.UUID     `gorm:"type:uuid;index:idx_read_throughs_user_book;not null" json:"user_id"`
+	BookID     uuid.UUID     `gorm:"type:uuid;index:idx_read_throughs_user_book;not null" json:"book_id"`
+	Status     ReadingStatus `gorm:"type:varchar(20);not null" json:"status"`
+	StartedAt  time.Time     `gorm:"not null" json:"started_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	Rating     *int          `gorm:"check:rating IS NULL OR (rating >= 1 AND rating <= 10)" json:"rating,omitempty"`
+	CreatedAt  time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -5,21 +5,53 @@ import (
 	"time"
 )
 
+type ReviewModerationStatus string
+
+const (
+	ReviewModerationPending  ReviewModerationStatus = "pending" // есть нерассмотренные жалобы
+	ReviewModerationApproved ReviewModerationStatus = "approved"
+	ReviewModerationHidden   ReviewModerationStatus = "hidden"
+	ReviewModerationDeleted  ReviewModerationStatus = "deleted"
+)
+
 type Review struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	BookID    string             `bson:"book_id" json:"book_id"` // Храним UUID как string
-	UserID    string             `bson:"user_id" json:"user_id"` // Храним UUID как string
-	Text      string             `bson:"text" json:"text"`
-	Rating    int                `bson:"rating" json:"rating"`
-	Likes     int                `bson:"likes" json:"likes"`
-	Dislikes  int                `bson:"dislikes" json:"dislikes"`
-	Versions  []ReviewVersion    `bson:"versions" json:"versions"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	BookID        string             `bson:"book_id" json:"book_id"`     // Храним UUID как string
+	UserID        string             `bson:"user_id" json:"user_id"`     // Храним UUID как string
+	Text          string             `bson:"text" json:"text"`           // исходный текст в ограниченном Markdown
+	TextHTML      string             `bson:"text_html" json:"text_html"` // безопасный HTML, см. pkg/markup
+	HasSpoilers   bool               `bson:"has_spoilers" json:"has_spoilers"`
+	Rating        int                `bson:"rating" json:"rating"`
+	Likes         int                `bson:"likes" json:"likes"`
+	Dislikes      int                `bson:"dislikes" json:"dislikes"`
+	CommentsCount int                `bson:"comments_count" json:"comments_count"` // комментарии без учета удаленных
+	Versions      []ReviewVersion    `bson:"versions" json:"versions"`             // прошлые состояния отзыва, от старых к новым
+	// VersionsTrimmed сколько самых старых версий удалено из Versions из-за ограничения длины истории:
+	// номера версий в API считаются с учетом удаленных и не сдвигаются
+	VersionsTrimmed int    `bson:"versions_trimmed,omitempty" json:"versions_trimmed,omitempty"`
+	EditedBy        string `bson:"edited_by,omitempty" json:"edited_by,omitempty"` // кто внес текущую редакцию; пусто — автор отзыва
+
+	// Модерация: скрытые и удаленные отзывы не показываются и не участвуют в рейтинге
+	Hidden           bool                   `bson:"hidden" json:"hidden"`
+	ReportsCount     int                    `bson:"reports_count" json:"reports_count"` // нерассмотренные жалобы
+	ModerationStatus ReviewModerationStatus `bson:"moderation_status,omitempty" json:"moderation_status,omitempty"`
+	DeletedAt        *time.Time             `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	AutoFlags        []string               `bson:"auto_flags,omitempty" json:"auto_flags,omitempty"` // категории, по которым отзыв отправил на модерацию фильтр
+	Unpublished      bool                   `bson:"unpublished,omitempty" json:"-"`                   // фильтр задержал новый отзыв, подписчики о нем еще не знают
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// IsVisible проверяет, можно ли показывать отзыв и учитывать его в рейтинге
+func (r *Review) IsVisible() bool {
+	return !r.Hidden && r.DeletedAt == nil
 }
 
+// ReviewVersion прошлое состояние отзыва: текст и оценка на момент EditedAt
 type ReviewVersion struct {
 	Text     string    `bson:"text" json:"text"`
+	Rating   int       `bson:"rating,omitempty" json:"rating,omitempty"` // 0 у версий, сохраненных до учета оценки
 	EditedAt time.Time `bson:"edited_at" json:"edited_at"`
 	EditedBy string    `bson:"edited_by" json:"edited_by"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// ReviewComment комментарий к отзыву; ответы ссылаются на родителя через ParentID
+type ReviewComment struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ReviewID     primitive.ObjectID  `bson:"review_id" json:"review_id"`
+	ParentID     *primitive.ObjectID `bson:"parent_id" json:"parent_id,omitempty"` // nil — комментарий верхнего уровня
+	UserID       string              `bson:"user_id" json:"user_id"`               // Храним UUID как string
+	Text         string              `bson:"text" json:"text"`
+	Depth        int                 `bson:"depth" json:"depth"` // 0 — верхний уровень
+	RepliesCount int                 `bson:"replies_count" json:"replies_count"`
+	Deleted      bool                `bson:"deleted" json:"deleted"` // удаленный комментарий с ответами остается в ветке без текста
+	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time           `bson:"updated_at" json:"updated_at"`
+}
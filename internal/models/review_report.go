@@ -0,0 +1,35 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type ReportReason string
+
+const (
+	ReportReasonSpam     ReportReason = "spam"
+	ReportReasonSpoilers ReportReason = "spoilers"
+	ReportReasonAbuse    ReportReason = "abuse"
+)
+
+// IsValid проверяет, что причина жалобы поддерживается
+func (r ReportReason) IsValid() bool {
+	switch r {
+	case ReportReasonSpam, ReportReasonSpoilers, ReportReasonAbuse:
+		return true
+	}
+	return false
+}
+
+// ReviewReport жалоба пользователя на отзыв; после решения модератора помечается resolved
+type ReviewReport struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ReviewID   primitive.ObjectID `bson:"review_id" json:"review_id"`
+	UserID     string             `bson:"user_id" json:"user_id"` // Храним UUID как string
+	Reason     ReportReason       `bson:"reason" json:"reason"`
+	Comment    string             `bson:"comment,omitempty" json:"comment,omitempty"`
+	Resolved   bool               `bson:"resolved" json:"resolved"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	ResolvedAt *time.Time         `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+}
@@ -13,11 +13,45 @@ const (
 	StatusDropped   ReadingStatus = "dropped"
 )
 
+// IsValid проверяет, что статус входит в допустимый набор
+func (s ReadingStatus) IsValid() bool {
+	switch s {
+	case StatusReading, StatusCompleted, StatusDropped:
+		return true
+	}
+	return false
+}
+
+// ProgressUnit единица, в которой пользователь отмечает прогресс
+type ProgressUnit string
+
+const (
+	ProgressUnitPages    ProgressUnit = "pages"
+	ProgressUnitPercent  ProgressUnit = "percent"
+	ProgressUnitLocation ProgressUnit = "location" // позиция в электронной книге (Kindle и т.п.)
+)
+
+// IsValid проверяет, что единица прогресса поддерживается
+func (u ProgressUnit) IsValid() bool {
+	switch u {
+	case ProgressUnitPages, ProgressUnitPercent, ProgressUnitLocation:
+		return true
+	}
+	return false
+}
+
 type UserBook struct {
 	UserID    uuid.UUID     `gorm:"type:uuid;index;primaryKey" json:"user_id"`
 	BookID    *uuid.UUID    `gorm:"type:uuid;index;primaryKey" json:"book_id"`
 	Status    ReadingStatus `gorm:"type:varchar(20);not null" json:"status"`
 	PagesRead int           `json:"pages_read"`
-	CreatedAt time.Time     `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+
+	ProgressUnit    ProgressUnit `gorm:"type:varchar(10);not null;default:'pages'" json:"progress_unit"`
+	ProgressPercent *float64     `json:"progress_percent,omitempty"` // nil, если процент посчитать не из чего
+	Location        *int         `json:"location,omitempty"`
+	TotalLocations  *int         `json:"total_locations,omitempty"`
+
+	ReadCount int       `gorm:"not null;default:0" json:"read_count"` // количество завершенных прочтений
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// UserReputation репутация пользователя. Хранятся составляющие, из которых сложен Score,
+// все поля обновляются приращениями по мере событий (голоса, подтверждение книг, решения модераторов)
+type UserReputation struct {
+	UserID              uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Score               int       `gorm:"not null;default:0;index" json:"score"`
+	ReviewLikes         int       `gorm:"not null;default:0" json:"review_likes"`         // лайки, полученные на отзывы
+	ReviewDislikes      int       `gorm:"not null;default:0" json:"review_dislikes"`      // дизлайки, полученные на отзывы
+	ConfirmedBooks      int       `gorm:"not null;default:0" json:"confirmed_books"`      // добавленные и подтвержденные книги
+	ModerationPenalties int       `gorm:"not null;default:0" json:"moderation_penalties"` // скрытые и удаленные модератором отзывы
+	UpdatedAt           time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
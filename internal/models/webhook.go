@@ -0,0 +1,43 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Webhook подписка внешнего сервиса на события каталога и отзывов
+type Webhook struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	URL        string    `gorm:"not null" json:"url"`
+	Secret     string    `gorm:"not null" json:"-"` // ключ HMAC-подписи доставок
+	EventTypes []string  `gorm:"type:jsonb;serializer:json;not null" json:"event_types"`
+	Active     bool      `gorm:"not null;default:true" json:"active"`
+	CreatedBy  uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// WebhookDeliveryStatus состояние доставки
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // ждет первой или повторной попытки
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // получатель ответил 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // попытки исчерпаны
+)
+
+// WebhookDelivery доставка события на вебхук и журнал ее попыток
+type WebhookDelivery struct {
+	ID             uuid.UUID             `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	WebhookID      uuid.UUID             `gorm:"type:uuid;not null;index" json:"webhook_id"`
+	EventType      string                `gorm:"type:varchar(50);not null" json:"event_type"`
+	Payload        string                `gorm:"type:text;not null" json:"payload"` // тело запроса, подписывается как есть
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_due,priority:1" json:"status"`
+	Attempts       int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  *time.Time            `gorm:"index:idx_webhook_deliveries_due,priority:2" json:"next_attempt_at,omitempty"`
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time             `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time             `gorm:"autoUpdateTime" json:"updated_at"`
+}
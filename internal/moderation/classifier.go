@@ -0,0 +1,180 @@
+// Package moderation автоматически проверяет пользовательские тексты (отзывы, фидбэк)
+// на мат, оскорбления и спам и решает, что с ними делать согласно политике по категориям.
+package moderation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Category категория нежелательного содержимого
+type Category string
+
+const (
+	CategoryProfanity Category = "profanity"
+	CategoryInsult    Category = "insult"
+	CategorySpam      Category = "spam"
+)
+
+// Match найденный фрагмент текста; Start и End — байтовые смещения в исходном тексте
+type Match struct {
+	Category Category
+	Start    int
+	End      int
+}
+
+// Classifier находит в тексте нежелательные фрагменты.
+// Встроенная реализация — WordlistClassifier, но можно подключить и внешний сервис
+type Classifier interface {
+	Classify(text string) []Match
+}
+
+// Rule правило словаря.
+//
+// Формат строки в файле: "<категория> <шаблон>", пустые строки и строки с # пропускаются.
+// Шаблон "слово" совпадает только с целым словом, "корень*" — с началом слова,
+// "*корень" — с концом, "*корень*" — с любой частью, "/выражение/" — регулярное выражение по всему тексту
+type Rule struct {
+	Category Category
+	Pattern  string
+}
+
+type wordRule struct {
+	category Category
+	stem     string
+	prefix   bool // слово начинается с stem
+	suffix   bool // слово заканчивается на stem
+}
+
+type regexRule struct {
+	category Category
+	pattern  *regexp.Regexp
+}
+
+// WordlistClassifier классификатор по словарям слов и регулярным выражениям.
+// Регистр, ё/е и похожие латинские буквы в русских словах не влияют на совпадение
+type WordlistClassifier struct {
+	words   []wordRule
+	regexes []regexRule
+}
+
+// NewWordlistClassifier собирает классификатор из правил
+func NewWordlistClassifier(rules []Rule) (*WordlistClassifier, error) {
+	classifier := &WordlistClassifier{}
+
+	for _, rule := range rules {
+		pattern := strings.TrimSpace(rule.Pattern)
+		if pattern == "" {
+			continue
+		}
+
+		if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			compiled, err := regexp.Compile("(?i)" + pattern[1:len(pattern)-1])
+			if err != nil {
+				return nil, fmt.Errorf("некорректное выражение %q: %w", pattern, err)
+			}
+			classifier.regexes = append(classifier.regexes, regexRule{category: rule.Category, pattern: compiled})
+			continue
+		}
+
+		classifier.words = append(classifier.words, wordRule{
+			category: rule.Category,
+			stem:     normalizeWord(strings.Trim(pattern, "*")),
+			prefix:   strings.HasSuffix(pattern, "*"),
+			suffix:   strings.HasPrefix(pattern, "*"),
+		})
+	}
+
+	return classifier, nil
+}
+
+// ParseRules читает правила словаря из r
+func ParseRules(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		category, pattern, ok := strings.Cut(text, " ")
+		if !ok || strings.TrimSpace(pattern) == "" {
+			return nil, fmt.Errorf("строка %d: ожидается \"<категория> <шаблон>\"", line)
+		}
+		rules = append(rules, Rule{Category: Category(category), Pattern: strings.TrimSpace(pattern)})
+	}
+
+	return rules, scanner.Err()
+}
+
+// Classify возвращает все совпадения правил в тексте в порядке следования
+func (c *WordlistClassifier) Classify(text string) []Match {
+	var matches []Match
+
+	start := -1
+	for i, r := range text + " " {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			if category, ok := c.matchWord(normalizeWord(text[start:i])); ok {
+				matches = append(matches, Match{Category: category, Start: start, End: i})
+			}
+			start = -1
+		}
+	}
+
+	for _, rule := range c.regexes {
+		for _, loc := range rule.pattern.FindAllStringIndex(text, -1) {
+			matches = append(matches, Match{Category: rule.category, Start: loc[0], End: loc[1]})
+		}
+	}
+
+	return matches
+}
+
+func (c *WordlistClassifier) matchWord(word string) (Category, bool) {
+	for _, rule := range c.words {
+		var matched bool
+		switch {
+		case rule.prefix && rule.suffix:
+			matched = strings.Contains(word, rule.stem)
+		case rule.prefix:
+			matched = strings.HasPrefix(word, rule.stem)
+		case rule.suffix:
+			matched = strings.HasSuffix(word, rule.stem)
+		default:
+			matched = word == rule.stem
+		}
+		if matched {
+			return rule.category, true
+		}
+	}
+	return "", false
+}
+
+// latinLookalikes латинские буквы, которыми подменяют кириллицу, чтобы обойти фильтр
+var latinLookalikes = strings.NewReplacer(
+	"a", "а", "b", "в", "c", "с", "e", "е", "h", "н", "k", "к", "m", "м",
+	"o", "о", "p", "р", "t", "т", "x", "х", "y", "у",
+)
+
+func normalizeWord(word string) string {
+	word = strings.ReplaceAll(strings.ToLower(word), "ё", "е")
+
+	for _, r := range word {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return latinLookalikes.Replace(word)
+		}
+	}
+	return word
+}
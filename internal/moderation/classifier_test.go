@@ -0,0 +1,69 @@
+package moderation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(strings.NewReader("# комментарий\n\nprofanity  плох*\nspam /buy now/\n"))
+	if err != nil {
+		t.Fatalf("ParseRules: %v", err)
+	}
+	want := []Rule{
+		{Category: CategoryProfanity, Pattern: "плох*"},
+		{Category: CategorySpam, Pattern: "/buy now/"},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("ParseRules = %v, want %v", rules, want)
+	}
+
+	if _, err := ParseRules(strings.NewReader("profanity\n")); err == nil {
+		t.Error("ParseRules без шаблона: ожидалась ошибка")
+	}
+}
+
+func TestNewWordlistClassifierInvalidRegex(t *testing.T) {
+	if _, err := NewWordlistClassifier([]Rule{{Category: CategorySpam, Pattern: "/(/"}}); err == nil {
+		t.Error("ожидалась ошибка для некорректного выражения")
+	}
+}
+
+func TestWordlistClassifierClassify(t *testing.T) {
+	classifier, err := NewWordlistClassifier([]Rule{
+		{Category: CategoryInsult, Pattern: "дурак"},
+		{Category: CategoryProfanity, Pattern: "плох*"},
+		{Category: CategoryProfanity, Pattern: "*ёж"},
+		{Category: CategoryInsult, Pattern: "*глуп*"},
+		{Category: CategorySpam, Pattern: "/https?://\\S+/"},
+	})
+	if err != nil {
+		t.Fatalf("NewWordlistClassifier: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []Match
+	}{
+		{"чистый текст", "хорошая книга", nil},
+		{"целое слово", "сам дурак!", []Match{{CategoryInsult, 7, 17}}},
+		{"целое слово не совпадает с частью", "дураки", nil},
+		{"регистр", "ДУРАК", []Match{{CategoryInsult, 0, 10}}},
+		{"начало слова", "плохая книга", []Match{{CategoryProfanity, 0, 12}}},
+		{"конец слова и ё/е", "ЕЖ", []Match{{CategoryProfanity, 0, 4}}},
+		{"часть слова", "поглупел", []Match{{CategoryInsult, 0, 16}}},
+		{"латинские двойники", "дурaк", []Match{{CategoryInsult, 0, 9}}},
+		{"латиница без кириллицы не подменяется", "tak", nil},
+		{"регулярное выражение", "see http://spam.example", []Match{{CategorySpam, 4, 23}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifier.Classify(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Classify(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package moderation
+
+import (
+	"book-management-system/config"
+	"book-management-system/pkg/logger"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Action что делать с текстом, в котором нашлась категория
+type Action string
+
+const (
+	ActionAllow  Action = "allow"  // пропустить как есть
+	ActionMask   Action = "mask"   // заменить найденные слова звездочками
+	ActionFlag   Action = "flag"   // сохранить, но отправить на модерацию
+	ActionReject Action = "reject" // не сохранять
+)
+
+// severity порядок действий: при нескольких категориях побеждает самое строгое
+var severity = map[Action]int{ActionAllow: 0, ActionMask: 1, ActionFlag: 2, ActionReject: 3}
+
+// Policy действие для каждой категории; категории без записи пропускаются
+type Policy map[Category]Action
+
+// DefaultPolicy политика по умолчанию
+var DefaultPolicy = Policy{
+	CategoryProfanity: ActionMask,
+	CategoryInsult:    ActionFlag,
+	CategorySpam:      ActionReject,
+}
+
+// ErrRejected текст отклонен фильтром
+var ErrRejected = errors.New("текст отклонен автоматической модерацией")
+
+// ParsePolicy разбирает политику вида "profanity=mask,insult=flag" поверх base
+func ParsePolicy(value string, base Policy) (Policy, error) {
+	policy := make(Policy, len(base))
+	for category, action := range base {
+		policy[category] = action
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		category, action, ok := strings.Cut(item, "=")
+		if _, known := severity[Action(action)]; !ok || !known {
+			return nil, fmt.Errorf("некорректное правило политики модерации %q", item)
+		}
+		policy[Category(strings.TrimSpace(category))] = Action(action)
+	}
+
+	return policy, nil
+}
+
+// Verdict результат проверки текста
+type Verdict struct {
+	Action Action
+	// Text исходный текст, в котором замаскированы категории с действием mask
+	Text string
+	// Categories категории, из-за которых текст отклонен или отправлен на модерацию
+	Categories []Category
+}
+
+// Err возвращает ErrRejected с перечнем категорий, если текст нельзя сохранять
+func (v Verdict) Err() error {
+	if v.Action != ActionReject {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrRejected, strings.Join(v.CategoryNames(), ", "))
+}
+
+// Flagged проверяет, нужно ли отправить текст на модерацию
+func (v Verdict) Flagged() bool {
+	return v.Action == ActionFlag
+}
+
+// CategoryNames возвращает категории строками, например для сохранения в MongoDB
+func (v Verdict) CategoryNames() []string {
+	names := make([]string, len(v.Categories))
+	for i, category := range v.Categories {
+		names[i] = string(category)
+	}
+	return names
+}
+
+// Filter применяет политику к результатам классификатора
+type Filter struct {
+	classifier Classifier
+	policy     Policy
+}
+
+// NewFilter создает фильтр с заданными классификатором и политикой
+func NewFilter(classifier Classifier, policy Policy) *Filter {
+	return &Filter{classifier: classifier, policy: policy}
+}
+
+// Check проверяет текст и решает, что с ним делать
+func (f *Filter) Check(text string) Verdict {
+	verdict := Verdict{Action: ActionAllow, Text: text}
+
+	var masked []Match
+	for _, match := range f.classifier.Classify(text) {
+		action, ok := f.policy[match.Category]
+		if !ok || action == ActionAllow {
+			continue
+		}
+
+		if action == ActionMask {
+			masked = append(masked, match)
+		} else if !slices.Contains(verdict.Categories, match.Category) {
+			verdict.Categories = append(verdict.Categories, match.Category)
+		}
+
+		if severity[action] > severity[verdict.Action] {
+			verdict.Action = action
+		}
+	}
+
+	verdict.Text = mask(text, masked)
+	return verdict
+}
+
+// mask заменяет найденные фрагменты звездочками, оставляя первую букву
+func mask(text string, matches []Match) string {
+	if len(matches) == 0 {
+		return text
+	}
+
+	masked := []rune{}
+	covered := make([]bool, len(text))
+	for _, match := range matches {
+		for i := match.Start; i < match.End; i++ {
+			covered[i] = true
+		}
+	}
+
+	for i, r := range text {
+		if covered[i] && i > 0 && covered[i-1] && r != ' ' {
+			masked = append(masked, '*')
+			continue
+		}
+		masked = append(masked, r)
+	}
+	return string(masked)
+}
+
+//go:embed wordlists/*.txt
+var wordlists embed.FS
+
+var (
+	filter     *Filter
+	filterOnce sync.Once
+)
+
+// GetFilter возвращает фильтр, собранный из встроенных словарей (ru, en), дополнительного
+// файла MODERATION_WORDLIST_FILE и политики MODERATION_POLICY (например "insult=reject,spam=flag")
+func GetFilter() *Filter {
+	filterOnce.Do(func() {
+		log := logger.GetLogger()
+
+		var rules []Rule
+		for _, name := range []string{"wordlists/ru.txt", "wordlists/en.txt"} {
+			data, err := wordlists.ReadFile(name)
+			if err != nil {
+				log.Fatalf("Ошибка чтения встроенного словаря %s: %v", name, err)
+			}
+			parsed, err := ParseRules(bytes.NewReader(data))
+			if err != nil {
+				log.Fatalf("Ошибка разбора встроенного словаря %s: %v", name, err)
+			}
+			rules = append(rules, parsed...)
+		}
+
+		if path := config.GetEnv("MODERATION_WORDLIST_FILE", ""); path != "" {
+			file, err := os.Open(path)
+			if err != nil {
+				log.Fatalf("Ошибка открытия словаря модерации %s: %v", path, err)
+			}
+			parsed, err := ParseRules(file)
+			file.Close()
+			if err != nil {
+				log.Fatalf("Ошибка разбора словаря модерации %s: %v", path, err)
+			}
+			rules = append(rules, parsed...)
+		}
+
+		classifier, err := NewWordlistClassifier(rules)
+		if err != nil {
+			log.Fatalf("Ошибка сборки словаря модерации: %v", err)
+		}
+
+		policy, err := ParsePolicy(config.GetEnv("MODERATION_POLICY", ""), DefaultPolicy)
+		if err != nil {
+			log.Fatalf("Ошибка разбора MODERATION_POLICY: %v", err)
+		}
+
+		filter = NewFilter(classifier, policy)
+	})
+	return filter
+}
@@ -0,0 +1,113 @@
+package moderation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// stubClassifier находит заданные фрагменты текста
+type stubClassifier []Match
+
+func (c stubClassifier) Classify(string) []Match {
+	return c
+}
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Policy
+		wantErr bool
+	}{
+		{"пустая строка — базовая политика", "", DefaultPolicy, false},
+		{
+			"переопределение и новая категория",
+			" insult=reject , ads=flag",
+			Policy{CategoryProfanity: ActionMask, CategoryInsult: ActionReject, CategorySpam: ActionReject, "ads": ActionFlag},
+			false,
+		},
+		{"неизвестное действие", "spam=ban", nil, true},
+		{"без действия", "spam", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolicy(tt.value, DefaultPolicy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolicy(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePolicy(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	if DefaultPolicy[CategoryInsult] != ActionFlag {
+		t.Error("ParsePolicy изменил базовую политику")
+	}
+}
+
+func TestFilterCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		matches    []Match
+		action     Action
+		masked     string
+		categories []Category
+	}{
+		{"ничего не найдено", "текст", nil, ActionAllow, "текст", nil},
+		{"маска оставляет первую букву", "ну блин же", []Match{{CategoryProfanity, 5, 13}}, ActionMask, "ну б*** же", nil},
+		{
+			"маска не трогает пробелы внутри фрагмента",
+			"buy now here",
+			[]Match{{CategoryProfanity, 0, 7}},
+			ActionMask, "b** *** here", nil,
+		},
+		{
+			"побеждает самое строгое действие",
+			"блин дурак",
+			[]Match{{CategoryProfanity, 0, 8}, {CategoryInsult, 9, 19}},
+			ActionFlag, "б*** дурак", []Category{CategoryInsult},
+		},
+		{
+			"категория без политики пропускается",
+			"текст",
+			[]Match{{"unknown", 0, 10}},
+			ActionAllow, "текст", nil,
+		},
+		{
+			"отклонение и повторы категорий",
+			"spam spam",
+			[]Match{{CategorySpam, 0, 4}, {CategorySpam, 5, 9}, {CategoryInsult, 0, 4}},
+			ActionReject, "spam spam", []Category{CategorySpam, CategoryInsult},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := NewFilter(stubClassifier(tt.matches), DefaultPolicy).Check(tt.text)
+			if verdict.Action != tt.action {
+				t.Errorf("Action = %s, want %s", verdict.Action, tt.action)
+			}
+			if verdict.Text != tt.masked {
+				t.Errorf("Text = %q, want %q", verdict.Text, tt.masked)
+			}
+			if !reflect.DeepEqual(verdict.Categories, tt.categories) {
+				t.Errorf("Categories = %v, want %v", verdict.Categories, tt.categories)
+			}
+		})
+	}
+}
+
+func TestVerdictErr(t *testing.T) {
+	if err := (Verdict{Action: ActionFlag}).Err(); err != nil {
+		t.Errorf("Err для flag = %v, want nil", err)
+	}
+
+	err := Verdict{Action: ActionReject, Categories: []Category{CategorySpam}}.Err()
+	if !errors.Is(err, ErrRejected) {
+		t.Errorf("Err для reject = %v, want ErrRejected", err)
+	}
+}
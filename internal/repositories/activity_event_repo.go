@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ActivityEventRepository struct {
+	db  *gorm.DB
+	log *logger.Logger
+}
+
+// NewActivityEventRepository создает репозиторий событий ленты
+func NewActivityEventRepository() *ActivityEventRepository {
+	return &ActivityEventRepository{
+		db:  database.DB,
+		log: logger.GetLogger(),
+	}
+}
+
+// FeedEvent событие ленты с именем того, кто его совершил, и названием книги
+type FeedEvent struct {
+	models.ActivityEvent
+	ActorName string
+	BookTitle string
+}
+
+// CreateEvents сохраняет события
+func (r *ActivityEventRepository) CreateEvents(events []models.ActivityEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := r.db.Create(&events).Error; err != nil {
+		r.log.Warnf("Ошибка сохранения событий ленты: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetFeed собирает ленту подписчика из событий тех, на кого он подписан, новые первыми.
+// События удаленных книг пропускаются. Маркер afterID — последнее событие предыдущей страницы
+func (r *ActivityEventRepository) GetFeed(followerID uuid.UUID, limit int, afterID *uuid.UUID) ([]FeedEvent, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var events []FeedEvent
+
+	query := r.db.Model(&models.ActivityEvent{}).
+		Select(`activity_events.*,
+			COALESCE(users.username, authors.name, '') AS actor_name,
+			books.title AS book_title`).
+		Joins("JOIN follows ON follows.target_type = activity_events.actor_type AND follows.target_id = activity_events.actor_id").
+		Joins("JOIN books ON books.id = activity_events.book_id AND books.deleted_at IS NULL").
+		// users.id хранится как text, поэтому приводим UUID события к тексту
+		Joins("LEFT JOIN users ON activity_events.actor_type = ? AND users.id = activity_events.actor_id::text", models.FollowTargetUser).
+		Joins("LEFT JOIN authors ON activity_events.actor_type = ? AND authors.id = activity_events.actor_id", models.FollowTargetAuthor).
+		Where("follows.follower_id = ?", followerID).
+		Order("activity_events.created_at DESC, activity_events.id DESC").
+		Limit(limit)
+
+	if afterID != nil {
+		query = query.Where("(activity_events.created_at, activity_events.id) < (?)", r.db.Model(&models.ActivityEvent{}).
+			Select("created_at, id").
+			Where("id = ?", *afterID))
+	}
+
+	if err := query.Scan(&events).Error; err != nil {
+		r.log.Warnf("Ошибка получения ленты пользователя %s: %v", followerID, err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetEventsByUser получает события, совершенные пользователем
+func (r *ActivityEventRepository) GetEventsByUser(userID uuid.UUID) ([]models.ActivityEvent, error) {
+	var events []models.ActivityEvent
+	err := r.db.Where("actor_type = ? AND actor_id = ?", models.FollowTargetUser, userID).
+		Order("created_at").
+		Find(&events).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения событий пользователя %s: %v", userID, err)
+		return nil, err
+	}
+	return events, nil
+}
+
+// DeleteEventsByUser удаляет события, совершенные пользователем
+func (r *ActivityEventRepository) DeleteEventsByUser(userID uuid.UUID) error {
+	err := r.db.Where("actor_type = ? AND actor_id = ?", models.FollowTargetUser, userID).
+		Delete(&models.ActivityEvent{}).Error
+	if err != nil {
+		r.log.Warnf("Ошибка удаления событий пользователя %s: %v", userID, err)
+		return err
+	}
+	return nil
+}
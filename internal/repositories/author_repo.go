@@ -43,8 +43,7 @@ func (r *AuthorRepository) CreateAuthor(author *models.Author, bookIds []uuid.UU
 
 func (r *AuthorRepository) GetAuthorByID(authorID uuid.UUID) (*models.Author, error) {
 	var author models.Author
-	err := r.db.Preload("Books").
-		Where("id = ?", authorID).
+	err := r.db.Where("id = ?", authorID).
 		First(&author).Error
 
 	if err != nil {
@@ -146,3 +145,13 @@ func (r *AuthorRepository) GetAuthorsByIDs(authorIDs []uuid.UUID) ([]models.Auth
 
 	return authors, nil
 }
+
+// GetAuthorByName ищет автора по имени без учета регистра
+func (r *AuthorRepository) GetAuthorByName(name string) (*models.Author, error) {
+	var author models.Author
+	err := r.db.Where("LOWER(name) = LOWER(?)", name).Order("created_at ASC").First(&author).Error
+	if err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
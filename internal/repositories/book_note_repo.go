@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookNoteRepository struct {
+	db  *gorm.DB
+	log *logger.Logger
+}
+
+// QuoteWithVotes цитата вместе с посчитанными голосами
+type QuoteWithVotes struct {
+	models.BookNote
+	Likes    int
+	Dislikes int
+}
+
+// NewBookNoteRepository создает новый репозиторий заметок к книгам
+func NewBookNoteRepository() *BookNoteRepository {
+	return &BookNoteRepository{
+		db:  database.DB,
+		log: logger.GetLogger(),
+	}
+}
+
+// CreateNote сохраняет новую заметку
+func (r *BookNoteRepository) CreateNote(note *models.BookNote) error {
+	err := r.db.Create(note).Error
+	if err != nil {
+		r.log.Warnf("Ошибка создания заметки: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetUserNote получает заметку пользователя к книге по ID
+func (r *BookNoteRepository) GetUserNote(userID, bookID, noteID uuid.UUID) (*models.BookNote, error) {
+	var note models.BookNote
+	err := r.db.Where("id = ? AND user_id = ? AND book_id = ?", noteID, userID, bookID).
+		First(&note).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения заметки %s: %v", noteID, err)
+		return nil, err
+	}
+	return &note, nil
+}
+
+// GetNotesByUserBook получает все заметки пользователя к книге
+func (r *BookNoteRepository) GetNotesByUserBook(userID, bookID uuid.UUID) ([]models.BookNote, error) {
+	var notes []models.BookNote
+	err := r.db.Where("user_id = ? AND book_id = ?", userID, bookID).
+		Order("created_at ASC").
+		Find(&notes).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения заметок пользователя %s к книге %s: %v", userID, bookID, err)
+		return nil, err
+	}
+	return notes, nil
+}
+
+// GetNotesByUser получает все заметки пользователя
+func (r *BookNoteRepository) GetNotesByUser(userID uuid.UUID) ([]models.BookNote, error) {
+	var notes []models.BookNote
+	err := r.db.Where("user_id = ?", userID).Find(&notes).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения заметок пользователя %s: %v", userID, err)
+		return nil, err
+	}
+	return notes, nil
+}
+
+// UpdateNote сохраняет изменения заметки
+func (r *BookNoteRepository) UpdateNote(note *models.BookNote) error {
+	err := r.db.Save(note).Error
+	if err != nil {
+		r.log.Warnf("Ошибка обновления заметки %s: %v", note.ID, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteNote удаляет заметку вместе с голосами за нее
+func (r *BookNoteRepository) DeleteNote(noteID uuid.UUID) error {
+	tx := r.db.Begin()
+
+	if err := tx.Where("note_id = ?", noteID).Delete(&models.BookNoteVote{}).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка удаления голосов за заметку %s: %v", noteID, err)
+		return err
+	}
+
+	if err := tx.Where("id = ?", noteID).Delete(&models.BookNote{}).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка удаления заметки %s: %v", noteID, err)
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// DeleteNotesByUser удаляет все заметки пользователя и голоса за них
+func (r *BookNoteRepository) DeleteNotesByUser(userID uuid.UUID) error {
+	tx := r.db.Begin()
+
+	if err := deleteNotesTx(tx, r.db.Model(&models.BookNote{}).Select("id").Where("user_id = ?", userID)); err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка удаления заметок пользователя %s: %v", userID, err)
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// GetPublicQuotesPaginated получает публичные цитаты книги с голосами и маркерной пагинацией
+func (r *BookNoteRepository) GetPublicQuotesPaginated(bookID uuid.UUID, limit int, afterID *uuid.UUID) ([]QuoteWithVotes, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var quotes []QuoteWithVotes
+
+	query := r.db.Model(&models.BookNote{}).
+		Select(`book_notes.*,
+			(SELECT COUNT(*) FROM book_note_votes v WHERE v.note_id = book_notes.id AND v.vote = 1) AS likes,
+			(SELECT COUNT(*) FROM book_note_votes v WHERE v.note_id = book_notes.id AND v.vote = -1) AS dislikes`).
+		Where("book_id = ? AND kind = ? AND visibility = ?", bookID, models.NoteKindQuote, models.NoteVisibilityPublic).
+		Order("created_at ASC").
+		Limit(limit)
+
+	if afterID != nil {
+		query = query.Where("created_at > (?)", r.db.Model(&models.BookNote{}).
+			Select("created_at").
+			Where("id = ?", *afterID))
+	}
+
+	if err := query.Scan(&quotes).Error; err != nil {
+		r.log.Warnf("Ошибка получения цитат книги %s: %v", bookID, err)
+		return nil, err
+	}
+
+	return quotes, nil
+}
+
+// GetPublicQuote получает публичную цитату книги по ID
+func (r *BookNoteRepository) GetPublicQuote(bookID, noteID uuid.UUID) (*models.BookNote, error) {
+	var note models.BookNote
+	err := r.db.Where("id = ? AND book_id = ? AND kind = ? AND visibility = ?",
+		noteID, bookID, models.NoteKindQuote, models.NoteVisibilityPublic).
+		First(&note).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения цитаты %s: %v", noteID, err)
+		return nil, err
+	}
+	return &note, nil
+}
+
+// VoteNote добавляет/обновляет голос пользователя за цитату, 0 — снимает голос
+func (r *BookNoteRepository) VoteNote(noteID, userID uuid.UUID, vote int) error {
+	var err error
+
+	if vote == 0 {
+		err = r.db.Where("note_id = ? AND user_id = ?", noteID, userID).
+			Delete(&models.BookNoteVote{}).Error
+	} else {
+		err = r.db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "note_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"vote"}),
+		}).Create(&models.BookNoteVote{NoteID: noteID, UserID: userID, Vote: vote}).Error
+	}
+
+	if err != nil {
+		r.log.Warnf("Ошибка голосования за цитату %s: %v", noteID, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetNoteVotesByUser получает все голоса пользователя за цитаты
+func (r *BookNoteRepository) GetNoteVotesByUser(userID uuid.UUID) ([]models.BookNoteVote, error) {
+	var votes []models.BookNoteVote
+	err := r.db.Where("user_id = ?", userID).Find(&votes).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения голосов пользователя %s за цитаты: %v", userID, err)
+		return nil, err
+	}
+	return votes, nil
+}
+
+// DeleteNoteVotesByUser удаляет все голоса пользователя за цитаты
+func (r *BookNoteRepository) DeleteNoteVotesByUser(userID uuid.UUID) error {
+	err := r.db.Where("user_id = ?", userID).Delete(&models.BookNoteVote{}).Error
+	if err != nil {
+		r.log.Warnf("Ошибка удаления голосов пользователя %s за цитаты: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
+// deleteNotesTx удаляет заметки, выбранные подзапросом noteIDs, вместе с голосами за них
+func deleteNotesTx(tx *gorm.DB, noteIDs *gorm.DB) error {
+	if err := tx.Where("note_id IN (?)", noteIDs).Delete(&models.BookNoteVote{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Where("id IN (?)", noteIDs).Delete(&models.BookNote{}).Error
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BookRatingRepository struct {
+	db  *gorm.DB
+	log *logger.Logger
+}
+
+// NewBookRatingRepository создает новый репозиторий оценок книг
+func NewBookRatingRepository() *BookRatingRepository {
+	return &BookRatingRepository{
+		db:  database.DB,
+		log: logger.GetLogger(),
+	}
+}
+
+// GetUserRatingsForBooks получает оценки пользователя для набора книг
+func (r *BookRatingRepository) GetUserRatingsForBooks(userID uuid.UUID, bookIDs []uuid.UUID) ([]models.BookRating, error) {
+	var ratings []models.BookRating
+
+	err := r.db.Model(&models.BookRating{}).
+		Where("user_id = ? AND book_id IN (?)", userID, bookIDs).
+		Find(&ratings).Error
+
+	if err != nil {
+		r.log.Warnf("Ошибка получения оценок пользователя %s: %v", userID, err)
+		return nil, err
+	}
+
+	return ratings, nil
+}
+
+// GetRatingsByUser получает все оценки пользователя
+func (r *BookRatingRepository) GetRatingsByUser(userID uuid.UUID) ([]models.BookRating, error) {
+	var ratings []models.BookRating
+
+	err := r.db.Where("user_id = ?", userID).Find(&ratings).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения оценок пользователя %s: %v", userID, err)
+		return nil, err
+	}
+
+	return ratings, nil
+}
+
+// DeleteRatingsByUser удаляет все оценки пользователя
+func (r *BookRatingRepository) DeleteRatingsByUser(userID uuid.UUID) error {
+	err := r.db.Where("user_id = ?", userID).Delete(&models.BookRating{}).Error
+	if err != nil {
+		r.log.Warnf("Ошибка удаления оценок пользователя %s: %v", userID, err)
+		return err
+	}
+
+	return nil
+}
@@ -4,10 +4,11 @@ import (
 	database2 "book-management-system/internal/database"
 	"book-management-system/internal/models"
 	"book-management-system/pkg/logger"
-	"context"
+	"book-management-system/pkg/utils"
 	"github.com/google/uuid"
-	"go.mongodb.org/mongo-driver/bson"
 	"gorm.io/gorm"
+	"strings"
+	"time"
 )
 
 type BookRepository struct {
@@ -27,9 +28,52 @@ func NewBookRepository() *BookRepository {
 func (r *BookRepository) CreateBook(book *models.Book, authorIDs []uuid.UUID) error {
 	tx := r.db.Begin()
 
+	if err := r.createBook(tx, book, authorIDs); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// NewBookWithAuthors книга для пакетного создания вместе с ее авторами и жанрами
+type NewBookWithAuthors struct {
+	Book      *models.Book
+	AuthorIDs []uuid.UUID
+	GenreIDs  []uuid.UUID
+}
+
+// CreateBooks создает пачку книг вместе с новыми авторами authors одной транзакцией: либо создаются все, либо ничего
+func (r *BookRepository) CreateBooks(authors []*models.Author, books []NewBookWithAuthors) error {
+	tx := r.db.Begin()
+
+	for _, author := range authors {
+		if err := tx.Create(author).Error; err != nil {
+			tx.Rollback()
+			r.log.Warnf("Ошибка создания автора %q: %v", author.Name, err)
+			return err
+		}
+	}
+	for _, entry := range books {
+		if err := r.createBook(tx, entry.Book, entry.AuthorIDs); err != nil {
+			tx.Rollback()
+			return err
+		}
+		for _, genreID := range entry.GenreIDs {
+			if err := tx.Create(&models.BookGenre{BookID: entry.Book.ID, GenreID: genreID}).Error; err != nil {
+				tx.Rollback()
+				r.log.Warnf("Ошибка связывания книги с жанром: %v", err)
+				return err
+			}
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (r *BookRepository) createBook(tx *gorm.DB, book *models.Book, authorIDs []uuid.UUID) error {
 	// Создаем книгу
 	if err := tx.Create(book).Error; err != nil {
-		tx.Rollback()
 		r.log.Warnf("Ошибка создания книги: %v", err)
 		return err
 	}
@@ -38,13 +82,11 @@ func (r *BookRepository) CreateBook(book *models.Book, authorIDs []uuid.UUID) er
 	for _, authorID := range authorIDs {
 		bookAuthor := models.BookAuthor{BookID: &book.ID, AuthorID: &authorID}
 		if err := tx.Create(&bookAuthor).Error; err != nil {
-			tx.Rollback()
 			r.log.Warnf("Ошибка связывания книги с авторамм: %v", err)
 			return err
 		}
 	}
-
-	return tx.Commit().Error
+	return nil
 }
 
 // GetBookByID получает книгу по ID, с возможной фильтрацией по `confirmed`
@@ -98,7 +140,20 @@ func (r *BookRepository) UpdateBook(book *models.Book, authorIDs []uuid.UUID) er
 	return tx.Commit().Error
 }
 
-// DeleteBook удаляет книгу и связи с авторами (ставит NULL)
+// ConfirmBook помечает книгу подтвержденной и запоминает время подтверждения, не трогая остальные поля
+// и связи с авторами. Возвращает false, если книга уже была подтверждена
+func (r *BookRepository) ConfirmBook(bookID uuid.UUID) (bool, error) {
+	result := r.db.Model(&models.Book{}).
+		Where("id = ? AND confirmed = ?", bookID, false).
+		Updates(map[string]interface{}{"confirmed": true, "confirmed_at": time.Now().UTC()})
+	if result.Error != nil {
+		r.log.Warnf("Ошибка подтверждения книги %s: %v", bookID, result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteBook удаляет книгу и связи с авторами (ставит NULL), удаление ее отзывов ставит в outbox
 func (r *BookRepository) DeleteBook(bookID uuid.UUID) error {
 	tx := r.db.Begin()
 
@@ -127,21 +182,24 @@ func (r *BookRepository) DeleteBook(bookID uuid.UUID) error {
 		return err
 	}
 
-	// 4. Удаляем саму книгу (soft delete)
+	// 4. Удаляем связи с жанрами: жанр без книг просто не показывается
+	if err := tx.Where("book_id = ?", bookID).Delete(&models.BookGenre{}).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка удаления связей книги с жанрами: %v", err)
+		return err
+	}
+
+	// 5. Удаляем саму книгу (soft delete)
 	if err := tx.Where("id = ?", bookID).Delete(&models.Book{}).Error; err != nil {
 		tx.Rollback()
 		r.log.Warnf("Ошибка удаления книги: %v", err)
 		return err
 	}
 
-	// 5. Удаляем отзывы из MongoDB
-	_, err := database2.MongoDB.Database("bookstore").
-		Collection("reviews").
-		DeleteMany(context.TODO(), bson.M{"book_id": bookID})
-
-	if err != nil {
+	// 6. Отзывы живут в MongoDB и в эту транзакцию не входят: удалим их из outbox после коммита
+	if err := enqueueOutboxMessage(tx, models.OutboxDeleteBookReviews, bookID); err != nil {
 		tx.Rollback()
-		r.log.Warnf("Ошибка удаления отзывов в MongoDB: %v", err)
+		r.log.Warnf("Ошибка записи удаления отзывов книги в outbox: %v", err)
 		return err
 	}
 
@@ -176,11 +234,15 @@ func (r *BookRepository) GetBooksPaginated(limit int, afterID *uuid.UUID) ([]mod
 	return books, nil
 }
 
-// UpdateBookRating обновляет средний рейтинг книги в PostgreSQL
-func (r *BookRepository) UpdateBookRating(bookID uuid.UUID, averageRating float64) error {
+// UpdateBookRating обновляет простой средний и байесовский рейтинги книги в PostgreSQL
+func (r *BookRepository) UpdateBookRating(bookID uuid.UUID, averageRating, bayesianRating float64, ratingsCount int) error {
 	err := r.db.Model(&models.Book{}).
 		Where("id = ?", bookID).
-		Update("average_rating", averageRating).Error
+		Updates(map[string]interface{}{
+			"average_rating":  averageRating,
+			"bayesian_rating": bayesianRating,
+			"ratings_count":   ratingsCount,
+		}).Error
 
 	if err != nil {
 		r.log.Warnf("Ошибка обновления среднего рейтинга книги %s: %v", bookID, err)
@@ -190,6 +252,26 @@ func (r *BookRepository) UpdateBookRating(bookID uuid.UUID, averageRating float6
 	return nil
 }
 
+// GetTopRatedBooks получает подтвержденные книги с отзывами по убыванию байесовского рейтинга
+func (r *BookRepository) GetTopRatedBooks(limit int) ([]models.Book, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var books []models.Book
+	err := r.db.Model(&models.Book{}).
+		Where("confirmed = ? AND ratings_count > 0", true).
+		Order("bayesian_rating DESC, ratings_count DESC, id ASC").
+		Limit(limit).
+		Find(&books).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения книг с лучшим рейтингом: %v", err)
+		return nil, err
+	}
+
+	return books, nil
+}
+
 // UpdateBookCover обновляет путь к обложке в базе
 func (r *BookRepository) UpdateBookCover(bookID string, coverPath string) error {
 	err := r.db.Model(&models.Book{}).
@@ -217,3 +299,174 @@ func (r *BookRepository) GetBooksByIds(bookIDs []uuid.UUID) ([]models.Book, erro
 
 	return books, nil
 }
+
+// GetAllBookIDs возвращает ID всех неудаленных книг, включая неподтвержденные
+func (r *BookRepository) GetAllBookIDs() ([]uuid.UUID, error) {
+	var bookIDs []uuid.UUID
+	if err := r.db.Model(&models.Book{}).Order("created_at ASC, id ASC").Pluck("id", &bookIDs).Error; err != nil {
+		r.log.Warnf("Ошибка получения ID книг: %v", err)
+		return nil, err
+	}
+	return bookIDs, nil
+}
+
+// GetRatedBookIDs возвращает ID книг, у которых есть учтенные в рейтинге отзывы
+func (r *BookRepository) GetRatedBookIDs() ([]uuid.UUID, error) {
+	var bookIDs []uuid.UUID
+	if err := r.db.Model(&models.Book{}).Where("ratings_count > 0").Order("id ASC").Pluck("id", &bookIDs).Error; err != nil {
+		r.log.Warnf("Ошибка получения ID книг с рейтингом: %v", err)
+		return nil, err
+	}
+	return bookIDs, nil
+}
+
+// GetAllConfirmedBooks возвращает все подтвержденные книги в порядке добавления
+func (r *BookRepository) GetAllConfirmedBooks() ([]models.Book, error) {
+	var books []models.Book
+	if err := r.db.Where("confirmed = ?", true).Order("created_at ASC, id ASC").Find(&books).Error; err != nil {
+		r.log.Warnf("Ошибка получения подтвержденных книг: %v", err)
+		return nil, err
+	}
+	return books, nil
+}
+
+// FindBooksByTitle ищет неудаленные книги с таким же названием без учета регистра
+func (r *BookRepository) FindBooksByTitle(title string) ([]models.Book, error) {
+	var books []models.Book
+	if err := r.db.Where("LOWER(title) = LOWER(?)", title).Find(&books).Error; err != nil {
+		r.log.Warnf("Ошибка поиска книг по названию: %v", err)
+		return nil, err
+	}
+	return books, nil
+}
+
+// FindBooksByISBNs возвращает неудаленные книги с любым из указанных ISBN-13
+func (r *BookRepository) FindBooksByISBNs(isbns []string) ([]models.Book, error) {
+	var books []models.Book
+	if len(isbns) == 0 {
+		return books, nil
+	}
+	if err := r.db.Where("isbn IN (?)", isbns).Find(&books).Error; err != nil {
+		r.log.Warnf("Ошибка поиска книг по ISBN: %v", err)
+		return nil, err
+	}
+	return books, nil
+}
+
+// FindBooksByNormalizedTitles возвращает неудаленные книги с любым из нормализованных названий, см. utils.NormalizeTitle
+func (r *BookRepository) FindBooksByNormalizedTitles(titles []string) ([]models.Book, error) {
+	var books []models.Book
+	if len(titles) == 0 {
+		return books, nil
+	}
+	if err := r.db.Where("normalized_title IN (?)", titles).Find(&books).Error; err != nil {
+		r.log.Warnf("Ошибка поиска книг по нормализованному названию: %v", err)
+		return nil, err
+	}
+	return books, nil
+}
+
+// GetConfirmedBooksInBatches обходит подтвержденные книги пачками по ID, не загружая каталог целиком
+func (r *BookRepository) GetConfirmedBooksInBatches(batchSize int, fn func([]models.Book) error) error {
+	var lastID *uuid.UUID
+
+	for {
+		var batch []models.Book
+
+		query := r.db.Where("confirmed = ?", true).Order("id ASC").Limit(batchSize)
+		if lastID != nil {
+			query = query.Where("id > ?", *lastID)
+		}
+
+		if err := query.Find(&batch).Error; err != nil {
+			r.log.Warnf("Ошибка обхода подтвержденных книг: %v", err)
+			return err
+		}
+
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := fn(batch); err != nil {
+			return err
+		}
+
+		lastID = &batch[len(batch)-1].ID
+	}
+}
+
+// GetNewestBooksPaginated возвращает подтвержденные книги по убыванию времени подтверждения с маркерной
+// пагинацией: книга, добавленная давно и подтвержденная сегодня, тоже новинка. Если genreID задан — только книги этого жанра
+func (r *BookRepository) GetNewestBooksPaginated(limit int, afterID *uuid.UUID, genreID *uuid.UUID) ([]models.Book, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var books []models.Book
+	query := r.db.Model(&models.Book{}).
+		Where("confirmed = ?", true).
+		Order("confirmed_at DESC, id DESC").
+		Limit(limit)
+
+	// id разделяет книги, подтвержденные в одно время, чтобы маркер не пропускал и не повторял их
+	if afterID != nil {
+		query = query.Where("(confirmed_at, id) < (?)", r.db.Model(&models.Book{}).
+			Select("confirmed_at, id").
+			Where("id = ?", *afterID))
+	}
+	if genreID != nil {
+		query = query.Where("id IN (?)", r.db.Model(&models.BookGenre{}).
+			Select("book_id").
+			Where("genre_id = ?", *genreID))
+	}
+
+	if err := query.Find(&books).Error; err != nil {
+		r.log.Warnf("Ошибка получения новых книг: %v", err)
+		return nil, err
+	}
+	return books, nil
+}
+
+// GetConfirmedBooksByAuthor возвращает подтвержденные книги автора по названию
+func (r *BookRepository) GetConfirmedBooksByAuthor(authorID uuid.UUID) ([]models.Book, error) {
+	var books []models.Book
+	err := r.db.Where("confirmed = ? AND id IN (?)", true, r.db.Model(&models.BookAuthor{}).
+		Select("book_id").
+		Where("author_id = ?", authorID)).
+		Order("title ASC").
+		Find(&books).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения книг автора %s: %v", authorID, err)
+		return nil, err
+	}
+	return books, nil
+}
+
+// SearchConfirmedBooks ищет подтвержденные книги по части названия или имени автора.
+// Название сравнивается в нормализованном виде, см. utils.NormalizeTitle
+func (r *BookRepository) SearchConfirmedBooks(query string, limit, offset int) ([]models.Book, error) {
+	var books []models.Book
+
+	pattern := "%" + escapeLike(utils.NormalizeTitle(query)) + "%"
+	authorPattern := "%" + escapeLike(strings.ToLower(strings.TrimSpace(query))) + "%"
+
+	err := r.db.Where("confirmed = ?", true).
+		Where("normalized_title LIKE ? OR id IN (?)", pattern, r.db.Model(&models.BookAuthor{}).
+			Select("book_authors.book_id").
+			Joins("JOIN authors ON authors.id = book_authors.author_id AND authors.deleted_at IS NULL").
+			Where("LOWER(authors.name) LIKE ?", authorPattern)).
+		Order("title ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&books).Error
+	if err != nil {
+		r.log.Warnf("Ошибка поиска книг по запросу %q: %v", query, err)
+		return nil, err
+	}
+	return books, nil
+}
+
+// escapeLike экранирует спецсимволы LIKE, чтобы пользовательский запрос искался буквально
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ConsistencyRepository запросы к PostgreSQL для поиска и исправления рассинхронизации хранилищ
+type ConsistencyRepository struct {
+	db  *gorm.DB
+	log *logger.Logger
+}
+
+// BookRatingSnapshot рейтинг книги, закешированный в PostgreSQL
+type BookRatingSnapshot struct {
+	ID            uuid.UUID
+	AverageRating float64
+	RatingsCount  int
+}
+
+// NewConsistencyRepository создает репозиторий проверки согласованности
+func NewConsistencyRepository() *ConsistencyRepository {
+	return &ConsistencyRepository{
+		db:  database.DB,
+		log: logger.GetLogger(),
+	}
+}
+
+// GetLiveBookIDs возвращает те из bookIDs, которые принадлежат существующим неудаленным книгам
+func (r *ConsistencyRepository) GetLiveBookIDs(bookIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var live []uuid.UUID
+	if len(bookIDs) == 0 {
+		return live, nil
+	}
+
+	if err := r.db.Model(&models.Book{}).Where("id IN (?)", bookIDs).Pluck("id", &live).Error; err != nil {
+		r.log.Warnf("Ошибка проверки существования книг: %v", err)
+		return nil, err
+	}
+	return live, nil
+}
+
+// GetBookRatingSnapshots возвращает закешированные рейтинги всех неудаленных книг
+func (r *ConsistencyRepository) GetBookRatingSnapshots() ([]BookRatingSnapshot, error) {
+	var snapshots []BookRatingSnapshot
+	if err := r.db.Model(&models.Book{}).Select("id, average_rating, ratings_count").Scan(&snapshots).Error; err != nil {
+		r.log.Warnf("Ошибка получения рейтингов книг: %v", err)
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// CountDanglingBookAuthors считает связи книг с авторами, у которых DeleteBook обнулил одну из сторон
+func (r *ConsistencyRepository) CountDanglingBookAuthors() (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.BookAuthor{}).Where("book_id IS NULL OR author_id IS NULL").Count(&count).Error; err != nil {
+		r.log.Warnf("Ошибка подсчета висячих связей книг с авторами: %v", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// DeleteDanglingBookAuthors удаляет связи книг с авторами без одной из сторон
+func (r *ConsistencyRepository) DeleteDanglingBookAuthors() (int64, error) {
+	result := r.db.Where("book_id IS NULL OR author_id IS NULL").Delete(&models.BookAuthor{})
+	if result.Error != nil {
+		r.log.Warnf("Ошибка удаления висячих связей книг с авторами: %v", result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// GetUsersWithOrphanBooks возвращает пользователей, у которых в библиотеке остались записи удаленных книг, и число таких записей
+func (r *ConsistencyRepository) GetUsersWithOrphanBooks() (map[uuid.UUID]int, error) {
+	var rows []struct {
+		UserID uuid.UUID
+		Count  int
+	}
+	err := r.db.Model(&models.UserBook{}).
+		Select("user_id, COUNT(*) AS count").
+		Where("book_id IS NULL").
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil {
+		r.log.Warnf("Ошибка поиска записей библиотеки без книги: %v", err)
+		return nil, err
+	}
+
+	users := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		users[row.UserID] = row.Count
+	}
+	return users, nil
+}
+
+// DeleteOrphanUserBooks удаляет записи библиотеки, книга которых удалена
+func (r *ConsistencyRepository) DeleteOrphanUserBooks() (int64, error) {
+	result := r.db.Where("book_id IS NULL").Delete(&models.UserBook{})
+	if result.Error != nil {
+		r.log.Warnf("Ошибка удаления записей библиотеки без книги: %v", result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
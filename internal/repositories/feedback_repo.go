@@ -26,9 +26,12 @@ func NewFeedbackRepository() *FeedbackRepository {
 	}
 }
 
-func (r *FeedbackRepository) CreateFeedback(feedbackDto dto.BaseFeedbackRequest, reviewer uuid.UUID) (primitive.ObjectID, error) {
+// CreateFeedback сохраняет отзыв о приложении; autoFlags — категории, найденные автоматическим фильтром
+func (r *FeedbackRepository) CreateFeedback(feedbackDto dto.BaseFeedbackRequest, reviewer uuid.UUID, autoFlags []string) (primitive.ObjectID, error) {
 	feedback := models.Feedback{
 		Checked:   false,
+		Flagged:   len(autoFlags) > 0,
+		AutoFlags: autoFlags,
 		Text:      feedbackDto.Text,
 		Rating:    feedbackDto.Rating,
 		UserID:    &reviewer,
@@ -125,3 +128,36 @@ func (r *FeedbackRepository) CheckReview(feedbackID primitive.ObjectID) error {
 	}
 	return nil
 }
+
+// GetFeedbacksByUser получает все отзывы о приложении, оставленные пользователем
+func (r *FeedbackRepository) GetFeedbacksByUser(userID uuid.UUID) ([]models.Feedback, error) {
+	cursor, err := database.MongoDB.Database("bookstore").
+		Collection(r.collection).
+		Find(context.TODO(), bson.M{"user_id": userID})
+	if err != nil {
+		r.log.Warnf("Ошибка получения отзывов о приложении пользователя %s: %v", userID, err)
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var feedbacks []models.Feedback
+	if err = cursor.All(context.TODO(), &feedbacks); err != nil {
+		r.log.Warnf("Ошибка обработки отзывов о приложении: %v", err)
+		return nil, err
+	}
+
+	return feedbacks, nil
+}
+
+// DeleteFeedbacksByUser удаляет все отзывы о приложении, оставленные пользователем
+func (r *FeedbackRepository) DeleteFeedbacksByUser(userID uuid.UUID) error {
+	_, err := database.MongoDB.Database("bookstore").
+		Collection(r.collection).
+		DeleteMany(context.TODO(), bson.M{"user_id": userID})
+	if err != nil {
+		r.log.Warnf("Ошибка удаления отзывов о приложении пользователя %s: %v", userID, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowRepository struct {
+	db  *gorm.DB
+	log *logger.Logger
+}
+
+// NewFollowRepository создает репозиторий подписок
+func NewFollowRepository() *FollowRepository {
+	return &FollowRepository{
+		db:  database.DB,
+		log: logger.GetLogger(),
+	}
+}
+
+// Follow подписывает пользователя; повторная подписка ничего не меняет
+func (r *FollowRepository) Follow(follow *models.Follow) error {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(follow).Error; err != nil {
+		r.log.Warnf("Ошибка подписки пользователя %s на %s %s: %v", follow.FollowerID, follow.TargetType, follow.TargetID, err)
+		return err
+	}
+	return nil
+}
+
+// Unfollow удаляет подписку; возвращает gorm.ErrRecordNotFound, если ее не было
+func (r *FollowRepository) Unfollow(followerID uuid.UUID, targetType models.FollowTargetType, targetID uuid.UUID) error {
+	result := r.db.Where("follower_id = ? AND target_type = ? AND target_id = ?", followerID, targetType, targetID).
+		Delete(&models.Follow{})
+	if result.Error != nil {
+		r.log.Warnf("Ошибка отписки пользователя %s от %s %s: %v", followerID, targetType, targetID, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetFollowing получает подписки пользователя, новые первыми
+func (r *FollowRepository) GetFollowing(followerID uuid.UUID) ([]models.Follow, error) {
+	var follows []models.Follow
+	if err := r.db.Where("follower_id = ?", followerID).Order("created_at DESC").Find(&follows).Error; err != nil {
+		r.log.Warnf("Ошибка получения подписок пользователя %s: %v", followerID, err)
+		return nil, err
+	}
+	return follows, nil
+}
+
+// CountFollowers считает подписчиков пользователя или автора
+func (r *FollowRepository) CountFollowers(targetType models.FollowTargetType, targetID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Follow{}).Where("target_type = ? AND target_id = ?", targetType, targetID).Count(&count).Error
+	if err != nil {
+		r.log.Warnf("Ошибка подсчета подписчиков %s %s: %v", targetType, targetID, err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// DeleteFollowsByUser удаляет подписки пользователя и подписки на него
+func (r *FollowRepository) DeleteFollowsByUser(userID uuid.UUID) error {
+	err := r.db.Where("follower_id = ? OR (target_type = ? AND target_id = ?)", userID, models.FollowTargetUser, userID).
+		Delete(&models.Follow{}).Error
+	if err != nil {
+		r.log.Warnf("Ошибка удаления подписок пользователя %s: %v", userID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GenreRepository struct {
+	db  *gorm.DB
+	log *logger.Logger
+}
+
+// NewGenreRepository создает репозиторий жанров
+func NewGenreRepository() *GenreRepository {
+	return &GenreRepository{
+		db:  database.DB,
+		log: logger.GetLogger(),
+	}
+}
+
+// GenreWithBookCount жанр с числом подтвержденных книг
+type GenreWithBookCount struct {
+	models.Genre
+	BookCount int
+}
+
+// GetGenreByID возвращает жанр по ID
+func (r *GenreRepository) GetGenreByID(genreID uuid.UUID) (*models.Genre, error) {
+	var genre models.Genre
+	if err := r.db.Where("id = ?", genreID).First(&genre).Error; err != nil {
+		r.log.Warnf("Ошибка получения жанра %s: %v", genreID, err)
+		return nil, err
+	}
+	return &genre, nil
+}
+
+// GetGenreByName ищет жанр по названию без учета регистра
+func (r *GenreRepository) GetGenreByName(name string) (*models.Genre, error) {
+	var genre models.Genre
+	if err := r.db.Where("LOWER(name) = LOWER(?)", name).First(&genre).Error; err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+// CreateGenre создает жанр; если жанр с таким названием уже есть (его могли создать параллельно), возвращает его
+func (r *GenreRepository) CreateGenre(name string) (*models.Genre, error) {
+	genre := models.Genre{ID: uuid.New(), Name: name}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&genre).Error; err != nil {
+		r.log.Warnf("Ошибка создания жанра %q: %v", name, err)
+		return nil, err
+	}
+	return r.GetGenreByName(name)
+}
+
+// GetGenresWithBookCounts возвращает жанры, в которых есть подтвержденные книги, по алфавиту
+func (r *GenreRepository) GetGenresWithBookCounts() ([]GenreWithBookCount, error) {
+	var genres []GenreWithBookCount
+	err := r.db.Table("genres").
+		Select("genres.*, COUNT(books.id) AS book_count").
+		Joins("JOIN book_genres ON book_genres.genre_id = genres.id").
+		Joins("JOIN books ON books.id = book_genres.book_id AND books.confirmed = ? AND books.deleted_at IS NULL", true).
+		Group("genres.id").
+		Order("genres.name ASC").
+		Scan(&genres).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения жанров: %v", err)
+		return nil, err
+	}
+	return genres, nil
+}
+
+// GetGenresForBooks возвращает жанры книг по названию: book_id → жанры
+func (r *GenreRepository) GetGenresForBooks(bookIDs []uuid.UUID) (map[uuid.UUID][]models.Genre, error) {
+	var rows []struct {
+		BookID uuid.UUID
+		models.Genre
+	}
+	err := r.db.Table("book_genres").
+		Select("book_genres.book_id, genres.*").
+		Joins("JOIN genres ON genres.id = book_genres.genre_id").
+		Where("book_genres.book_id IN (?)", bookIDs).
+		Order("genres.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения жанров книг: %v", err)
+		return nil, err
+	}
+
+	genres := make(map[uuid.UUID][]models.Genre, len(bookIDs))
+	for _, row := range rows {
+		genres[row.BookID] = append(genres[row.BookID], row.Genre)
+	}
+	return genres, nil
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ErrImportJobFinished задача уже завершена, например помечена прерванной
+var ErrImportJobFinished = errors.New("задача загрузки уже завершена")
+
+type ImportJobRepository struct {
+	db  *gorm.DB
+	log *logger.Logger
+}
+
+// NewImportJobRepository создает репозиторий задач массовой загрузки книг
+func NewImportJobRepository() *ImportJobRepository {
+	return &ImportJobRepository{
+		db:  database.DB,
+		log: logger.GetLogger(),
+	}
+}
+
+// CreateJob сохраняет новую задачу загрузки
+func (r *ImportJobRepository) CreateJob(job *models.ImportJob) error {
+	if err := r.db.Create(job).Error; err != nil {
+		r.log.Warnf("Ошибка создания задачи загрузки: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetJobByID возвращает задачу загрузки по ID
+func (r *ImportJobRepository) GetJobByID(jobID uuid.UUID) (*models.ImportJob, error) {
+	var job models.ImportJob
+	if err := r.db.Where("id = ?", jobID).First(&job).Error; err != nil {
+		r.log.Warnf("Ошибка получения задачи загрузки %s: %v", jobID, err)
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetRecentJobs возвращает последние задачи загрузки, новые первыми
+func (r *ImportJobRepository) GetRecentJobs(limit int) ([]models.ImportJob, error) {
+	var jobs []models.ImportJob
+	if err := r.db.Order("created_at DESC").Limit(limit).Find(&jobs).Error; err != nil {
+		r.log.Warnf("Ошибка получения задач загрузки: %v", err)
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// MarkRunning переводит ожидающую задачу в работу. Возвращает false, если задача уже не ожидает,
+// например ее пометили прерванной после перезапуска другого экземпляра сервиса
+func (r *ImportJobRepository) MarkRunning(job *models.ImportJob) (bool, error) {
+	now := time.Now().UTC()
+	result := r.db.Model(&models.ImportJob{}).
+		Where("id = ? AND status = ?", job.ID, models.ImportJobPending).
+		Updates(map[string]interface{}{"status": models.ImportJobRunning, "total": job.Total, "started_at": now})
+	if result.Error != nil {
+		r.log.Warnf("Ошибка запуска задачи загрузки %s: %v", job.ID, result.Error)
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	job.Status = models.ImportJobRunning
+	job.StartedAt = &now
+	return true, nil
+}
+
+// SaveProgress сохраняет счетчики, ошибки и состояние задачи, пока она не завершена. Задачу, которую уже
+// пометили прерванной, сохранение не возвращает в работу: тогда возвращается ErrImportJobFinished
+func (r *ImportJobRepository) SaveProgress(job *models.ImportJob) error {
+	result := r.db.Model(job).
+		Where("status IN (?)", []models.ImportJobStatus{models.ImportJobPending, models.ImportJobRunning}).
+		Select("*").
+		Updates(job)
+	if result.Error != nil {
+		r.log.Warnf("Ошибка сохранения прогресса задачи загрузки %s: %v", job.ID, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrImportJobFinished
+	}
+	return nil
+}
+
+// FailStaleJobs помечает прерванными незавершенные задачи, которые не обновлялись с before:
+// работающая задача сохраняет прогресс после каждой пачки, так что такие задачи потеряны вместе с экземпляром,
+// который их обрабатывал
+func (r *ImportJobRepository) FailStaleJobs(before time.Time, reason string) (int64, error) {
+	result := r.db.Model(&models.ImportJob{}).
+		Where("status IN (?) AND updated_at < ?", []models.ImportJobStatus{models.ImportJobPending, models.ImportJobRunning}, before).
+		Updates(map[string]interface{}{"status": models.ImportJobFailed, "error": reason, "finished_at": time.Now().UTC()})
+	if result.Error != nil {
+		r.log.Warnf("Ошибка завершения прерванных задач загрузки: %v", result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
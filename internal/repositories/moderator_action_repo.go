@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ModeratorActionRepository struct {
+	db  *gorm.DB
+	log *logger.Logger
+}
+
+// NewModeratorActionRepository создает репозиторий журнала действий модераторов
+func NewModeratorActionRepository() *ModeratorActionRepository {
+	return &ModeratorActionRepository{
+		db:  database.DB,
+		log: logger.GetLogger(),
+	}
+}
+
+// CreateAction записывает действие модератора в журнал
+func (r *ModeratorActionRepository) CreateAction(action *models.ModeratorAction) error {
+	if err := r.db.Create(action).Error; err != nil {
+		r.log.Warnf("Ошибка записи действия модератора: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetActionsByTarget получает историю действий модераторов над объектом
+func (r *ModeratorActionRepository) GetActionsByTarget(targetType, targetID string) ([]models.ModeratorAction, error) {
+	var actions []models.ModeratorAction
+
+	err := r.db.Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Order("created_at ASC").
+		Find(&actions).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения действий модераторов над %s %s: %v", targetType, targetID, err)
+		return nil, err
+	}
+
+	return actions, nil
+}
+
+// GetActionsByModerator получает все действия модератора
+func (r *ModeratorActionRepository) GetActionsByModerator(moderatorID uuid.UUID) ([]models.ModeratorAction, error) {
+	var actions []models.ModeratorAction
+
+	err := r.db.Where("moderator_id = ?", moderatorID).
+		Order("created_at ASC").
+		Find(&actions).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения действий модератора %s: %v", moderatorID, err)
+		return nil, err
+	}
+
+	return actions, nil
+}
@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type NotificationRepository struct {
+	db  *gorm.DB
+	log *logger.Logger
+}
+
+// NewNotificationRepository создает репозиторий уведомлений
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{
+		db:  database.DB,
+		log: logger.GetLogger(),
+	}
+}
+
+// CreateNotification сохраняет уведомление
+func (r *NotificationRepository) CreateNotification(notification *models.Notification) error {
+	if err := r.db.Create(notification).Error; err != nil {
+		r.log.Warnf("Ошибка сохранения уведомления пользователю %s: %v", notification.UserID, err)
+		return err
+	}
+	return nil
+}
+
+// GetNotificationsPaginated получает уведомления пользователя, новые первыми, с маркерной пагинацией
+func (r *NotificationRepository) GetNotificationsPaginated(userID uuid.UUID, unreadOnly bool, limit int, afterID *uuid.UUID) ([]models.Notification, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var notifications []models.Notification
+
+	query := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit)
+
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if afterID != nil {
+		query = query.Where("(created_at, id) < (?)", r.db.Model(&models.Notification{}).
+			Select("created_at, id").
+			Where("id = ?", *afterID))
+	}
+
+	if err := query.Find(&notifications).Error; err != nil {
+		r.log.Warnf("Ошибка получения уведомлений пользователя %s: %v", userID, err)
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// CountUnread считает непрочитанные уведомления пользователя
+func (r *NotificationRepository) CountUnread(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	if err != nil {
+		r.log.Warnf("Ошибка подсчета непрочитанных уведомлений пользователя %s: %v", userID, err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkRead отмечает уведомление прочитанным; возвращает gorm.ErrRecordNotFound, если у пользователя его нет
+func (r *NotificationRepository) MarkRead(userID, notificationID uuid.UUID) error {
+	result := r.db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now().UTC()))
+	if result.Error != nil {
+		r.log.Warnf("Ошибка отметки уведомления %s прочитанным: %v", notificationID, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя и возвращает их количество
+func (r *NotificationRepository) MarkAllRead(userID uuid.UUID) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now().UTC())
+	if result.Error != nil {
+		r.log.Warnf("Ошибка отметки уведомлений пользователя %s прочитанными: %v", userID, result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// GetPreferences получает сохраненные настройки уведомлений пользователя
+func (r *NotificationRepository) GetPreferences(userID uuid.UUID) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	if err := r.db.Where("user_id = ?", userID).Find(&preferences).Error; err != nil {
+		r.log.Warnf("Ошибка получения настроек уведомлений пользователя %s: %v", userID, err)
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// IsEnabled проверяет, включен ли для пользователя вид уведомлений
+func (r *NotificationRepository) IsEnabled(userID uuid.UUID, notificationType string) (bool, error) {
+	preference := models.NotificationPreference{Enabled: true}
+	err := r.db.Where("user_id = ? AND type = ?", userID, notificationType).Limit(1).Find(&preference).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения настройки уведомлений %s пользователя %s: %v", notificationType, userID, err)
+		return false, err
+	}
+	return preference.Enabled, nil
+}
+
+// SavePreferences сохраняет настройки уведомлений пользователя
+func (r *NotificationRepository) SavePreferences(preferences []models.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&preferences).Error
+	if err != nil {
+		r.log.Warnf("Ошибка сохранения настроек уведомлений: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetNotificationsByUser получает все уведомления пользователя для выгрузки данных
+func (r *NotificationRepository) GetNotificationsByUser(userID uuid.UUID) ([]models.Notification, error) {
+	var notifications []models.Notification
+	if err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&notifications).Error; err != nil {
+		r.log.Warnf("Ошибка получения уведомлений пользователя %s: %v", userID, err)
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// DeleteNotificationsByUser удаляет уведомления и настройки пользователя,
+// а в чужих уведомлениях стирает его как инициатора
+func (r *NotificationRepository) DeleteNotificationsByUser(userID uuid.UUID) error {
+	tx := r.db.Begin()
+
+	if err := tx.Where("user_id = ?", userID).Delete(&models.Notification{}).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка удаления уведомлений пользователя %s: %v", userID, err)
+		return err
+	}
+
+	if err := tx.Where("user_id = ?", userID).Delete(&models.NotificationPreference{}).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка удаления настроек уведомлений пользователя %s: %v", userID, err)
+		return err
+	}
+
+	if err := tx.Model(&models.Notification{}).Where("actor_id = ?", userID).Update("actor_id", nil).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка обезличивания уведомлений от пользователя %s: %v", userID, err)
+		return err
+	}
+
+	return tx.Commit().Error
+}
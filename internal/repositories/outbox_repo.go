@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type OutboxRepository struct {
+	db  *gorm.DB
+	log *logger.Logger
+}
+
+// NewOutboxRepository создает репозиторий транзакционного outbox
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{
+		db:  database.DB,
+		log: logger.GetLogger(),
+	}
+}
+
+// enqueueOutboxMessage добавляет сообщение в outbox. Вызывается с транзакцией изменения,
+// чтобы сообщение появилось только вместе с ним
+func enqueueOutboxMessage(tx *gorm.DB, messageType models.OutboxMessageType, bookID uuid.UUID) error {
+	now := time.Now().UTC()
+	message := models.OutboxMessage{
+		ID:            uuid.New(),
+		Type:          messageType,
+		BookID:        bookID,
+		NextAttemptAt: &now,
+	}
+	return tx.Create(&message).Error
+}
+
+// Enqueue добавляет сообщение в outbox вне транзакции — для повтора побочного эффекта, который не удался сразу
+func (r *OutboxRepository) Enqueue(messageType models.OutboxMessageType, bookID uuid.UUID) error {
+	if err := enqueueOutboxMessage(r.db, messageType, bookID); err != nil {
+		r.log.Warnf("Ошибка записи сообщения %s для книги %s в outbox: %v", messageType, bookID, err)
+		return err
+	}
+	return nil
+}
+
+// ClaimDueMessages забирает сообщения, время обработки которых наступило, и откладывает их на lease,
+// чтобы другой экземпляр сервиса не взял их одновременно
+func (r *OutboxRepository) ClaimDueMessages(limit int, lease time.Duration) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	now := time.Now().UTC()
+
+	tx := r.db.Begin()
+
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка выборки сообщений outbox: %v", err)
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		tx.Rollback()
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+	}
+
+	if err := tx.Model(&models.OutboxMessage{}).Where("id IN (?)", ids).Update("next_attempt_at", now.Add(lease)).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка резервирования сообщений outbox: %v", err)
+		return nil, err
+	}
+
+	return messages, tx.Commit().Error
+}
+
+// SaveAttempt сохраняет результат обработки сообщения
+func (r *OutboxRepository) SaveAttempt(message *models.OutboxMessage) error {
+	err := r.db.Model(&models.OutboxMessage{}).Where("id = ?", message.ID).Updates(map[string]interface{}{
+		"attempts":        message.Attempts,
+		"next_attempt_at": message.NextAttemptAt,
+		"last_error":      message.LastError,
+		"processed_at":    message.ProcessedAt,
+		"updated_at":      time.Now().UTC(),
+	}).Error
+	if err != nil {
+		r.log.Warnf("Ошибка сохранения обработки сообщения outbox %s: %v", message.ID, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteProcessedBefore удаляет обработанные сообщения старше before
+func (r *OutboxRepository) DeleteProcessedBefore(before time.Time) error {
+	err := r.db.Where("processed_at IS NOT NULL AND processed_at < ?", before).Delete(&models.OutboxMessage{}).Error
+	if err != nil {
+		r.log.Warnf("Ошибка очистки outbox: %v", err)
+		return err
+	}
+	return nil
+}
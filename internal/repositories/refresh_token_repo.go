@@ -61,17 +61,52 @@ func (r *RefreshTokenRepository) DeleteToken(token string) error {
 	return nil
 }
 
+// GetTokensByUser получает все refresh-токены пользователя
+func (r *RefreshTokenRepository) GetTokensByUser(userID uuid.UUID) ([]models.RefreshToken, error) {
+	var tokens []models.RefreshToken
+	err := r.db.Where("user_id = ?", userID).Find(&tokens).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения refresh-токенов пользователя %s: %v", userID, err)
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// DeleteTokensByUser отзывает все refresh-токены пользователя
+func (r *RefreshTokenRepository) DeleteTokensByUser(userID uuid.UUID) error {
+	err := r.db.Where("user_id = ?", userID).Delete(&models.RefreshToken{}).Error
+	if err != nil {
+		r.log.Warnf("Ошибка отзыва refresh-токенов пользователя %s: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
 // CleanupExpiredTokens удаляет просроченные refresh-токены (CRON)
-func (r *RefreshTokenRepository) CleanupExpiredTokens() {
-	r.db.Where("expires_at < ?", time.Now()).Delete(&models.RefreshToken{})
+func (r *RefreshTokenRepository) CleanupExpiredTokens() (int64, error) {
+	result := r.db.Where("expires_at < ?", time.Now()).Delete(&models.RefreshToken{})
+	if result.Error != nil {
+		r.log.Warnf("Ошибка удаления устаревших refresh-токенов: %v", result.Error)
+		return 0, result.Error
+	}
 	r.log.Info("Удалены устаревшие refresh-токены")
+	return result.RowsAffected, nil
+}
+
+// DeleteAllTokens отзывает refresh-токены всех пользователей
+func (r *RefreshTokenRepository) DeleteAllTokens() (int64, error) {
+	result := r.db.Where("1 = 1").Delete(&models.RefreshToken{})
+	if result.Error != nil {
+		r.log.Warnf("Ошибка отзыва всех refresh-токенов: %v", result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
 }
 
-// Запуск задачи удаления токенов раз в день
 func StartTokenCleanupTask(repo *RefreshTokenRepository) {
 	go func() {
 		for {
-			repo.CleanupExpiredTokens()
+			_, _ = repo.CleanupExpiredTokens()
 			time.Sleep(24 * time.Hour) // Удаляем раз в день
 		}
 	}()
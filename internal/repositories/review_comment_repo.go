@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type ReviewCommentRepository struct {
+	collection        string
+	reviewsCollection string
+	log               *logger.Logger
+}
+
+// NewReviewCommentRepository создает новый экземпляр ReviewCommentRepository
+func NewReviewCommentRepository() *ReviewCommentRepository {
+	return &ReviewCommentRepository{
+		collection:        "review_comments",
+		reviewsCollection: "reviews",
+		log:               logger.GetLogger(),
+	}
+}
+
+// CreateComment добавляет комментарий и обновляет счетчики отзыва и родительского комментария
+func (r *ReviewCommentRepository) CreateComment(comment *models.ReviewComment) error {
+	db := database.MongoDB.Database("bookstore")
+
+	result, err := db.Collection(r.collection).InsertOne(context.TODO(), comment)
+	if err != nil {
+		r.log.Warnf("Ошибка добавления комментария к отзыву: %v", err)
+		return err
+	}
+	comment.ID = result.InsertedID.(primitive.ObjectID)
+
+	if comment.ParentID != nil {
+		if err := r.incrementReplies(*comment.ParentID, 1); err != nil {
+			return err
+		}
+	}
+
+	return r.incrementReviewComments(comment.ReviewID, 1)
+}
+
+// GetCommentByID получает комментарий по ID
+func (r *ReviewCommentRepository) GetCommentByID(commentID primitive.ObjectID) (*models.ReviewComment, error) {
+	var comment models.ReviewComment
+	err := database.MongoDB.Database("bookstore").Collection(r.collection).
+		FindOne(context.TODO(), bson.M{"_id": commentID}).
+		Decode(&comment)
+	if err != nil {
+		r.log.Warnf("Ошибка получения комментария по ID=%s: %v", commentID.Hex(), err)
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetCommentsPaginated получает комментарии отзыва с маркерной пагинацией.
+// Без parentID возвращаются комментарии верхнего уровня, иначе — прямые ответы на parentID
+func (r *ReviewCommentRepository) GetCommentsPaginated(reviewID primitive.ObjectID, parentID *primitive.ObjectID, limit int, afterID *primitive.ObjectID) ([]models.ReviewComment, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	filter := bson.M{"review_id": reviewID, "parent_id": nil}
+	if parentID != nil {
+		filter["parent_id"] = *parentID
+	}
+	// ObjectID растет со временем создания, поэтому годится как маркер
+	if afterID != nil {
+		filter["_id"] = bson.M{"$gt": *afterID}
+	}
+
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.collection).Find(context.TODO(), filter,
+		options.Find().SetSort(bson.M{"_id": 1}).SetLimit(int64(limit)))
+	if err != nil {
+		r.log.Warnf("Ошибка получения комментариев к отзыву %s: %v", reviewID.Hex(), err)
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	comments := []models.ReviewComment{}
+	if err = cursor.All(context.TODO(), &comments); err != nil {
+		r.log.Warnf("Ошибка обработки комментариев к отзыву: %v", err)
+		return nil, err
+	}
+	return comments, nil
+}
+
+// UpdateCommentText обновляет текст комментария
+func (r *ReviewCommentRepository) UpdateCommentText(commentID primitive.ObjectID, text string) error {
+	_, err := database.MongoDB.Database("bookstore").Collection(r.collection).UpdateOne(context.TODO(),
+		bson.M{"_id": commentID},
+		bson.M{"$set": bson.M{"text": text, "updated_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка обновления комментария %s: %v", commentID.Hex(), err)
+		return err
+	}
+	return nil
+}
+
+// DeleteComment удаляет комментарий. Если на него уже ответили, комментарий остается
+// в ветке без текста, чтобы не рвать обсуждение
+func (r *ReviewCommentRepository) DeleteComment(comment *models.ReviewComment) error {
+	collection := database.MongoDB.Database("bookstore").Collection(r.collection)
+
+	if comment.RepliesCount > 0 {
+		_, err := collection.UpdateOne(context.TODO(),
+			bson.M{"_id": comment.ID},
+			bson.M{"$set": bson.M{"deleted": true, "text": "", "updated_at": time.Now().UTC()}},
+		)
+		if err != nil {
+			r.log.Warnf("Ошибка мягкого удаления комментария %s: %v", comment.ID.Hex(), err)
+			return err
+		}
+	} else {
+		if _, err := collection.DeleteOne(context.TODO(), bson.M{"_id": comment.ID}); err != nil {
+			r.log.Warnf("Ошибка удаления комментария %s: %v", comment.ID.Hex(), err)
+			return err
+		}
+
+		if comment.ParentID != nil {
+			if err := r.incrementReplies(*comment.ParentID, -1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return r.incrementReviewComments(comment.ReviewID, -1)
+}
+
+// GetCommentsByUser получает все комментарии пользователя
+func (r *ReviewCommentRepository) GetCommentsByUser(userID string) ([]models.ReviewComment, error) {
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.collection).Find(context.TODO(), bson.M{"user_id": userID})
+	if err != nil {
+		r.log.Warnf("Ошибка получения комментариев пользователя %s: %v", userID, err)
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	comments := []models.ReviewComment{}
+	if err = cursor.All(context.TODO(), &comments); err != nil {
+		r.log.Warnf("Ошибка обработки комментариев пользователя: %v", err)
+		return nil, err
+	}
+	return comments, nil
+}
+
+// AnonymizeCommentsByUser отвязывает комментарии от пользователя, сохраняя структуру веток
+func (r *ReviewCommentRepository) AnonymizeCommentsByUser(userID string, anonymousID string) error {
+	_, err := database.MongoDB.Database("bookstore").Collection(r.collection).UpdateMany(context.TODO(),
+		bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{"user_id": anonymousID}},
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка анонимизации комментариев пользователя %s: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
+// incrementReplies меняет счетчик ответов родительского комментария
+func (r *ReviewCommentRepository) incrementReplies(commentID primitive.ObjectID, delta int) error {
+	_, err := database.MongoDB.Database("bookstore").Collection(r.collection).UpdateOne(context.TODO(),
+		bson.M{"_id": commentID},
+		bson.M{"$inc": bson.M{"replies_count": delta}},
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка обновления счетчика ответов комментария %s: %v", commentID.Hex(), err)
+		return err
+	}
+	return nil
+}
+
+// incrementReviewComments меняет счетчик комментариев отзыва
+func (r *ReviewCommentRepository) incrementReviewComments(reviewID primitive.ObjectID, delta int) error {
+	_, err := database.MongoDB.Database("bookstore").Collection(r.reviewsCollection).UpdateOne(context.TODO(),
+		bson.M{"_id": reviewID},
+		bson.M{"$inc": bson.M{"comments_count": delta}},
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка обновления счетчика комментариев отзыва %s: %v", reviewID.Hex(), err)
+		return err
+	}
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"book-management-system/internal/dto"
 	"book-management-system/internal/models"
 	"book-management-system/pkg/logger"
+	"book-management-system/pkg/markup"
 	"context"
+	"errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -14,42 +16,65 @@ import (
 )
 
 type ReviewRepository struct {
-	collection      string
-	votesCollection string
-	log             *logger.Logger
+	collection         string
+	votesCollection    string
+	commentsCollection string
+	log                *logger.Logger
 }
 
 // NewReviewRepository создает новый экземпляр ReviewRepository
 func NewReviewRepository() *ReviewRepository {
 	return &ReviewRepository{
-		collection:      "reviews",
-		votesCollection: "review_votes",
-		log:             logger.GetLogger(),
+		collection:         "reviews",
+		votesCollection:    "review_votes",
+		commentsCollection: "review_comments",
+		log:                logger.GetLogger(),
 	}
 }
 
-// CreateReview добавляет новый отзыв в MongoDB
-func (r *ReviewRepository) CreateReview(reviewDto dto.BaseReviewRequest, creator string) error {
+// CreateReview добавляет новый отзыв в MongoDB вместе с отрендеренным текстом.
+// Повторный отзыв пользователя на ту же книгу отклоняется уникальным индексом (book_id, user_id)
+func (r *ReviewRepository) CreateReview(reviewDto dto.BaseReviewRequest, creator string, rendered markup.Result) (*models.Review, error) {
 	review := models.Review{
-		BookID:    reviewDto.BookID,
-		UserID:    creator,
-		Text:      reviewDto.Text,
-		Rating:    reviewDto.Rating,
-		Likes:     0,
-		Dislikes:  0,
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
+		BookID:      reviewDto.BookID,
+		UserID:      creator,
+		Text:        reviewDto.Text,
+		TextHTML:    rendered.HTML,
+		HasSpoilers: rendered.HasSpoilers,
+		Rating:      reviewDto.Rating,
+		Likes:       0,
+		Dislikes:    0,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
 	}
-	_, err := database.MongoDB.Database("bookstore").Collection(r.collection).InsertOne(context.TODO(), review)
+	result, err := database.MongoDB.Database("bookstore").Collection(r.collection).InsertOne(context.TODO(), review)
 	if err != nil {
 		r.log.Warnf("Ошибка добавления отзыва: %v", err)
-		return err
+		return nil, err
 	}
-	return nil
+	review.ID = result.InsertedID.(primitive.ObjectID)
+	return &review, nil
 }
 
-// UpdateReviewText обновляет существующий отзыв и сохраняет его версию
-func (r *ReviewRepository) UpdateReviewText(reviewID primitive.ObjectID, updatedText string, editor string) error {
+// GetReviewByBookAndUser получает отзыв пользователя на книгу, включая скрытые модератором
+func (r *ReviewRepository) GetReviewByBookAndUser(bookID string, userID string) (*models.Review, error) {
+	var review models.Review
+	err := database.MongoDB.Database("bookstore").Collection(r.collection).
+		FindOne(context.TODO(), bson.M{"book_id": bookID, "user_id": userID}).
+		Decode(&review)
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// reviewMaxVersions сколько прошлых версий хранится в документе отзыва. Без ограничения часто правимый отзыв
+// со временем уперся бы в предел размера документа MongoDB (16 МБ) и перестал бы обновляться
+const reviewMaxVersions = 100
+
+// UpdateReview обновляет текст и оценку отзыва, сохраняя прежнее состояние в versions.
+// Хранятся последние reviewMaxVersions версий, число удаленных старых копится в versions_trimmed
+func (r *ReviewRepository) UpdateReview(reviewID primitive.ObjectID, updatedText string, updatedRating int, rendered markup.Result, editor string) error {
 	collection := database.MongoDB.Database("bookstore").Collection(r.collection)
 
 	// Получаем текущий отзыв
@@ -60,20 +85,43 @@ func (r *ReviewRepository) UpdateReviewText(reviewID primitive.ObjectID, updated
 		return err
 	}
 
+	previousEditor := existingReview.EditedBy
+	if previousEditor == "" {
+		previousEditor = existingReview.UserID
+	}
+
+	set := bson.M{
+		"text":         updatedText,
+		"text_html":    rendered.HTML,
+		"has_spoilers": rendered.HasSpoilers,
+		"rating":       updatedRating,
+		"updated_at":   time.Now().UTC(),
+	}
+
 	// Добавляем версию
 	updatedReview := bson.M{
-		"$set": bson.M{
-			"text":       updatedText,
-			"updated_at": primitive.NewDateTimeFromTime(existingReview.UpdatedAt),
-		},
+		"$set": set,
 		"$push": bson.M{
-			"versions": models.ReviewVersion{
-				Text:     existingReview.Text,
-				EditedAt: existingReview.UpdatedAt,
-				EditedBy: editor,
+			"versions": bson.M{
+				"$each": []models.ReviewVersion{{
+					Text:     existingReview.Text,
+					Rating:   existingReview.Rating,
+					EditedAt: existingReview.UpdatedAt,
+					EditedBy: previousEditor,
+				}},
+				"$slice": -reviewMaxVersions,
 			},
 		},
 	}
+	if trimmed := len(existingReview.Versions) + 1 - reviewMaxVersions; trimmed > 0 {
+		updatedReview["$inc"] = bson.M{"versions_trimmed": trimmed}
+	}
+	// edited_by храним, только если редакцию внес не автор отзыва
+	if editor == existingReview.UserID {
+		updatedReview["$unset"] = bson.M{"edited_by": ""}
+	} else {
+		set["edited_by"] = editor
+	}
 
 	_, err = collection.UpdateOne(context.TODO(), bson.M{"_id": reviewID}, updatedReview)
 	if err != nil {
@@ -94,10 +142,13 @@ func (r *ReviewRepository) GetReviewById(reviewID primitive.ObjectID) (*models.R
 	return &review, nil
 }
 
-// GetReviewsByBookID получает отзывы по ID книги
-func (r *ReviewRepository) GetReviewsByBookID(bookID primitive.ObjectID) ([]models.Review, error) {
-	var reviews []models.Review
-	cursor, err := database.MongoDB.Database("bookstore").Collection(r.collection).Find(context.TODO(), bson.M{"book_id": bookID})
+// GetReviewsByBookID получает видимые отзывы книги от новых к старым, не больше limit
+func (r *ReviewRepository) GetReviewsByBookID(bookID string, limit int) ([]models.Review, error) {
+	reviews := []models.Review{}
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.collection).Find(context.TODO(),
+		visibleReviewsFilter(bson.M{"book_id": bookID}),
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit)),
+	)
 	if err != nil {
 		r.log.Warnf("Ошибка получения отзывов: %v", err)
 		return nil, err
@@ -111,43 +162,94 @@ func (r *ReviewRepository) GetReviewsByBookID(bookID primitive.ObjectID) ([]mode
 	return reviews, nil
 }
 
-// DeleteReviewByID удаляет отзыв
+// DeleteReviewByID удаляет отзыв вместе с комментариями к нему
 func (r *ReviewRepository) DeleteReviewByID(reviewID primitive.ObjectID) error {
 	_, err := database.MongoDB.Database("bookstore").Collection(r.collection).DeleteOne(context.TODO(), bson.M{"_id": reviewID})
 	if err != nil {
 		r.log.Warnf("Ошибка удаления отзыва: %v", err)
 		return err
 	}
+
+	_, err = database.MongoDB.Database("bookstore").Collection(r.commentsCollection).DeleteMany(context.TODO(), bson.M{"review_id": reviewID})
+	if err != nil {
+		r.log.Warnf("Ошибка удаления комментариев к отзыву %s: %v", reviewID.Hex(), err)
+		return err
+	}
+	return nil
+}
+
+// DeleteReviewsByBookID удаляет все отзывы книги вместе с комментариями и голосами. Отзывы удаляются последними,
+// поэтому повторный вызов после частичной ошибки доделает работу
+func (r *ReviewRepository) DeleteReviewsByBookID(bookID string) error {
+	db := database.MongoDB.Database("bookstore")
+
+	cursor, err := db.Collection(r.collection).Find(context.TODO(), bson.M{"book_id": bookID},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		r.log.Warnf("Ошибка получения отзывов книги %s: %v", bookID, err)
+		return err
+	}
+
+	var reviews []models.Review
+	if err := cursor.All(context.TODO(), &reviews); err != nil {
+		r.log.Warnf("Ошибка чтения отзывов книги %s: %v", bookID, err)
+		return err
+	}
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	reviewIDs := make([]primitive.ObjectID, len(reviews))
+	for i, review := range reviews {
+		reviewIDs[i] = review.ID
+	}
+	byReview := bson.M{"review_id": bson.M{"$in": reviewIDs}}
+
+	if _, err := db.Collection(r.commentsCollection).DeleteMany(context.TODO(), byReview); err != nil {
+		r.log.Warnf("Ошибка удаления комментариев к отзывам книги %s: %v", bookID, err)
+		return err
+	}
+
+	if _, err := db.Collection(r.votesCollection).DeleteMany(context.TODO(), byReview); err != nil {
+		r.log.Warnf("Ошибка удаления голосов за отзывы книги %s: %v", bookID, err)
+		return err
+	}
+
+	if _, err := db.Collection(r.collection).DeleteMany(context.TODO(), bson.M{"_id": bson.M{"$in": reviewIDs}}); err != nil {
+		r.log.Warnf("Ошибка удаления отзывов книги %s: %v", bookID, err)
+		return err
+	}
 	return nil
 }
 
-// VoteReview добавляет/обновляет голос за отзыв
-// VoteReview добавляет/обновляет голос пользователя за отзыв
-func (r *ReviewRepository) VoteReview(reviewID primitive.ObjectID, userID string, vote int) error {
+// VoteReview добавляет/обновляет голос пользователя за отзыв и возвращает его прежний голос (0, если не голосовал)
+func (r *ReviewRepository) VoteReview(reviewID primitive.ObjectID, userID string, vote int) (int, error) {
 	collection := database.MongoDB.Database("bookstore").Collection(r.votesCollection)
+	filter := bson.M{"review_id": reviewID, "user_id": userID}
 
-	// Если голос 0, удаляем его
+	var result *mongo.SingleResult
 	if vote == 0 {
-		_, err := collection.DeleteOne(context.TODO(), bson.M{"review_id": reviewID, "user_id": userID})
-		if err != nil {
-			r.log.Warnf("Ошибка удаления голоса: %v", err)
-			return err
-		}
+		// Если голос 0, удаляем его
+		result = collection.FindOneAndDelete(context.TODO(), filter)
 	} else {
 		// Обновляем или вставляем голос
-		_, err := collection.UpdateOne(
+		result = collection.FindOneAndUpdate(
 			context.TODO(),
-			bson.M{"review_id": reviewID, "user_id": userID},
+			filter,
 			bson.M{"$set": bson.M{"vote": vote}},
-			options.Update().SetUpsert(true),
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
 		)
-		if err != nil {
-			r.log.Warnf("Ошибка голосования за отзыв: %v", err)
-			return err
-		}
 	}
 
-	return r.recalculateVotes(reviewID)
+	var previous struct {
+		Vote int `bson:"vote"`
+	}
+	if err := result.Decode(&previous); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		r.log.Warnf("Ошибка голосования за отзыв: %v", err)
+		return 0, err
+	}
+
+	return previous.Vote, r.recalculateVotes(reviewID)
 }
 
 // recalculateVotes пересчитывает лайки/дизлайки для отзыва
@@ -192,22 +294,34 @@ func (r *ReviewRepository) recalculateVotes(reviewID primitive.ObjectID) error {
 	return nil
 }
 
-// CalculateAverageRating вычисляет средний рейтинг книги на основе отзывов
-func (r *ReviewRepository) CalculateAverageRating(bookID string) (float64, error) {
-	collection := database.MongoDB.Database("bookstore").Collection(r.collection)
+// GetBookRatingInputs получает оценки видимых отзывов книги вместе с голосами за них
+func (r *ReviewRepository) GetBookRatingInputs(bookID string) ([]models.Review, error) {
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.collection).Find(context.TODO(),
+		visibleReviewsFilter(bson.M{"book_id": bookID}),
+		options.Find().SetProjection(bson.M{"user_id": 1, "rating": 1, "likes": 1, "dislikes": 1}),
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка получения оценок отзывов книги %s: %v", bookID, err)
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
 
-	// Агрегируем средний рейтинг книги
-	pipeline := mongo.Pipeline{
-		bson.D{{"$match", bson.D{{"book_id", bookID}}}},
-		bson.D{{"$group", bson.D{
-			{"_id", nil},
-			{"average_rating", bson.D{{"$avg", "$rating"}}},
-		}}},
+	reviews := []models.Review{}
+	if err = cursor.All(context.TODO(), &reviews); err != nil {
+		r.log.Warnf("Ошибка обработки оценок отзывов книги %s: %v", bookID, err)
+		return nil, err
 	}
+	return reviews, nil
+}
 
-	cursor, err := collection.Aggregate(context.TODO(), pipeline)
+// CalculateGlobalAverageRating считает среднюю оценку по всем видимым отзывам — априорное среднее для байесовского рейтинга
+func (r *ReviewRepository) CalculateGlobalAverageRating() (float64, error) {
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.collection).Aggregate(context.TODO(), bson.A{
+		bson.M{"$match": visibleReviewsFilter(bson.M{})},
+		bson.M{"$group": bson.M{"_id": nil, "average_rating": bson.M{"$avg": "$rating"}}},
+	})
 	if err != nil {
-		r.log.Warnf("Ошибка агрегации среднего рейтинга для книги %s: %v", bookID, err)
+		r.log.Warnf("Ошибка агрегации общего среднего рейтинга: %v", err)
 		return 0, err
 	}
 	defer cursor.Close(context.TODO())
@@ -215,16 +329,317 @@ func (r *ReviewRepository) CalculateAverageRating(bookID string) (float64, error
 	var result struct {
 		AverageRating float64 `bson:"average_rating"`
 	}
-
 	if cursor.Next(context.TODO()) {
 		if err := cursor.Decode(&result); err != nil {
-			r.log.Warnf("Ошибка декодирования среднего рейтинга: %v", err)
+			r.log.Warnf("Ошибка декодирования общего среднего рейтинга: %v", err)
 			return 0, err
 		}
+	}
+	return result.AverageRating, nil
+}
+
+// GetReviewsByUserForBooks получает отзывы пользователя на указанные книги
+func (r *ReviewRepository) GetReviewsByUserForBooks(userID string, bookIDs []string) ([]models.Review, error) {
+	var reviews []models.Review
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.collection).Find(context.TODO(), bson.M{
+		"user_id": userID,
+		"book_id": bson.M{"$in": bookIDs},
+	})
+	if err != nil {
+		r.log.Warnf("Ошибка получения отзывов пользователя %s: %v", userID, err)
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err = cursor.All(context.TODO(), &reviews); err != nil {
+		r.log.Warnf("Ошибка обработки отзывов пользователя: %v", err)
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// GetReviewsByUser получает все отзывы пользователя
+func (r *ReviewRepository) GetReviewsByUser(userID string) ([]models.Review, error) {
+	var reviews []models.Review
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.collection).Find(context.TODO(), bson.M{"user_id": userID})
+	if err != nil {
+		r.log.Warnf("Ошибка получения отзывов пользователя %s: %v", userID, err)
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err = cursor.All(context.TODO(), &reviews); err != nil {
+		r.log.Warnf("Ошибка обработки отзывов пользователя: %v", err)
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// GetVotesByUser получает все голоса пользователя за отзывы
+func (r *ReviewRepository) GetVotesByUser(userID string) ([]models.ReviewVote, error) {
+	var votes []models.ReviewVote
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.votesCollection).Find(context.TODO(), bson.M{"user_id": userID})
+	if err != nil {
+		r.log.Warnf("Ошибка получения голосов пользователя %s: %v", userID, err)
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err = cursor.All(context.TODO(), &votes); err != nil {
+		r.log.Warnf("Ошибка обработки голосов пользователя: %v", err)
+		return nil, err
+	}
+	return votes, nil
+}
+
+// AnonymizeReviewsByUser отвязывает отзывы и их версии от пользователя, сохраняя сам текст и оценку
+func (r *ReviewRepository) AnonymizeReviewsByUser(userID string, anonymousID string) error {
+	collection := database.MongoDB.Database("bookstore").Collection(r.collection)
+
+	_, err := collection.UpdateMany(context.TODO(),
+		bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{"user_id": anonymousID}},
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка анонимизации отзывов пользователя %s: %v", userID, err)
+		return err
+	}
+
+	// Пользователь мог редактировать и чужие отзывы (если модератор), чистим автора правок везде
+	_, err = collection.UpdateMany(context.TODO(),
+		bson.M{"edited_by": userID},
+		bson.M{"$set": bson.M{"edited_by": anonymousID}},
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка анонимизации правок отзывов пользователя %s: %v", userID, err)
+		return err
+	}
+
+	_, err = collection.UpdateMany(context.TODO(),
+		bson.M{"versions.edited_by": userID},
+		bson.M{"$set": bson.M{"versions.$[version].edited_by": anonymousID}},
+		options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: []interface{}{bson.M{"version.edited_by": userID}},
+		}),
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка анонимизации версий отзывов пользователя %s: %v", userID, err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteVotesByUser удаляет голоса пользователя, пересчитывает счетчики затронутых отзывов и возвращает удаленные голоса
+func (r *ReviewRepository) DeleteVotesByUser(userID string) ([]models.ReviewVote, error) {
+	votes, err := r.GetVotesByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = database.MongoDB.Database("bookstore").Collection(r.votesCollection).DeleteMany(context.TODO(), bson.M{"user_id": userID})
+	if err != nil {
+		r.log.Warnf("Ошибка удаления голосов пользователя %s: %v", userID, err)
+		return nil, err
+	}
+
+	for _, vote := range votes {
+		if err := r.recalculateVotes(vote.ReviewID); err != nil {
+			return nil, err
+		}
+	}
+
+	return votes, nil
+}
+
+// IncrementReportsCount увеличивает счетчик открытых жалоб и возвращает обновленный отзыв
+func (r *ReviewRepository) IncrementReportsCount(reviewID primitive.ObjectID) (*models.Review, error) {
+	var review models.Review
+	err := database.MongoDB.Database("bookstore").Collection(r.collection).FindOneAndUpdate(context.TODO(),
+		bson.M{"_id": reviewID},
+		bson.M{"$inc": bson.M{"reports_count": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&review)
+	if err != nil {
+		r.log.Warnf("Ошибка обновления счетчика жалоб отзыва %s: %v", reviewID.Hex(), err)
+		return nil, err
+	}
+	return &review, nil
+}
+
+// MarkReviewReported ставит отзыв в очередь модерации и при необходимости скрывает его до решения модератора
+func (r *ReviewRepository) MarkReviewReported(reviewID primitive.ObjectID, hide bool) error {
+	set := bson.M{"moderation_status": models.ReviewModerationPending}
+	if hide {
+		set["hidden"] = true
+	}
+
+	_, err := database.MongoDB.Database("bookstore").Collection(r.collection).UpdateOne(context.TODO(),
+		bson.M{"_id": reviewID},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка постановки отзыва %s в очередь модерации: %v", reviewID.Hex(), err)
+		return err
+	}
+	return nil
+}
+
+// FlagReview отправляет отзыв в очередь модерации по срабатыванию автоматического фильтра
+// и скрывает его до решения модератора. unpublished отмечает новый отзыв, о котором еще никому не сообщали
+func (r *ReviewRepository) FlagReview(reviewID primitive.ObjectID, categories []string, unpublished bool) error {
+	set := bson.M{"moderation_status": models.ReviewModerationPending, "hidden": true}
+	if unpublished {
+		set["unpublished"] = true
+	}
+
+	_, err := database.MongoDB.Database("bookstore").Collection(r.collection).UpdateOne(context.TODO(),
+		bson.M{"_id": reviewID},
+		bson.M{
+			"$set":      set,
+			"$addToSet": bson.M{"auto_flags": bson.M{"$each": categories}},
+		},
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка автоматической отправки отзыва %s на модерацию: %v", reviewID.Hex(), err)
+		return err
+	}
+	return nil
+}
+
+// ApplyModerationDecision сохраняет решение модератора и обнуляет счетчик открытых жалоб.
+// Отзыв, который решение делает видимым, перестает считаться неопубликованным
+func (r *ReviewRepository) ApplyModerationDecision(reviewID primitive.ObjectID, status models.ReviewModerationStatus, hidden bool, deletedAt *time.Time) error {
+	update := bson.M{
+		"$set": bson.M{
+			"moderation_status": status,
+			"hidden":            hidden,
+			"reports_count":     0,
+		},
+	}
+	if deletedAt != nil {
+		update["$set"].(bson.M)["deleted_at"] = *deletedAt
 	} else {
-		// Если отзывов нет, устанавливаем рейтинг в 0
-		result.AverageRating = 0
+		unset := bson.M{"deleted_at": ""}
+		if !hidden {
+			unset["unpublished"] = ""
+		}
+		update["$unset"] = unset
 	}
 
-	return result.AverageRating, nil
+	_, err := database.MongoDB.Database("bookstore").Collection(r.collection).UpdateOne(context.TODO(), bson.M{"_id": reviewID}, update)
+	if err != nil {
+		r.log.Warnf("Ошибка сохранения решения модератора по отзыву %s: %v", reviewID.Hex(), err)
+		return err
+	}
+	return nil
+}
+
+// GetReviewsForModeration получает отзывы с заданным статусом модерации с маркерной пагинацией
+func (r *ReviewRepository) GetReviewsForModeration(status models.ReviewModerationStatus, limit int, afterID *primitive.ObjectID) ([]models.Review, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	filter := bson.M{"moderation_status": status}
+	if afterID != nil {
+		filter["_id"] = bson.M{"$gt": *afterID}
+	}
+
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.collection).Find(context.TODO(), filter,
+		options.Find().SetSort(bson.M{"_id": 1}).SetLimit(int64(limit)))
+	if err != nil {
+		r.log.Warnf("Ошибка получения очереди модерации: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	reviews := []models.Review{}
+	if err = cursor.All(context.TODO(), &reviews); err != nil {
+		r.log.Warnf("Ошибка обработки очереди модерации: %v", err)
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// visibleReviewsFilter дополняет фильтр условием, что отзыв не скрыт и не удален модератором
+func visibleReviewsFilter(filter bson.M) bson.M {
+	filter["hidden"] = bson.M{"$ne": true}
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// BookReviewStats сводка отзывов одной книги для проверки согласованности хранилищ
+type BookReviewStats struct {
+	BookID  string  `bson:"_id"`
+	Count   int     `bson:"count"`
+	Average float64 `bson:"average"`
+}
+
+// GetReviewCountsByBook считает все отзывы, включая скрытые, по книгам
+func (r *ReviewRepository) GetReviewCountsByBook() ([]BookReviewStats, error) {
+	return r.aggregateBookStats(bson.M{})
+}
+
+// GetVisibleRatingStatsByBook считает число и простое среднее оценок видимых отзывов по книгам —
+// то же, что RecalculateBookRating кладет в average_rating и ratings_count
+func (r *ReviewRepository) GetVisibleRatingStatsByBook() ([]BookReviewStats, error) {
+	return r.aggregateBookStats(visibleReviewsFilter(bson.M{}))
+}
+
+func (r *ReviewRepository) aggregateBookStats(filter bson.M) ([]BookReviewStats, error) {
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.collection).Aggregate(context.TODO(), bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$group": bson.M{"_id": "$book_id", "count": bson.M{"$sum": 1}, "average": bson.M{"$avg": "$rating"}}},
+	})
+	if err != nil {
+		r.log.Warnf("Ошибка агрегации отзывов по книгам: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	stats := []BookReviewStats{}
+	if err := cursor.All(context.TODO(), &stats); err != nil {
+		r.log.Warnf("Ошибка обработки агрегации отзывов по книгам: %v", err)
+		return nil, err
+	}
+	return stats, nil
+}
+
+// OrphanVotes голоса за отзыв, которого больше нет
+type OrphanVotes struct {
+	ReviewID primitive.ObjectID `bson:"_id"`
+	Count    int                `bson:"count"`
+}
+
+// GetOrphanVotes находит голоса за удаленные отзывы, сгруппированные по отзыву
+func (r *ReviewRepository) GetOrphanVotes() ([]OrphanVotes, error) {
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.votesCollection).Aggregate(context.TODO(), bson.A{
+		bson.M{"$group": bson.M{"_id": "$review_id", "count": bson.M{"$sum": 1}}},
+		bson.M{"$lookup": bson.M{"from": r.collection, "localField": "_id", "foreignField": "_id", "as": "review"}},
+		bson.M{"$match": bson.M{"review": bson.M{"$size": 0}}},
+	})
+	if err != nil {
+		r.log.Warnf("Ошибка поиска голосов за удаленные отзывы: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	orphans := []OrphanVotes{}
+	if err := cursor.All(context.TODO(), &orphans); err != nil {
+		r.log.Warnf("Ошибка обработки голосов за удаленные отзывы: %v", err)
+		return nil, err
+	}
+	return orphans, nil
+}
+
+// DeleteVotesByReviewIDs удаляет голоса за указанные отзывы
+func (r *ReviewRepository) DeleteVotesByReviewIDs(reviewIDs []primitive.ObjectID) (int64, error) {
+	result, err := database.MongoDB.Database("bookstore").Collection(r.votesCollection).DeleteMany(context.TODO(),
+		bson.M{"review_id": bson.M{"$in": reviewIDs}})
+	if err != nil {
+		r.log.Warnf("Ошибка удаления голосов за отзывы: %v", err)
+		return 0, err
+	}
+	return result.DeletedCount, nil
 }
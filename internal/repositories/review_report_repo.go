@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type ReviewReportRepository struct {
+	collection string
+	log        *logger.Logger
+}
+
+// NewReviewReportRepository создает новый экземпляр ReviewReportRepository
+func NewReviewReportRepository() *ReviewReportRepository {
+	return &ReviewReportRepository{
+		collection: "review_reports",
+		log:        logger.GetLogger(),
+	}
+}
+
+// CreateReport сохраняет жалобу; у пользователя может быть только одна открытая жалоба на отзыв.
+// Возвращает false, если такая жалоба уже есть
+func (r *ReviewReportRepository) CreateReport(report *models.ReviewReport) (bool, error) {
+	result, err := database.MongoDB.Database("bookstore").Collection(r.collection).UpdateOne(context.TODO(),
+		bson.M{"review_id": report.ReviewID, "user_id": report.UserID, "resolved": false},
+		bson.M{"$setOnInsert": report},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка сохранения жалобы на отзыв %s: %v", report.ReviewID.Hex(), err)
+		return false, err
+	}
+
+	if result.UpsertedID == nil {
+		return false, nil
+	}
+	report.ID = result.UpsertedID.(primitive.ObjectID)
+	return true, nil
+}
+
+// GetReportsByReview получает все жалобы на отзыв, включая рассмотренные
+func (r *ReviewReportRepository) GetReportsByReview(reviewID primitive.ObjectID) ([]models.ReviewReport, error) {
+	return r.find(bson.M{"review_id": reviewID})
+}
+
+// GetReportsByUser получает все жалобы пользователя
+func (r *ReviewReportRepository) GetReportsByUser(userID string) ([]models.ReviewReport, error) {
+	return r.find(bson.M{"user_id": userID})
+}
+
+// CountOpenReasons считает открытые жалобы по причинам для набора отзывов
+func (r *ReviewReportRepository) CountOpenReasons(reviewIDs []primitive.ObjectID) (map[primitive.ObjectID]map[models.ReportReason]int, error) {
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.collection).Aggregate(context.TODO(), bson.A{
+		bson.M{"$match": bson.M{"review_id": bson.M{"$in": reviewIDs}, "resolved": false}},
+		bson.M{"$group": bson.M{
+			"_id":   bson.M{"review_id": "$review_id", "reason": "$reason"},
+			"count": bson.M{"$sum": 1},
+		}},
+	})
+	if err != nil {
+		r.log.Warnf("Ошибка подсчета жалоб по причинам: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var rows []struct {
+		ID struct {
+			ReviewID primitive.ObjectID  `bson:"review_id"`
+			Reason   models.ReportReason `bson:"reason"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := cursor.All(context.TODO(), &rows); err != nil {
+		r.log.Warnf("Ошибка обработки количества жалоб: %v", err)
+		return nil, err
+	}
+
+	reasons := make(map[primitive.ObjectID]map[models.ReportReason]int, len(reviewIDs))
+	for _, row := range rows {
+		if reasons[row.ID.ReviewID] == nil {
+			reasons[row.ID.ReviewID] = make(map[models.ReportReason]int)
+		}
+		reasons[row.ID.ReviewID][row.ID.Reason] = row.Count
+	}
+
+	return reasons, nil
+}
+
+// ResolveReports помечает все открытые жалобы на отзыв рассмотренными
+func (r *ReviewReportRepository) ResolveReports(reviewID primitive.ObjectID) error {
+	_, err := database.MongoDB.Database("bookstore").Collection(r.collection).UpdateMany(context.TODO(),
+		bson.M{"review_id": reviewID, "resolved": false},
+		bson.M{"$set": bson.M{"resolved": true, "resolved_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка закрытия жалоб на отзыв %s: %v", reviewID.Hex(), err)
+		return err
+	}
+	return nil
+}
+
+// AnonymizeReportsByUser отвязывает жалобы от пользователя, оставляя их как доказательства для модерации
+func (r *ReviewReportRepository) AnonymizeReportsByUser(userID string, anonymousID string) error {
+	_, err := database.MongoDB.Database("bookstore").Collection(r.collection).UpdateMany(context.TODO(),
+		bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{"user_id": anonymousID}},
+	)
+	if err != nil {
+		r.log.Warnf("Ошибка анонимизации жалоб пользователя %s: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
+func (r *ReviewReportRepository) find(filter bson.M) ([]models.ReviewReport, error) {
+	cursor, err := database.MongoDB.Database("bookstore").Collection(r.collection).Find(context.TODO(), filter,
+		options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		r.log.Warnf("Ошибка получения жалоб на отзывы: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	reports := []models.ReviewReport{}
+	if err = cursor.All(context.TODO(), &reports); err != nil {
+		r.log.Warnf("Ошибка обработки жалоб на отзывы: %v", err)
+		return nil, err
+	}
+	return reports, nil
+}
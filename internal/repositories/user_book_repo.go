@@ -4,10 +4,26 @@ import (
 	"book-management-system/internal/database"
 	"book-management-system/internal/models"
 	"book-management-system/pkg/logger"
+	"errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"time"
 )
 
+// ErrReadThroughInProgress — нельзя начать новое прочтение, пока не закрыто текущее
+var ErrReadThroughInProgress = errors.New("текущее прочтение книги еще не завершено")
+
+// ReadingProgressUpdate новое состояние прогресса чтения, уже проверенное сервисом
+type ReadingProgressUpdate struct {
+	Status          models.ReadingStatus
+	Unit            models.ProgressUnit
+	PagesRead       int
+	ProgressPercent *float64
+	Location        *int
+	TotalLocations  *int
+	Rating          *int
+}
+
 type UserBookRepository struct {
 	db  *gorm.DB
 	log *logger.Logger
@@ -21,52 +37,351 @@ func NewUserBookRepository() *UserBookRepository {
 	}
 }
 
-// AddUserBook добавляет книгу в список пользователя
+// AddUserBook добавляет книгу в список пользователя и открывает первое прочтение
 func (r *UserBookRepository) AddUserBook(userID, bookID uuid.UUID) error {
 	userBook := models.UserBook{
+		UserID:       userID,
+		BookID:       &bookID,
+		Status:       models.StatusReading, // По умолчанию статус "Читаю"
+		PagesRead:    0,
+		ProgressUnit: models.ProgressUnitPages,
+	}
+
+	tx := r.db.Begin()
+
+	if err := tx.Create(&userBook).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка добавления книги в список пользователя: %v", err)
+		return err
+	}
+
+	readThrough := models.ReadThrough{
+		ID:        uuid.New(),
 		UserID:    userID,
-		BookID:    &bookID,
-		Status:    models.StatusReading, // По умолчанию статус "Читаю"
-		PagesRead: 0,
+		BookID:    bookID,
+		Status:    models.StatusReading,
+		StartedAt: time.Now().UTC(),
+	}
+
+	if err := tx.Create(&readThrough).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка создания прочтения книги: %v", err)
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// UpdateReadingProgress обновляет статус чтения и прогресс (страницы, процент, позиция),
+// закрывая текущее прочтение при завершении или отказе от книги
+func (r *UserBookRepository) UpdateReadingProgress(userID, bookID uuid.UUID, progress ReadingProgressUpdate) error {
+	status := progress.Status
+
+	tx := r.db.Begin()
+
+	var userBook models.UserBook
+	if err := tx.Where("user_id = ? AND book_id = ?", userID, bookID).First(&userBook).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка получения книги из списка пользователя: %v", err)
+		return err
 	}
 
-	err := r.db.Create(&userBook).Error
+	readThrough, err := r.currentReadThroughTx(tx, &userBook)
 	if err != nil {
-		r.log.Warnf("Ошибка добавления книги в список пользователя: %v", err)
+		tx.Rollback()
+		r.log.Warnf("Ошибка получения текущего прочтения: %v", err)
 		return err
 	}
 
-	return nil
+	updates := map[string]interface{}{
+		"status":           status,
+		"pages_read":       progress.PagesRead,
+		"progress_unit":    progress.Unit,
+		"progress_percent": progress.ProgressPercent,
+		"location":         progress.Location,
+		"total_locations":  progress.TotalLocations,
+	}
+
+	switch {
+	case readThrough.FinishedAt == nil && status != models.StatusReading:
+		// Книгу дочитали или бросили — закрываем текущее прочтение
+		now := time.Now().UTC()
+		readThrough.FinishedAt = &now
+		if status == models.StatusCompleted {
+			updates["read_count"] = gorm.Expr("read_count + 1")
+		}
+	case readThrough.FinishedAt != nil && status == models.StatusReading:
+		// Снова "читаю" после завершения — это новое прочтение, старое не трогаем
+		readThrough = &models.ReadThrough{
+			ID:        uuid.New(),
+			UserID:    userID,
+			BookID:    bookID,
+			StartedAt: time.Now().UTC(),
+		}
+	case readThrough.FinishedAt != nil && status != readThrough.Status:
+		// Исправление итога уже закрытого прочтения: брошена <-> дочитана
+		if status == models.StatusCompleted {
+			updates["read_count"] = gorm.Expr("read_count + 1")
+		} else if readThrough.Status == models.StatusCompleted {
+			updates["read_count"] = gorm.Expr("GREATEST(read_count - 1, 0)")
+		}
+	}
+
+	readThrough.Status = status
+	if progress.Rating != nil {
+		readThrough.Rating = progress.Rating
+	}
+
+	if err := tx.Save(readThrough).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка обновления прочтения: %v", err)
+		return err
+	}
+
+	if err := tx.Model(&models.UserBook{}).
+		Where("user_id = ? AND book_id = ?", userID, bookID).
+		Updates(updates).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка обновления прогресса чтения: %v", err)
+		return err
+	}
+
+	return tx.Commit().Error
 }
 
-// UpdateReadingProgress обновляет статус чтения и количество прочитанных страниц
-func (r *UserBookRepository) UpdateReadingProgress(userID, bookID uuid.UUID, status models.ReadingStatus, pagesRead int) error {
-	err := r.db.Model(&models.UserBook{}).
+// StartReread начинает новое прочтение уже завершенной или брошенной книги
+func (r *UserBookRepository) StartReread(userID, bookID uuid.UUID, startedAt time.Time) (*models.ReadThrough, error) {
+	tx := r.db.Begin()
+
+	var userBook models.UserBook
+	if err := tx.Where("user_id = ? AND book_id = ?", userID, bookID).First(&userBook).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка получения книги из списка пользователя: %v", err)
+		return nil, err
+	}
+
+	current, err := r.currentReadThroughTx(tx, &userBook)
+	if err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка получения текущего прочтения: %v", err)
+		return nil, err
+	}
+
+	if current.FinishedAt == nil {
+		tx.Rollback()
+		return nil, ErrReadThroughInProgress
+	}
+
+	readThrough := &models.ReadThrough{
+		ID:        uuid.New(),
+		UserID:    userID,
+		BookID:    bookID,
+		Status:    models.StatusReading,
+		StartedAt: startedAt,
+	}
+
+	if err := tx.Create(readThrough).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка создания повторного прочтения: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Model(&models.UserBook{}).
 		Where("user_id = ? AND book_id = ?", userID, bookID).
-		Updates(models.UserBook{
-			Status:    status,
-			PagesRead: pagesRead,
-		}).Error
+		Updates(map[string]interface{}{
+			"status":     models.StatusReading,
+			"pages_read": 0,
+			// Процент и позицию обнуляем, только если они вообще отслеживались
+			"progress_percent": gorm.Expr("CASE WHEN progress_percent IS NULL THEN NULL ELSE 0 END"),
+			"location":         gorm.Expr("CASE WHEN location IS NULL THEN NULL ELSE 0 END"),
+		}).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка сброса прогресса чтения: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return readThrough, nil
+}
+
+// currentReadThroughTx возвращает последнее прочтение книги; для записей, добавленных
+// до появления истории прочтений, создает его задним числом из агрегата
+func (r *UserBookRepository) currentReadThroughTx(tx *gorm.DB, userBook *models.UserBook) (*models.ReadThrough, error) {
+	var readThrough models.ReadThrough
+
+	err := tx.Where("user_id = ? AND book_id = ?", userBook.UserID, userBook.BookID).
+		Order("started_at DESC").
+		First(&readThrough).Error
+
+	if err == nil {
+		return &readThrough, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	readThrough = models.ReadThrough{
+		ID:        uuid.New(),
+		UserID:    userBook.UserID,
+		BookID:    *userBook.BookID,
+		Status:    userBook.Status,
+		StartedAt: userBook.CreatedAt,
+	}
+	if userBook.Status != models.StatusReading {
+		finishedAt := userBook.UpdatedAt
+		readThrough.FinishedAt = &finishedAt
+	}
+
+	if err := tx.Create(&readThrough).Error; err != nil {
+		return nil, err
+	}
+
+	// Завершенная до появления счетчика книга уже прочитана один раз
+	if userBook.Status == models.StatusCompleted && userBook.ReadCount == 0 {
+		if err := tx.Model(&models.UserBook{}).
+			Where("user_id = ? AND book_id = ?", userBook.UserID, userBook.BookID).
+			Update("read_count", 1).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return &readThrough, nil
+}
+
+// GetReadThroughs получает историю прочтений книги пользователем
+func (r *UserBookRepository) GetReadThroughs(userID, bookID uuid.UUID) ([]models.ReadThrough, error) {
+	var readThroughs []models.ReadThrough
+
+	err := r.db.Where("user_id = ? AND book_id = ?", userID, bookID).
+		Order("started_at ASC").
+		Find(&readThroughs).Error
 
 	if err != nil {
-		r.log.Warnf("Ошибка обновления прогресса чтения: %v", err)
+		r.log.Warnf("Ошибка получения истории прочтений книги %s: %v", bookID, err)
+		return nil, err
+	}
+
+	return readThroughs, nil
+}
+
+// GetReadThroughsByUser получает все прочтения пользователя
+func (r *UserBookRepository) GetReadThroughsByUser(userID uuid.UUID) ([]models.ReadThrough, error) {
+	var readThroughs []models.ReadThrough
+
+	err := r.db.Where("user_id = ?", userID).Find(&readThroughs).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения прочтений пользователя %s: %v", userID, err)
+		return nil, err
+	}
+
+	return readThroughs, nil
+}
+
+// GetReadThrough получает прочтение книги пользователем по ID
+func (r *UserBookRepository) GetReadThrough(userID, bookID, readThroughID uuid.UUID) (*models.ReadThrough, error) {
+	var readThrough models.ReadThrough
+
+	err := r.db.Where("id = ? AND user_id = ? AND book_id = ?", readThroughID, userID, bookID).
+		First(&readThrough).Error
+
+	if err != nil {
+		r.log.Warnf("Ошибка получения прочтения %s: %v", readThroughID, err)
+		return nil, err
+	}
+
+	return &readThrough, nil
+}
+
+// UpdateReadThrough сохраняет изменения прочтения (даты, оценку)
+func (r *UserBookRepository) UpdateReadThrough(readThrough *models.ReadThrough) error {
+	err := r.db.Save(readThrough).Error
+	if err != nil {
+		r.log.Warnf("Ошибка обновления прочтения %s: %v", readThrough.ID, err)
 		return err
 	}
 
 	return nil
 }
 
-// RemoveUserBook удаляет книгу из списка пользователя (soft delete)
-func (r *UserBookRepository) RemoveUserBook(userID, bookID uuid.UUID) error {
-	err := r.db.Where("user_id = ? AND book_id = ?", userID, bookID).
-		Delete(&models.UserBook{}).Error
+// UserBookStats агрегированная статистика чтения пользователя
+type UserBookStats struct {
+	TotalBooks  int
+	Reading     int
+	Completed   int
+	Dropped     int
+	TotalReads  int
+	Rereads     int
+	RereadBooks int
+}
+
+// GetUserBookStats считает статистику по списку книг и истории прочтений
+func (r *UserBookRepository) GetUserBookStats(userID uuid.UUID) (*UserBookStats, error) {
+	var stats UserBookStats
+
+	err := r.db.Model(&models.UserBook{}).
+		Select(`COUNT(*) AS total_books,
+			COUNT(*) FILTER (WHERE status = ?) AS reading,
+			COUNT(*) FILTER (WHERE status = ?) AS completed,
+			COUNT(*) FILTER (WHERE status = ?) AS dropped,
+			COALESCE(SUM(read_count), 0) AS total_reads,
+			COALESCE(SUM(GREATEST(read_count - 1, 0)), 0) AS rereads,
+			COUNT(*) FILTER (WHERE read_count > 1) AS reread_books`,
+			models.StatusReading, models.StatusCompleted, models.StatusDropped).
+		Where("user_id = ? AND book_id IS NOT NULL", userID).
+		Scan(&stats).Error
 
 	if err != nil {
+		r.log.Warnf("Ошибка подсчета статистики чтения пользователя %s: %v", userID, err)
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// RemoveUserBook удаляет книгу из списка пользователя вместе с заметками и историей прочтений
+func (r *UserBookRepository) RemoveUserBook(userID, bookID uuid.UUID) error {
+	tx := r.db.Begin()
+
+	notes := r.db.Model(&models.BookNote{}).Select("id").Where("user_id = ? AND book_id = ?", userID, bookID)
+	if err := deleteNotesTx(tx, notes); err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка удаления заметок к книге из списка пользователя: %v", err)
+		return err
+	}
+
+	if err := tx.Where("user_id = ? AND book_id = ?", userID, bookID).
+		Delete(&models.ReadThrough{}).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка удаления истории прочтений книги: %v", err)
+		return err
+	}
+
+	if err := tx.Where("user_id = ? AND book_id = ?", userID, bookID).
+		Delete(&models.UserBook{}).Error; err != nil {
+		tx.Rollback()
 		r.log.Warnf("Ошибка удаления книги из списка пользователя: %v", err)
 		return err
 	}
 
-	return nil
+	return tx.Commit().Error
+}
+
+// GetUserBook получает книгу из списка пользователя
+func (r *UserBookRepository) GetUserBook(userID, bookID uuid.UUID) (*models.UserBook, error) {
+	var userBook models.UserBook
+
+	err := r.db.Where("user_id = ? AND book_id = ?", userID, bookID).
+		First(&userBook).Error
+
+	if err != nil {
+		r.log.Warnf("Ошибка получения книги %s из списка пользователя %s: %v", bookID, userID, err)
+		return nil, err
+	}
+
+	return &userBook, nil
 }
 
 // GetUserBooks получает список книг пользователя
@@ -83,3 +398,59 @@ func (r *UserBookRepository) GetUserBooks(userID uuid.UUID) ([]models.UserBook,
 
 	return userBooks, nil
 }
+
+// GetUserBooksInBatches обходит список книг пользователя пачками, не загружая его целиком
+func (r *UserBookRepository) GetUserBooksInBatches(userID uuid.UUID, batchSize int, fn func([]models.UserBook) error) error {
+	var lastBookID *uuid.UUID
+
+	for {
+		var batch []models.UserBook
+
+		// Ключ (user_id, book_id) составной, поэтому пагинируем по book_id в рамках пользователя
+		query := r.db.Where("user_id = ? AND book_id IS NOT NULL", userID).
+			Order("book_id ASC").
+			Limit(batchSize)
+
+		if lastBookID != nil {
+			query = query.Where("book_id > ?", *lastBookID)
+		}
+
+		if err := query.Find(&batch).Error; err != nil {
+			r.log.Warnf("Ошибка обхода списка книг пользователя %s: %v", userID, err)
+			return err
+		}
+
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := fn(batch); err != nil {
+			return err
+		}
+
+		if len(batch) < batchSize {
+			return nil
+		}
+
+		lastBookID = batch[len(batch)-1].BookID
+	}
+}
+
+// DeleteUserBooksByUser удаляет весь список книг пользователя вместе с историей прочтений
+func (r *UserBookRepository) DeleteUserBooksByUser(userID uuid.UUID) error {
+	tx := r.db.Begin()
+
+	if err := tx.Where("user_id = ?", userID).Delete(&models.ReadThrough{}).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка удаления истории прочтений пользователя %s: %v", userID, err)
+		return err
+	}
+
+	if err := tx.Where("user_id = ?", userID).Delete(&models.UserBook{}).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка удаления списка книг пользователя %s: %v", userID, err)
+		return err
+	}
+
+	return tx.Commit().Error
+}
@@ -6,6 +6,7 @@ import (
 	"book-management-system/pkg/logger"
 	"errors"
 	"github.com/google/uuid"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -36,7 +37,7 @@ func (r *UserRepository) CreateUser(user models.User) error {
 // GetUserByEmail ищет пользователя по email
 func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
 	var user models.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.db.Where("email = ? AND deleted_at IS NULL", email).First(&user).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, errors.New("пользователь не найден")
 	} else if err != nil {
@@ -49,10 +50,52 @@ func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
 
 func (r *UserRepository) GetUserByID(userID uuid.UUID) (*models.User, error) {
 	var user models.User
-	err := r.db.Where("id = ?", userID).First(&user).Error
+	err := r.db.Where("id = ? AND deleted_at IS NULL", userID).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 
 	return &user, nil
 }
+
+// GetUsersByIDs возвращает пользователей по списку ID, включая удаленных
+func (r *UserRepository) GetUsersByIDs(userIDs []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	if err := r.db.Where("id IN (?)", userIDs).Find(&users).Error; err != nil {
+		r.log.Warnf("Ошибка получения пользователей по множественным id: %v", err)
+		return nil, err
+	}
+	return users, nil
+}
+
+// AnonymizeUser стирает персональные данные пользователя и помечает его удаленным
+func (r *UserRepository) AnonymizeUser(userID uuid.UUID) error {
+	placeholder := "deleted-" + userID.String()
+	now := time.Now().UTC()
+
+	err := r.db.Model(&models.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"username":   placeholder,
+			"email":      placeholder + "@deleted.invalid",
+			"password":   "",
+			"deleted_at": now,
+		}).Error
+
+	if err != nil {
+		r.log.Warnf("Ошибка анонимизации пользователя %s: %v", userID, err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateUserRole меняет роль пользователя
+func (r *UserRepository) UpdateUserRole(userID uuid.UUID, role string) error {
+	err := r.db.Model(&models.User{}).Where("id = ?", userID).Update("role", role).Error
+	if err != nil {
+		r.log.Warnf("Ошибка смены роли пользователя %s: %v", userID, err)
+		return err
+	}
+	return nil
+}
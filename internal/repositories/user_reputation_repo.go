@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type UserReputationRepository struct {
+	db  *gorm.DB
+	log *logger.Logger
+}
+
+// NewUserReputationRepository создает репозиторий репутации пользователей
+func NewUserReputationRepository() *UserReputationRepository {
+	return &UserReputationRepository{
+		db:  database.DB,
+		log: logger.GetLogger(),
+	}
+}
+
+// ApplyDelta прибавляет к репутации пользователя приращения из delta, создавая запись при первом событии
+func (r *UserReputationRepository) ApplyDelta(delta models.UserReputation) error {
+	delta.UpdatedAt = time.Now().UTC()
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "score"}, Value: gorm.Expr("user_reputations.score + ?", delta.Score)},
+			{Column: clause.Column{Name: "review_likes"}, Value: gorm.Expr("user_reputations.review_likes + ?", delta.ReviewLikes)},
+			{Column: clause.Column{Name: "review_dislikes"}, Value: gorm.Expr("user_reputations.review_dislikes + ?", delta.ReviewDislikes)},
+			{Column: clause.Column{Name: "confirmed_books"}, Value: gorm.Expr("user_reputations.confirmed_books + ?", delta.ConfirmedBooks)},
+			{Column: clause.Column{Name: "moderation_penalties"}, Value: gorm.Expr("user_reputations.moderation_penalties + ?", delta.ModerationPenalties)},
+			{Column: clause.Column{Name: "updated_at"}, Value: delta.UpdatedAt},
+		},
+	}).Create(&delta).Error
+	if err != nil {
+		r.log.Warnf("Ошибка обновления репутации пользователя %s: %v", delta.UserID, err)
+		return err
+	}
+	return nil
+}
+
+// GetReputation получает репутацию пользователя; у пользователя без событий ее еще нет — возвращаем нулевую
+func (r *UserReputationRepository) GetReputation(userID uuid.UUID) (*models.UserReputation, error) {
+	reputation := models.UserReputation{UserID: userID}
+
+	err := r.db.Where("user_id = ?", userID).Limit(1).Find(&reputation).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения репутации пользователя %s: %v", userID, err)
+		return nil, err
+	}
+	return &reputation, nil
+}
+
+// GetScores получает очки репутации для набора пользователей
+func (r *UserReputationRepository) GetScores(userIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	var reputations []models.UserReputation
+
+	err := r.db.Select("user_id", "score").Where("user_id IN (?)", userIDs).Find(&reputations).Error
+	if err != nil {
+		r.log.Warnf("Ошибка получения репутации пользователей: %v", err)
+		return nil, err
+	}
+
+	scores := make(map[uuid.UUID]int, len(reputations))
+	for _, reputation := range reputations {
+		scores[reputation.UserID] = reputation.Score
+	}
+	return scores, nil
+}
+
+// DeleteReputation удаляет репутацию пользователя
+func (r *UserReputationRepository) DeleteReputation(userID uuid.UUID) error {
+	err := r.db.Where("user_id = ?", userID).Delete(&models.UserReputation{}).Error
+	if err != nil {
+		r.log.Warnf("Ошибка удаления репутации пользователя %s: %v", userID, err)
+		return err
+	}
+	return nil
+}
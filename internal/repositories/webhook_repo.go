@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"book-management-system/internal/database"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"encoding/json"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type WebhookRepository struct {
+	db  *gorm.DB
+	log *logger.Logger
+}
+
+// NewWebhookRepository создает репозиторий вебхуков и их доставок
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{
+		db:  database.DB,
+		log: logger.GetLogger(),
+	}
+}
+
+// CreateWebhook сохраняет подписку
+func (r *WebhookRepository) CreateWebhook(webhook *models.Webhook) error {
+	if err := r.db.Create(webhook).Error; err != nil {
+		r.log.Warnf("Ошибка создания вебхука: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetWebhooks получает все подписки
+func (r *WebhookRepository) GetWebhooks() ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	if err := r.db.Order("created_at").Find(&webhooks).Error; err != nil {
+		r.log.Warnf("Ошибка получения вебхуков: %v", err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetWebhookByID получает подписку по ID
+func (r *WebhookRepository) GetWebhookByID(webhookID uuid.UUID) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if err := r.db.Where("id = ?", webhookID).First(&webhook).Error; err != nil {
+		r.log.Warnf("Ошибка получения вебхука %s: %v", webhookID, err)
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetActiveWebhooksForEvent получает включенные подписки на событие
+func (r *WebhookRepository) GetActiveWebhooksForEvent(eventType string) ([]models.Webhook, error) {
+	eventTypes, err := json.Marshal([]string{eventType})
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []models.Webhook
+	if err := r.db.Where("active = ? AND event_types @> ?", true, string(eventTypes)).Find(&webhooks).Error; err != nil {
+		r.log.Warnf("Ошибка получения вебхуков события %s: %v", eventType, err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook сохраняет адрес, события и включенность подписки
+func (r *WebhookRepository) UpdateWebhook(webhook *models.Webhook) error {
+	err := r.db.Model(webhook).Select("url", "event_types", "active", "updated_at").Updates(webhook).Error
+	if err != nil {
+		r.log.Warnf("Ошибка обновления вебхука %s: %v", webhook.ID, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом доставок
+func (r *WebhookRepository) DeleteWebhook(webhookID uuid.UUID) error {
+	tx := r.db.Begin()
+
+	if err := tx.Where("webhook_id = ?", webhookID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка удаления доставок вебхука %s: %v", webhookID, err)
+		return err
+	}
+
+	result := tx.Where("id = ?", webhookID).Delete(&models.Webhook{})
+	if result.Error != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка удаления вебхука %s: %v", webhookID, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return gorm.ErrRecordNotFound
+	}
+
+	return tx.Commit().Error
+}
+
+// CreateDeliveries ставит доставки в очередь
+func (r *WebhookRepository) CreateDeliveries(deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := r.db.Create(&deliveries).Error; err != nil {
+		r.log.Warnf("Ошибка постановки доставок вебхуков в очередь: %v", err)
+		return err
+	}
+	return nil
+}
+
+// ClaimDueDeliveries забирает доставки, время попытки которых наступило, и откладывает их на lease,
+// чтобы другой экземпляр сервиса не отправил их одновременно. Если попытка не будет сохранена
+// (например, процесс упал), доставка вернется в очередь по истечении lease
+func (r *WebhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	now := time.Now().UTC()
+
+	tx := r.db.Begin()
+
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка выборки доставок вебхуков: %v", err)
+		return nil, err
+	}
+
+	if len(deliveries) == 0 {
+		tx.Rollback()
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(deliveries))
+	for i, delivery := range deliveries {
+		ids[i] = delivery.ID
+	}
+
+	if err := tx.Model(&models.WebhookDelivery{}).Where("id IN (?)", ids).Update("next_attempt_at", now.Add(lease)).Error; err != nil {
+		tx.Rollback()
+		r.log.Warnf("Ошибка резервирования доставок вебхуков: %v", err)
+		return nil, err
+	}
+
+	return deliveries, tx.Commit().Error
+}
+
+// SaveDeliveryAttempt сохраняет результат попытки доставки
+func (r *WebhookRepository) SaveDeliveryAttempt(delivery *models.WebhookDelivery) error {
+	err := r.db.Model(&models.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":           delivery.Status,
+		"attempts":         delivery.Attempts,
+		"next_attempt_at":  delivery.NextAttemptAt,
+		"last_status_code": delivery.LastStatusCode,
+		"last_error":       delivery.LastError,
+		"delivered_at":     delivery.DeliveredAt,
+		"updated_at":       time.Now().UTC(),
+	}).Error
+	if err != nil {
+		r.log.Warnf("Ошибка сохранения попытки доставки %s: %v", delivery.ID, err)
+		return err
+	}
+	return nil
+}
+
+// GetDeliveriesPaginated получает журнал доставок вебхука, новые первыми
+func (r *WebhookRepository) GetDeliveriesPaginated(webhookID uuid.UUID, limit int, afterID *uuid.UUID) ([]models.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var deliveries []models.WebhookDelivery
+
+	query := r.db.Where("webhook_id = ?", webhookID).
+		Order("created_at DESC, id DESC").
+		Limit(limit)
+
+	if afterID != nil {
+		query = query.Where("(created_at, id) < (?)", r.db.Model(&models.WebhookDelivery{}).
+			Select("created_at, id").
+			Where("id = ?", *afterID))
+	}
+
+	if err := query.Find(&deliveries).Error; err != nil {
+		r.log.Warnf("Ошибка получения доставок вебхука %s: %v", webhookID, err)
+		return nil, err
+	}
+	return deliveries, nil
+}
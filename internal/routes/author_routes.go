@@ -4,18 +4,10 @@ import (
 	"book-management-system/internal/constants"
 	"book-management-system/internal/handlers"
 	"book-management-system/internal/middleware"
-	"book-management-system/internal/repositories"
-	"book-management-system/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterAuthorRoutes(r *gin.RouterGroup,
-	bookRepo *repositories.BookRepository,
-	booksAuthorMappingRepo *repositories.BookAuthorRepository,
-	authorRepo *repositories.AuthorRepository) {
-
-	authorService := services.NewAuthorService(bookRepo, booksAuthorMappingRepo, authorRepo)
-	authorHandler := handlers.NewAuthorHandler(authorService)
+func RegisterAuthorRoutes(r *gin.RouterGroup, authorHandler *handlers.AuthorHandler) {
 	authorRoutes := r.Group("/authors")
 	{
 		authorRoutes.GET("/", authorHandler.GetAuthorsPaginated)
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"book-management-system/internal/handlers"
+	"book-management-system/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterBookNoteRoutes регистрирует роуты заметок к книгам и публичных цитат
+func RegisterBookNoteRoutes(r *gin.RouterGroup, noteHandler *handlers.BookNoteHandler) {
+	noteRoutes := r.Group("/users/me/books/:bookID/notes")
+	noteRoutes.Use(middleware.AuthMiddleware())
+	{
+		noteRoutes.GET("/", noteHandler.GetNotes)
+		noteRoutes.POST("/", noteHandler.CreateNote)
+		noteRoutes.PUT("/:noteID", noteHandler.UpdateNote)
+		noteRoutes.DELETE("/:noteID", noteHandler.DeleteNote)
+	}
+
+	r.GET("/books/:bookID/quotes", noteHandler.GetPublicQuotes)
+	r.POST("/books/:bookID/quotes/:noteID/vote", middleware.AuthMiddleware(), noteHandler.VoteQuote)
+}
@@ -4,25 +4,23 @@ import (
 	"book-management-system/internal/constants"
 	"book-management-system/internal/handlers"
 	"book-management-system/internal/middleware"
-	"book-management-system/internal/repositories"
-	"book-management-system/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterBookRoutes(
 	r *gin.RouterGroup,
-	bookRepo *repositories.BookRepository,
-	booksAuthorMappingRepo *repositories.BookAuthorRepository,
-	authorRepo *repositories.AuthorRepository,
+	bookHandler *handlers.BookHandler,
+	bibliographyHandler *handlers.BibliographyHandler,
+	bookMetadataHandler *handlers.BookMetadataHandler,
 ) {
-
-	bookService := services.NewBookService(bookRepo, booksAuthorMappingRepo, authorRepo)
-	bookHandler := handlers.NewBookHandler(bookService)
-
 	bookRoutes := r.Group("/books")
 	{
 		bookRoutes.GET("/", bookHandler.GetBooksPaginated)
+		bookRoutes.GET("/top-rated", bookHandler.GetTopRatedBooks)
+		bookRoutes.GET("/export", bibliographyHandler.ExportCatalog)
 		bookRoutes.GET("/:bookID", bookHandler.GetBookByID)
+		bookRoutes.GET("/:bookID/citation", bibliographyHandler.GetBookCitation)
+		bookRoutes.GET("/:bookID/jsonld", bookMetadataHandler.GetBookJSONLD)
 		bookRoutes.POST("/", middleware.AuthMiddleware(), bookHandler.CreateBook)
 		bookRoutes.PUT("/:bookID", middleware.AuthMiddleware(), bookHandler.UpdateBook)
 		bookRoutes.DELETE("/:bookID", middleware.AuthMiddleware(), middleware.RoleMiddleware(constants.Roles.Moderator, constants.Roles.Admin), bookHandler.DeleteBook)
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"book-management-system/internal/constants"
+	"book-management-system/internal/handlers"
+	"book-management-system/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterConsistencyRoutes регистрирует админские роуты проверки согласованности хранилищ
+func RegisterConsistencyRoutes(r *gin.RouterGroup, consistencyHandler *handlers.ConsistencyHandler) {
+	consistencyRoutes := r.Group("/admin/consistency")
+	consistencyRoutes.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware(constants.Roles.Admin))
+	{
+		consistencyRoutes.GET("/", consistencyHandler.CheckConsistency)
+		consistencyRoutes.POST("/repair", consistencyHandler.RepairConsistency)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"book-management-system/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterFeedRoutes регистрирует ленты Atom для читалок лент; ленты открыты без авторизации
+func RegisterFeedRoutes(r *gin.Engine, feedHandler *handlers.FeedHandler) {
+	feedRoutes := r.Group("/feeds")
+	{
+		feedRoutes.GET("/books.atom", feedHandler.GetNewBooksFeed)
+		feedRoutes.GET("/books/:bookID/reviews.atom", feedHandler.GetBookReviewsFeed)
+		feedRoutes.GET("/authors/:authorFile", feedHandler.GetAuthorFeed)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"book-management-system/internal/handlers"
+	"book-management-system/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterFollowRoutes регистрирует роуты подписок и ленты активности
+func RegisterFollowRoutes(r *gin.RouterGroup, followHandler *handlers.FollowHandler) {
+	r.GET("/users/me/feed", middleware.AuthMiddleware(), followHandler.GetFeed)
+	r.GET("/users/me/following", middleware.AuthMiddleware(), followHandler.GetFollowing)
+	r.POST("/users/:userID/follow", middleware.AuthMiddleware(), followHandler.FollowUser)
+	r.DELETE("/users/:userID/follow", middleware.AuthMiddleware(), followHandler.UnfollowUser)
+	r.POST("/authors/:authorID/follow", middleware.AuthMiddleware(), followHandler.FollowAuthor)
+	r.DELETE("/authors/:authorID/follow", middleware.AuthMiddleware(), followHandler.UnfollowAuthor)
+}
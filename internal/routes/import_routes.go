@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"book-management-system/internal/constants"
+	"book-management-system/internal/handlers"
+	"book-management-system/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterImportRoutes регистрирует админские роуты массовой загрузки книг
+func RegisterImportRoutes(r *gin.RouterGroup, importHandler *handlers.ImportHandler) {
+	importRoutes := r.Group("/admin/import")
+	importRoutes.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware(constants.Roles.Admin))
+	{
+		importRoutes.POST("/books", importHandler.StartBookImport)
+		importRoutes.GET("/jobs", importHandler.GetImportJobs)
+		importRoutes.GET("/jobs/:jobID", importHandler.GetImportJob)
+	}
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"book-management-system/internal/handlers"
+	"book-management-system/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterLibraryExportRoutes регистрирует роуты выгрузки библиотеки пользователя
+func RegisterLibraryExportRoutes(r *gin.RouterGroup, exportHandler *handlers.LibraryExportHandler) {
+	r.GET("/users/me/export", middleware.AuthMiddleware(), exportHandler.ExportLibrary)
+}
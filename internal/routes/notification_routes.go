@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"book-management-system/internal/handlers"
+	"book-management-system/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterNotificationRoutes регистрирует роуты центра уведомлений и подписывает его на шину событий
+func RegisterNotificationRoutes(r *gin.RouterGroup, notificationHandler *handlers.NotificationHandler) {
+	notificationRoutes := r.Group("/users/me/notifications")
+	notificationRoutes.Use(middleware.AuthMiddleware())
+	{
+		notificationRoutes.GET("/", notificationHandler.GetNotifications)
+		notificationRoutes.GET("/stream", notificationHandler.StreamNotifications)
+		notificationRoutes.POST("/read-all", notificationHandler.MarkAllNotificationsRead)
+		notificationRoutes.POST("/:notificationID/read", notificationHandler.MarkNotificationRead)
+		notificationRoutes.GET("/preferences", notificationHandler.GetNotificationPreferences)
+		notificationRoutes.PUT("/preferences", notificationHandler.UpdateNotificationPreferences)
+	}
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"book-management-system/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterOPDSRoutes регистрирует каталог OPDS: /opds — версия 1.2, /opds/v2 — версия 2.0.
+// Каталог открыт без авторизации, как и список книг в API
+func RegisterOPDSRoutes(r *gin.Engine, opds1Handler, opds2Handler *handlers.OPDSHandler) {
+	opdsRoutes := r.Group("/opds")
+	{
+		opdsRoutes.GET("/", opds1Handler.GetRoot)
+		opdsRoutes.GET("/new", opds1Handler.GetNewestBooks)
+		opdsRoutes.GET("/books", opds1Handler.GetAllBooks)
+		opdsRoutes.GET("/top", opds1Handler.GetTopRatedBooks)
+		opdsRoutes.GET("/authors", opds1Handler.GetAuthors)
+		opdsRoutes.GET("/authors/:authorID", opds1Handler.GetAuthorBooks)
+		opdsRoutes.GET("/genres", opds1Handler.GetGenres)
+		opdsRoutes.GET("/genres/:genreID", opds1Handler.GetGenreBooks)
+		opdsRoutes.GET("/search", opds1Handler.Search)
+		opdsRoutes.GET("/opensearch.xml", opds1Handler.GetOpenSearchDescription)
+	}
+
+	opds2Routes := r.Group("/opds/v2")
+	{
+		opds2Routes.GET("/", opds2Handler.GetRoot)
+		opds2Routes.GET("/new", opds2Handler.GetNewestBooks)
+		opds2Routes.GET("/books", opds2Handler.GetAllBooks)
+		opds2Routes.GET("/top", opds2Handler.GetTopRatedBooks)
+		opds2Routes.GET("/authors", opds2Handler.GetAuthors)
+		opds2Routes.GET("/authors/:authorID", opds2Handler.GetAuthorBooks)
+		opds2Routes.GET("/genres", opds2Handler.GetGenres)
+		opds2Routes.GET("/genres/:genreID", opds2Handler.GetGenreBooks)
+		opds2Routes.GET("/search", opds2Handler.Search)
+	}
+}
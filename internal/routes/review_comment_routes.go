@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"book-management-system/internal/handlers"
+	"book-management-system/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterReviewCommentRoutes регистрирует роуты комментариев к отзывам
+func RegisterReviewCommentRoutes(r *gin.RouterGroup, commentHandler *handlers.ReviewCommentHandler) {
+	commentRoutes := r.Group("/reviews/:reviewID/comments")
+	{
+		commentRoutes.GET("/", commentHandler.GetComments)
+		commentRoutes.POST("/", middleware.AuthMiddleware(), commentHandler.CreateComment)
+		commentRoutes.PUT("/:commentID", middleware.AuthMiddleware(), commentHandler.UpdateComment)
+		commentRoutes.DELETE("/:commentID", middleware.AuthMiddleware(), commentHandler.DeleteComment)
+	}
+}
@@ -1,22 +1,18 @@
 package routes
 
 import (
+	"book-management-system/internal/constants"
 	"book-management-system/internal/handlers"
 	"book-management-system/internal/middleware"
-	"book-management-system/internal/repositories"
-	"book-management-system/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 // RegisterReviewRoutes регистрирует роуты отзывов
 func RegisterReviewRoutes(
 	r *gin.RouterGroup,
-	reviewRepo *repositories.ReviewRepository,
-	bookRepo *repositories.BookRepository,
+	reviewHandler *handlers.ReviewHandler,
+	moderationHandler *handlers.ReviewModerationHandler,
 ) {
-	reviewService := services.NewReviewService(reviewRepo, bookRepo)
-	reviewHandler := handlers.NewReviewHandler(reviewService)
-
 	reviewRoutes := r.Group("/reviews")
 	{
 		reviewRoutes.POST("/", middleware.AuthMiddleware(), reviewHandler.CreateReview)
@@ -24,5 +20,24 @@ func RegisterReviewRoutes(
 		reviewRoutes.PUT("/:reviewID", middleware.AuthMiddleware(), reviewHandler.UpdateReview)
 		reviewRoutes.DELETE("/:reviewID", middleware.AuthMiddleware(), reviewHandler.DeleteReview)
 		reviewRoutes.POST("/:reviewID/vote", middleware.AuthMiddleware(), reviewHandler.VoteReview)
+		reviewRoutes.POST("/:reviewID/report", middleware.AuthMiddleware(), moderationHandler.ReportReview)
+		reviewRoutes.GET("/:reviewID/versions", reviewHandler.GetReviewVersions)
+		reviewRoutes.POST("/:reviewID/versions/:number/restore",
+			middleware.AuthMiddleware(),
+			middleware.RoleMiddleware(constants.Roles.Moderator, constants.Roles.Admin),
+			moderationHandler.RestoreReviewVersion,
+		)
+	}
+
+	r.PUT("/books/:bookID/reviews/me", middleware.AuthMiddleware(), reviewHandler.UpsertMyReview)
+
+	moderationRoutes := r.Group("/moderation/reviews")
+	moderationRoutes.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware(constants.Roles.Moderator, constants.Roles.Admin))
+	{
+		moderationRoutes.GET("/", moderationHandler.GetQueue)
+		moderationRoutes.GET("/:reviewID", moderationHandler.GetReviewDetails)
+		moderationRoutes.POST("/:reviewID/approve", moderationHandler.ApproveReview)
+		moderationRoutes.POST("/:reviewID/hide", moderationHandler.HideReview)
+		moderationRoutes.POST("/:reviewID/delete", moderationHandler.DeleteReview)
 	}
 }
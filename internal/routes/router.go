@@ -1,21 +1,17 @@
 package routes
 
 import (
+	"book-management-system/config"
+	"book-management-system/internal/feeds"
+	"book-management-system/internal/handlers"
 	"book-management-system/internal/middleware"
-	"book-management-system/internal/repositories"
 	"github.com/gin-gonic/gin"
 )
 
-// InitRouter собирает все роуты проекта
-func InitRouter() *gin.Engine {
-	userRepo := repositories.NewUserRepository()
-	refreshTokenRepo := repositories.NewRefreshTokenRepository()
-	bookRepo := repositories.NewBookRepository()
-	booksAuthorMappingRepo := repositories.NewBookAuthorRepository()
-	userBookRepo := repositories.NewUserBookRepository()
-	authorRepo := repositories.NewAuthorRepository()
-	reviewRepo := repositories.NewReviewRepository()
-	feedbackRepo := repositories.NewFeedbackRepository()
+// InitRouter собирает все роуты проекта поверх уже созданных сервисов
+func InitRouter(s *Services) *gin.Engine {
+	site := handlers.NewPublicSite(config.GetEnv("PUBLIC_BASE_URL", ""))
+	bookMetadataHandler := handlers.NewBookMetadataHandler(s.BookMetadata, site)
 
 	r := gin.Default()
 
@@ -25,15 +21,34 @@ func InitRouter() *gin.Engine {
 	RegisterSwaggerRoutes(r)
 	// хэлс-чек
 	RegisterHealthCheckRoutes(r)
+	// обложки книг, на них ссылаются каталог OPDS и поле cover_image
+	r.Static("/uploads", "./uploads")
+	// каталог OPDS для читалок
+	RegisterOPDSRoutes(r,
+		handlers.NewOPDSHandler(s.OPDS, feeds.OPDS1, "/opds", site),
+		handlers.NewOPDSHandler(s.OPDS, feeds.OPDS2, "/opds/v2", site))
+	// ленты Atom
+	RegisterFeedRoutes(r, handlers.NewFeedHandler(s.Feed, site))
+	// страницы книг для соцсетей
+	RegisterShareRoutes(r, bookMetadataHandler)
 
 	apiV1 := r.Group("/api/v1")
 
-	RegisterUserRoutes(apiV1, userRepo, refreshTokenRepo)
-	RegisterBookRoutes(apiV1, bookRepo, booksAuthorMappingRepo, authorRepo)
-	RegisterUserBookRoutes(apiV1, userBookRepo)
-	RegisterAuthorRoutes(apiV1, bookRepo, booksAuthorMappingRepo, authorRepo)
-	RegisterReviewRoutes(apiV1, reviewRepo, bookRepo)
-	RegisterFeedbackRoutes(apiV1, feedbackRepo)
+	RegisterUserRoutes(apiV1, handlers.NewUserHandler(s.User))
+	RegisterBookRoutes(apiV1, handlers.NewBookHandler(s.Book), handlers.NewBibliographyHandler(s.Catalog), bookMetadataHandler)
+	RegisterUserBookRoutes(apiV1, handlers.NewUserBookHandler(s.UserBook))
+	RegisterAuthorRoutes(apiV1, handlers.NewAuthorHandler(s.Author))
+	RegisterReviewRoutes(apiV1, handlers.NewReviewHandler(s.Review, s.ReviewModeration), handlers.NewReviewModerationHandler(s.ReviewModeration))
+	RegisterFeedbackRoutes(apiV1, handlers.NewFeedbackHandler(s.Feedback))
+	RegisterLibraryExportRoutes(apiV1, handlers.NewLibraryExportHandler(s.LibraryExport))
+	RegisterUserDataRoutes(apiV1, handlers.NewUserDataHandler(s.UserData))
+	RegisterBookNoteRoutes(apiV1, handlers.NewBookNoteHandler(s.BookNote))
+	RegisterReviewCommentRoutes(apiV1, handlers.NewReviewCommentHandler(s.ReviewComment))
+	RegisterFollowRoutes(apiV1, handlers.NewFollowHandler(s.Follow, s.Activity))
+	RegisterNotificationRoutes(apiV1, handlers.NewNotificationHandler(s.Notification))
+	RegisterWebhookRoutes(apiV1, handlers.NewWebhookHandler(s.Webhook))
+	RegisterConsistencyRoutes(apiV1, handlers.NewConsistencyHandler(s.Consistency))
+	RegisterImportRoutes(apiV1, handlers.NewImportHandler(s.Import))
 
 	return r
 }
@@ -0,0 +1,103 @@
+package routes
+
+import (
+	"book-management-system/internal/events"
+	"book-management-system/internal/repositories"
+	"book-management-system/internal/services"
+)
+
+// Services сервисы сервера. Собираются один раз на процесс: роуты получают хэндлеры поверх них,
+// а фоновые воркеры запускает main
+type Services struct {
+	User             *services.UserService
+	Book             *services.BookService
+	Catalog          *services.CatalogService
+	BookMetadata     *services.BookMetadataService
+	UserBook         *services.UserBookService
+	Author           *services.AuthorService
+	Review           *services.ReviewService
+	ReviewModeration *services.ReviewModerationService
+	ReviewComment    *services.ReviewCommentService
+	Outbox           *services.OutboxService
+	Feedback         *services.FeedbackService
+	LibraryExport    *services.LibraryExportService
+	UserData         *services.UserDataService
+	BookNote         *services.BookNoteService
+	Follow           *services.FollowService
+	Activity         *services.ActivityService
+	Notification     *services.NotificationService
+	Webhook          *services.WebhookService
+	Consistency      *services.ConsistencyService
+	Import           *services.ImportService
+	Feed             *services.FeedService
+	OPDS             *services.OPDSService
+
+	RefreshTokenRepo *repositories.RefreshTokenRepository
+}
+
+// NewServices создает репозитории и сервисы и подписывает обработчики событий на шину
+func NewServices() *Services {
+	userRepo := repositories.NewUserRepository()
+	refreshTokenRepo := repositories.NewRefreshTokenRepository()
+	bookRepo := repositories.NewBookRepository()
+	booksAuthorMappingRepo := repositories.NewBookAuthorRepository()
+	userBookRepo := repositories.NewUserBookRepository()
+	authorRepo := repositories.NewAuthorRepository()
+	reviewRepo := repositories.NewReviewRepository()
+	feedbackRepo := repositories.NewFeedbackRepository()
+	bookRatingRepo := repositories.NewBookRatingRepository()
+	bookNoteRepo := repositories.NewBookNoteRepository()
+	reviewCommentRepo := repositories.NewReviewCommentRepository()
+	reviewReportRepo := repositories.NewReviewReportRepository()
+	moderatorActionRepo := repositories.NewModeratorActionRepository()
+	userReputationRepo := repositories.NewUserReputationRepository()
+	followRepo := repositories.NewFollowRepository()
+	activityEventRepo := repositories.NewActivityEventRepository()
+	notificationRepo := repositories.NewNotificationRepository()
+	webhookRepo := repositories.NewWebhookRepository()
+	outboxRepo := repositories.NewOutboxRepository()
+	consistencyRepo := repositories.NewConsistencyRepository()
+	importJobRepo := repositories.NewImportJobRepository()
+	genreRepo := repositories.NewGenreRepository()
+
+	reputationService := services.NewReputationService(userReputationRepo)
+	activityService := services.NewActivityService(activityEventRepo, booksAuthorMappingRepo)
+	bookService := services.NewBookService(bookRepo, booksAuthorMappingRepo, authorRepo, reputationService, activityService)
+	authorService := services.NewAuthorService(bookRepo, booksAuthorMappingRepo, authorRepo)
+	reviewService := services.NewReviewService(reviewRepo, bookRepo, outboxRepo, reputationService, activityService)
+	notificationService := services.NewNotificationService(notificationRepo)
+	webhookService := services.NewWebhookService(webhookRepo)
+
+	bus := events.GetBus()
+	bus.Subscribe(notificationService.HandleEvent)
+	bus.Subscribe(webhookService.HandleEvent)
+
+	return &Services{
+		User:             services.NewUserService(userRepo, refreshTokenRepo, reputationService),
+		Book:             bookService,
+		Catalog:          services.NewCatalogService(bookRepo, booksAuthorMappingRepo, authorRepo, bookService),
+		BookMetadata:     services.NewBookMetadataService(bookService),
+		UserBook:         services.NewUserBookService(userBookRepo, bookRepo, activityService),
+		Author:           authorService,
+		Review:           reviewService,
+		ReviewModeration: services.NewReviewModerationService(reviewRepo, reviewReportRepo, moderatorActionRepo, reviewService),
+		ReviewComment:    services.NewReviewCommentService(reviewCommentRepo, reviewRepo),
+		Outbox:           services.NewOutboxService(outboxRepo, reviewRepo, reviewService),
+		Feedback:         services.NewFeedbackService(feedbackRepo),
+		LibraryExport:    services.NewLibraryExportService(userBookRepo, bookRepo, booksAuthorMappingRepo, authorRepo, bookRatingRepo, reviewRepo),
+		UserData: services.NewUserDataService(userRepo, userBookRepo, bookRatingRepo, bookNoteRepo, refreshTokenRepo, reviewRepo,
+			reviewCommentRepo, reviewReportRepo, moderatorActionRepo, feedbackRepo, userReputationRepo, followRepo, activityEventRepo,
+			notificationRepo, outboxRepo, reputationService),
+		BookNote:     services.NewBookNoteService(bookNoteRepo, userBookRepo),
+		Follow:       services.NewFollowService(followRepo, userRepo, authorRepo),
+		Activity:     activityService,
+		Notification: notificationService,
+		Webhook:      webhookService,
+		Consistency:  services.NewConsistencyService(consistencyRepo, reviewRepo, outboxRepo),
+		Import:       services.NewImportService(importJobRepo, bookRepo, booksAuthorMappingRepo, authorRepo, genreRepo, bookService),
+		Feed:         services.NewFeedService(bookService, authorRepo, reviewRepo, userRepo),
+		OPDS:         services.NewOPDSService(bookService, authorService, genreRepo),
+
+		RefreshTokenRepo: refreshTokenRepo,
+	}
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"book-management-system/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterShareRoutes регистрирует HTML-страницы для превью ссылок в соцсетях; страницы открыты без авторизации
+func RegisterShareRoutes(r *gin.Engine, bookMetadataHandler *handlers.BookMetadataHandler) {
+	shareRoutes := r.Group("/share")
+	{
+		shareRoutes.GET("/books/:bookID", bookMetadataHandler.GetBookSharePage)
+	}
+}
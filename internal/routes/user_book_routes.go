@@ -3,22 +3,21 @@ package routes
 import (
 	"book-management-system/internal/handlers"
 	"book-management-system/internal/middleware"
-	"book-management-system/internal/repositories"
-	"book-management-system/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 // RegisterUserBookRoutes регистрирует роуты для управления книгами пользователя
-func RegisterUserBookRoutes(r *gin.RouterGroup, userBookRepo *repositories.UserBookRepository) {
-	userBookService := services.NewUserBookService(userBookRepo)
-	userBookHandler := handlers.NewUserBookHandler(userBookService)
-
+func RegisterUserBookRoutes(r *gin.RouterGroup, userBookHandler *handlers.UserBookHandler) {
 	userBookRoutes := r.Group("/users/me/books")
 	userBookRoutes.Use(middleware.AuthMiddleware())
 	{
 		userBookRoutes.POST("/", userBookHandler.AddBookToUser)
 		userBookRoutes.GET("/", userBookHandler.GetUserBooks)
+		userBookRoutes.GET("/stats", userBookHandler.GetStats)
 		userBookRoutes.PUT("/:bookID/progress", userBookHandler.UpdateReadingProgress)
 		userBookRoutes.DELETE("/:bookID", userBookHandler.RemoveBookFromUser)
+		userBookRoutes.GET("/:bookID/history", userBookHandler.GetReadingHistory)
+		userBookRoutes.PUT("/:bookID/history/:readThroughID", userBookHandler.UpdateReadThrough)
+		userBookRoutes.POST("/:bookID/reread", userBookHandler.StartReread)
 	}
 }
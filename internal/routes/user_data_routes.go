@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"book-management-system/internal/constants"
+	"book-management-system/internal/handlers"
+	"book-management-system/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterUserDataRoutes регистрирует роуты выгрузки и удаления персональных данных
+func RegisterUserDataRoutes(r *gin.RouterGroup, userDataHandler *handlers.UserDataHandler) {
+	r.POST("/users/me/data-export", middleware.AuthMiddleware(), userDataHandler.ExportMyData)
+	r.DELETE("/users/me", middleware.AuthMiddleware(), userDataHandler.DeleteMe)
+
+	adminRoutes := r.Group("/admin/users")
+	adminRoutes.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware(constants.Roles.Admin))
+	{
+		adminRoutes.POST("/:userID/data-export", userDataHandler.AdminExportUserData)
+		adminRoutes.DELETE("/:userID", userDataHandler.AdminDeleteUser)
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"book-management-system/internal/constants"
+	"book-management-system/internal/handlers"
+	"book-management-system/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterWebhookRoutes регистрирует админские роуты вебхуков, подписывает их на шину событий и запускает доставку
+func RegisterWebhookRoutes(r *gin.RouterGroup, webhookHandler *handlers.WebhookHandler) {
+	webhookRoutes := r.Group("/admin/webhooks")
+	webhookRoutes.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware(constants.Roles.Admin))
+	{
+		webhookRoutes.POST("/", webhookHandler.CreateWebhook)
+		webhookRoutes.GET("/", webhookHandler.GetWebhooks)
+		webhookRoutes.GET("/:webhookID", webhookHandler.GetWebhook)
+		webhookRoutes.PUT("/:webhookID", webhookHandler.UpdateWebhook)
+		webhookRoutes.DELETE("/:webhookID", webhookHandler.DeleteWebhook)
+		webhookRoutes.GET("/:webhookID/deliveries", webhookHandler.GetWebhookDeliveries)
+		webhookRoutes.POST("/:webhookID/test", webhookHandler.TestWebhook)
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ActivityService записывает события для лент подписчиков и собирает ленту
+type ActivityService struct {
+	repo           *repositories.ActivityEventRepository
+	bookAuthorRepo *repositories.BookAuthorRepository
+	log            *logger.Logger
+}
+
+// NewActivityService создает сервис ленты активности
+func NewActivityService(repo *repositories.ActivityEventRepository, bookAuthorRepo *repositories.BookAuthorRepository) *ActivityService {
+	return &ActivityService{
+		repo:           repo,
+		bookAuthorRepo: bookAuthorRepo,
+		log:            logger.GetLogger(),
+	}
+}
+
+// RecordUserEvent записывает действие пользователя. Лента вторична, поэтому ошибка
+// только логируется и не мешает основному действию
+func (s *ActivityService) RecordUserEvent(userID uuid.UUID, eventType models.ActivityEventType, bookID uuid.UUID, reviewID string) {
+	err := s.repo.CreateEvents([]models.ActivityEvent{{
+		ID:        uuid.New(),
+		ActorType: models.FollowTargetUser,
+		ActorID:   userID,
+		Type:      eventType,
+		BookID:    bookID,
+		ReviewID:  reviewID,
+	}})
+	if err != nil {
+		s.log.Warnf("Ошибка записи события %s пользователя %s: %v", eventType, userID, err)
+	}
+}
+
+// RecordAuthorNewBook записывает новую подтвержденную книгу в ленту каждого ее автора
+func (s *ActivityService) RecordAuthorNewBook(bookID uuid.UUID) {
+	authorIDs, err := s.bookAuthorRepo.GetAuthorIDsByBookID(bookID)
+	if err != nil {
+		s.log.Warnf("Ошибка получения авторов книги %s для ленты: %v", bookID, err)
+		return
+	}
+
+	events := make([]models.ActivityEvent, len(authorIDs))
+	for i, authorID := range authorIDs {
+		events[i] = models.ActivityEvent{
+			ID:        uuid.New(),
+			ActorType: models.FollowTargetAuthor,
+			ActorID:   authorID,
+			Type:      models.ActivityAuthorNewBook,
+			BookID:    bookID,
+		}
+	}
+
+	if err := s.repo.CreateEvents(events); err != nil {
+		s.log.Warnf("Ошибка записи новой книги %s в ленту авторов: %v", bookID, err)
+	}
+}
+
+// GetFeed получает ленту пользователя по его подпискам
+func (s *ActivityService) GetFeed(userID uuid.UUID, limit int, afterID *uuid.UUID) (*dto.FeedResponse, error) {
+	events, err := s.repo.GetFeed(userID, limit, afterID)
+	if err != nil {
+		s.log.Warnf("Ошибка получения ленты: %v", err)
+		return nil, err
+	}
+
+	if len(events) == 0 {
+		return &dto.FeedResponse{Events: []dto.FeedEventResponse{}, NextCursor: nil}, nil
+	}
+
+	responses := make([]dto.FeedEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = dto.FeedEventResponse{
+			ID:        event.ID,
+			Type:      string(event.Type),
+			ActorType: string(event.ActorType),
+			ActorID:   event.ActorID,
+			ActorName: event.ActorName,
+			BookID:    event.BookID,
+			BookTitle: event.BookTitle,
+			ReviewID:  event.ReviewID,
+			CreatedAt: event.CreatedAt,
+		}
+	}
+
+	nextAfterID := &events[len(events)-1].ID
+
+	return &dto.FeedResponse{
+		Events:     responses,
+		NextCursor: nextAfterID,
+	}, nil
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/pkg/utils"
+	"github.com/google/uuid"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// shareDescriptionLength сколько символов описания попадает в og:description; соцсети все равно обрезают длиннее
+const shareDescriptionLength = 200
+
+// BookSharePage данные страницы книги для ссылок в соцсетях: теги Open Graph и Twitter и разметка JSON-LD
+type BookSharePage struct {
+	URL         string
+	Title       string // название и авторы, как в заголовке страницы
+	BookTitle   string
+	Authors     string
+	Description string // описание, обрезанное до shareDescriptionLength
+	Image       string // абсолютный адрес обложки; пусто, если обложки нет
+	SiteName    string
+	UpdatedAt   time.Time
+	JSONLD      *dto.BookJSONLD
+}
+
+// BookMetadataService собирает метаданные подтвержденной книги для поисковиков и соцсетей
+type BookMetadataService struct {
+	bookService *BookService
+}
+
+// NewBookMetadataService создает сервис метаданных книги
+func NewBookMetadataService(bookService *BookService) *BookMetadataService {
+	return &BookMetadataService{bookService: bookService}
+}
+
+// BookJSONLD возвращает разметку schema.org Book и время последнего изменения книги. site — абсолютный адрес сайта
+func (s *BookMetadataService) BookJSONLD(site string, bookID uuid.UUID) (*dto.BookJSONLD, time.Time, error) {
+	book, err := s.bookService.GetConfirmedBookByIdWithAuthors(bookID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return bookJSONLD(site, book), book.UpdatedAt, nil
+}
+
+// SharePage возвращает данные страницы книги для соцсетей
+func (s *BookMetadataService) SharePage(site string, bookID uuid.UUID) (*BookSharePage, error) {
+	book, err := s.bookService.GetConfirmedBookByIdWithAuthors(bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonld := bookJSONLD(site, book)
+	authors := make([]string, len(book.Authors))
+	for i, author := range book.Authors {
+		authors[i] = author.Name
+	}
+
+	page := &BookSharePage{
+		URL:         jsonld.URL,
+		Title:       book.Title,
+		BookTitle:   book.Title,
+		Authors:     strings.Join(authors, ", "),
+		Description: truncateText(strings.Join(strings.Fields(book.Description), " "), shareDescriptionLength),
+		Image:       jsonld.Image,
+		SiteName:    siteName,
+		UpdatedAt:   book.UpdatedAt,
+		JSONLD:      jsonld,
+	}
+	if page.Authors != "" {
+		page.Title += " — " + page.Authors
+	}
+	return page, nil
+}
+
+// bookJSONLD строит разметку schema.org Book; адрес книги — страница /share/books/{bookID}
+func bookJSONLD(site string, book *dto.BookResponse) *dto.BookJSONLD {
+	pageURL := site + "/share/books/" + book.ID.String()
+	jsonld := &dto.BookJSONLD{
+		Context:       "https://schema.org",
+		Type:          "Book",
+		ID:            pageURL,
+		URL:           pageURL,
+		Name:          book.Title,
+		Description:   book.Description,
+		ISBN:          book.ISBN,
+		NumberOfPages: book.PageCount,
+	}
+	if book.CoverImage != "" {
+		jsonld.Image = utils.AbsoluteURL(site, book.CoverImage)
+	}
+	if book.PublishedYear > 0 {
+		jsonld.DatePublished = strconv.Itoa(book.PublishedYear)
+	}
+	if book.Publisher != "" {
+		jsonld.Publisher = &dto.SchemaOrganization{Type: "Organization", Name: book.Publisher}
+	}
+	for _, author := range book.Authors {
+		jsonld.Author = append(jsonld.Author, dto.SchemaPerson{Type: "Person", Name: author.Name})
+	}
+	// Рейтинг без отзывов поисковики считают ошибкой разметки, поэтому его не выводим
+	if book.RatingsCount > 0 {
+		jsonld.AggregateRating = &dto.SchemaAggregateRating{
+			Type:        "AggregateRating",
+			RatingValue: math.Round(book.AverageRating*10) / 10,
+			BestRating:  10,
+			WorstRating: 1,
+			RatingCount: book.RatingsCount,
+		}
+	}
+	return jsonld
+}
+
+// truncateText обрезает текст до limit символов по границе слова и добавляет многоточие
+func truncateText(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	runes := []rune(text)[:limit]
+	truncated := string(runes)
+	if cut := strings.LastIndex(truncated, " "); cut > 0 {
+		truncated = truncated[:cut]
+	}
+	return strings.TrimRight(truncated, " ,.;:—-") + "…"
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		text  string
+		limit int
+		want  string
+	}{
+		{"", 10, ""},
+		{"Короткое описание", 17, "Короткое описание"},
+		{"Короткое описание", 100, "Короткое описание"},
+		{"Роман о войне и мире", 12, "Роман о…"},
+		{"Роман о войне, мире и людях", 15, "Роман о войне…"},
+		{"Роман о войне — и мире", 16, "Роман о войне…"},
+		{"Сверхдлинноеслово", 5, "Сверх…"},
+		{"Первая фраза. Вторая фраза", 14, "Первая фраза…"},
+	}
+
+	for _, tt := range tests {
+		got := truncateText(tt.text, tt.limit)
+		if got != tt.want {
+			t.Errorf("truncateText(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+		}
+		if utf8.RuneCountInString(strings.TrimSuffix(got, "…")) > tt.limit {
+			t.Errorf("truncateText(%q, %d) длиннее лимита: %q", tt.text, tt.limit, got)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("truncateText(%q, %d) разрезал символ: %q", tt.text, tt.limit, got)
+		}
+	}
+}
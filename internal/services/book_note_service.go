@@ -0,0 +1,205 @@
+package services
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+)
+
+var (
+	ErrInvalidNoteKind       = errors.New("недопустимый тип заметки")
+	ErrInvalidNoteVisibility = errors.New("недопустимая видимость заметки")
+	ErrInvalidNotePage       = errors.New("номер страницы не может быть отрицательным")
+	ErrEmptyNoteText         = errors.New("текст заметки пуст")
+)
+
+type BookNoteService struct {
+	noteRepo     *repositories.BookNoteRepository
+	userBookRepo *repositories.UserBookRepository
+	log          *logger.Logger
+}
+
+// NewBookNoteService создает сервис заметок к книгам
+func NewBookNoteService(noteRepo *repositories.BookNoteRepository, userBookRepo *repositories.UserBookRepository) *BookNoteService {
+	return &BookNoteService{
+		noteRepo:     noteRepo,
+		userBookRepo: userBookRepo,
+		log:          logger.GetLogger(),
+	}
+}
+
+// CreateNote создает заметку к книге из списка пользователя
+func (s *BookNoteService) CreateNote(userID, bookID uuid.UUID, req dto.BookNoteRequest) (*dto.BookNoteResponse, error) {
+	// Заметки живут только внутри списка пользователя
+	if _, err := s.userBookRepo.GetUserBook(userID, bookID); err != nil {
+		s.log.Warnf("Книга %s отсутствует в списке пользователя %s: %v", bookID, userID, err)
+		return nil, err
+	}
+
+	note := &models.BookNote{
+		ID:     uuid.New(),
+		UserID: userID,
+		BookID: bookID,
+	}
+
+	if err := applyNoteRequest(note, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.noteRepo.CreateNote(note); err != nil {
+		s.log.Warnf("Ошибка создания заметки: %v", err)
+		return nil, err
+	}
+
+	return toBookNoteResponse(note), nil
+}
+
+// GetNotes получает заметки пользователя к книге
+func (s *BookNoteService) GetNotes(userID, bookID uuid.UUID) ([]dto.BookNoteResponse, error) {
+	notes, err := s.noteRepo.GetNotesByUserBook(userID, bookID)
+	if err != nil {
+		s.log.Warnf("Ошибка получения заметок: %v", err)
+		return nil, err
+	}
+
+	responses := make([]dto.BookNoteResponse, len(notes))
+	for i := range notes {
+		responses[i] = *toBookNoteResponse(&notes[i])
+	}
+
+	return responses, nil
+}
+
+// UpdateNote обновляет заметку пользователя
+func (s *BookNoteService) UpdateNote(userID, bookID, noteID uuid.UUID, req dto.BookNoteRequest) (*dto.BookNoteResponse, error) {
+	note, err := s.noteRepo.GetUserNote(userID, bookID, noteID)
+	if err != nil {
+		s.log.Warnf("Ошибка получения заметки перед обновлением: %v", err)
+		return nil, err
+	}
+
+	if err := applyNoteRequest(note, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.noteRepo.UpdateNote(note); err != nil {
+		s.log.Warnf("Ошибка обновления заметки: %v", err)
+		return nil, err
+	}
+
+	return toBookNoteResponse(note), nil
+}
+
+// DeleteNote удаляет заметку пользователя
+func (s *BookNoteService) DeleteNote(userID, bookID, noteID uuid.UUID) error {
+	if _, err := s.noteRepo.GetUserNote(userID, bookID, noteID); err != nil {
+		s.log.Warnf("Ошибка получения заметки перед удалением: %v", err)
+		return err
+	}
+
+	if err := s.noteRepo.DeleteNote(noteID); err != nil {
+		s.log.Warnf("Ошибка удаления заметки: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetPublicQuotes получает публичные цитаты книги с голосами
+func (s *BookNoteService) GetPublicQuotes(bookID uuid.UUID, limit int, afterID *uuid.UUID) (*dto.PaginatedQuotesResponse, error) {
+	quotes, err := s.noteRepo.GetPublicQuotesPaginated(bookID, limit, afterID)
+	if err != nil {
+		s.log.Warnf("Ошибка получения цитат книги: %v", err)
+		return nil, err
+	}
+
+	if len(quotes) == 0 {
+		return &dto.PaginatedQuotesResponse{Quotes: []dto.QuoteResponse{}, NextCursor: nil}, nil
+	}
+
+	responses := make([]dto.QuoteResponse, len(quotes))
+	for i, quote := range quotes {
+		responses[i] = dto.QuoteResponse{
+			ID:        quote.ID,
+			BookID:    quote.BookID,
+			UserID:    quote.UserID,
+			Text:      quote.Text,
+			Page:      quote.Page,
+			Location:  quote.Location,
+			Likes:     quote.Likes,
+			Dislikes:  quote.Dislikes,
+			CreatedAt: quote.CreatedAt,
+		}
+	}
+
+	nextAfterID := &quotes[len(quotes)-1].ID
+
+	return &dto.PaginatedQuotesResponse{
+		Quotes:     responses,
+		NextCursor: nextAfterID,
+	}, nil
+}
+
+// VoteQuote голосует за публичную цитату
+func (s *BookNoteService) VoteQuote(bookID, noteID, userID uuid.UUID, vote int) error {
+	if _, err := s.noteRepo.GetPublicQuote(bookID, noteID); err != nil {
+		s.log.Warnf("Ошибка получения цитаты перед голосованием: %v", err)
+		return err
+	}
+
+	return s.noteRepo.VoteNote(noteID, userID, vote)
+}
+
+// applyNoteRequest валидирует запрос и переносит поля в заметку
+func applyNoteRequest(note *models.BookNote, req dto.BookNoteRequest) error {
+	kind := models.NoteKind(req.Kind)
+	switch kind {
+	case models.NoteKindNote, models.NoteKindHighlight, models.NoteKindQuote:
+	default:
+		return ErrInvalidNoteKind
+	}
+
+	visibility := models.NoteVisibility(req.Visibility)
+	switch visibility {
+	case "":
+		visibility = models.NoteVisibilityPrivate
+	case models.NoteVisibilityPrivate, models.NoteVisibilityPublic:
+	default:
+		return ErrInvalidNoteVisibility
+	}
+
+	if req.Page != nil && *req.Page < 0 {
+		return ErrInvalidNotePage
+	}
+
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return ErrEmptyNoteText
+	}
+
+	note.Kind = kind
+	note.Text = text
+	note.Page = req.Page
+	note.Location = req.Location
+	note.Visibility = visibility
+
+	return nil
+}
+
+func toBookNoteResponse(note *models.BookNote) *dto.BookNoteResponse {
+	return &dto.BookNoteResponse{
+		ID:         note.ID,
+		BookID:     note.BookID,
+		Kind:       string(note.Kind),
+		Text:       note.Text,
+		Page:       note.Page,
+		Location:   note.Location,
+		Visibility: string(note.Visibility),
+		CreatedAt:  note.CreatedAt,
+		UpdatedAt:  note.UpdatedAt,
+	}
+}
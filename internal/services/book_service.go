@@ -2,16 +2,25 @@ package services
 
 import (
 	"book-management-system/internal/dto"
+	"book-management-system/internal/events"
 	"book-management-system/internal/models"
 	"book-management-system/internal/repositories"
 	"book-management-system/pkg/logger"
+	"book-management-system/pkg/utils"
+	"errors"
 	"github.com/google/uuid"
+	"time"
 )
 
+var ErrInvalidPageCount = errors.New("количество страниц не может быть отрицательным")
+
 type BookService struct {
 	authorRepository            *repositories.AuthorRepository
 	bookRepository              *repositories.BookRepository
 	bookAuthorMappingRepository *repositories.BookAuthorRepository
+	reputationService           *ReputationService
+	activityService             *ActivityService
+	bus                         *events.Bus
 	log                         *logger.Logger
 }
 
@@ -20,23 +29,49 @@ func NewBookService(
 	bookRepository *repositories.BookRepository,
 	bookAuthorMappingRepository *repositories.BookAuthorRepository,
 	authorRepository *repositories.AuthorRepository,
+	reputationService *ReputationService,
+	activityService *ActivityService,
 ) *BookService {
 	return &BookService{
 		authorRepository:            authorRepository,
 		bookRepository:              bookRepository,
 		bookAuthorMappingRepository: bookAuthorMappingRepository,
+		reputationService:           reputationService,
+		activityService:             activityService,
+		bus:                         events.GetBus(),
 		log:                         logger.GetLogger(),
 	}
 }
 
-// CreateBook создает новую книгу и связывает с авторами
-func (s *BookService) CreateBook(title, description, coverImage string, authorIDs []uuid.UUID, userRole string) (*models.Book, error) {
+// CreateBook создает новую книгу и связывает с авторами.
+// Книги модераторов, админов и доверенных пользователей публикуются сразу, остальные ждут подтверждения
+func (s *BookService) CreateBook(title, description, coverImage string, pageCount int, authorIDs []uuid.UUID, userRole string, creatorID uuid.UUID) (*models.Book, error) {
+	if pageCount < 0 {
+		return nil, ErrInvalidPageCount
+	}
+
+	confirmed := userRole == "moderator" || userRole == "admin" // Если создает модератор или админ, сразу подтверждаем
+	if !confirmed {
+		trusted, err := s.reputationService.IsTrusted(creatorID)
+		if err != nil {
+			s.log.Warnf("Ошибка проверки репутации пользователя %s: %v", creatorID, err)
+			return nil, err
+		}
+		confirmed = trusted
+	}
+
 	book := &models.Book{
 		ID:          uuid.New(),
 		Title:       title,
 		Description: description,
 		CoverImage:  coverImage,
-		Confirmed:   userRole == "moderator" || userRole == "admin", // Если создает модератор или админ, сразу подтверждаем
+		PageCount:   pageCount,
+		Confirmed:   confirmed,
+		CreatedBy:   &creatorID,
+	}
+	if confirmed {
+		now := time.Now().UTC()
+		book.ConfirmedAt = &now
 	}
 
 	err := s.bookRepository.CreateBook(book, authorIDs)
@@ -45,23 +80,56 @@ func (s *BookService) CreateBook(title, description, coverImage string, authorID
 		return nil, err
 	}
 
+	// Репутацию за автоматически подтвержденную книгу не начисляем: иначе доверенный пользователь
+	// набирал бы ее, просто добавляя книги. Бонус дает только подтверждение другим модератором
+	s.announceNewBook(book, creatorID)
+
 	return book, nil
 }
 
-func (s *BookService) ConfirmBook(bookID uuid.UUID) error {
+// announceNewBook публикует события о созданной книге, а подтвержденную добавляет в ленты подписчиков ее авторов
+func (s *BookService) announceNewBook(book *models.Book, creatorID uuid.UUID) {
+	event := events.Event{Type: events.BookCreated, UserID: creatorID, ActorID: creatorID, BookID: &book.ID, BookTitle: book.Title}
+	s.bus.Publish(event)
+
+	if book.Confirmed {
+		s.activityService.RecordAuthorNewBook(book.ID)
+
+		event.Type = events.BookConfirmed
+		s.bus.Publish(event)
+	}
+}
+
+// ConfirmBook подтверждает книгу и начисляет репутацию пользователю, который ее добавил, если подтверждает
+// не он сам. moderatorID — uuid.Nil, если книгу подтверждает администратор из командной строки
+func (s *BookService) ConfirmBook(bookID uuid.UUID, moderatorID uuid.UUID) error {
 	book, err := s.bookRepository.GetBookByID(bookID, false)
 	if err != nil {
 		s.log.Warnf("Ошибка получения книги перед подтверждением: %v", err)
 		return err
 	}
 
-	book.Confirmed = true
-
-	err = s.bookRepository.UpdateBook(book, nil)
+	confirmed, err := s.bookRepository.ConfirmBook(bookID)
 	if err != nil {
 		s.log.Warnf("Ошибка подтверждения книги: %v", err)
 		return err
 	}
+
+	if !confirmed {
+		return nil
+	}
+
+	event := events.Event{Type: events.BookConfirmed, BookID: &bookID, BookTitle: book.Title}
+	if book.CreatedBy != nil {
+		if *book.CreatedBy != moderatorID {
+			if err := s.reputationService.OnBookConfirmed(*book.CreatedBy); err != nil {
+				s.log.Warnf("Ошибка начисления репутации за книгу %s: %v", bookID, err)
+			}
+		}
+		event.UserID = *book.CreatedBy
+	}
+	s.activityService.RecordAuthorNewBook(bookID)
+	s.bus.Publish(event)
 	return nil
 }
 
@@ -76,7 +144,11 @@ func (s *BookService) GetBookByID(bookID uuid.UUID) (*models.Book, error) {
 }
 
 // UpdateBook обновляет данные книги и связи с авторами
-func (s *BookService) UpdateBook(bookID uuid.UUID, title, description, coverImage string, authorIDs []uuid.UUID) error {
+func (s *BookService) UpdateBook(bookID uuid.UUID, title, description, coverImage string, pageCount int, authorIDs []uuid.UUID) error {
+	if pageCount < 0 {
+		return ErrInvalidPageCount
+	}
+
 	book, err := s.bookRepository.GetBookByID(bookID, false)
 	if err != nil {
 		s.log.Warnf("Ошибка получения книги перед обновлением: %v", err)
@@ -86,12 +158,15 @@ func (s *BookService) UpdateBook(bookID uuid.UUID, title, description, coverImag
 	book.Title = title
 	book.Description = description
 	book.CoverImage = coverImage
+	book.PageCount = pageCount
 
 	err = s.bookRepository.UpdateBook(book, authorIDs)
 	if err != nil {
 		s.log.Warnf("Ошибка обновления книги: %v", err)
 		return err
 	}
+
+	s.bus.Publish(events.Event{Type: events.BookUpdated, BookID: &bookID, BookTitle: book.Title})
 	return nil
 }
 
@@ -102,6 +177,8 @@ func (s *BookService) DeleteBook(bookID uuid.UUID) error {
 		s.log.Warnf("Ошибка удаления книги: %v", err)
 		return err
 	}
+
+	s.bus.Publish(events.Event{Type: events.BookDeleted, BookID: &bookID})
 	return nil
 }
 
@@ -117,6 +194,95 @@ func (s *BookService) GetBooksPaginated(limit int, afterID *uuid.UUID) (*dto.Pag
 		return &dto.PaginatedBooksResponse{Books: []dto.BookResponse{}, NextCursor: nil}, nil
 	}
 
+	bookResponses, err := s.toBookResponsesWithAuthors(books)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextAfterID *uuid.UUID
+	if len(books) > 0 {
+		nextAfterID = &books[len(books)-1].ID
+	}
+
+	return &dto.PaginatedBooksResponse{
+		Books:      bookResponses,
+		NextCursor: nextAfterID,
+	}, nil
+
+}
+
+// GetTopRatedBooks получает подтвержденные книги с лучшим байесовским рейтингом
+func (s *BookService) GetTopRatedBooks(limit int) (*dto.TopRatedBooksResponse, error) {
+	books, err := s.bookRepository.GetTopRatedBooks(limit)
+	if err != nil {
+		s.log.Warnf("Ошибка получения книг с лучшим рейтингом: %v", err)
+		return nil, err
+	}
+
+	if len(books) == 0 {
+		return &dto.TopRatedBooksResponse{Books: []dto.BookResponse{}}, nil
+	}
+
+	bookResponses, err := s.toBookResponsesWithAuthors(books)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TopRatedBooksResponse{Books: bookResponses}, nil
+}
+
+// GetNewestBooks получает подтвержденные книги от новых к старым с маркерной пагинацией,
+// при заданном genreID — только книги этого жанра
+func (s *BookService) GetNewestBooks(limit int, afterID *uuid.UUID, genreID *uuid.UUID) (*dto.PaginatedBooksResponse, error) {
+	books, err := s.bookRepository.GetNewestBooksPaginated(limit, afterID, genreID)
+	if err != nil {
+		s.log.Warnf("Ошибка получения новых книг: %v", err)
+		return nil, err
+	}
+
+	if len(books) == 0 {
+		return &dto.PaginatedBooksResponse{Books: []dto.BookResponse{}, NextCursor: nil}, nil
+	}
+
+	bookResponses, err := s.toBookResponsesWithAuthors(books)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PaginatedBooksResponse{
+		Books:      bookResponses,
+		NextCursor: &books[len(books)-1].ID,
+	}, nil
+}
+
+// GetBooksByAuthor получает подтвержденные книги автора по названию
+func (s *BookService) GetBooksByAuthor(authorID uuid.UUID) ([]dto.BookResponse, error) {
+	books, err := s.bookRepository.GetConfirmedBooksByAuthor(authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(books) == 0 {
+		return []dto.BookResponse{}, nil
+	}
+	return s.toBookResponsesWithAuthors(books)
+}
+
+// SearchBooks ищет подтвержденные книги по части названия или имени автора
+func (s *BookService) SearchBooks(query string, limit, offset int) ([]dto.BookResponse, error) {
+	books, err := s.bookRepository.SearchConfirmedBooks(query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(books) == 0 {
+		return []dto.BookResponse{}, nil
+	}
+	return s.toBookResponsesWithAuthors(books)
+}
+
+// toBookResponsesWithAuthors собирает ответы API для книг, подгружая авторов одним запросом
+func (s *BookService) toBookResponsesWithAuthors(books []models.Book) ([]dto.BookResponse, error) {
 	bookIDs := make([]uuid.UUID, len(books))
 	for i, book := range books {
 		bookIDs[i] = book.ID
@@ -160,24 +326,25 @@ func (s *BookService) GetBooksPaginated(limit int, afterID *uuid.UUID) (*dto.Pag
 	bookResponses := make([]dto.BookResponse, len(books))
 	for i, book := range books {
 		bookResponses[i] = dto.BookResponse{
-			ID:          book.ID,
-			Title:       book.Title,
-			Description: book.Description,
-			CoverImage:  book.CoverImage,
-			Authors:     bookAuthorMap[book.ID], // Авторы привязываются из мапы
+			ID:             book.ID,
+			Title:          book.Title,
+			Description:    book.Description,
+			CoverImage:     book.CoverImage,
+			PageCount:      book.PageCount,
+			ISBN:           utils.DerefString(book.ISBN),
+			Publisher:      book.Publisher,
+			PublishedYear:  book.PublishedYear,
+			AverageRating:  book.AverageRating,
+			BayesianRating: book.BayesianRating,
+			RatingsCount:   book.RatingsCount,
+			Authors:        bookAuthorMap[book.ID], // Авторы привязываются из мапы
+			CreatedAt:      book.CreatedAt,
+			ConfirmedAt:    book.ConfirmedAt,
+			UpdatedAt:      book.UpdatedAt,
 		}
 	}
 
-	var nextAfterID *uuid.UUID
-	if len(books) > 0 {
-		nextAfterID = &books[len(books)-1].ID
-	}
-
-	return &dto.PaginatedBooksResponse{
-		Books:      bookResponses,
-		NextCursor: nextAfterID,
-	}, nil
-
+	return bookResponses, nil
 }
 
 // UpdateBookCover обновляет путь к обложке книги
@@ -214,11 +381,21 @@ func (s *BookService) GetConfirmedBookByIdWithAuthors(bookID uuid.UUID) (*dto.Bo
 	}
 
 	bookResponse := &dto.BookResponse{
-		ID:          book.ID,
-		Title:       book.Title,
-		Description: book.Description,
-		CoverImage:  book.CoverImage,
-		Authors:     authorResponses,
+		ID:             book.ID,
+		Title:          book.Title,
+		Description:    book.Description,
+		CoverImage:     book.CoverImage,
+		PageCount:      book.PageCount,
+		ISBN:           utils.DerefString(book.ISBN),
+		Publisher:      book.Publisher,
+		PublishedYear:  book.PublishedYear,
+		AverageRating:  book.AverageRating,
+		BayesianRating: book.BayesianRating,
+		RatingsCount:   book.RatingsCount,
+		Authors:        authorResponses,
+		CreatedAt:      book.CreatedAt,
+		ConfirmedAt:    book.ConfirmedAt,
+		UpdatedAt:      book.UpdatedAt,
 	}
 
 	return bookResponse, nil
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"book-management-system/internal/bibliography"
+	"book-management-system/internal/dto"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"book-management-system/pkg/utils"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var ErrEmptyCatalogTitle = errors.New("у книги нет названия")
+
+// catalogExportBatchSize сколько книг выгружается за один проход
+const catalogExportBatchSize = 200
+
+// CatalogService выгружает подтвержденный каталог и загружает его в другую базу
+type CatalogService struct {
+	bookRepo       *repositories.BookRepository
+	bookAuthorRepo *repositories.BookAuthorRepository
+	authorRepo     *repositories.AuthorRepository
+	bookService    *BookService
+	log            *logger.Logger
+}
+
+// NewCatalogService создает сервис выгрузки и загрузки каталога
+func NewCatalogService(
+	bookRepo *repositories.BookRepository,
+	bookAuthorRepo *repositories.BookAuthorRepository,
+	authorRepo *repositories.AuthorRepository,
+	bookService *BookService,
+) *CatalogService {
+	return &CatalogService{
+		bookRepo:       bookRepo,
+		bookAuthorRepo: bookAuthorRepo,
+		authorRepo:     authorRepo,
+		bookService:    bookService,
+		log:            logger.GetLogger(),
+	}
+}
+
+// ExportCatalog выгружает все подтвержденные книги с именами авторов
+func (s *CatalogService) ExportCatalog() ([]dto.CatalogBook, error) {
+	books, err := s.bookRepo.GetAllConfirmedBooks()
+	if err != nil {
+		return nil, err
+	}
+
+	authorNames, err := s.authorNamesByBook(books)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := make([]dto.CatalogBook, len(books))
+	for i, book := range books {
+		catalog[i] = dto.CatalogBook{
+			Title:       book.Title,
+			Description: book.Description,
+			CoverImage:  book.CoverImage,
+			PageCount:   book.PageCount,
+			Authors:     authorNames[book.ID],
+		}
+		if catalog[i].Authors == nil {
+			catalog[i].Authors = []string{}
+		}
+	}
+	return catalog, nil
+}
+
+// ExportBibliography пишет подтвержденный каталог в w библиографическими записями формата, пачками
+func (s *CatalogService) ExportBibliography(format bibliography.Format, w io.Writer) error {
+	writer := bibliography.NewWriter(format, w)
+
+	if err := writer.Begin(); err != nil {
+		return err
+	}
+
+	err := s.bookRepo.GetConfirmedBooksInBatches(catalogExportBatchSize, func(books []models.Book) error {
+		authorNames, err := s.authorNamesByBook(books)
+		if err != nil {
+			return err
+		}
+
+		for _, book := range books {
+			if err := writer.Write(bibliographyRecord(book, authorNames[book.ID])); err != nil {
+				return err
+			}
+		}
+
+		// Отдаем клиенту готовую пачку, не дожидаясь конца выгрузки
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Warnf("Ошибка выгрузки каталога в формате %s: %v", format, err)
+		return err
+	}
+
+	return writer.End()
+}
+
+// CiteBook формирует библиографическую ссылку на подтвержденную книгу
+func (s *CatalogService) CiteBook(bookID uuid.UUID, style bibliography.Style) (*dto.BookCitationResponse, error) {
+	book, err := s.bookRepo.GetBookByID(bookID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	authorNames, err := s.authorNamesByBook([]models.Book{*book})
+	if err != nil {
+		s.log.Warnf("Ошибка получения авторов книги %s для ссылки: %v", bookID, err)
+		return nil, err
+	}
+
+	return &dto.BookCitationResponse{
+		BookID:   book.ID,
+		Style:    string(style),
+		Citation: bibliography.Cite(bibliographyRecord(*book, authorNames[book.ID]), style),
+	}, nil
+}
+
+func bibliographyRecord(book models.Book, authors []string) bibliography.Record {
+	return bibliography.Record{
+		ID:          book.ID,
+		Title:       book.Title,
+		Authors:     authors,
+		ISBN:        utils.DerefString(book.ISBN),
+		Publisher:   book.Publisher,
+		Year:        book.PublishedYear,
+		PageCount:   book.PageCount,
+		Description: book.Description,
+		CreatedAt:   book.CreatedAt,
+	}
+}
+
+// ImportCatalog загружает книги от имени пользователя: подтверждение книг зависит от его роли, как при
+// создании через API. Авторы ищутся по имени и создаются, если их нет. Книга с тем же названием и теми же
+// авторами пропускается, поэтому повторная загрузка того же файла ничего не дублирует
+func (s *CatalogService) ImportCatalog(books []dto.CatalogBook, importer *models.User) *dto.CatalogImportResult {
+	result := &dto.CatalogImportResult{Failed: []dto.CatalogImportError{}}
+	authorIDs := make(map[string]uuid.UUID)
+
+	for i, entry := range books {
+		created, err := s.importBook(entry, importer, authorIDs, result)
+		switch {
+		case err != nil:
+			result.Failed = append(result.Failed, dto.CatalogImportError{Index: i, Title: entry.Title, Error: err.Error()})
+		case created:
+			result.Created++
+		default:
+			result.Skipped++
+		}
+	}
+
+	s.log.Infof("Загрузка каталога: создано %d, пропущено %d, ошибок %d", result.Created, result.Skipped, len(result.Failed))
+	return result
+}
+
+func (s *CatalogService) importBook(entry dto.CatalogBook, importer *models.User, authorIDs map[string]uuid.UUID, result *dto.CatalogImportResult) (bool, error) {
+	title := strings.TrimSpace(entry.Title)
+	if title == "" {
+		return false, ErrEmptyCatalogTitle
+	}
+
+	ids := make([]uuid.UUID, 0, len(entry.Authors))
+	for _, name := range entry.Authors {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		authorID, err := s.resolveAuthor(name, authorIDs, result)
+		if err != nil {
+			return false, err
+		}
+		if !slices.Contains(ids, authorID) {
+			ids = append(ids, authorID)
+		}
+	}
+
+	exists, err := s.bookExists(title, ids)
+	if err != nil || exists {
+		return false, err
+	}
+
+	_, err = s.bookService.CreateBook(title, entry.Description, entry.CoverImage, entry.PageCount, ids, importer.Role, importer.ID)
+	return err == nil, err
+}
+
+// resolveAuthor находит автора по имени или создает его; найденные ID кешируются на время загрузки
+func (s *CatalogService) resolveAuthor(name string, authorIDs map[string]uuid.UUID, result *dto.CatalogImportResult) (uuid.UUID, error) {
+	key := strings.ToLower(name)
+	if authorID, ok := authorIDs[key]; ok {
+		return authorID, nil
+	}
+
+	author, err := s.authorRepo.GetAuthorByName(name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		author = &models.Author{ID: uuid.New(), Name: name}
+		if err = s.authorRepo.CreateAuthor(author, nil); err == nil {
+			result.AuthorsCreated++
+		}
+	}
+	if err != nil {
+		s.log.Warnf("Ошибка поиска или создания автора %q: %v", name, err)
+		return uuid.Nil, err
+	}
+
+	authorIDs[key] = author.ID
+	return author.ID, nil
+}
+
+// bookExists проверяет, есть ли книга с таким названием и тем же набором авторов
+func (s *CatalogService) bookExists(title string, authorIDs []uuid.UUID) (bool, error) {
+	candidates, err := s.bookRepo.FindBooksByTitle(title)
+	if err != nil {
+		return false, err
+	}
+
+	for _, candidate := range candidates {
+		existing, err := s.bookAuthorRepo.GetAuthorIDsByBookID(candidate.ID)
+		if err != nil {
+			return false, err
+		}
+		if sameIDs(existing, authorIDs) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *CatalogService) authorNamesByBook(books []models.Book) (map[uuid.UUID][]string, error) {
+	bookIDs := make([]uuid.UUID, len(books))
+	for i, book := range books {
+		bookIDs[i] = book.ID
+	}
+
+	links, err := s.bookAuthorRepo.GetAuthorsForBooks(bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var authorIDs []uuid.UUID
+	for _, link := range links {
+		if link.AuthorID != nil {
+			authorIDs = append(authorIDs, *link.AuthorID)
+		}
+	}
+	authors, err := s.authorRepo.GetAuthorsByIDs(authorIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(authors))
+	for _, author := range authors {
+		names[author.ID] = author.Name
+	}
+
+	byBook := make(map[uuid.UUID][]string)
+	for _, link := range links {
+		if link.BookID == nil || link.AuthorID == nil {
+			continue
+		}
+		if name, ok := names[*link.AuthorID]; ok {
+			byBook[*link.BookID] = append(byBook[*link.BookID], name)
+		}
+	}
+	return byBook, nil
+}
+
+func sameIDs(a, b []uuid.UUID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, id := range a {
+		if !slices.Contains(b, id) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math"
+	"time"
+)
+
+const (
+	ConsistencyModeDryRun = "dry-run"
+	ConsistencyModeApply  = "apply"
+
+	consistencySampleLimit = 20
+	// ratingTolerance погрешность сравнения среднего из MongoDB с закешированным в PostgreSQL
+	ratingTolerance = 1e-6
+)
+
+// ConsistencyService ищет рассинхронизацию между PostgreSQL и MongoDB и при необходимости исправляет ее
+type ConsistencyService struct {
+	repo       *repositories.ConsistencyRepository
+	reviewRepo *repositories.ReviewRepository
+	outboxRepo *repositories.OutboxRepository
+	log        *logger.Logger
+}
+
+// NewConsistencyService создает сервис проверки согласованности
+func NewConsistencyService(
+	repo *repositories.ConsistencyRepository,
+	reviewRepo *repositories.ReviewRepository,
+	outboxRepo *repositories.OutboxRepository,
+) *ConsistencyService {
+	return &ConsistencyService{
+		repo:       repo,
+		reviewRepo: reviewRepo,
+		outboxRepo: outboxRepo,
+		log:        logger.GetLogger(),
+	}
+}
+
+// consistencyCheck одна проверка: находит расхождения и, если apply, исправляет их
+type consistencyCheck struct {
+	name        string
+	description string
+	run         func(result *dto.ConsistencyCheckResult, apply bool) error
+}
+
+// Check запускает все проверки. Ошибка одной проверки попадает в отчет и не останавливает остальные.
+// Порядок важен: удаление отзывов удаленных книг влияет на рейтинги и голоса
+func (s *ConsistencyService) Check(apply bool) *dto.ConsistencyReport {
+	checks := []consistencyCheck{
+		{"orphan_reviews", "Отзывы в MongoDB на несуществующие или удаленные книги; удаляются вместе с комментариями и голосами", s.checkOrphanReviews},
+		{"dangling_book_authors", "Связи book_authors с пустым book_id или author_id после удаления книг и авторов; удаляются", s.checkDanglingBookAuthors},
+		{"orphan_user_books", "Записи user_books с пустым book_id после удаления книг; удаляются", s.checkOrphanUserBooks},
+		{"stale_ratings", "Книги, у которых average_rating или ratings_count не совпадают с видимыми отзывами; пересчет ставится в outbox", s.checkStaleRatings},
+		{"orphan_votes", "Голоса в MongoDB за удаленные отзывы; удаляются", s.checkOrphanVotes},
+	}
+
+	report := &dto.ConsistencyReport{
+		Mode:      ConsistencyModeDryRun,
+		CheckedAt: time.Now().UTC(),
+		Checks:    make([]dto.ConsistencyCheckResult, 0, len(checks)),
+	}
+	if apply {
+		report.Mode = ConsistencyModeApply
+	}
+
+	for _, check := range checks {
+		result := dto.ConsistencyCheckResult{Name: check.name, Description: check.description}
+		if err := check.run(&result, apply); err != nil {
+			s.log.Warnf("Проверка согласованности %s не завершилась: %v", check.name, err)
+			result.Error = err.Error()
+		}
+		report.Found += result.Found
+		report.Repaired += result.Repaired
+		report.Checks = append(report.Checks, result)
+	}
+
+	s.log.Infof("Проверка согласованности (%s): найдено %d, исправлено %d", report.Mode, report.Found, report.Repaired)
+	return report
+}
+
+func (s *ConsistencyService) checkOrphanReviews(result *dto.ConsistencyCheckResult, apply bool) error {
+	stats, err := s.reviewRepo.GetReviewCountsByBook()
+	if err != nil {
+		return err
+	}
+
+	bookIDs := make([]uuid.UUID, 0, len(stats))
+	for _, stat := range stats {
+		if bookID, err := uuid.Parse(stat.BookID); err == nil {
+			bookIDs = append(bookIDs, bookID)
+		}
+	}
+	live, err := s.repo.GetLiveBookIDs(bookIDs)
+	if err != nil {
+		return err
+	}
+	liveSet := make(map[string]bool, len(live))
+	for _, bookID := range live {
+		liveSet[bookID.String()] = true
+	}
+
+	for _, stat := range stats {
+		if liveSet[stat.BookID] {
+			continue
+		}
+		result.Found += stat.Count
+		addSample(result, stat.BookID)
+
+		if apply {
+			if err := s.reviewRepo.DeleteReviewsByBookID(stat.BookID); err != nil {
+				return err
+			}
+			result.Repaired += stat.Count
+		}
+	}
+	return nil
+}
+
+func (s *ConsistencyService) checkDanglingBookAuthors(result *dto.ConsistencyCheckResult, apply bool) error {
+	count, err := s.repo.CountDanglingBookAuthors()
+	if err != nil {
+		return err
+	}
+	result.Found = int(count)
+
+	if apply && count > 0 {
+		deleted, err := s.repo.DeleteDanglingBookAuthors()
+		if err != nil {
+			return err
+		}
+		result.Repaired = int(deleted)
+	}
+	return nil
+}
+
+func (s *ConsistencyService) checkOrphanUserBooks(result *dto.ConsistencyCheckResult, apply bool) error {
+	users, err := s.repo.GetUsersWithOrphanBooks()
+	if err != nil {
+		return err
+	}
+	for userID, count := range users {
+		result.Found += count
+		addSample(result, userID.String())
+	}
+
+	if apply && result.Found > 0 {
+		deleted, err := s.repo.DeleteOrphanUserBooks()
+		if err != nil {
+			return err
+		}
+		result.Repaired = int(deleted)
+	}
+	return nil
+}
+
+// checkStaleRatings сравнивает только среднее и число оценок: байесовская оценка зависит от общего
+// среднего по всем отзывам и устаревает у всех книг при каждом новом отзыве, это не рассинхронизация
+func (s *ConsistencyService) checkStaleRatings(result *dto.ConsistencyCheckResult, apply bool) error {
+	stats, err := s.reviewRepo.GetVisibleRatingStatsByBook()
+	if err != nil {
+		return err
+	}
+	expected := make(map[string]repositories.BookReviewStats, len(stats))
+	for _, stat := range stats {
+		expected[stat.BookID] = stat
+	}
+
+	snapshots, err := s.repo.GetBookRatingSnapshots()
+	if err != nil {
+		return err
+	}
+
+	for _, snapshot := range snapshots {
+		stat := expected[snapshot.ID.String()]
+		if snapshot.RatingsCount == stat.Count && math.Abs(snapshot.AverageRating-stat.Average) <= ratingTolerance {
+			continue
+		}
+		result.Found++
+		addSample(result, snapshot.ID.String())
+
+		if apply {
+			if err := s.outboxRepo.Enqueue(models.OutboxRecalculateBookRating, snapshot.ID); err != nil {
+				return err
+			}
+			result.Repaired++
+		}
+	}
+	return nil
+}
+
+func (s *ConsistencyService) checkOrphanVotes(result *dto.ConsistencyCheckResult, apply bool) error {
+	orphans, err := s.reviewRepo.GetOrphanVotes()
+	if err != nil {
+		return err
+	}
+
+	reviewIDs := make([]primitive.ObjectID, len(orphans))
+	for i, orphan := range orphans {
+		reviewIDs[i] = orphan.ReviewID
+		result.Found += orphan.Count
+		addSample(result, orphan.ReviewID.Hex())
+	}
+
+	if apply && len(reviewIDs) > 0 {
+		deleted, err := s.reviewRepo.DeleteVotesByReviewIDs(reviewIDs)
+		if err != nil {
+			return err
+		}
+		result.Repaired = int(deleted)
+	}
+	return nil
+}
+
+func addSample(result *dto.ConsistencyCheckResult, id string) {
+	if len(result.Sample) < consistencySampleLimit {
+		result.Sample = append(result.Sample, id)
+	}
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/feeds"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"book-management-system/pkg/markup"
+	"fmt"
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+const (
+	feedSize     = 50 // записей в ленте; читалки лент хранят историю сами, поэтому лента без пагинации
+	feedIDPrefix = "urn:book-management-system:feeds:"
+)
+
+// FeedService собирает ленты Atom для читалок лент: новые подтвержденные книги, книги автора и отзывы на книгу
+type FeedService struct {
+	bookService *BookService
+	authorRepo  *repositories.AuthorRepository
+	reviewRepo  *repositories.ReviewRepository
+	userRepo    *repositories.UserRepository
+	log         *logger.Logger
+}
+
+// NewFeedService создает сервис лент Atom
+func NewFeedService(
+	bookService *BookService,
+	authorRepo *repositories.AuthorRepository,
+	reviewRepo *repositories.ReviewRepository,
+	userRepo *repositories.UserRepository,
+) *FeedService {
+	return &FeedService{
+		bookService: bookService,
+		authorRepo:  authorRepo,
+		reviewRepo:  reviewRepo,
+		userRepo:    userRepo,
+		log:         logger.GetLogger(),
+	}
+}
+
+// NewBooks лента последних подтвержденных книг. site — абсолютный адрес сайта для ссылок
+func (s *FeedService) NewBooks(site string) (*feeds.Feed, error) {
+	page, err := s.bookService.GetNewestBooks(feedSize, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := newAtomFeed(site, "books", "/feeds/books.atom", "Новые книги")
+	feed.Links = append(feed.Links, feeds.Link{Rel: "alternate", Href: site + "/api/v1/books/", Type: "application/json"})
+	for _, book := range page.Books {
+		feed.Entries = append(feed.Entries, bookFeedEntry(site, book))
+	}
+	// Пустой каталог: фиксированное время, чтобы ETag и Last-Modified не менялись от запроса к запросу
+	feed.Updated = feeds.LatestUpdate(feed.Entries, time.Unix(0, 0).UTC())
+	return feed, nil
+}
+
+// AuthorBooks лента подтвержденных книг автора от новых к старым
+func (s *FeedService) AuthorBooks(site string, authorID uuid.UUID) (*feeds.Feed, error) {
+	author, err := s.authorRepo.GetAuthorByID(authorID)
+	if err != nil {
+		return nil, err
+	}
+	books, err := s.bookService.GetBooksByAuthor(authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(books, func(a, b dto.BookResponse) int { return bookListedAt(b).Compare(bookListedAt(a)) })
+	books = books[:min(len(books), feedSize)]
+
+	feed := newAtomFeed(site, "authors:"+authorID.String(), "/feeds/authors/"+authorID.String()+".atom", "Новые книги автора: "+author.Name)
+	feed.Links = append(feed.Links, feeds.Link{Rel: "alternate", Href: site + "/api/v1/authors/" + authorID.String(), Type: "application/json"})
+	for _, book := range books {
+		feed.Entries = append(feed.Entries, bookFeedEntry(site, book))
+	}
+	feed.Updated = feeds.LatestUpdate(feed.Entries, author.UpdatedAt)
+	return feed, nil
+}
+
+// BookReviews лента видимых отзывов на подтвержденную книгу от новых к старым
+func (s *FeedService) BookReviews(site string, bookID uuid.UUID) (*feeds.Feed, error) {
+	book, err := s.bookService.GetConfirmedBookByIdWithAuthors(bookID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.reviewRepo.GetReviewsByBookID(bookID.String(), feedSize)
+	if err != nil {
+		return nil, err
+	}
+	usernames, err := s.reviewerNames(reviews)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := newAtomFeed(site, "books:"+bookID.String()+":reviews", "/feeds/books/"+bookID.String()+"/reviews.atom", "Отзывы на книгу «"+book.Title+"»")
+	feed.Links = append(feed.Links, feeds.Link{Rel: "related", Href: site + "/api/v1/books/" + bookID.String(), Type: "application/json", Title: book.Title})
+	for _, review := range reviews {
+		name := usernames[review.UserID]
+		feed.Entries = append(feed.Entries, feeds.Entry{
+			ID:        "urn:book-management-system:review:" + review.ID.Hex(),
+			Title:     fmt.Sprintf("%s: %d/10", name, review.Rating),
+			Updated:   review.UpdatedAt,
+			Published: review.CreatedAt,
+			Authors:   []feeds.Person{{Name: name}},
+			// Спойлеры в ленте не раскрываем: читалки лент не умеют их прятать
+			Content: markup.StripSpoilers(review.Text),
+			Links:   []feeds.Link{{Rel: "alternate", Href: site + "/api/v1/reviews/" + review.ID.Hex(), Type: "application/json"}},
+		})
+	}
+	feed.Updated = feeds.LatestUpdate(feed.Entries, book.UpdatedAt)
+	return feed, nil
+}
+
+// reviewerNames возвращает имена авторов отзывов: user_id → имя для ленты
+func (s *FeedService) reviewerNames(reviews []models.Review) (map[string]string, error) {
+	names := make(map[string]string, len(reviews))
+	var userIDs []uuid.UUID
+	for _, review := range reviews {
+		names[review.UserID] = "Читатель" // анонимизированные отзывы и пользователи, которых уже нет
+		if userID, err := uuid.Parse(review.UserID); err == nil {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return names, nil
+	}
+
+	users, err := s.userRepo.GetUsersByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if user.DeletedAt == nil {
+			names[user.ID.String()] = user.Username
+		}
+	}
+	return names, nil
+}
+
+// newAtomFeed создает ленту со ссылкой на себя; path — путь ленты на сайте
+func newAtomFeed(site, id, path, title string) *feeds.Feed {
+	return &feeds.Feed{
+		ID:     feedIDPrefix + id,
+		Title:  title,
+		Author: &feeds.Person{Name: siteName, URI: site},
+		Links:  []feeds.Link{{Rel: "self", Href: site + path, Type: feeds.AtomType}},
+	}
+}
+
+// bookListedAt когда книга появилась в каталоге: время подтверждения, а у книг без него — время добавления
+func bookListedAt(book dto.BookResponse) time.Time {
+	if book.ConfirmedAt != nil {
+		return *book.ConfirmedAt
+	}
+	return book.CreatedAt
+}
+
+// bookEntryUpdated время изменения записи о книге в ленте: подтверждение тоже меняет запись
+func bookEntryUpdated(book dto.BookResponse) time.Time {
+	if listedAt := bookListedAt(book); listedAt.After(book.UpdatedAt) {
+		return listedAt
+	}
+	return book.UpdatedAt
+}
+
+// bookFeedEntry запись ленты о книге
+func bookFeedEntry(site string, book dto.BookResponse) feeds.Entry {
+	entry := feeds.Entry{
+		ID:        "urn:uuid:" + book.ID.String(),
+		Title:     book.Title,
+		Updated:   bookEntryUpdated(book),
+		Published: bookListedAt(book),
+		Summary:   book.Description,
+		Publication: &feeds.Publication{
+			ISBN:      book.ISBN,
+			Publisher: book.Publisher,
+			Year:      book.PublishedYear,
+			PageCount: book.PageCount,
+		},
+		Links: []feeds.Link{{Rel: "alternate", Href: site + "/api/v1/books/" + book.ID.String(), Type: "application/json"}},
+	}
+	for _, author := range book.Authors {
+		entry.Authors = append(entry.Authors, feeds.Person{Name: author.Name, URI: site + "/feeds/authors/" + author.ID.String() + ".atom"})
+	}
+	return entry
+}
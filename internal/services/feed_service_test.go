@@ -0,0 +1,35 @@
+package services
+
+import (
+	"book-management-system/internal/dto"
+	"testing"
+	"time"
+)
+
+func TestBookEntryTimes(t *testing.T) {
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	edited := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	confirmed := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		book        dto.BookResponse
+		listedAt    time.Time
+		wantUpdated time.Time
+	}{
+		{"подтверждена после правки", dto.BookResponse{CreatedAt: created, UpdatedAt: edited, ConfirmedAt: &confirmed}, confirmed, confirmed},
+		{"правка после подтверждения", dto.BookResponse{CreatedAt: created, UpdatedAt: confirmed, ConfirmedAt: &edited}, edited, confirmed},
+		{"без времени подтверждения", dto.BookResponse{CreatedAt: created, UpdatedAt: edited}, created, edited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bookListedAt(tt.book); !got.Equal(tt.listedAt) {
+				t.Errorf("bookListedAt = %v, want %v", got, tt.listedAt)
+			}
+			if got := bookEntryUpdated(tt.book); !got.Equal(tt.wantUpdated) {
+				t.Errorf("bookEntryUpdated = %v, want %v", got, tt.wantUpdated)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"book-management-system/internal/dto"
+	"book-management-system/internal/moderation"
 	"book-management-system/internal/repositories"
 	"book-management-system/pkg/logger"
 	"book-management-system/pkg/utils"
@@ -11,18 +12,32 @@ import (
 
 type FeedbackService struct {
 	feedbackRepo *repositories.FeedbackRepository
+	filter       *moderation.Filter
 	log          *logger.Logger
 }
 
 func NewFeedbackService(feedbackRepo *repositories.FeedbackRepository) *FeedbackService {
 	return &FeedbackService{
 		feedbackRepo: feedbackRepo,
+		filter:       moderation.GetFilter(),
 		log:          logger.GetLogger(),
 	}
 }
 
+// CreateFeedback проверяет текст автоматическим фильтром и сохраняет отзыв о приложении
 func (s *FeedbackService) CreateFeedback(feedback dto.BaseFeedbackRequest, creator uuid.UUID) (primitive.ObjectID, error) {
-	createdId, err := s.feedbackRepo.CreateFeedback(feedback, creator)
+	verdict := s.filter.Check(feedback.Text)
+	if err := verdict.Err(); err != nil {
+		return primitive.NilObjectID, err
+	}
+	feedback.Text = verdict.Text
+
+	var autoFlags []string
+	if verdict.Flagged() {
+		autoFlags = verdict.CategoryNames()
+	}
+
+	createdId, err := s.feedbackRepo.CreateFeedback(feedback, creator, autoFlags)
 	if err != nil {
 		s.log.Warnf("Failed to create feedback %v", err)
 		return primitive.NilObjectID, err
@@ -53,6 +68,8 @@ func (s *FeedbackService) FindPaginatedFeedbacks(onlyUnchecked bool, limit int,
 			Text:      feedback.Text,
 			Rating:    feedback.Rating,
 			Checked:   feedback.Checked,
+			Flagged:   feedback.Flagged,
+			AutoFlags: feedback.AutoFlags,
 			CreatedAt: feedback.CreatedAt,
 			UpdatedAt: feedback.UpdatedAt,
 		}
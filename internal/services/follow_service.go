@@ -0,0 +1,86 @@
+package services
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrCannotFollowSelf = errors.New("нельзя подписаться на самого себя")
+
+// FollowService управляет подписками на пользователей и авторов
+type FollowService struct {
+	followRepo *repositories.FollowRepository
+	userRepo   *repositories.UserRepository
+	authorRepo *repositories.AuthorRepository
+	log        *logger.Logger
+}
+
+// NewFollowService создает сервис подписок
+func NewFollowService(
+	followRepo *repositories.FollowRepository,
+	userRepo *repositories.UserRepository,
+	authorRepo *repositories.AuthorRepository,
+) *FollowService {
+	return &FollowService{
+		followRepo: followRepo,
+		userRepo:   userRepo,
+		authorRepo: authorRepo,
+		log:        logger.GetLogger(),
+	}
+}
+
+// Follow подписывает пользователя на другого пользователя или автора.
+// Если цели нет, возвращает gorm.ErrRecordNotFound
+func (s *FollowService) Follow(followerID uuid.UUID, targetType models.FollowTargetType, targetID uuid.UUID) error {
+	switch targetType {
+	case models.FollowTargetUser:
+		if followerID == targetID {
+			return ErrCannotFollowSelf
+		}
+		if _, err := s.userRepo.GetUserByID(targetID); err != nil {
+			return err
+		}
+	case models.FollowTargetAuthor:
+		authors, err := s.authorRepo.GetAuthorsByIDs([]uuid.UUID{targetID})
+		if err != nil {
+			return err
+		}
+		if len(authors) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+	}
+
+	return s.followRepo.Follow(&models.Follow{
+		FollowerID: followerID,
+		TargetType: targetType,
+		TargetID:   targetID,
+	})
+}
+
+// Unfollow отменяет подписку
+func (s *FollowService) Unfollow(followerID uuid.UUID, targetType models.FollowTargetType, targetID uuid.UUID) error {
+	return s.followRepo.Unfollow(followerID, targetType, targetID)
+}
+
+// GetFollowing получает подписки пользователя
+func (s *FollowService) GetFollowing(followerID uuid.UUID) ([]dto.FollowResponse, error) {
+	follows, err := s.followRepo.GetFollowing(followerID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.FollowResponse, len(follows))
+	for i, follow := range follows {
+		responses[i] = dto.FollowResponse{
+			TargetType: string(follow.TargetType),
+			TargetID:   follow.TargetID,
+			CreatedAt:  follow.CreatedAt,
+		}
+	}
+	return responses, nil
+}
@@ -0,0 +1,559 @@
+package services
+
+import (
+	"book-management-system/internal/importer"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"book-management-system/pkg/utils"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	importBatchSize       = 200              // книг в одной транзакции; после каждой пачки сохраняется прогресс
+	importMaxStoredErrors = 1000             // сколько ошибок записей хранить в задаче; счетчик Failed считает все
+	importStaleAfter      = 10 * time.Minute // задача без прогресса дольше этого считается прерванной
+)
+
+var (
+	ErrEmptyImportFile      = errors.New("файл загрузки пуст")
+	ErrInvalidPublishedYear = errors.New("некорректный год издания")
+)
+
+// ImportService выполняет массовую загрузку книг из CSV, JSON Lines и ONIX в фоне.
+// Загрузка доступна только администраторам, поэтому книги сразу подтверждены
+type ImportService struct {
+	jobRepo        *repositories.ImportJobRepository
+	bookRepo       *repositories.BookRepository
+	bookAuthorRepo *repositories.BookAuthorRepository
+	authorRepo     *repositories.AuthorRepository
+	genreRepo      *repositories.GenreRepository
+	bookService    *BookService
+	log            *logger.Logger
+}
+
+// NewImportService создает сервис массовой загрузки книг
+func NewImportService(
+	jobRepo *repositories.ImportJobRepository,
+	bookRepo *repositories.BookRepository,
+	bookAuthorRepo *repositories.BookAuthorRepository,
+	authorRepo *repositories.AuthorRepository,
+	genreRepo *repositories.GenreRepository,
+	bookService *BookService,
+) *ImportService {
+	return &ImportService{
+		jobRepo:        jobRepo,
+		bookRepo:       bookRepo,
+		bookAuthorRepo: bookAuthorRepo,
+		authorRepo:     authorRepo,
+		genreRepo:      genreRepo,
+		bookService:    bookService,
+		log:            logger.GetLogger(),
+	}
+}
+
+// StartStaleJobSweeper сразу и затем каждые importStaleAfter/2 помечает прерванными задачи, которые
+// перестали двигаться, пока ctx не отменен. Файл хранится только в памяти экземпляра, который его принял,
+// поэтому задачу остановившегося экземпляра продолжить нельзя. Проверка периодическая: экземпляр может
+// упасть, пока остальные работают, а задача, которую еще обрабатывают, обновляется после каждой пачки
+func (s *ImportService) StartStaleJobSweeper(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(importStaleAfter / 2)
+		defer ticker.Stop()
+
+		for {
+			s.FailInterruptedJobs()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// FailInterruptedJobs помечает прерванными задачи без прогресса дольше importStaleAfter.
+// Если такую задачу все же кто-то обрабатывает, SaveProgress вернет ErrImportJobFinished и обработка остановится
+func (s *ImportService) FailInterruptedJobs() {
+	count, err := s.jobRepo.FailStaleJobs(time.Now().UTC().Add(-importStaleAfter), "загрузка прервана остановкой сервиса, отправьте файл повторно")
+	if err == nil && count > 0 {
+		s.log.Warnf("Помечено прерванными задач загрузки: %d", count)
+	}
+}
+
+// StartImport создает задачу загрузки и обрабатывает файл в фоне. Прогресс читается через GetJob
+func (s *ImportService) StartImport(data []byte, format importer.Format, fileName string, creatorID uuid.UUID) (*models.ImportJob, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, ErrEmptyImportFile
+	}
+
+	job := &models.ImportJob{
+		ID:        uuid.New(),
+		Format:    string(format),
+		FileName:  fileName,
+		Status:    models.ImportJobPending,
+		CreatedBy: creatorID,
+		Errors:    []models.ImportRecordError{},
+	}
+	if err := s.jobRepo.CreateJob(job); err != nil {
+		return nil, err
+	}
+
+	// Фоновая обработка меняет свою копию задачи, а вызывающий получает снимок на момент создания
+	running := *job
+	go s.run(&running, data)
+
+	s.log.Infof("Задача загрузки %s создана: формат %s, файл %q, %d байт", job.ID, format, fileName, len(data))
+	return job, nil
+}
+
+// GetJob возвращает задачу загрузки с прогрессом и ошибками записей
+func (s *ImportService) GetJob(jobID uuid.UUID) (*models.ImportJob, error) {
+	return s.jobRepo.GetJobByID(jobID)
+}
+
+// GetRecentJobs возвращает последние задачи загрузки
+func (s *ImportService) GetRecentJobs(limit int) ([]models.ImportJob, error) {
+	return s.jobRepo.GetRecentJobs(limit)
+}
+
+func (s *ImportService) run(job *models.ImportJob, data []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Errorf("Паника в задаче загрузки %s: %v", job.ID, r)
+			s.finish(job, fmt.Sprintf("внутренняя ошибка: %v", r))
+		}
+	}()
+
+	records, err := importer.Parse(importer.Format(job.Format), bytes.NewReader(data))
+	if err != nil {
+		s.log.Warnf("Не удалось разобрать файл задачи загрузки %s: %v", job.ID, err)
+		s.finish(job, err.Error())
+		return
+	}
+
+	job.Total = len(records)
+	started, err := s.jobRepo.MarkRunning(job)
+	if err != nil {
+		s.finish(job, "не удалось запустить обработку: "+err.Error())
+		return
+	}
+	if !started {
+		// Задачу уже завершили, например пометили прерванной: finish ее не перезапишет, но зафиксирует причину в логе
+		s.log.Warnf("Задача загрузки %s уже не ожидает обработки", job.ID)
+		s.finish(job, "задача уже не ожидала обработки")
+		return
+	}
+
+	batch := newImportBatchState(job)
+	for start := 0; start < len(records); start += importBatchSize {
+		end := min(start+importBatchSize, len(records))
+		s.processBatch(batch, records[start:end])
+
+		job.Processed = end
+		if err := s.jobRepo.SaveProgress(job); err != nil {
+			s.log.Warnf("Задача загрузки %s остановлена: %v", job.ID, err)
+			s.finish(job, "не удалось сохранить прогресс: "+err.Error())
+			return
+		}
+	}
+
+	s.finish(job, "")
+	s.log.Infof("Задача загрузки %s завершена: записей %d, создано %d, пропущено %d, ошибок %d, новых авторов %d",
+		job.ID, job.Total, job.Created, job.Skipped, job.Failed, job.AuthorsCreated)
+}
+
+// finish завершает задачу: с пустой причиной — успешно, иначе — с ошибкой всего файла.
+// Уже завершенную задачу SaveProgress не меняет
+func (s *ImportService) finish(job *models.ImportJob, reason string) {
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	job.Status = models.ImportJobCompleted
+	if reason != "" {
+		job.Status = models.ImportJobFailed
+		job.Error = reason
+	}
+	_ = s.jobRepo.SaveProgress(job)
+}
+
+// importBatchState состояние задачи между пачками: кеш авторов и жанров и ключи уже созданных книг,
+// чтобы дубли внутри одного файла тоже пропускались
+type importBatchState struct {
+	job       *models.ImportJob
+	authorIDs map[string]uuid.UUID // имя автора в нижнем регистре → ID автора, который есть в базе
+	genreIDs  map[string]uuid.UUID // название жанра в нижнем регистре → ID
+	created   importedKeys
+}
+
+func newImportBatchState(job *models.ImportJob) *importBatchState {
+	return &importBatchState{
+		job:       job,
+		authorIDs: make(map[string]uuid.UUID),
+		genreIDs:  make(map[string]uuid.UUID),
+		created:   newImportedKeys(),
+	}
+}
+
+// rememberAuthors учитывает авторов, созданных вместе с книгами
+func (b *importBatchState) rememberAuthors(authors []*models.Author) {
+	for _, author := range authors {
+		b.job.AuthorsCreated++
+		b.authorIDs[strings.ToLower(author.Name)] = author.ID
+	}
+}
+
+// importedKeys ключи книг из файла для поиска дублей среди них
+type importedKeys struct {
+	isbns  map[string]bool
+	titles map[string][]importedBookKey // нормализованное название → книги с таким названием
+}
+
+type importedBookKey struct {
+	authorIDs []uuid.UUID
+	hasISBN   bool
+}
+
+func newImportedKeys() importedKeys {
+	return importedKeys{isbns: make(map[string]bool), titles: make(map[string][]importedBookKey)}
+}
+
+func (k importedKeys) add(candidate *importCandidate) {
+	if candidate.book.ISBN != nil {
+		k.isbns[*candidate.book.ISBN] = true
+	}
+	key := candidate.book.NormalizedTitle
+	k.titles[key] = append(k.titles[key], importedBookKey{authorIDs: candidate.authorIDs, hasISBN: candidate.book.ISBN != nil})
+}
+
+// contains проверяет, есть ли среди ключей дубль книги — по тому же правилу, что и duplicateChecker
+func (k importedKeys) contains(candidate *importCandidate) bool {
+	isbn := candidate.book.ISBN
+	if isbn != nil && k.isbns[*isbn] {
+		return true
+	}
+	for _, key := range k.titles[candidate.book.NormalizedTitle] {
+		if (isbn == nil || !key.hasISBN) && sameIDs(key.authorIDs, candidate.authorIDs) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *importBatchState) fail(record importer.Record, err error) {
+	b.job.Failed++
+	if len(b.job.Errors) >= importMaxStoredErrors {
+		return
+	}
+
+	reference := record.Reference
+	if reference == "" {
+		reference = record.ISBN
+	}
+	b.job.Errors = append(b.job.Errors, models.ImportRecordError{
+		Record:    record.Number,
+		Reference: reference,
+		Title:     record.Title,
+		Error:     err.Error(),
+	})
+}
+
+// importCandidate запись, прошедшая проверку, вместе с книгой, которую из нее создадим
+type importCandidate struct {
+	record         importer.Record
+	book           *models.Book
+	authorIDs      []uuid.UUID
+	missingAuthors []string         // авторы, которых еще нет в базе; создаются, только если книга не дубль
+	newAuthors     []*models.Author // авторы из missingAuthors, создаются в одной транзакции с книгой
+	genreIDs       []uuid.UUID
+}
+
+// processBatch проверяет записи пачки, отбрасывает дубли и создает оставшиеся книги одной транзакцией
+func (s *ImportService) processBatch(state *importBatchState, records []importer.Record) {
+	candidates := make([]*importCandidate, 0, len(records))
+	for _, record := range records {
+		candidate, err := s.prepareCandidate(state, record)
+		if err != nil {
+			state.fail(record, err)
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	duplicate, err := s.duplicateChecker(candidates)
+	if err != nil {
+		for _, candidate := range candidates {
+			state.fail(candidate.record, err)
+		}
+		return
+	}
+
+	// Книги пачки сверяются и между собой: ключи попадают в state.created, только когда книгу удалось создать
+	planned := make(map[string]*models.Author)
+	pending := newImportedKeys()
+	toCreate := make([]*importCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if duplicate(candidate) {
+			state.job.Skipped++
+			continue
+		}
+		planAuthors(planned, candidate)
+		if state.created.contains(candidate) || pending.contains(candidate) {
+			state.job.Skipped++
+			continue
+		}
+		if err := s.resolveGenres(state, candidate); err != nil {
+			state.fail(candidate.record, err)
+			continue
+		}
+
+		pending.add(candidate)
+		toCreate = append(toCreate, candidate)
+	}
+
+	for _, candidate := range s.createBooks(state, toCreate) {
+		state.created.add(candidate)
+		state.job.Created++
+		s.bookService.announceNewBook(candidate.book, state.job.CreatedBy)
+	}
+}
+
+func (s *ImportService) prepareCandidate(state *importBatchState, record importer.Record) (*importCandidate, error) {
+	if record.Err != nil {
+		return nil, record.Err
+	}
+
+	title := strings.TrimSpace(record.Title)
+	if title == "" {
+		return nil, ErrEmptyCatalogTitle
+	}
+	if record.PageCount < 0 {
+		return nil, ErrInvalidPageCount
+	}
+	if record.PublishedYear < 0 || record.PublishedYear > time.Now().Year()+1 {
+		return nil, ErrInvalidPublishedYear
+	}
+
+	confirmedAt := time.Now().UTC()
+	book := &models.Book{
+		ID:              uuid.New(),
+		Title:           title,
+		NormalizedTitle: utils.NormalizeTitle(title),
+		Description:     strings.TrimSpace(record.Description),
+		CoverImage:      strings.TrimSpace(record.CoverImage),
+		PageCount:       record.PageCount,
+		Publisher:       strings.TrimSpace(record.Publisher),
+		PublishedYear:   record.PublishedYear,
+		Confirmed:       true,
+		ConfirmedAt:     &confirmedAt,
+		CreatedBy:       &state.job.CreatedBy,
+	}
+	if strings.TrimSpace(record.ISBN) != "" {
+		isbn, err := importer.NormalizeISBN(record.ISBN)
+		if err != nil {
+			return nil, err
+		}
+		book.ISBN = &isbn
+	}
+
+	candidate := &importCandidate{record: record, book: book}
+	for _, name := range record.Authors {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		authorID, found, err := s.lookupAuthor(state, name)
+		switch {
+		case err != nil:
+			return nil, err
+		case !found:
+			if !slices.ContainsFunc(candidate.missingAuthors, func(missing string) bool { return strings.EqualFold(missing, name) }) {
+				candidate.missingAuthors = append(candidate.missingAuthors, name)
+			}
+		case !slices.Contains(candidate.authorIDs, authorID):
+			candidate.authorIDs = append(candidate.authorIDs, authorID)
+		}
+	}
+	return candidate, nil
+}
+
+// lookupAuthor ищет автора по имени в кеше задачи и в базе, не создавая его
+func (s *ImportService) lookupAuthor(state *importBatchState, name string) (uuid.UUID, bool, error) {
+	key := strings.ToLower(name)
+	if authorID, ok := state.authorIDs[key]; ok {
+		return authorID, true, nil
+	}
+
+	author, err := s.authorRepo.GetAuthorByName(name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, false, nil
+	}
+	if err != nil {
+		s.log.Warnf("Ошибка поиска автора %q: %v", name, err)
+		return uuid.Nil, false, err
+	}
+
+	state.authorIDs[key] = author.ID
+	return author.ID, true, nil
+}
+
+// planAuthors назначает ID авторам книги, которых нет в базе. Новый автор нескольких книг пачки получает
+// один ID, а создается вместе с первой из этих книг, которую удастся сохранить
+func planAuthors(planned map[string]*models.Author, candidate *importCandidate) {
+	for _, name := range candidate.missingAuthors {
+		key := strings.ToLower(name)
+		author, ok := planned[key]
+		if !ok {
+			author = &models.Author{ID: uuid.New(), Name: name}
+			planned[key] = author
+		}
+		if !slices.Contains(candidate.authorIDs, author.ID) {
+			candidate.authorIDs = append(candidate.authorIDs, author.ID)
+		}
+		candidate.newAuthors = append(candidate.newAuthors, author)
+	}
+	candidate.missingAuthors = nil
+}
+
+// resolveGenres находит жанры книги по названию и создает недостающие. Жанры разбираются только для книг,
+// которые точно будут созданы, чтобы дубли не плодили пустые жанры
+func (s *ImportService) resolveGenres(state *importBatchState, candidate *importCandidate) error {
+	for _, name := range candidate.record.Genres {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		key := strings.ToLower(name)
+		genreID, ok := state.genreIDs[key]
+		if !ok {
+			genre, err := s.genreRepo.GetGenreByName(name)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				genre, err = s.genreRepo.CreateGenre(name)
+			}
+			if err != nil {
+				s.log.Warnf("Ошибка получения жанра %q: %v", name, err)
+				return err
+			}
+			genreID = genre.ID
+			state.genreIDs[key] = genreID
+		}
+
+		if !slices.Contains(candidate.genreIDs, genreID) {
+			candidate.genreIDs = append(candidate.genreIDs, genreID)
+		}
+	}
+	return nil
+}
+
+// duplicateChecker загружает книги пачки, совпадающие по ISBN или нормализованному названию, и возвращает проверку.
+// Дубль — книга с тем же ISBN либо с тем же названием и теми же авторами, если у одной из книг ISBN не указан:
+// разные ISBN означают разные издания одной книги
+func (s *ImportService) duplicateChecker(candidates []*importCandidate) (func(*importCandidate) bool, error) {
+	var isbns, titles []string
+	for _, candidate := range candidates {
+		if candidate.book.ISBN != nil {
+			isbns = append(isbns, *candidate.book.ISBN)
+		}
+		titles = append(titles, candidate.book.NormalizedTitle)
+	}
+
+	byISBN, err := s.bookRepo.FindBooksByISBNs(isbns)
+	if err != nil {
+		return nil, err
+	}
+	existingISBN := make(map[string]bool, len(byISBN))
+	for _, book := range byISBN {
+		existingISBN[*book.ISBN] = true
+	}
+
+	byTitle, err := s.bookRepo.FindBooksByNormalizedTitles(titles)
+	if err != nil {
+		return nil, err
+	}
+	bookIDs := make([]uuid.UUID, len(byTitle))
+	for i, book := range byTitle {
+		bookIDs[i] = book.ID
+	}
+	authorsByBook := make(map[uuid.UUID][]uuid.UUID)
+	if len(bookIDs) > 0 {
+		links, err := s.bookAuthorRepo.GetAuthorsForBooks(bookIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range links {
+			if link.BookID != nil && link.AuthorID != nil {
+				authorsByBook[*link.BookID] = append(authorsByBook[*link.BookID], *link.AuthorID)
+			}
+		}
+	}
+
+	return func(candidate *importCandidate) bool {
+		isbn := candidate.book.ISBN
+		if isbn != nil && existingISBN[*isbn] {
+			return true
+		}
+		// Если части авторов нет в базе, книги с тем же набором авторов быть не может
+		if len(candidate.missingAuthors) > 0 {
+			return false
+		}
+
+		for _, book := range byTitle {
+			if book.NormalizedTitle == candidate.book.NormalizedTitle && (isbn == nil || book.ISBN == nil) &&
+				sameIDs(authorsByBook[book.ID], candidate.authorIDs) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// createBooks создает книги пачки и их новых авторов одной транзакцией. Если транзакция не прошла (например,
+// ту же книгу одновременно создали через API), книги создаются по одной, чтобы ошибка досталась только своей записи
+func (s *ImportService) createBooks(state *importBatchState, candidates []*importCandidate) []*importCandidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	books := make([]repositories.NewBookWithAuthors, len(candidates))
+	var authors []*models.Author
+	for i, candidate := range candidates {
+		books[i] = repositories.NewBookWithAuthors{Book: candidate.book, AuthorIDs: candidate.authorIDs, GenreIDs: candidate.genreIDs}
+		for _, author := range candidate.newAuthors {
+			if !slices.Contains(authors, author) {
+				authors = append(authors, author)
+			}
+		}
+	}
+	if err := s.bookRepo.CreateBooks(authors, books); err == nil {
+		state.rememberAuthors(authors)
+		return candidates
+	}
+
+	created := make([]*importCandidate, 0, len(candidates))
+	for i, candidate := range candidates {
+		// Автор, общий с уже сохраненной книгой, создан вместе с ней
+		var bookAuthors []*models.Author
+		for _, author := range candidate.newAuthors {
+			if _, ok := state.authorIDs[strings.ToLower(author.Name)]; !ok {
+				bookAuthors = append(bookAuthors, author)
+			}
+		}
+		if err := s.bookRepo.CreateBooks(bookAuthors, books[i:i+1]); err != nil {
+			state.fail(candidate.record, err)
+			continue
+		}
+		state.rememberAuthors(bookAuthors)
+		created = append(created, candidate)
+	}
+	return created
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"book-management-system/internal/models"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestImportedKeysContains(t *testing.T) {
+	lem, strugatsky := uuid.New(), uuid.New()
+	candidate := func(title, isbn string, authorIDs ...uuid.UUID) *importCandidate {
+		book := &models.Book{NormalizedTitle: title}
+		if isbn != "" {
+			book.ISBN = &isbn
+		}
+		return &importCandidate{book: book, authorIDs: authorIDs}
+	}
+
+	keys := newImportedKeys()
+	keys.add(candidate("солярис", "9780306406157", lem))
+	keys.add(candidate("пикник на обочине", "", strugatsky))
+
+	tests := []struct {
+		name      string
+		candidate *importCandidate
+		want      bool
+	}{
+		{"same ISBN", candidate("другое название", "9780306406157"), true},
+		{"other edition of a book with ISBN", candidate("солярис", "9791000000008", lem), false},
+		{"same title and authors without ISBN", candidate("солярис", "", lem), true},
+		{"same title, other authors", candidate("солярис", "", strugatsky), false},
+		{"book without ISBN matches any edition", candidate("пикник на обочине", "9780804429573", strugatsky), true},
+		{"authors compared as a set", candidate("пикник на обочине", "", strugatsky, lem), false},
+		{"unknown title", candidate("непобедимый", "", lem), false},
+	}
+
+	for _, tt := range tests {
+		if got := keys.contains(tt.candidate); got != tt.want {
+			t.Errorf("%s: contains = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlanAuthors(t *testing.T) {
+	existing := uuid.New()
+	planned := make(map[string]*models.Author)
+
+	first := &importCandidate{authorIDs: []uuid.UUID{existing}, missingAuthors: []string{"Станислав Лем", "Борис Стругацкий"}}
+	second := &importCandidate{missingAuthors: []string{"станислав лем"}}
+	planAuthors(planned, first)
+	planAuthors(planned, second)
+
+	if len(planned) != 2 {
+		t.Fatalf("запланировано авторов %d, want 2", len(planned))
+	}
+	lem := planned["станислав лем"]
+	if lem == nil || lem.Name != "Станислав Лем" {
+		t.Fatalf("автор «станислав лем» = %+v", lem)
+	}
+
+	tests := []struct {
+		name       string
+		candidate  *importCandidate
+		authorIDs  []uuid.UUID
+		newAuthors []*models.Author
+	}{
+		{"first", first, []uuid.UUID{existing, lem.ID, planned["борис стругацкий"].ID}, []*models.Author{lem, planned["борис стругацкий"]}},
+		{"second reuses the planned author", second, []uuid.UUID{lem.ID}, []*models.Author{lem}},
+	}
+
+	for _, tt := range tests {
+		if tt.candidate.missingAuthors != nil {
+			t.Errorf("%s: missingAuthors = %v, want nil", tt.name, tt.candidate.missingAuthors)
+		}
+		if !sameIDs(tt.candidate.authorIDs, tt.authorIDs) || len(tt.candidate.authorIDs) != len(tt.authorIDs) {
+			t.Errorf("%s: authorIDs = %v, want %v", tt.name, tt.candidate.authorIDs, tt.authorIDs)
+		}
+		if len(tt.candidate.newAuthors) != len(tt.newAuthors) {
+			t.Errorf("%s: newAuthors = %v, want %v", tt.name, tt.candidate.newAuthors, tt.newAuthors)
+			continue
+		}
+		for i := range tt.newAuthors {
+			if tt.candidate.newAuthors[i] != tt.newAuthors[i] {
+				t.Errorf("%s: newAuthors[%d] = %v, want %v", tt.name, i, tt.candidate.newAuthors[i], tt.newAuthors[i])
+			}
+		}
+	}
+}
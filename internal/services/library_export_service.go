@@ -0,0 +1,432 @@
+package services
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"book-management-system/pkg/utils"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type LibraryExportFormat string
+
+const (
+	LibraryExportCSV       LibraryExportFormat = "csv"
+	LibraryExportJSON      LibraryExportFormat = "json"
+	LibraryExportGoodreads LibraryExportFormat = "goodreads"
+)
+
+// libraryExportBatchSize сколько записей библиотеки обрабатывается за один проход
+const libraryExportBatchSize = 200
+
+// ParseLibraryExportFormat проверяет формат выгрузки библиотеки
+func ParseLibraryExportFormat(format string) (LibraryExportFormat, error) {
+	switch LibraryExportFormat(format) {
+	case LibraryExportCSV, LibraryExportJSON, LibraryExportGoodreads:
+		return LibraryExportFormat(format), nil
+	default:
+		return "", fmt.Errorf("неподдерживаемый формат выгрузки: %s", format)
+	}
+}
+
+// ContentType возвращает MIME-тип файла выгрузки
+func (f LibraryExportFormat) ContentType() string {
+	if f == LibraryExportJSON {
+		return "application/json; charset=utf-8"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// FileName возвращает имя файла выгрузки
+func (f LibraryExportFormat) FileName() string {
+	switch f {
+	case LibraryExportJSON:
+		return "library.json"
+	case LibraryExportGoodreads:
+		return "goodreads_library_export.csv"
+	default:
+		return "library.csv"
+	}
+}
+
+type LibraryExportService struct {
+	userBookRepo                *repositories.UserBookRepository
+	bookRepo                    *repositories.BookRepository
+	bookAuthorMappingRepository *repositories.BookAuthorRepository
+	authorRepo                  *repositories.AuthorRepository
+	bookRatingRepo              *repositories.BookRatingRepository
+	reviewRepo                  *repositories.ReviewRepository
+	log                         *logger.Logger
+}
+
+// NewLibraryExportService создает сервис выгрузки библиотеки пользователя
+func NewLibraryExportService(
+	userBookRepo *repositories.UserBookRepository,
+	bookRepo *repositories.BookRepository,
+	bookAuthorMappingRepository *repositories.BookAuthorRepository,
+	authorRepo *repositories.AuthorRepository,
+	bookRatingRepo *repositories.BookRatingRepository,
+	reviewRepo *repositories.ReviewRepository,
+) *LibraryExportService {
+	return &LibraryExportService{
+		userBookRepo:                userBookRepo,
+		bookRepo:                    bookRepo,
+		bookAuthorMappingRepository: bookAuthorMappingRepository,
+		authorRepo:                  authorRepo,
+		bookRatingRepo:              bookRatingRepo,
+		reviewRepo:                  reviewRepo,
+		log:                         logger.GetLogger(),
+	}
+}
+
+// ExportLibrary пишет библиотеку пользователя в w пачками, не собирая ее целиком в памяти
+func (s *LibraryExportService) ExportLibrary(userID uuid.UUID, format LibraryExportFormat, w io.Writer) error {
+	writer := newLibraryWriter(format, w)
+
+	if err := writer.Begin(); err != nil {
+		return err
+	}
+
+	err := s.userBookRepo.GetUserBooksInBatches(userID, libraryExportBatchSize, func(userBooks []models.UserBook) error {
+		entries, err := s.resolveEntries(userID, userBooks)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if err := writer.Write(entry); err != nil {
+				return err
+			}
+		}
+
+		// Отдаем клиенту готовую пачку, не дожидаясь конца выгрузки
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.log.Warnf("Ошибка выгрузки библиотеки пользователя %s: %v", userID, err)
+		return err
+	}
+
+	return writer.End()
+}
+
+// resolveEntries подтягивает к пачке записей названия книг, авторов, оценки и отзывы
+func (s *LibraryExportService) resolveEntries(userID uuid.UUID, userBooks []models.UserBook) ([]dto.LibraryExportEntry, error) {
+	bookIDs := make([]uuid.UUID, 0, len(userBooks))
+	stringBookIDs := make([]string, 0, len(userBooks))
+	for _, userBook := range userBooks {
+		bookIDs = append(bookIDs, *userBook.BookID)
+		stringBookIDs = append(stringBookIDs, utils.ConvertUUIDToString(*userBook.BookID))
+	}
+
+	books, err := s.bookRepo.GetBooksByIds(bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	booksMap := make(map[uuid.UUID]models.Book, len(books))
+	for _, book := range books {
+		booksMap[book.ID] = book
+	}
+
+	authorsMap, err := s.resolveAuthorNames(bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ratings, err := s.bookRatingRepo.GetUserRatingsForBooks(userID, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ratingsMap := make(map[uuid.UUID]int, len(ratings))
+	for _, rating := range ratings {
+		ratingsMap[rating.BookID] = rating.Rating
+	}
+
+	reviews, err := s.reviewRepo.GetReviewsByUserForBooks(utils.ConvertUUIDToString(userID), stringBookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewsMap := make(map[string]models.Review, len(reviews))
+	for _, review := range reviews {
+		reviewsMap[review.BookID] = review
+	}
+
+	entries := make([]dto.LibraryExportEntry, 0, len(userBooks))
+	for _, userBook := range userBooks {
+		bookID := *userBook.BookID
+
+		entry := dto.LibraryExportEntry{
+			BookID:    bookID,
+			Title:     booksMap[bookID].Title,
+			Authors:   authorsMap[bookID],
+			Status:    string(userBook.Status),
+			PagesRead: userBook.PagesRead,
+			ReadCount: userBook.ReadCount,
+			AddedAt:   userBook.CreatedAt,
+			UpdatedAt: userBook.UpdatedAt,
+		}
+
+		if review, exists := reviewsMap[utils.ConvertUUIDToString(bookID)]; exists {
+			text := review.Text
+			rating := review.Rating
+			entry.Review = &text
+			entry.Rating = &rating
+		}
+
+		// Явная оценка книги приоритетнее оценки из отзыва
+		if rating, exists := ratingsMap[bookID]; exists {
+			entry.Rating = &rating
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// resolveAuthorNames возвращает карту {bookID -> имена авторов}
+func (s *LibraryExportService) resolveAuthorNames(bookIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	bookAuthors, err := s.bookAuthorMappingRepository.GetAuthorsForBooks(bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	authorIDs := make([]uuid.UUID, 0, len(bookAuthors))
+	for _, ba := range bookAuthors {
+		if ba.AuthorID != nil {
+			authorIDs = append(authorIDs, *ba.AuthorID)
+		}
+	}
+
+	authors, err := s.authorRepo.GetAuthorsByIDs(authorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	authorNames := make(map[uuid.UUID]string, len(authors))
+	for _, author := range authors {
+		authorNames[author.ID] = author.Name
+	}
+
+	result := make(map[uuid.UUID][]string, len(bookIDs))
+	for _, ba := range bookAuthors {
+		if ba.BookID == nil || ba.AuthorID == nil {
+			continue
+		}
+		if name, exists := authorNames[*ba.AuthorID]; exists {
+			result[*ba.BookID] = append(result[*ba.BookID], name)
+		}
+	}
+
+	return result, nil
+}
+
+// libraryWriter пишет записи выгрузки в конкретном формате
+type libraryWriter interface {
+	Begin() error
+	Write(entry dto.LibraryExportEntry) error
+	Flush() error
+	End() error
+}
+
+func newLibraryWriter(format LibraryExportFormat, w io.Writer) libraryWriter {
+	switch format {
+	case LibraryExportJSON:
+		return &jsonLibraryWriter{w: w}
+	case LibraryExportGoodreads:
+		return &goodreadsLibraryWriter{csv: csv.NewWriter(w)}
+	default:
+		return &csvLibraryWriter{csv: csv.NewWriter(w)}
+	}
+}
+
+// csvLibraryWriter — плоский CSV с полями LibraryExportEntry
+type csvLibraryWriter struct {
+	csv *csv.Writer
+}
+
+func (w *csvLibraryWriter) Begin() error {
+	return w.csv.Write([]string{"book_id", "title", "authors", "status", "pages_read", "read_count", "rating", "review", "added_at", "updated_at"})
+}
+
+func (w *csvLibraryWriter) Write(entry dto.LibraryExportEntry) error {
+	return w.csv.Write([]string{
+		entry.BookID.String(),
+		entry.Title,
+		strings.Join(entry.Authors, "; "),
+		entry.Status,
+		strconv.Itoa(entry.PagesRead),
+		strconv.Itoa(entry.ReadCount),
+		optionalInt(entry.Rating),
+		optionalString(entry.Review),
+		entry.AddedAt.UTC().Format(time.RFC3339),
+		entry.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (w *csvLibraryWriter) Flush() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *csvLibraryWriter) End() error {
+	return w.Flush()
+}
+
+// jsonLibraryWriter пишет JSON-массив поэлементно
+type jsonLibraryWriter struct {
+	w       io.Writer
+	written int
+}
+
+func (w *jsonLibraryWriter) Begin() error {
+	_, err := io.WriteString(w.w, "[")
+	return err
+}
+
+func (w *jsonLibraryWriter) Write(entry dto.LibraryExportEntry) error {
+	if w.written > 0 {
+		if _, err := io.WriteString(w.w, ","); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	w.written++
+	_, err = w.w.Write(data)
+	return err
+}
+
+func (w *jsonLibraryWriter) Flush() error {
+	return nil
+}
+
+func (w *jsonLibraryWriter) End() error {
+	_, err := io.WriteString(w.w, "]")
+	return err
+}
+
+// goodreadsLibraryWriter пишет CSV в формате импорта Goodreads
+type goodreadsLibraryWriter struct {
+	csv *csv.Writer
+}
+
+var goodreadsHeader = []string{
+	"Book Id", "Title", "Author", "Author l-f", "Additional Authors", "ISBN", "ISBN13",
+	"My Rating", "Average Rating", "Publisher", "Binding", "Number of Pages", "Year Published",
+	"Original Publication Year", "Date Read", "Date Added", "Bookshelves", "Bookshelves with positions",
+	"Exclusive Shelf", "My Review", "Spoiler", "Private Notes", "Read Count", "Owned Copies",
+}
+
+func (w *goodreadsLibraryWriter) Begin() error {
+	return w.csv.Write(goodreadsHeader)
+}
+
+func (w *goodreadsLibraryWriter) Write(entry dto.LibraryExportEntry) error {
+	const dateLayout = "2006/01/02"
+
+	var author, authorLastFirst, additionalAuthors string
+	if len(entry.Authors) > 0 {
+		author = entry.Authors[0]
+		authorLastFirst = goodreadsLastFirst(author)
+		additionalAuthors = strings.Join(entry.Authors[1:], ", ")
+	}
+
+	// Goodreads оценивает по пятибалльной шкале, у нас десятибалльная
+	myRating := "0"
+	if entry.Rating != nil {
+		myRating = strconv.Itoa((*entry.Rating + 1) / 2)
+	}
+
+	exclusiveShelf, bookshelves, dateRead := "currently-reading", "", ""
+	switch models.ReadingStatus(entry.Status) {
+	case models.StatusCompleted:
+		exclusiveShelf = "read"
+		dateRead = entry.UpdatedAt.UTC().Format(dateLayout)
+	case models.StatusDropped:
+		exclusiveShelf = "to-read"
+		bookshelves = "dropped"
+	}
+
+	return w.csv.Write([]string{
+		entry.BookID.String(),
+		entry.Title,
+		author,
+		authorLastFirst,
+		additionalAuthors,
+		"",
+		"",
+		myRating,
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
+		dateRead,
+		entry.AddedAt.UTC().Format(dateLayout),
+		bookshelves,
+		"",
+		exclusiveShelf,
+		optionalString(entry.Review),
+		"",
+		"",
+		strconv.Itoa(entry.ReadCount),
+		"0",
+	})
+}
+
+func (w *goodreadsLibraryWriter) Flush() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *goodreadsLibraryWriter) End() error {
+	return w.Flush()
+}
+
+// goodreadsLastFirst превращает "Имя Фамилия" в "Фамилия, Имя"
+func goodreadsLastFirst(name string) string {
+	parts := strings.Fields(name)
+	if len(parts) < 2 {
+		return name
+	}
+	return parts[len(parts)-1] + ", " + strings.Join(parts[:len(parts)-1], " ")
+}
+
+func optionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func optionalString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
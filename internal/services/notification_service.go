@@ -0,0 +1,250 @@
+package services
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/events"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"slices"
+	"sync"
+)
+
+// notificationStreamBuffer сколько уведомлений ждут отправки в одно SSE-соединение;
+// медленный клиент пропустит лишние и дочитает их из GET /users/me/notifications
+const notificationStreamBuffer = 16
+
+var ErrUnknownNotificationType = errors.New("неизвестный вид уведомлений")
+
+// notificationTypes события, о которых сообщаем пользователю, в порядке показа в настройках
+var notificationTypes = []events.Type{
+	events.BookConfirmed,
+	events.ReviewReplied,
+	events.CommentReplied,
+	events.ReviewLiked,
+	events.ReviewModerated,
+}
+
+// notificationMessages тексты уведомлений по видам событий
+var notificationMessages = map[events.Type]func(events.Event) string{
+	events.BookConfirmed: func(e events.Event) string {
+		return fmt.Sprintf("Ваша книга «%s» подтверждена и опубликована", e.BookTitle)
+	},
+	events.ReviewReplied: func(events.Event) string {
+		return "Ваш отзыв прокомментировали"
+	},
+	events.CommentReplied: func(events.Event) string {
+		return "На ваш комментарий ответили"
+	},
+	events.ReviewLiked: func(events.Event) string {
+		return "Вашему отзыву поставили лайк"
+	},
+	events.ReviewModerated: func(events.Event) string {
+		return "Модератор скрыл ваш отзыв"
+	},
+}
+
+// NotificationService сохраняет уведомления из шины событий и доставляет их подключенным клиентам
+type NotificationService struct {
+	repo *repositories.NotificationRepository
+	log  *logger.Logger
+
+	mu sync.Mutex
+	// streams открытые SSE-соединения по пользователям. Живут в памяти процесса:
+	// при нескольких экземплярах сервиса клиент получит в реальном времени только события своего экземпляра
+	streams map[uuid.UUID]map[chan dto.NotificationResponse]struct{}
+	// closed выставляет CloseStreams: после остановки сервера новые потоки сразу закрываются
+	closed bool
+}
+
+// NewNotificationService создает сервис уведомлений. Чтобы получать события, его нужно подписать на шину
+func NewNotificationService(repo *repositories.NotificationRepository) *NotificationService {
+	return &NotificationService{
+		repo:    repo,
+		log:     logger.GetLogger(),
+		streams: make(map[uuid.UUID]map[chan dto.NotificationResponse]struct{}),
+	}
+}
+
+// HandleEvent превращает событие в уведомление, если пользователь их не отключил, и отправляет в открытые соединения
+func (s *NotificationService) HandleEvent(event events.Event) {
+	message, ok := notificationMessages[event.Type]
+	if !ok || event.UserID == uuid.Nil || event.UserID == event.ActorID {
+		return
+	}
+
+	enabled, err := s.repo.IsEnabled(event.UserID, string(event.Type))
+	if err != nil || !enabled {
+		return
+	}
+
+	notification := &models.Notification{
+		ID:        uuid.New(),
+		UserID:    event.UserID,
+		Type:      string(event.Type),
+		BookID:    event.BookID,
+		ReviewID:  event.ReviewID,
+		CommentID: event.CommentID,
+		Message:   message(event),
+	}
+	if event.ActorID != uuid.Nil {
+		notification.ActorID = &event.ActorID
+	}
+
+	if err := s.repo.CreateNotification(notification); err != nil {
+		return
+	}
+
+	s.push(event.UserID, toNotificationResponse(notification))
+}
+
+// GetNotifications получает уведомления пользователя и число непрочитанных
+func (s *NotificationService) GetNotifications(userID uuid.UUID, unreadOnly bool, limit int, afterID *uuid.UUID) (*dto.NotificationsResponse, error) {
+	notifications, err := s.repo.GetNotificationsPaginated(userID, unreadOnly, limit, afterID)
+	if err != nil {
+		return nil, err
+	}
+
+	unreadCount, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.NotificationResponse, len(notifications))
+	for i := range notifications {
+		responses[i] = toNotificationResponse(&notifications[i])
+	}
+
+	var nextCursor *uuid.UUID
+	if len(notifications) > 0 {
+		nextCursor = &notifications[len(notifications)-1].ID
+	}
+
+	return &dto.NotificationsResponse{
+		Notifications: responses,
+		UnreadCount:   unreadCount,
+		NextCursor:    nextCursor,
+	}, nil
+}
+
+// MarkRead отмечает уведомление прочитанным
+func (s *NotificationService) MarkRead(userID, notificationID uuid.UUID) error {
+	return s.repo.MarkRead(userID, notificationID)
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя
+func (s *NotificationService) MarkAllRead(userID uuid.UUID) (int64, error) {
+	return s.repo.MarkAllRead(userID)
+}
+
+// GetPreferences получает настройки всех видов уведомлений; не сохраненные виды включены
+func (s *NotificationService) GetPreferences(userID uuid.UUID) ([]dto.NotificationPreferenceResponse, error) {
+	saved, err := s.repo.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[string]bool, len(saved))
+	for _, preference := range saved {
+		enabled[preference.Type] = preference.Enabled
+	}
+
+	preferences := make([]dto.NotificationPreferenceResponse, len(notificationTypes))
+	for i, eventType := range notificationTypes {
+		value, ok := enabled[string(eventType)]
+		preferences[i] = dto.NotificationPreferenceResponse{Type: string(eventType), Enabled: value || !ok}
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences включает и отключает виды уведомлений
+func (s *NotificationService) UpdatePreferences(userID uuid.UUID, changes map[string]bool) ([]dto.NotificationPreferenceResponse, error) {
+	preferences := make([]models.NotificationPreference, 0, len(changes))
+	for notificationType, enabled := range changes {
+		if !slices.Contains(notificationTypes, events.Type(notificationType)) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownNotificationType, notificationType)
+		}
+		preferences = append(preferences, models.NotificationPreference{
+			UserID:  userID,
+			Type:    notificationType,
+			Enabled: enabled,
+		})
+	}
+
+	if err := s.repo.SavePreferences(preferences); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(userID)
+}
+
+// Subscribe открывает поток уведомлений пользователя; вызывающий обязан вызвать возвращенную функцию отписки
+func (s *NotificationService) Subscribe(userID uuid.UUID) (<-chan dto.NotificationResponse, func()) {
+	stream := make(chan dto.NotificationResponse, notificationStreamBuffer)
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		close(stream)
+		return stream, func() {}
+	}
+	if s.streams[userID] == nil {
+		s.streams[userID] = make(map[chan dto.NotificationResponse]struct{})
+	}
+	s.streams[userID][stream] = struct{}{}
+	s.mu.Unlock()
+
+	return stream, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.streams[userID], stream)
+		if len(s.streams[userID]) == 0 {
+			delete(s.streams, userID)
+		}
+	}
+}
+
+// CloseStreams закрывает все открытые потоки, чтобы SSE-соединения завершились и не задерживали остановку сервера:
+// http.Server.Shutdown не отменяет контексты активных запросов
+func (s *NotificationService) CloseStreams() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for userID, streams := range s.streams {
+		for stream := range streams {
+			close(stream)
+		}
+		delete(s.streams, userID)
+	}
+	s.closed = true
+}
+
+// push отправляет уведомление во все соединения пользователя, не дожидаясь медленных клиентов
+func (s *NotificationService) push(userID uuid.UUID, notification dto.NotificationResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for stream := range s.streams[userID] {
+		select {
+		case stream <- notification:
+		default:
+			s.log.Warnf("Поток уведомлений пользователя %s переполнен, уведомление %s не отправлено", userID, notification.ID)
+		}
+	}
+}
+
+func toNotificationResponse(notification *models.Notification) dto.NotificationResponse {
+	return dto.NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Message:   notification.Message,
+		ActorID:   notification.ActorID,
+		BookID:    notification.BookID,
+		ReviewID:  notification.ReviewID,
+		CommentID: notification.CommentID,
+		Read:      notification.ReadAt != nil,
+		CreatedAt: notification.CreatedAt,
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestNotificationCloseStreams(t *testing.T) {
+	tests := []struct {
+		name             string
+		subscribedBefore int
+	}{
+		{"без потоков", 0},
+		{"один поток", 1},
+		{"несколько потоков", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewNotificationService(nil)
+			userID := uuid.New()
+
+			var unsubscribes []func()
+			for i := 0; i < tt.subscribedBefore; i++ {
+				stream, unsubscribe := service.Subscribe(userID)
+				unsubscribes = append(unsubscribes, unsubscribe)
+				defer func() {
+					if _, ok := <-stream; ok {
+						t.Error("поток, открытый до CloseStreams, не закрыт")
+					}
+				}()
+			}
+
+			service.CloseStreams()
+			// Отписка после закрытия не должна паниковать
+			for _, unsubscribe := range unsubscribes {
+				unsubscribe()
+			}
+
+			stream, unsubscribe := service.Subscribe(userID)
+			defer unsubscribe()
+			if _, ok := <-stream; ok {
+				t.Error("поток, открытый после CloseStreams, не закрыт")
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/feeds"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	opdsPageSize     = 50 // книг и авторов на странице каталога
+	opdsCatalogTitle = "Каталог Book Management System"
+	siteName         = "Book Management System" // название сайта: автор лент OPDS и Atom, og:site_name
+	opdsIDPrefix     = "urn:book-management-system:opds:"
+)
+
+var ErrEmptySearchQuery = errors.New("пустой поисковый запрос")
+
+// OPDSService собирает ленты каталога OPDS для читалок (KOReader и т.п.). В каталоге только подтвержденные книги;
+// адреса и форматы версий 1.2 и 2.0 подставляет feeds.Catalog, поэтому ленты обеих версий строятся одним кодом
+type OPDSService struct {
+	bookService   *BookService
+	authorService *AuthorService
+	genreRepo     *repositories.GenreRepository
+	log           *logger.Logger
+}
+
+// NewOPDSService создает сервис каталога OPDS
+func NewOPDSService(bookService *BookService, authorService *AuthorService, genreRepo *repositories.GenreRepository) *OPDSService {
+	return &OPDSService{
+		bookService:   bookService,
+		authorService: authorService,
+		genreRepo:     genreRepo,
+		log:           logger.GetLogger(),
+	}
+}
+
+// Root корень каталога: разделы новинок, лучших, всех книг, авторов и жанров
+func (s *OPDSService) Root(catalog feeds.Catalog) *feeds.Feed {
+	now := time.Now().UTC()
+	feed := s.newFeed(catalog, "", nil, feeds.KindNavigation, opdsCatalogTitle)
+	feed.Updated = now
+	feed.Links = append(feed.Links,
+		catalog.Link(feeds.RelSortNew, "new", nil, feeds.KindAcquisition, "Новинки"),
+		catalog.Link(feeds.RelSortPopular, "top", nil, feeds.KindAcquisition, "Лучшие книги"),
+	)
+
+	sections := []struct {
+		section, title, content string
+		kind                    feeds.Kind
+	}{
+		{"new", "Новинки", "Последние добавленные в каталог книги", feeds.KindAcquisition},
+		{"top", "Лучшие книги", "Книги с самым высоким рейтингом по отзывам", feeds.KindAcquisition},
+		{"books", "Все книги", "Весь каталог в порядке добавления", feeds.KindAcquisition},
+		{"authors", "Авторы", "Книги по авторам", feeds.KindNavigation},
+		{"genres", "Жанры", "Книги по жанрам", feeds.KindNavigation},
+	}
+	for _, section := range sections {
+		feed.Entries = append(feed.Entries, feeds.Entry{
+			ID:      opdsIDPrefix + section.section,
+			Title:   section.title,
+			Updated: now,
+			Content: section.content,
+			Links:   []feeds.Link{catalog.Link("subsection", section.section, nil, section.kind, section.title)},
+		})
+	}
+	return feed
+}
+
+// NewestBooks подтвержденные книги от новых к старым
+func (s *OPDSService) NewestBooks(catalog feeds.Catalog, afterID *uuid.UUID) (*feeds.Feed, error) {
+	page, err := s.bookService.GetNewestBooks(opdsPageSize, afterID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.bookPage(catalog, "new", "Новинки", afterID, page)
+}
+
+// AllBooks все подтвержденные книги в порядке добавления
+func (s *OPDSService) AllBooks(catalog feeds.Catalog, afterID *uuid.UUID) (*feeds.Feed, error) {
+	page, err := s.bookService.GetBooksPaginated(opdsPageSize, afterID)
+	if err != nil {
+		return nil, err
+	}
+	return s.bookPage(catalog, "books", "Все книги", afterID, page)
+}
+
+// TopRatedBooks книги с лучшим байесовским рейтингом; одна страница без продолжения
+func (s *OPDSService) TopRatedBooks(catalog feeds.Catalog) (*feeds.Feed, error) {
+	top, err := s.bookService.GetTopRatedBooks(opdsPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := s.newFeed(catalog, "top", nil, feeds.KindAcquisition, "Лучшие книги")
+	if err := s.fillBooks(catalog, feed, top.Books); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// Authors авторы каталога в порядке добавления
+func (s *OPDSService) Authors(catalog feeds.Catalog, afterID *uuid.UUID) (*feeds.Feed, error) {
+	page, err := s.authorService.GetAuthorList(opdsPageSize, afterID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	feed := s.newFeed(catalog, "authors", cursorQuery(afterID), feeds.KindNavigation, "Авторы")
+	feed.Updated = now
+	for _, author := range page.Authors {
+		section := "authors/" + author.ID.String()
+		feed.Entries = append(feed.Entries, feeds.Entry{
+			ID:      opdsIDPrefix + section,
+			Title:   author.Name,
+			Updated: now,
+			Links:   []feeds.Link{catalog.Link("subsection", section, nil, feeds.KindAcquisition, author.Name)},
+		})
+	}
+	if len(page.Authors) == opdsPageSize && page.NextCursor != nil {
+		feed.Links = append(feed.Links, catalog.Link("next", "authors", cursorQuery(page.NextCursor), feeds.KindNavigation, "Дальше"))
+	}
+	return feed, nil
+}
+
+// AuthorBooks подтвержденные книги автора
+func (s *OPDSService) AuthorBooks(catalog feeds.Catalog, authorID uuid.UUID) (*feeds.Feed, error) {
+	author, err := s.authorService.GetAuthorByID(authorID)
+	if err != nil {
+		return nil, err
+	}
+	books, err := s.bookService.GetBooksByAuthor(authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := s.newFeed(catalog, "authors/"+authorID.String(), nil, feeds.KindAcquisition, author.Name)
+	feed.Links = append(feed.Links, catalog.Link("up", "authors", nil, feeds.KindNavigation, "Авторы"))
+	if err := s.fillBooks(catalog, feed, books); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// Genres жанры, в которых есть подтвержденные книги
+func (s *OPDSService) Genres(catalog feeds.Catalog) (*feeds.Feed, error) {
+	genres, err := s.genreRepo.GetGenresWithBookCounts()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	feed := s.newFeed(catalog, "genres", nil, feeds.KindNavigation, "Жанры")
+	feed.Updated = now
+	for _, genre := range genres {
+		section := "genres/" + genre.ID.String()
+		feed.Entries = append(feed.Entries, feeds.Entry{
+			ID:      opdsIDPrefix + section,
+			Title:   genre.Name,
+			Updated: now,
+			Content: fmt.Sprintf("Книг: %d", genre.BookCount),
+			Links:   []feeds.Link{catalog.Link("subsection", section, nil, feeds.KindAcquisition, genre.Name)},
+		})
+	}
+	return feed, nil
+}
+
+// GenreBooks подтвержденные книги жанра от новых к старым
+func (s *OPDSService) GenreBooks(catalog feeds.Catalog, genreID uuid.UUID, afterID *uuid.UUID) (*feeds.Feed, error) {
+	genre, err := s.genreRepo.GetGenreByID(genreID)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.bookService.GetNewestBooks(opdsPageSize, afterID, &genreID)
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := s.bookPage(catalog, "genres/"+genreID.String(), genre.Name, afterID, page)
+	if err != nil {
+		return nil, err
+	}
+	feed.Links = append(feed.Links, catalog.Link("up", "genres", nil, feeds.KindNavigation, "Жанры"))
+	return feed, nil
+}
+
+// Search ищет подтвержденные книги по названию или автору; страницы нумеруются с единицы, как startPage в OpenSearch
+func (s *OPDSService) Search(catalog feeds.Catalog, query string, page int) (*feeds.Feed, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+	page = max(page, 1)
+
+	// Берем на одну книгу больше страницы, чтобы понять, есть ли следующая
+	books, err := s.bookService.SearchBooks(query, opdsPageSize+1, (page-1)*opdsPageSize)
+	if err != nil {
+		return nil, err
+	}
+	hasNext := len(books) > opdsPageSize
+	books = books[:min(len(books), opdsPageSize)]
+
+	params := url.Values{"q": {query}, "page": {strconv.Itoa(page)}}
+	feed := s.newFeed(catalog, "search", params, feeds.KindAcquisition, fmt.Sprintf("Поиск: %s", query))
+	feed.ID = opdsIDPrefix + "search:" + url.QueryEscape(query) + ":" + strconv.Itoa(page)
+	feed.Paging = &feeds.Paging{TotalResults: -1, ItemsPerPage: opdsPageSize, StartIndex: (page-1)*opdsPageSize + 1}
+	if hasNext {
+		params := url.Values{"q": {query}, "page": {strconv.Itoa(page + 1)}}
+		feed.Links = append(feed.Links, catalog.Link("next", "search", params, feeds.KindAcquisition, "Дальше"))
+	}
+	if page > 1 {
+		params := url.Values{"q": {query}, "page": {strconv.Itoa(page - 1)}}
+		feed.Links = append(feed.Links, catalog.Link("previous", "search", params, feeds.KindAcquisition, "Назад"))
+	}
+
+	if err := s.fillBooks(catalog, feed, books); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// newFeed создает ленту раздела со ссылками на себя, корень и поиск
+func (s *OPDSService) newFeed(catalog feeds.Catalog, section string, query url.Values, kind feeds.Kind, title string) *feeds.Feed {
+	id := opdsIDPrefix + "root"
+	if section != "" {
+		id = opdsIDPrefix + section
+	}
+
+	return &feeds.Feed{
+		ID:     id,
+		Title:  title,
+		Author: &feeds.Person{Name: siteName, URI: catalog.Site},
+		Links: []feeds.Link{
+			catalog.Link("self", section, query, kind, ""),
+			catalog.Link("start", "", nil, feeds.KindNavigation, opdsCatalogTitle),
+			catalog.SearchLink(),
+		},
+	}
+}
+
+// bookPage лента страницы книг с маркерной пагинацией; ссылка next есть, только если страница заполнена целиком
+func (s *OPDSService) bookPage(catalog feeds.Catalog, section, title string, afterID *uuid.UUID, page *dto.PaginatedBooksResponse) (*feeds.Feed, error) {
+	feed := s.newFeed(catalog, section, cursorQuery(afterID), feeds.KindAcquisition, title)
+	if len(page.Books) == opdsPageSize && page.NextCursor != nil {
+		feed.Links = append(feed.Links, catalog.Link("next", section, cursorQuery(page.NextCursor), feeds.KindAcquisition, "Дальше"))
+	}
+
+	if err := s.fillBooks(catalog, feed, page.Books); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// fillBooks добавляет в ленту записи книг с жанрами и ставит время обновления ленты по самой свежей книге
+func (s *OPDSService) fillBooks(catalog feeds.Catalog, feed *feeds.Feed, books []dto.BookResponse) error {
+	feed.Updated = time.Now().UTC()
+	if len(books) == 0 {
+		return nil
+	}
+
+	bookIDs := make([]uuid.UUID, len(books))
+	for i, book := range books {
+		bookIDs[i] = book.ID
+	}
+	genres, err := s.genreRepo.GetGenresForBooks(bookIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, book := range books {
+		entry := feeds.Entry{
+			ID:        "urn:uuid:" + book.ID.String(),
+			Title:     book.Title,
+			Updated:   bookEntryUpdated(book),
+			Published: bookListedAt(book),
+			Summary:   book.Description,
+			Publication: &feeds.Publication{
+				ISBN:      book.ISBN,
+				Publisher: book.Publisher,
+				Year:      book.PublishedYear,
+				PageCount: book.PageCount,
+			},
+			Links: []feeds.Link{{
+				Rel:  "alternate",
+				Href: catalog.SiteURL("/api/v1/books/" + book.ID.String()),
+				Type: "application/json",
+			}},
+		}
+
+		for _, author := range book.Authors {
+			section := "authors/" + author.ID.String()
+			entry.Authors = append(entry.Authors, feeds.Person{Name: author.Name, URI: catalog.URL(section, nil)})
+			entry.Links = append(entry.Links, catalog.Link("related", section, nil, feeds.KindAcquisition, "Все книги автора: "+author.Name))
+		}
+		for _, genre := range genres[book.ID] {
+			entry.Categories = append(entry.Categories, feeds.Category{Term: genre.Name, Label: genre.Name})
+		}
+		if book.CoverImage != "" {
+			href := catalog.SiteURL(book.CoverImage)
+			imageType := coverImageType(book.CoverImage)
+			entry.Links = append(entry.Links,
+				feeds.Link{Rel: feeds.RelImage, Href: href, Type: imageType},
+				feeds.Link{Rel: feeds.RelThumbnail, Href: href, Type: imageType},
+			)
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	feed.Updated = feeds.LatestUpdate(feed.Entries, feed.Updated)
+	return nil
+}
+
+// coverImageType определяет тип обложки по расширению; обложки загружаются в jpg и png, поэтому по умолчанию jpeg
+func coverImageType(cover string) string {
+	if parsed, err := url.Parse(cover); err == nil {
+		cover = parsed.Path
+	}
+	if imageType := mime.TypeByExtension(strings.ToLower(path.Ext(cover))); strings.HasPrefix(imageType, "image/") {
+		return imageType
+	}
+	return "image/jpeg"
+}
+
+// cursorQuery параметры запроса для маркерной пагинации
+func cursorQuery(afterID *uuid.UUID) url.Values {
+	if afterID == nil {
+		return nil
+	}
+	return url.Values{"after_id": {afterID.String()}}
+}
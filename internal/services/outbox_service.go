@@ -0,0 +1,131 @@
+package services
+
+import (
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"book-management-system/pkg/utils"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	outboxPollInterval    = 5 * time.Second
+	outboxClaimBatch      = 50
+	outboxClaimLease      = 5 * time.Minute
+	outboxBaseBackoff     = 10 * time.Second // после n-й неудачи ждем base * 2^(n-1), но не больше outboxMaxBackoff
+	outboxMaxBackoff      = time.Hour
+	outboxRetention       = 7 * 24 * time.Hour // сколько хранить обработанные сообщения для разбора инцидентов
+	outboxCleanupInterval = 24 * time.Hour
+)
+
+// OutboxService применяет побочные эффекты в MongoDB из транзакционного outbox.
+// Сообщение повторяется с растущей задержкой, пока не будет обработано: хранилища должны сойтись
+type OutboxService struct {
+	repo          *repositories.OutboxRepository
+	reviewRepo    *repositories.ReviewRepository
+	reviewService *ReviewService
+	log           *logger.Logger
+}
+
+// NewOutboxService создает сервис обработки outbox
+func NewOutboxService(
+	repo *repositories.OutboxRepository,
+	reviewRepo *repositories.ReviewRepository,
+	reviewService *ReviewService,
+) *OutboxService {
+	return &OutboxService{
+		repo:          repo,
+		reviewRepo:    reviewRepo,
+		reviewService: reviewService,
+		log:           logger.GetLogger(),
+	}
+}
+
+// StartWorker запускает фоновую обработку outbox, раз в час обновляет априорное среднее рейтинга
+// и раз в сутки удаляет старые обработанные сообщения. Все три цикла завершаются при отмене ctx
+func (s *OutboxService) StartWorker(ctx context.Context) {
+	go s.every(ctx, outboxPollInterval, true, func() { s.processDue(ctx) })
+	go s.every(ctx, outboxCleanupInterval, true, func() { _ = s.CleanupProcessed() })
+	go s.every(ctx, ratingPriorRefreshInterval, false, func() { _ = s.reviewService.RefreshRatingPrior() })
+}
+
+// every вызывает task с интервалом interval до отмены ctx; при now первый вызов выполняется сразу
+func (s *OutboxService) every(ctx context.Context, interval time.Duration, now bool, task func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	if now {
+		task()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			task()
+		}
+	}
+}
+
+// CleanupProcessed удаляет обработанные сообщения старше срока хранения
+func (s *OutboxService) CleanupProcessed() error {
+	return s.repo.DeleteProcessedBefore(time.Now().UTC().Add(-outboxRetention))
+}
+
+func (s *OutboxService) processDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		messages, err := s.repo.ClaimDueMessages(outboxClaimBatch, outboxClaimLease)
+		if err != nil || len(messages) == 0 {
+			return
+		}
+
+		for i := range messages {
+			if ctx.Err() != nil {
+				return
+			}
+			s.process(&messages[i])
+		}
+	}
+}
+
+func (s *OutboxService) process(message *models.OutboxMessage) {
+	err := s.apply(message)
+
+	now := time.Now().UTC()
+	message.Attempts++
+	message.LastError = ""
+
+	if err == nil {
+		message.NextAttemptAt = nil
+		message.ProcessedAt = &now
+	} else {
+		next := now.Add(outboxBackoff(message.Attempts))
+		message.NextAttemptAt = &next
+		message.LastError = err.Error()
+		s.log.Warnf("Сообщение outbox %s (%s, книга %s) не обработано (попытка %d): %v",
+			message.ID, message.Type, message.BookID, message.Attempts, err)
+	}
+
+	_ = s.repo.SaveAttempt(message)
+}
+
+// apply выполняет побочный эффект сообщения; каждый обработчик безопасно повторять
+func (s *OutboxService) apply(message *models.OutboxMessage) error {
+	switch message.Type {
+	case models.OutboxDeleteBookReviews:
+		return s.reviewRepo.DeleteReviewsByBookID(utils.ConvertUUIDToString(message.BookID))
+	case models.OutboxRecalculateBookRating:
+		return s.reviewService.RecalculateBookRating(message.BookID)
+	}
+	return fmt.Errorf("неизвестный тип сообщения outbox: %s", message.Type)
+}
+
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, outboxMaxBackoff)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, outboxBaseBackoff},
+		{2, 2 * outboxBaseBackoff},
+		{3, 4 * outboxBaseBackoff},
+		{100, outboxMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestOutboxEveryStopsOnCancel(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  time.Duration
+		now       bool
+		wantCalls func(int64) bool
+	}{
+		{"first call at once", time.Hour, true, func(n int64) bool { return n == 1 }},
+		{"waits for the first tick", time.Hour, false, func(n int64) bool { return n == 0 }},
+		{"repeats on ticks", time.Millisecond, false, func(n int64) bool { return n > 1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int64
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+
+			go func() {
+				(&OutboxService{}).every(ctx, tt.interval, tt.now, func() { calls.Add(1) })
+				close(done)
+			}()
+
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("every не завершился после отмены контекста")
+			}
+
+			if n := calls.Load(); !tt.wantCalls(n) {
+				t.Errorf("task вызван %d раз", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"book-management-system/config"
+	"book-management-system/internal/dto"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"book-management-system/pkg/utils"
+	"github.com/google/uuid"
+	"strconv"
+)
+
+// Очки репутации за события
+const (
+	reputationPerLike          = 1
+	reputationPerDislike       = -1
+	reputationPerConfirmedBook = 10
+	reputationPerPenalty       = -20
+
+	defaultTrustedReputation = 100
+)
+
+// ReputationService считает репутацию пользователей и решает, какие привилегии она открывает
+type ReputationService struct {
+	repo             *repositories.UserReputationRepository
+	trustedThreshold int
+	log              *logger.Logger
+}
+
+// NewReputationService создает сервис репутации.
+// Порог доверенного пользователя берется из REPUTATION_TRUSTED_THRESHOLD
+func NewReputationService(repo *repositories.UserReputationRepository) *ReputationService {
+	log := logger.GetLogger()
+
+	trustedThreshold, err := strconv.Atoi(config.GetEnv("REPUTATION_TRUSTED_THRESHOLD", strconv.Itoa(defaultTrustedReputation)))
+	if err != nil || trustedThreshold <= 0 {
+		log.Warnf("Некорректный REPUTATION_TRUSTED_THRESHOLD, используем %d", defaultTrustedReputation)
+		trustedThreshold = defaultTrustedReputation
+	}
+
+	return &ReputationService{
+		repo:             repo,
+		trustedThreshold: trustedThreshold,
+		log:              log,
+	}
+}
+
+// OnReviewVoteChanged учитывает смену голоса за отзыв автора: previousVote и vote — 1, -1 или 0
+func (s *ReputationService) OnReviewVoteChanged(authorID string, previousVote, vote int) error {
+	if previousVote == vote {
+		return nil
+	}
+
+	userID, err := utils.ConvertStringToUUID(authorID)
+	if err != nil || userID == uuid.Nil {
+		// Отзыв удаленного пользователя — репутацию начислять некому
+		return nil
+	}
+
+	delta := models.UserReputation{UserID: userID}
+	for _, change := range []struct{ vote, sign int }{{previousVote, -1}, {vote, 1}} {
+		switch change.vote {
+		case 1:
+			delta.ReviewLikes += change.sign
+			delta.Score += change.sign * reputationPerLike
+		case -1:
+			delta.ReviewDislikes += change.sign
+			delta.Score += change.sign * reputationPerDislike
+		}
+	}
+
+	return s.repo.ApplyDelta(delta)
+}
+
+// OnBookConfirmed начисляет репутацию за добавленную книгу, которая прошла подтверждение
+func (s *ReputationService) OnBookConfirmed(creatorID uuid.UUID) error {
+	return s.repo.ApplyDelta(models.UserReputation{
+		UserID:         creatorID,
+		ConfirmedBooks: 1,
+		Score:          reputationPerConfirmedBook,
+	})
+}
+
+// OnModerationPenalty снимает репутацию за отзыв, скрытый или удаленный модератором
+func (s *ReputationService) OnModerationPenalty(authorID string) error {
+	userID, err := utils.ConvertStringToUUID(authorID)
+	if err != nil || userID == uuid.Nil {
+		return nil
+	}
+
+	return s.repo.ApplyDelta(models.UserReputation{
+		UserID:              userID,
+		ModerationPenalties: 1,
+		Score:               reputationPerPenalty,
+	})
+}
+
+// OnModerationPenaltyRevoked возвращает репутацию, если модератор одобрил ранее скрытый или удаленный отзыв
+func (s *ReputationService) OnModerationPenaltyRevoked(authorID string) error {
+	userID, err := utils.ConvertStringToUUID(authorID)
+	if err != nil || userID == uuid.Nil {
+		return nil
+	}
+
+	return s.repo.ApplyDelta(models.UserReputation{
+		UserID:              userID,
+		ModerationPenalties: -1,
+		Score:               -reputationPerPenalty,
+	})
+}
+
+// GetReputation получает репутацию пользователя для профиля
+func (s *ReputationService) GetReputation(userID uuid.UUID) (*dto.ReputationResponse, error) {
+	reputation, err := s.repo.GetReputation(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ReputationResponse{
+		Score:               reputation.Score,
+		ReviewLikes:         reputation.ReviewLikes,
+		ReviewDislikes:      reputation.ReviewDislikes,
+		ConfirmedBooks:      reputation.ConfirmedBooks,
+		ModerationPenalties: reputation.ModerationPenalties,
+		Trusted:             reputation.Score >= s.trustedThreshold,
+	}, nil
+}
+
+// IsTrusted проверяет, набрал ли пользователь репутацию доверенного: его книги не требуют подтверждения
+func (s *ReputationService) IsTrusted(userID uuid.UUID) (bool, error) {
+	reputation, err := s.repo.GetReputation(userID)
+	if err != nil {
+		return false, err
+	}
+	return reputation.Score >= s.trustedThreshold, nil
+}
+
+// GetScores получает очки репутации авторов по их строковым ID (так они хранятся в MongoDB)
+func (s *ReputationService) GetScores(userIDs []string) (map[string]int, error) {
+	ids := make([]uuid.UUID, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if id, err := utils.ConvertStringToUUID(userID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	scores, err := s.repo.GetScores(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int, len(scores))
+	for id, score := range scores {
+		result[utils.ConvertUUIDToString(id)] = score
+	}
+	return result, nil
+}
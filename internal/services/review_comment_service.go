@@ -0,0 +1,238 @@
+package services
+
+import (
+	"book-management-system/internal/dto"
+	"book-management-system/internal/events"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"book-management-system/pkg/utils"
+	"errors"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"strings"
+	"time"
+)
+
+// maxReviewCommentDepth — максимальная вложенность ответов; 0 — комментарий верхнего уровня
+const maxReviewCommentDepth = 4
+
+var (
+	ErrEmptyCommentText       = errors.New("текст комментария не может быть пустым")
+	ErrCommentDepthExceeded   = errors.New("превышена максимальная вложенность ответов")
+	ErrParentCommentMismatch  = errors.New("родительский комментарий относится к другому отзыву")
+	ErrCommentDeleted         = errors.New("комментарий удален")
+	ErrInvalidParentCommentID = errors.New("неверный идентификатор родительского комментария")
+)
+
+type ReviewCommentService struct {
+	commentRepo *repositories.ReviewCommentRepository
+	reviewRepo  *repositories.ReviewRepository
+	bus         *events.Bus
+	log         *logger.Logger
+}
+
+// NewReviewCommentService создает сервис комментариев к отзывам
+func NewReviewCommentService(commentRepo *repositories.ReviewCommentRepository, reviewRepo *repositories.ReviewRepository) *ReviewCommentService {
+	return &ReviewCommentService{
+		commentRepo: commentRepo,
+		reviewRepo:  reviewRepo,
+		bus:         events.GetBus(),
+		log:         logger.GetLogger(),
+	}
+}
+
+// GetComments получает комментарии верхнего уровня или ответы на parentID
+func (s *ReviewCommentService) GetComments(reviewID primitive.ObjectID, parentID *primitive.ObjectID, limit int, afterID *primitive.ObjectID) (*dto.PaginatedReviewCommentsResponse, error) {
+	if _, err := s.getVisibleReview(reviewID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.GetCommentsPaginated(reviewID, parentID, limit, afterID)
+	if err != nil {
+		s.log.Warnf("Ошибка получения комментариев к отзыву: %v", err)
+		return nil, err
+	}
+
+	responses := make([]dto.ReviewCommentResponse, len(comments))
+	for i := range comments {
+		responses[i] = toReviewCommentResponse(&comments[i])
+	}
+
+	var nextCursor *string
+	if len(comments) > 0 {
+		last := comments[len(comments)-1].ID.Hex()
+		nextCursor = &last
+	}
+
+	return &dto.PaginatedReviewCommentsResponse{
+		Comments:   responses,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// GetCommentByID получает комментарий отзыва по ID
+func (s *ReviewCommentService) GetCommentByID(reviewID, commentID primitive.ObjectID) (*models.ReviewComment, error) {
+	comment, err := s.commentRepo.GetCommentByID(commentID)
+	if err != nil {
+		return nil, err
+	}
+	// Комментарий из другого отзыва для этого маршрута не существует
+	if comment.ReviewID != reviewID {
+		return nil, mongo.ErrNoDocuments
+	}
+	return comment, nil
+}
+
+// getVisibleReview получает отзыв для обсуждения; скрытые, удаленные и ожидающие модерации отзывы считаются
+// отсутствующими, как и в самих роутах отзывов
+func (s *ReviewCommentService) getVisibleReview(reviewID primitive.ObjectID) (*models.Review, error) {
+	review, err := s.reviewRepo.GetReviewById(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !review.IsVisible() {
+		return nil, mongo.ErrNoDocuments
+	}
+	return review, nil
+}
+
+// CreateComment создает комментарий или ответ на комментарий
+func (s *ReviewCommentService) CreateComment(reviewID primitive.ObjectID, req dto.CreateReviewCommentRequest, author string) (*dto.ReviewCommentResponse, error) {
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, ErrEmptyCommentText
+	}
+
+	review, err := s.getVisibleReview(reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Отвечают автору отзыва, а в ветке — автору родительского комментария
+	recipient := review.UserID
+
+	now := time.Now().UTC()
+	comment := &models.ReviewComment{
+		ReviewID:  reviewID,
+		UserID:    author,
+		Text:      text,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if req.ParentID != "" {
+		parentID, err := utils.ConvertStringToObjectID(req.ParentID)
+		if err != nil {
+			return nil, ErrInvalidParentCommentID
+		}
+
+		parent, err := s.commentRepo.GetCommentByID(parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ReviewID != reviewID {
+			return nil, ErrParentCommentMismatch
+		}
+		if parent.Deleted {
+			return nil, ErrCommentDeleted
+		}
+		if parent.Depth >= maxReviewCommentDepth {
+			return nil, ErrCommentDepthExceeded
+		}
+
+		comment.ParentID = &parent.ID
+		comment.Depth = parent.Depth + 1
+		recipient = parent.UserID
+	}
+
+	if err := s.commentRepo.CreateComment(comment); err != nil {
+		s.log.Warnf("Ошибка создания комментария: %v", err)
+		return nil, err
+	}
+
+	s.publishReply(review, comment, recipient)
+
+	response := toReviewCommentResponse(comment)
+	return &response, nil
+}
+
+// UpdateComment меняет текст комментария
+func (s *ReviewCommentService) UpdateComment(comment *models.ReviewComment, text string) (*dto.ReviewCommentResponse, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrEmptyCommentText
+	}
+	if comment.Deleted {
+		return nil, ErrCommentDeleted
+	}
+
+	if err := s.commentRepo.UpdateCommentText(comment.ID, text); err != nil {
+		s.log.Warnf("Ошибка обновления комментария: %v", err)
+		return nil, err
+	}
+
+	comment.Text = text
+	comment.UpdatedAt = time.Now().UTC()
+
+	response := toReviewCommentResponse(comment)
+	return &response, nil
+}
+
+// DeleteComment удаляет комментарий
+func (s *ReviewCommentService) DeleteComment(comment *models.ReviewComment) error {
+	if comment.Deleted {
+		return ErrCommentDeleted
+	}
+
+	if err := s.commentRepo.DeleteComment(comment); err != nil {
+		s.log.Warnf("Ошибка удаления комментария: %v", err)
+		return err
+	}
+	return nil
+}
+
+// publishReply сообщает о новом ответе; комментарии анонимизированных пользователей пропускаем
+func (s *ReviewCommentService) publishReply(review *models.Review, comment *models.ReviewComment, recipient string) {
+	recipientID, err := utils.ConvertStringToUUID(recipient)
+	if err != nil || recipientID == uuid.Nil {
+		return
+	}
+
+	event := events.Event{
+		Type:      events.ReviewReplied,
+		UserID:    recipientID,
+		ReviewID:  review.ID.Hex(),
+		CommentID: comment.ID.Hex(),
+	}
+	if comment.ParentID != nil {
+		event.Type = events.CommentReplied
+	}
+	if actorID, err := utils.ConvertStringToUUID(comment.UserID); err == nil {
+		event.ActorID = actorID
+	}
+	if bookID, err := utils.ConvertStringToUUID(review.BookID); err == nil {
+		event.BookID = &bookID
+	}
+	s.bus.Publish(event)
+}
+
+func toReviewCommentResponse(comment *models.ReviewComment) dto.ReviewCommentResponse {
+	response := dto.ReviewCommentResponse{
+		ID:           comment.ID.Hex(),
+		ReviewID:     comment.ReviewID.Hex(),
+		UserID:       comment.UserID,
+		Text:         comment.Text,
+		Depth:        comment.Depth,
+		RepliesCount: comment.RepliesCount,
+		Deleted:      comment.Deleted,
+		CreatedAt:    comment.CreatedAt,
+		UpdatedAt:    comment.UpdatedAt,
+	}
+	if comment.ParentID != nil {
+		parentID := comment.ParentID.Hex()
+		response.ParentID = &parentID
+	}
+	return response
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"book-management-system/config"
+	"book-management-system/internal/dto"
+	"book-management-system/internal/events"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"book-management-system/pkg/markup"
+	"book-management-system/pkg/utils"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"strconv"
+	"time"
+)
+
+// Действия модератора над отзывами для журнала moderator_actions
+const (
+	moderationTargetReview     = "review"
+	moderationActionApprove    = "approve_review"
+	moderationActionHide       = "hide_review"
+	moderationActionDelete     = "delete_review"
+	moderationActionRestore    = "restore_review_version"
+	defaultReportHideThreshold = 3
+)
+
+var (
+	ErrInvalidReportReason     = errors.New("недопустимая причина жалобы")
+	ErrAlreadyReported         = errors.New("жалоба на этот отзыв уже отправлена")
+	ErrCannotReportOwnReview   = errors.New("нельзя пожаловаться на свой отзыв")
+	ErrInvalidModerationStatus = errors.New("недопустимый статус модерации")
+	ErrReviewVersionNotFound   = errors.New("версия отзыва не найдена")
+	ErrReviewVersionIsCurrent  = errors.New("эта версия отзыва уже текущая")
+)
+
+// ReviewModerationService обрабатывает жалобы на отзывы и решения модераторов
+type ReviewModerationService struct {
+	reviewRepo    *repositories.ReviewRepository
+	reportRepo    *repositories.ReviewReportRepository
+	actionRepo    *repositories.ModeratorActionRepository
+	reviewService *ReviewService
+	hideThreshold int
+	log           *logger.Logger
+}
+
+// NewReviewModerationService создает сервис модерации отзывов.
+// Порог автоматического скрытия берется из REVIEW_REPORT_HIDE_THRESHOLD
+func NewReviewModerationService(
+	reviewRepo *repositories.ReviewRepository,
+	reportRepo *repositories.ReviewReportRepository,
+	actionRepo *repositories.ModeratorActionRepository,
+	reviewService *ReviewService,
+) *ReviewModerationService {
+	log := logger.GetLogger()
+
+	hideThreshold, err := strconv.Atoi(config.GetEnv("REVIEW_REPORT_HIDE_THRESHOLD", strconv.Itoa(defaultReportHideThreshold)))
+	if err != nil || hideThreshold <= 0 {
+		log.Warnf("Некорректный REVIEW_REPORT_HIDE_THRESHOLD, используем %d", defaultReportHideThreshold)
+		hideThreshold = defaultReportHideThreshold
+	}
+
+	return &ReviewModerationService{
+		reviewRepo:    reviewRepo,
+		reportRepo:    reportRepo,
+		actionRepo:    actionRepo,
+		reviewService: reviewService,
+		hideThreshold: hideThreshold,
+		log:           log,
+	}
+}
+
+// ReportReview сохраняет жалобу и скрывает отзыв, если набралось достаточно жалоб
+func (s *ReviewModerationService) ReportReview(reviewID primitive.ObjectID, reporter string, req dto.ReportReviewRequest) error {
+	reason := models.ReportReason(req.Reason)
+	if !reason.IsValid() {
+		return ErrInvalidReportReason
+	}
+
+	review, err := s.reviewRepo.GetReviewById(reviewID)
+	if err != nil {
+		return err
+	}
+	if review.DeletedAt != nil {
+		return mongo.ErrNoDocuments
+	}
+	if review.UserID == reporter {
+		return ErrCannotReportOwnReview
+	}
+
+	created, err := s.reportRepo.CreateReport(&models.ReviewReport{
+		ReviewID:  reviewID,
+		UserID:    reporter,
+		Reason:    reason,
+		Comment:   req.Comment,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	if !created {
+		return ErrAlreadyReported
+	}
+
+	review, err = s.reviewRepo.IncrementReportsCount(reviewID)
+	if err != nil {
+		return err
+	}
+
+	// Отзыв уже скрыт решением модератора — жалоба остается как доказательство
+	if review.ModerationStatus == models.ReviewModerationHidden {
+		return nil
+	}
+
+	hide := !review.Hidden && review.ReportsCount >= s.hideThreshold
+	if err := s.reviewRepo.MarkReviewReported(reviewID, hide); err != nil {
+		return err
+	}
+
+	if hide {
+		s.log.Infof("Отзыв %s скрыт до решения модератора: %d жалоб", reviewID.Hex(), review.ReportsCount)
+		return s.recalculateRating(review)
+	}
+
+	return nil
+}
+
+// GetQueue получает отзывы с заданным статусом модерации, по умолчанию — ожидающие решения
+func (s *ReviewModerationService) GetQueue(status string, limit int, afterID *primitive.ObjectID) (*dto.PaginatedModerationReviewsResponse, error) {
+	moderationStatus := models.ReviewModerationPending
+	if status != "" {
+		moderationStatus = models.ReviewModerationStatus(status)
+	}
+
+	switch moderationStatus {
+	case models.ReviewModerationPending, models.ReviewModerationApproved,
+		models.ReviewModerationHidden, models.ReviewModerationDeleted:
+	default:
+		return nil, ErrInvalidModerationStatus
+	}
+
+	reviews, err := s.reviewRepo.GetReviewsForModeration(moderationStatus, limit, afterID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewIDs := make([]primitive.ObjectID, len(reviews))
+	for i, review := range reviews {
+		reviewIDs[i] = review.ID
+	}
+
+	reasons, err := s.reportRepo.CountOpenReasons(reviewIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ModerationReviewResponse, len(reviews))
+	for i := range reviews {
+		responses[i] = toModerationReviewResponse(&reviews[i], reasons[reviews[i].ID])
+	}
+
+	var nextCursor *string
+	if len(reviews) > 0 {
+		last := reviews[len(reviews)-1].ID.Hex()
+		nextCursor = &last
+	}
+
+	return &dto.PaginatedModerationReviewsResponse{
+		Reviews:    responses,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// GetReviewDetails получает отзыв со всеми жалобами и историей решений модераторов
+func (s *ReviewModerationService) GetReviewDetails(reviewID primitive.ObjectID) (*dto.ReviewModerationDetailsResponse, error) {
+	review, err := s.reviewRepo.GetReviewById(reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := s.reportRepo.GetReportsByReview(reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := s.actionRepo.GetActionsByTarget(moderationTargetReview, reviewID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	openReasons := make(map[models.ReportReason]int)
+	reportResponses := make([]dto.ReviewReportResponse, len(reports))
+	for i, report := range reports {
+		if !report.Resolved {
+			openReasons[report.Reason]++
+		}
+		reportResponses[i] = dto.ReviewReportResponse{
+			ID:        report.ID.Hex(),
+			UserID:    report.UserID,
+			Reason:    string(report.Reason),
+			Comment:   report.Comment,
+			Resolved:  report.Resolved,
+			CreatedAt: report.CreatedAt,
+		}
+	}
+
+	actionResponses := make([]dto.ModeratorActionResponse, len(actions))
+	for i, action := range actions {
+		actionResponses[i] = dto.ModeratorActionResponse{
+			ModeratorID: utils.ConvertUUIDToString(action.ModeratorID),
+			Action:      action.Action,
+			Comment:     action.Comment,
+			CreatedAt:   action.CreatedAt,
+		}
+	}
+
+	return &dto.ReviewModerationDetailsResponse{
+		Review:  toModerationReviewResponse(review, openReasons),
+		Reports: reportResponses,
+		Actions: actionResponses,
+	}, nil
+}
+
+// ApproveReview оставляет отзыв видимым и закрывает жалобы на него
+func (s *ReviewModerationService) ApproveReview(reviewID primitive.ObjectID, moderatorID uuid.UUID, comment string) error {
+	return s.decide(reviewID, moderatorID, moderationActionApprove, models.ReviewModerationApproved, false, nil, comment)
+}
+
+// HideReview скрывает отзыв от читателей, сохраняя его для истории
+func (s *ReviewModerationService) HideReview(reviewID primitive.ObjectID, moderatorID uuid.UUID, comment string) error {
+	return s.decide(reviewID, moderatorID, moderationActionHide, models.ReviewModerationHidden, true, nil, comment)
+}
+
+// DeleteReview мягко удаляет отзыв: текст, жалобы и журнал остаются как доказательства
+func (s *ReviewModerationService) DeleteReview(reviewID primitive.ObjectID, moderatorID uuid.UUID, comment string) error {
+	now := time.Now().UTC()
+	return s.decide(reviewID, moderatorID, moderationActionDelete, models.ReviewModerationDeleted, true, &now, comment)
+}
+
+// RestoreReviewVersion возвращает отзыву текст и оценку версии number.
+// Текущее состояние при этом само становится версией, так что откат тоже можно откатить
+func (s *ReviewModerationService) RestoreReviewVersion(reviewID primitive.ObjectID, number int, moderatorID uuid.UUID, comment string) error {
+	review, err := s.reviewRepo.GetReviewById(reviewID)
+	if err != nil {
+		return err
+	}
+
+	history := reviewHistory(review)
+	index := reviewVersionIndex(review, number)
+	if index < 0 || index >= len(history) {
+		return ErrReviewVersionNotFound
+	}
+	if index == len(history)-1 {
+		return ErrReviewVersionIsCurrent
+	}
+
+	version := history[index]
+	// У старых версий оценка не сохранялась — оставляем текущую
+	rating := version.Rating
+	if rating == 0 {
+		rating = review.Rating
+	}
+
+	if err := s.reviewRepo.UpdateReview(reviewID, version.Text, rating, markup.Render(version.Text), utils.ConvertUUIDToString(moderatorID)); err != nil {
+		return err
+	}
+
+	actionComment := fmt.Sprintf("Восстановлена версия %d", number)
+	if comment != "" {
+		actionComment += ": " + comment
+	}
+	if err := s.actionRepo.CreateAction(&models.ModeratorAction{
+		ModeratorID: moderatorID,
+		Action:      moderationActionRestore,
+		TargetID:    reviewID.Hex(),
+		TargetType:  moderationTargetReview,
+		Comment:     actionComment,
+	}); err != nil {
+		return err
+	}
+
+	s.log.Infof("Модератор %s восстановил версию %d отзыва %s", moderatorID, number, reviewID.Hex())
+
+	if rating != review.Rating && review.IsVisible() {
+		return s.recalculateRating(review)
+	}
+	return nil
+}
+
+func (s *ReviewModerationService) decide(
+	reviewID primitive.ObjectID,
+	moderatorID uuid.UUID,
+	action string,
+	status models.ReviewModerationStatus,
+	hidden bool,
+	deletedAt *time.Time,
+	comment string,
+) error {
+	review, err := s.reviewRepo.GetReviewById(reviewID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.reviewRepo.ApplyModerationDecision(reviewID, status, hidden, deletedAt); err != nil {
+		return err
+	}
+
+	if err := s.reportRepo.ResolveReports(reviewID); err != nil {
+		return err
+	}
+
+	if err := s.actionRepo.CreateAction(&models.ModeratorAction{
+		ModeratorID: moderatorID,
+		Action:      action,
+		TargetID:    reviewID.Hex(),
+		TargetType:  moderationTargetReview,
+		Comment:     comment,
+	}); err != nil {
+		return err
+	}
+
+	s.log.Infof("Модератор %s: %s для отзыва %s", moderatorID, action, reviewID.Hex())
+
+	// Штраф к репутации автора один на отзыв: повторное скрытие или удаление скрытого отзыва его не удваивает,
+	// а одобрение снимает
+	wasPenalized, penalized := isPenaltyStatus(review.ModerationStatus), isPenaltyStatus(status)
+	if !wasPenalized && penalized {
+		if err := s.reviewService.reputationService.OnModerationPenalty(review.UserID); err != nil {
+			s.log.Warnf("Ошибка штрафа репутации автора отзыва %s: %v", reviewID.Hex(), err)
+		}
+		s.reviewService.publishReviewEvent(events.ReviewModerated, review, utils.ConvertUUIDToString(moderatorID))
+	} else if wasPenalized && !penalized {
+		if err := s.reviewService.reputationService.OnModerationPenaltyRevoked(review.UserID); err != nil {
+			s.log.Warnf("Ошибка возврата репутации автора отзыва %s: %v", reviewID.Hex(), err)
+		}
+	}
+
+	// Новый отзыв, задержанный фильтром, публикуется только сейчас
+	if review.Unpublished && !hidden && deletedAt == nil {
+		s.reviewService.announceReview(review)
+	}
+
+	// Видимость изменилась — отзыв вошел в рейтинг книги или выпал из него
+	if review.IsVisible() != (!hidden && deletedAt == nil) {
+		return s.recalculateRating(review)
+	}
+	return nil
+}
+
+// isPenaltyStatus проверяет, является ли статус решением модератора против автора отзыва
+func isPenaltyStatus(status models.ReviewModerationStatus) bool {
+	return status == models.ReviewModerationHidden || status == models.ReviewModerationDeleted
+}
+
+func (s *ReviewModerationService) recalculateRating(review *models.Review) error {
+	bookID, err := utils.ConvertStringToUUID(review.BookID)
+	if err != nil {
+		s.log.Warnf("Ошибка конвертации строки bookID %s в UUID: %v", review.BookID, err)
+		return err
+	}
+	return s.reviewService.refreshBookRating(bookID)
+}
+
+func toModerationReviewResponse(review *models.Review, reasons map[models.ReportReason]int) dto.ModerationReviewResponse {
+	reportReasons := make(map[string]int, len(reasons))
+	for reason, count := range reasons {
+		reportReasons[string(reason)] = count
+	}
+
+	return dto.ModerationReviewResponse{
+		ID:               review.ID.Hex(),
+		BookID:           review.BookID,
+		UserID:           review.UserID,
+		Text:             review.Text,
+		Rating:           review.Rating,
+		Hidden:           review.Hidden,
+		ModerationStatus: string(review.ModerationStatus),
+		ReportsCount:     review.ReportsCount,
+		ReportReasons:    reportReasons,
+		AutoFlags:        review.AutoFlags,
+		CreatedAt:        review.CreatedAt,
+		DeletedAt:        review.DeletedAt,
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"book-management-system/config"
+	"book-management-system/internal/models"
+	"book-management-system/pkg/logger"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRatingMinVotes = 10
+
+	// ratingPriorRefreshInterval как часто воркер outbox пересчитывает априорное среднее
+	ratingPriorRefreshInterval = time.Hour
+	// ratingPriorTolerance на сколько должно сдвинуться априорное среднее, чтобы пересчитать рейтинги всех книг
+	ratingPriorTolerance = 0.05
+)
+
+// ratingSettings настройки расчета рейтинга книги:
+//   - RATING_MIN_VOTES — сколько голосов «весит» априорное среднее в байесовской оценке (по умолчанию 10);
+//   - RATING_WEIGHT_BY_HELPFULNESS — учитывать лайки и дизлайки отзыва (по умолчанию true);
+//   - RATING_WEIGHT_BY_REPUTATION — учитывать репутацию автора отзыва (по умолчанию false)
+type ratingSettings struct {
+	minVotes            int
+	weightByHelpfulness bool
+	weightByReputation  bool
+}
+
+func loadRatingSettings(log *logger.Logger) ratingSettings {
+	settings := ratingSettings{minVotes: defaultRatingMinVotes, weightByHelpfulness: true}
+
+	if minVotes, err := strconv.Atoi(config.GetEnv("RATING_MIN_VOTES", strconv.Itoa(defaultRatingMinVotes))); err != nil || minVotes < 0 {
+		log.Warnf("Некорректный RATING_MIN_VOTES, используем %d", defaultRatingMinVotes)
+	} else {
+		settings.minVotes = minVotes
+	}
+
+	if value, err := strconv.ParseBool(config.GetEnv("RATING_WEIGHT_BY_HELPFULNESS", "true")); err == nil {
+		settings.weightByHelpfulness = value
+	} else {
+		log.Warnf("Некорректный RATING_WEIGHT_BY_HELPFULNESS, учитываем полезность отзывов")
+	}
+
+	if value, err := strconv.ParseBool(config.GetEnv("RATING_WEIGHT_BY_REPUTATION", "false")); err == nil {
+		settings.weightByReputation = value
+	} else {
+		log.Warnf("Некорректный RATING_WEIGHT_BY_REPUTATION, репутацию авторов не учитываем")
+	}
+
+	return settings
+}
+
+// bookRatingResult рассчитанный рейтинг книги
+type bookRatingResult struct {
+	average  float64 // простое среднее оценок
+	bayesian float64 // байесовская оценка с учетом весов отзывов
+	count    int
+}
+
+// calculateBookRating считает простое среднее и байесовскую оценку
+//
+//	bayesian = (minVotes * prior + n * weightedMean) / (minVotes + n)
+//
+// Веса отзывов влияют только на среднее: их сумма нормируется к числу отзывов, поэтому
+// книга с одним восторженным отзывом остается близко к prior, пока не наберет голоса
+func calculateBookRating(reviews []models.Review, prior float64, reputation map[string]int, settings ratingSettings) bookRatingResult {
+	if len(reviews) == 0 {
+		return bookRatingResult{}
+	}
+
+	var sum, weightedSum, totalWeight float64
+	for _, review := range reviews {
+		weight := 1.0
+		if settings.weightByHelpfulness {
+			weight *= helpfulnessWeight(review.Likes, review.Dislikes)
+		}
+		if settings.weightByReputation {
+			weight *= reputationWeight(reputation[review.UserID])
+		}
+
+		sum += float64(review.Rating)
+		weightedSum += weight * float64(review.Rating)
+		totalWeight += weight
+	}
+
+	n := float64(len(reviews))
+	weightedMean := weightedSum / totalWeight
+	minVotes := float64(settings.minVotes)
+
+	return bookRatingResult{
+		average:  sum / n,
+		bayesian: (minVotes*prior + n*weightedMean) / (minVotes + n),
+		count:    len(reviews),
+	}
+}
+
+// helpfulnessWeight доля полезных голосов со сглаживанием Лапласа, умноженная на 2:
+// отзыв без голосов весит 1, полезный — до 2, бесполезный — почти 0
+func helpfulnessWeight(likes, dislikes int) float64 {
+	return 2 * float64(likes+1) / float64(likes+dislikes+2)
+}
+
+// reputationWeight растет логарифмически, чтобы популярный автор не перевешивал всех остальных
+func reputationWeight(reputation int) float64 {
+	if reputation <= 0 {
+		return 1
+	}
+	return 1 + math.Log10(1+float64(reputation))
+}
+
+// ratingPrior кэш априорного среднего: считать его при каждом пересчете рейтинга значит сканировать все отзывы
+type ratingPrior struct {
+	mu     sync.Mutex
+	value  float64
+	loaded bool
+}
+
+// get возвращает закэшированное среднее и признак того, что оно уже загружено
+func (p *ratingPrior) get() (float64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.value, p.loaded
+}
+
+// set сохраняет свежее среднее
+func (p *ratingPrior) set(value float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.value, p.loaded = value, true
+}
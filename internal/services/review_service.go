@@ -2,60 +2,253 @@ package services
 
 import (
 	"book-management-system/internal/dto"
+	"book-management-system/internal/events"
 	"book-management-system/internal/models"
+	"book-management-system/internal/moderation"
 	"book-management-system/internal/repositories"
 	"book-management-system/pkg/logger"
+	"book-management-system/pkg/markup"
+	"book-management-system/pkg/textdiff"
 	"book-management-system/pkg/utils"
+	"errors"
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"math"
+	"time"
 )
 
+// ReviewTextFormat определяет, в каком виде отдавать текст отзыва
+type ReviewTextFormat string
+
+const (
+	ReviewTextSource ReviewTextFormat = "source"
+	ReviewTextHTML   ReviewTextFormat = "html"
+	ReviewTextBoth   ReviewTextFormat = "both"
+)
+
+var (
+	ErrInvalidReviewTextFormat = errors.New("недопустимый формат текста отзыва: ожидается source, html или both")
+	ErrInvalidReviewBookID     = errors.New("неверный идентификатор книги")
+	ErrReviewAlreadyExists     = errors.New("вы уже оставили отзыв на эту книгу")
+	ErrReviewHiddenByModerator = errors.New("отзыв скрыт модератором и не может быть изменен")
+)
+
+// ParseReviewTextFormat разбирает формат текста из query-параметра, по умолчанию отдаем оба вида
+func ParseReviewTextFormat(value string) (ReviewTextFormat, error) {
+	switch format := ReviewTextFormat(value); format {
+	case "":
+		return ReviewTextBoth, nil
+	case ReviewTextSource, ReviewTextHTML, ReviewTextBoth:
+		return format, nil
+	}
+	return "", ErrInvalidReviewTextFormat
+}
+
 type ReviewService struct {
-	reviewRepo *repositories.ReviewRepository
-	bookRepo   *repositories.BookRepository
-	log        *logger.Logger
+	reviewRepo     *repositories.ReviewRepository
+	bookRepo       *repositories.BookRepository
+	outboxRepo     *repositories.OutboxRepository
+	filter         *moderation.Filter
+	ratingSettings ratingSettings
+	ratingPrior    *ratingPrior
+	// reputationService учитывает голоса за отзывы в репутации авторов
+	reputationService *ReputationService
+	activityService   *ActivityService
+	bus               *events.Bus
+	log               *logger.Logger
 }
 
 // NewReviewService создает новый сервис
-func NewReviewService(reviewRepo *repositories.ReviewRepository, bookRepo *repositories.BookRepository) *ReviewService {
+func NewReviewService(
+	reviewRepo *repositories.ReviewRepository,
+	bookRepo *repositories.BookRepository,
+	outboxRepo *repositories.OutboxRepository,
+	reputationService *ReputationService,
+	activityService *ActivityService,
+) *ReviewService {
+	log := logger.GetLogger()
+
 	return &ReviewService{
-		reviewRepo: reviewRepo,
-		bookRepo:   bookRepo,
-		log:        logger.GetLogger(),
+		reviewRepo:        reviewRepo,
+		bookRepo:          bookRepo,
+		outboxRepo:        outboxRepo,
+		reputationService: reputationService,
+		activityService:   activityService,
+		bus:               events.GetBus(),
+		filter:            moderation.GetFilter(),
+		ratingSettings:    loadRatingSettings(log),
+		ratingPrior:       &ratingPrior{},
+		log:               log,
 	}
 }
 
-// CreateReview добавляет новый отзыв и пересчитывает рейтинг
-func (s *ReviewService) CreateReview(review dto.BaseReviewRequest, creator string) error {
-	err := s.reviewRepo.CreateReview(review, creator)
+// CreateReview добавляет новый отзыв на подтвержденную книгу и пересчитывает рейтинг
+func (s *ReviewService) CreateReview(review dto.BaseReviewRequest, creator string) (*models.Review, error) {
+	bookID, err := s.getReviewableBook(review.BookID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Индекс тоже не даст вставить дубль, но так ответ понятнее и не зависит от того, создан ли индекс
+	if _, err := s.reviewRepo.GetReviewByBookAndUser(review.BookID, creator); err == nil {
+		return nil, ErrReviewAlreadyExists
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		s.log.Warnf("Ошибка проверки повторного отзыва: %v", err)
+		return nil, err
+	}
+
+	verdict := s.filter.Check(review.Text)
+	if err := verdict.Err(); err != nil {
+		return nil, err
+	}
+	review.Text = verdict.Text
+
+	created, err := s.reviewRepo.CreateReview(review, creator, markup.Render(review.Text))
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrReviewAlreadyExists
+		}
 		s.log.Warnf("Ошибка создания отзыва: %v", err)
-		return err
+		return nil, err
+	}
+
+	if verdict.Flagged() {
+		// Отзыв на модерации подписчикам и внешним сервисам не показываем — о нем сообщит одобрение модератора
+		if err := s.flagReview(created, verdict, true); err != nil {
+			return nil, err
+		}
+	} else {
+		s.announceReview(created)
 	}
 
 	// Пересчитываем рейтинг книги
-	bookIdAsUUID, err := utils.ConvertStringToUUID(review.BookID)
+	return created, s.refreshBookRating(bookID)
+}
+
+// UpsertUserReview создает отзыв пользователя на книгу или обновляет уже существующий.
+// Второй результат сообщает, был ли отзыв создан
+func (s *ReviewService) UpsertUserReview(bookID string, userID string, text string, rating int) (*models.Review, bool, error) {
+	existing, err := s.reviewRepo.GetReviewByBookAndUser(bookID, userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		created, err := s.CreateReview(dto.BaseReviewRequest{BookID: bookID, Text: text, Rating: rating}, userID)
+		if errors.Is(err, ErrReviewAlreadyExists) {
+			// Параллельный запрос успел создать отзыв — просто обновляем его
+			return s.UpsertUserReview(bookID, userID, text, rating)
+		}
+		return created, err == nil, err
+	}
 	if err != nil {
-		s.log.Warnf("Ошибка конвертации строки bookID %s s в UUID: %v", review.BookID, err)
+		s.log.Warnf("Ошибка получения отзыва пользователя %s на книгу %s: %v", userID, bookID, err)
+		return nil, false, err
 	}
-	return s.RecalculateBookRating(bookIdAsUUID)
+
+	if !existing.IsVisible() {
+		return nil, false, ErrReviewHiddenByModerator
+	}
+
+	if err := s.UpdateReview(existing.ID, text, rating, userID); err != nil {
+		return nil, false, err
+	}
+
+	updated, err := s.reviewRepo.GetReviewById(existing.ID)
+	return updated, false, err
 }
 
+// announceReview записывает публикацию отзыва в ленту активности автора и сообщает о ней подписчикам событий
+func (s *ReviewService) announceReview(review *models.Review) {
+	authorID, err := utils.ConvertStringToUUID(review.UserID)
+	if err != nil || authorID == uuid.Nil {
+		return
+	}
+	bookID, err := utils.ConvertStringToUUID(review.BookID)
+	if err != nil {
+		return
+	}
+
+	s.activityService.RecordUserEvent(authorID, models.ActivityReviewPosted, bookID, review.ID.Hex())
+	s.bus.Publish(events.Event{Type: events.ReviewCreated, ActorID: authorID, BookID: &bookID, ReviewID: review.ID.Hex()})
+}
+
+// flagReview отправляет отзыв на модерацию по вердикту фильтра; unpublished — отзыв новый и о нем еще не сообщали
+func (s *ReviewService) flagReview(review *models.Review, verdict moderation.Verdict, unpublished bool) error {
+	if err := s.reviewRepo.FlagReview(review.ID, verdict.CategoryNames(), unpublished); err != nil {
+		return err
+	}
+	s.log.Infof("Отзыв %s отправлен на модерацию фильтром: %v", review.ID.Hex(), verdict.Categories)
+
+	review.Hidden = true
+	review.ModerationStatus = models.ReviewModerationPending
+	review.AutoFlags = append(review.AutoFlags, verdict.CategoryNames()...)
+	return nil
+}
+
+// getReviewableBook проверяет, что книга существует и подтверждена, и возвращает ее ID
+func (s *ReviewService) getReviewableBook(bookID string) (uuid.UUID, error) {
+	bookUUID, err := utils.ConvertStringToUUID(bookID)
+	if err != nil {
+		s.log.Warnf("Ошибка конвертации строки bookID %s в UUID: %v", bookID, err)
+		return uuid.Nil, ErrInvalidReviewBookID
+	}
+
+	if _, err := s.bookRepo.GetBookByID(bookUUID, true); err != nil {
+		return uuid.Nil, err
+	}
+	return bookUUID, nil
+}
+
+// GetReviewById получает отзыв по ID; скрытые и удаленные модератором отзывы считаются отсутствующими
 func (s *ReviewService) GetReviewById(reviewID primitive.ObjectID) (*models.Review, error) {
 	review, err := s.reviewRepo.GetReviewById(reviewID)
 	if err != nil {
 		s.log.Warnf("Ошибка получения review по ID %s : %v", reviewID.Hex(), err)
+		return nil, err
+	}
 
+	if !review.IsVisible() {
+		return nil, mongo.ErrNoDocuments
 	}
 
 	return review, nil
 }
 
+// VoteReview сохраняет голос за отзыв и меняет репутацию автора.
+// Голоса меняют вес отзыва, поэтому пересчитываем рейтинг книги.
+// За скрытые и удаленные отзывы голосовать нельзя: их никто не видит
 func (s *ReviewService) VoteReview(reviewID primitive.ObjectID, userID string, vote int) error {
-	return s.reviewRepo.VoteReview(reviewID, userID, vote)
+	review, err := s.GetReviewById(reviewID)
+	if err != nil {
+		return err
+	}
+
+	previousVote, err := s.reviewRepo.VoteReview(reviewID, userID, vote)
+	if err != nil {
+		return err
+	}
+
+	// Голос за собственный отзыв репутацию не накручивает
+	if review.UserID != userID {
+		if err := s.reputationService.OnReviewVoteChanged(review.UserID, previousVote, vote); err != nil {
+			s.log.Warnf("Ошибка обновления репутации автора отзыва %s: %v", reviewID.Hex(), err)
+		}
+		if vote == 1 && previousVote != 1 {
+			s.publishReviewEvent(events.ReviewLiked, review, userID)
+		}
+	}
+
+	if !s.ratingSettings.weightByHelpfulness && !s.ratingSettings.weightByReputation {
+		return nil
+	}
+
+	bookID, err := utils.ConvertStringToUUID(review.BookID)
+	if err != nil {
+		s.log.Warnf("Ошибка конвертации строки bookID %s в UUID: %v", review.BookID, err)
+		return err
+	}
+	return s.refreshBookRating(bookID)
 }
 
-// UpdateReview обновляет отзыв, если изменился рейтинг — пересчитываем средний
+// UpdateReview обновляет текст и оценку отзыва, если изменилась оценка — пересчитываем средний
 func (s *ReviewService) UpdateReview(reviewID primitive.ObjectID, updatedText string, updatedRating int, editor string) error {
 	existingReview, err := s.reviewRepo.GetReviewById(reviewID)
 	if err != nil {
@@ -63,15 +256,29 @@ func (s *ReviewService) UpdateReview(reviewID primitive.ObjectID, updatedText st
 		return err
 	}
 
+	verdict := s.filter.Check(updatedText)
+	if err := verdict.Err(); err != nil {
+		return err
+	}
+	updatedText = verdict.Text
+
 	// Проверяем, изменился ли рейтинг
 	shouldRecalculate := existingReview.Rating != updatedRating
 
-	err = s.reviewRepo.UpdateReviewText(reviewID, updatedText, editor)
+	err = s.reviewRepo.UpdateReview(reviewID, updatedText, updatedRating, markup.Render(updatedText), editor)
 	if err != nil {
 		s.log.Warnf("Ошибка обновления отзыва: %v", err)
 		return err
 	}
 
+	if verdict.Flagged() {
+		// Отзыв скрывается до решения модератора и больше не участвует в рейтинге
+		shouldRecalculate = shouldRecalculate || existingReview.IsVisible()
+		if err := s.flagReview(existingReview, verdict, false); err != nil {
+			return err
+		}
+	}
+
 	// Если рейтинг изменился, пересчитываем
 	if shouldRecalculate {
 		bookIdAsUUID, err := utils.ConvertStringToUUID(existingReview.BookID)
@@ -79,12 +286,47 @@ func (s *ReviewService) UpdateReview(reviewID primitive.ObjectID, updatedText st
 			s.log.Warnf("Ошибка конвертации строки bookID %s s в UUID: %v", existingReview.BookID, err)
 		}
 
-		return s.RecalculateBookRating(bookIdAsUUID)
+		return s.refreshBookRating(bookIdAsUUID)
 	}
 
 	return nil
 }
 
+// GetReviewVersions получает историю правок отзыва с построчными отличиями каждой версии от предыдущей
+func (s *ReviewService) GetReviewVersions(reviewID primitive.ObjectID) (*dto.ReviewVersionsResponse, error) {
+	review, err := s.GetReviewById(reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := reviewHistory(review)
+	versions := make([]dto.ReviewVersionResponse, len(history))
+	previousText := ""
+	for i, version := range history {
+		diff := textdiff.Lines(previousText, version.Text)
+		diffLines := make([]dto.ReviewDiffLine, len(diff))
+		for j, line := range diff {
+			diffLines[j] = dto.ReviewDiffLine{Op: string(line.Op), Text: line.Text}
+		}
+
+		versions[i] = dto.ReviewVersionResponse{
+			Number:   review.VersionsTrimmed + i + 1,
+			Text:     version.Text,
+			EditedBy: version.EditedBy,
+			EditedAt: version.EditedAt.UTC().Format(time.RFC3339),
+			Current:  i == len(history)-1,
+			Diff:     diffLines,
+		}
+		if version.Rating != 0 {
+			rating := version.Rating
+			versions[i].Rating = &rating
+		}
+		previousText = version.Text
+	}
+
+	return &dto.ReviewVersionsResponse{ReviewID: reviewID.Hex(), Versions: versions}, nil
+}
+
 // DeleteReviewByID удаляет отзыв и пересчитывает рейтинг
 func (s *ReviewService) DeleteReviewByID(reviewID primitive.ObjectID) error {
 	review, err := s.reviewRepo.GetReviewById(reviewID)
@@ -105,21 +347,123 @@ func (s *ReviewService) DeleteReviewByID(reviewID primitive.ObjectID) error {
 	}
 
 	// Пересчитываем рейтинг книги
-	return s.RecalculateBookRating(bookIdAsUUID)
+	return s.refreshBookRating(bookIdAsUUID)
 }
 
-// RecalculateBookRating пересчитывает средний рейтинг книги
+// refreshBookRating пересчитывает рейтинг книги после изменения отзыва. Отзыв в MongoDB уже изменен,
+// поэтому неудачный пересчет не отменяет запрос, а ставится в outbox и будет повторен воркером
+func (s *ReviewService) refreshBookRating(bookID uuid.UUID) error {
+	err := s.RecalculateBookRating(bookID)
+	if err == nil {
+		return nil
+	}
+	return s.outboxRepo.Enqueue(models.OutboxRecalculateBookRating, bookID)
+}
+
+// RecalculateBookRating пересчитывает простой средний и байесовский рейтинги книги
 func (s *ReviewService) RecalculateBookRating(bookID uuid.UUID) error {
+	prior, err := s.getRatingPrior()
+	if err != nil {
+		return err
+	}
+	return s.recalculateBookRating(bookID, prior)
+}
+
+// RecalculateBookRatings пересчитывает рейтинги книг с одним свежим априорным средним
+// и возвращает книги, рейтинг которых пересчитать не удалось
+func (s *ReviewService) RecalculateBookRatings(bookIDs []uuid.UUID) ([]uuid.UUID, error) {
+	prior, err := s.reviewRepo.CalculateGlobalAverageRating()
+	if err != nil {
+		s.log.Warnf("Ошибка агрегации общего рейтинга: %v", err)
+		return nil, err
+	}
+	s.ratingPrior.set(prior)
+
+	failed := []uuid.UUID{}
+	for _, bookID := range bookIDs {
+		if err := s.recalculateBookRating(bookID, prior); err != nil {
+			failed = append(failed, bookID)
+		}
+	}
+	return failed, nil
+}
+
+// RefreshRatingPrior пересчитывает априорное среднее. Байесовский рейтинг каждой книги зависит от него,
+// поэтому если среднее сдвинулось больше чем на ratingPriorTolerance, пересчитываются все книги с отзывами
+func (s *ReviewService) RefreshRatingPrior() error {
+	prior, err := s.reviewRepo.CalculateGlobalAverageRating()
+	if err != nil {
+		s.log.Warnf("Ошибка агрегации общего рейтинга: %v", err)
+		return err
+	}
+
+	previous, loaded := s.ratingPrior.get()
+	if loaded && math.Abs(prior-previous) < ratingPriorTolerance {
+		return nil
+	}
+	s.ratingPrior.set(prior)
+	if !loaded {
+		return nil
+	}
+
+	bookIDs, err := s.bookRepo.GetRatedBookIDs()
+	if err != nil {
+		return err
+	}
+	s.log.Infof("Априорное среднее рейтинга сдвинулось с %.2f до %.2f, пересчитываем %d книг", previous, prior, len(bookIDs))
+
+	failed, err := s.RecalculateBookRatings(bookIDs)
+	if err != nil {
+		return err
+	}
+	// Неудавшиеся пересчеты доведет outbox
+	for _, bookID := range failed {
+		if err := s.outboxRepo.Enqueue(models.OutboxRecalculateBookRating, bookID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getRatingPrior возвращает закэшированное априорное среднее, при первом обращении считает его
+func (s *ReviewService) getRatingPrior() (float64, error) {
+	if prior, loaded := s.ratingPrior.get(); loaded {
+		return prior, nil
+	}
+
+	prior, err := s.reviewRepo.CalculateGlobalAverageRating()
+	if err != nil {
+		s.log.Warnf("Ошибка агрегации общего рейтинга: %v", err)
+		return 0, err
+	}
+	s.ratingPrior.set(prior)
+	return prior, nil
+}
+
+func (s *ReviewService) recalculateBookRating(bookID uuid.UUID, prior float64) error {
 	bookIdStringified := utils.ConvertUUIDToString(bookID)
 
-	averageRating, err := s.reviewRepo.CalculateAverageRating(bookIdStringified)
+	reviews, err := s.reviewRepo.GetBookRatingInputs(bookIdStringified)
 	if err != nil {
-		s.log.Warnf("Ошибка агрегации рейтинга: %v", err)
+		s.log.Warnf("Ошибка получения оценок книги: %v", err)
 		return err
 	}
 
-	// Обновляем средний рейтинг в PostgreSQL
-	err = s.bookRepo.UpdateBookRating(bookID, averageRating)
+	var reputation map[string]int
+	if s.ratingSettings.weightByReputation && len(reviews) > 0 {
+		userIDs := make([]string, len(reviews))
+		for i, review := range reviews {
+			userIDs[i] = review.UserID
+		}
+		if reputation, err = s.reputationService.GetScores(userIDs); err != nil {
+			return err
+		}
+	}
+
+	rating := calculateBookRating(reviews, prior, reputation, s.ratingSettings)
+
+	// Обновляем рейтинги в PostgreSQL
+	err = s.bookRepo.UpdateBookRating(bookID, rating.average, rating.bayesian, rating.count)
 	if err != nil {
 		s.log.Warnf("Ошибка обновления среднего рейтинга: %v", err)
 		return err
@@ -127,3 +471,81 @@ func (s *ReviewService) RecalculateBookRating(bookID uuid.UUID) error {
 
 	return nil
 }
+
+// publishReviewEvent сообщает автору отзыва о событии; анонимизированным отзывам сообщать некому
+func (s *ReviewService) publishReviewEvent(eventType events.Type, review *models.Review, actor string) {
+	authorID, err := utils.ConvertStringToUUID(review.UserID)
+	if err != nil || authorID == uuid.Nil {
+		return
+	}
+
+	event := events.Event{Type: eventType, UserID: authorID, ReviewID: review.ID.Hex()}
+	if actorID, err := utils.ConvertStringToUUID(actor); err == nil {
+		event.ActorID = actorID
+	}
+	if bookID, err := utils.ConvertStringToUUID(review.BookID); err == nil {
+		event.BookID = &bookID
+	}
+	s.bus.Publish(event)
+}
+
+// reviewHistory возвращает сохраненные версии отзыва от старых к текущей; текущая — последняя.
+// Версия с индексом i имеет номер review.VersionsTrimmed+i+1
+func reviewHistory(review *models.Review) []models.ReviewVersion {
+	editedBy := review.EditedBy
+	if editedBy == "" {
+		editedBy = review.UserID
+	}
+
+	history := make([]models.ReviewVersion, 0, len(review.Versions)+1)
+	history = append(history, review.Versions...)
+	return append(history, models.ReviewVersion{
+		Text:     review.Text,
+		Rating:   review.Rating,
+		EditedAt: review.UpdatedAt,
+		EditedBy: editedBy,
+	})
+}
+
+// reviewVersionIndex переводит номер версии из API в индекс в reviewHistory. Номера учитывают версии,
+// удаленные из-за ограничения длины истории; у удаленных версий индекс отрицательный
+func reviewVersionIndex(review *models.Review, number int) int {
+	return number - review.VersionsTrimmed - 1
+}
+
+// ToReviewResponse собирает ответ API с текстом отзыва в нужном формате
+func ToReviewResponse(review *models.Review, format ReviewTextFormat) dto.ReviewResponse {
+	textHTML, hasSpoilers := review.TextHTML, review.HasSpoilers
+	// Отзывы, созданные до появления разметки, рендерим на лету
+	if textHTML == "" && review.Text != "" {
+		rendered := markup.Render(review.Text)
+		textHTML, hasSpoilers = rendered.HTML, rendered.HasSpoilers
+	}
+
+	response := dto.ReviewResponse{
+		ID:              review.ID.Hex(),
+		BookID:          review.BookID,
+		HasSpoilers:     hasSpoilers,
+		Rating:          review.Rating,
+		UserID:          review.UserID,
+		Likes:           review.Likes,
+		Dislikes:        review.Dislikes,
+		CommentsCount:   review.CommentsCount,
+		UnderModeration: review.Hidden && review.ModerationStatus == models.ReviewModerationPending,
+		CreatedAt:       review.CreatedAt.UTC().Format(time.RFC3339),
+	}
+
+	if format != ReviewTextHTML {
+		response.Text = &review.Text
+	}
+	if format != ReviewTextSource {
+		response.TextHTML = &textHTML
+	}
+
+	if !review.UpdatedAt.IsZero() && !review.UpdatedAt.Equal(review.CreatedAt) {
+		updatedAt := review.UpdatedAt.UTC().Format(time.RFC3339)
+		response.UpdatedAt = &updatedAt
+	}
+
+	return response
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"book-management-system/internal/models"
+	"testing"
+	"time"
+)
+
+func TestReviewHistoryNumbering(t *testing.T) {
+	edited := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	review := &models.Review{
+		UserID:    "author",
+		Text:      "текущий",
+		Rating:    8,
+		UpdatedAt: edited,
+		Versions: []models.ReviewVersion{
+			{Text: "третья", Rating: 6, EditedBy: "author"},
+			{Text: "четвертая", Rating: 7, EditedBy: "moderator"},
+		},
+		VersionsTrimmed: 2,
+	}
+
+	history := reviewHistory(review)
+	if len(history) != 3 {
+		t.Fatalf("len(history) = %d, want 3", len(history))
+	}
+	if current := history[2]; current.Text != "текущий" || current.EditedBy != "author" || !current.EditedAt.Equal(edited) {
+		t.Errorf("текущая версия = %+v", current)
+	}
+
+	tests := []struct {
+		number int
+		index  int
+		text   string
+	}{
+		{1, -1, ""},
+		{2, -1, ""},
+		{3, 0, "третья"},
+		{4, 1, "четвертая"},
+		{5, 2, "текущий"},
+		{6, 3, ""},
+	}
+
+	for _, tt := range tests {
+		index := reviewVersionIndex(review, tt.number)
+		if index != tt.index && !(tt.index < 0 && index < 0) {
+			t.Errorf("reviewVersionIndex(%d) = %d, want %d", tt.number, index, tt.index)
+		}
+		if index >= 0 && index < len(history) && history[index].Text != tt.text {
+			t.Errorf("версия %d = %q, want %q", tt.number, history[index].Text, tt.text)
+		}
+	}
+}
@@ -5,19 +5,34 @@ import (
 	"book-management-system/internal/models"
 	"book-management-system/internal/repositories"
 	"book-management-system/pkg/logger"
+	"errors"
 	"github.com/google/uuid"
+	"math"
+	"time"
+)
+
+var (
+	ErrInvalidRating           = errors.New("оценка должна быть от 1 до 10")
+	ErrInvalidReadThroughDates = errors.New("дата окончания прочтения раньше даты начала")
+	ErrInvalidReadingStatus    = errors.New("недопустимый статус чтения")
+	ErrInvalidProgressUnit     = errors.New("недопустимая единица прогресса")
+	ErrInvalidProgress         = errors.New("некорректное значение прогресса чтения")
 )
 
 type UserBookService struct {
-	repo *repositories.UserBookRepository
-	log  *logger.Logger
+	repo            *repositories.UserBookRepository
+	bookRepo        *repositories.BookRepository
+	activityService *ActivityService
+	log             *logger.Logger
 }
 
 // NewUserBookService создает новый сервис
-func NewUserBookService(repo *repositories.UserBookRepository) *UserBookService {
+func NewUserBookService(repo *repositories.UserBookRepository, bookRepo *repositories.BookRepository, activityService *ActivityService) *UserBookService {
 	return &UserBookService{
-		repo: repo,
-		log:  logger.GetLogger(),
+		repo:            repo,
+		bookRepo:        bookRepo,
+		activityService: activityService,
+		log:             logger.GetLogger(),
 	}
 }
 
@@ -28,17 +43,53 @@ func (s *UserBookService) AddBookToUser(userID, bookID uuid.UUID) error {
 		s.log.Warnf("Ошибка добавления книги пользователю: %v", err)
 		return err
 	}
+
+	s.activityService.RecordUserEvent(userID, models.ActivityBookAdded, bookID, "")
 	return nil
 }
 
-// UpdateReadingProgress обновляет статус и прогресс чтения
-func (s *UserBookService) UpdateReadingProgress(userID, bookID uuid.UUID, status models.ReadingStatus, pagesRead int) error {
-	err := s.repo.UpdateReadingProgress(userID, bookID, status, pagesRead)
+// UpdateReadingProgress проверяет и обновляет статус и прогресс чтения.
+// Страницы ограничиваются объемом книги, а при достижении конца книга считается прочитанной
+func (s *UserBookService) UpdateReadingProgress(userID, bookID uuid.UUID, req dto.UpdateReadingProgressRequest) (*dto.UserBookResponse, error) {
+	if err := validateRating(req.Rating); err != nil {
+		return nil, err
+	}
+
+	userBook, err := s.repo.GetUserBook(userID, bookID)
 	if err != nil {
+		s.log.Warnf("Ошибка получения книги из списка пользователя: %v", err)
+		return nil, err
+	}
+
+	// Изданий у книг пока нет, поэтому объем берем из самой книги
+	book, err := s.bookRepo.GetBookByID(bookID, false)
+	if err != nil {
+		s.log.Warnf("Ошибка получения книги для проверки прогресса: %v", err)
+		return nil, err
+	}
+
+	progress, err := resolveReadingProgress(req, userBook, book.PageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateReadingProgress(userID, bookID, progress); err != nil {
 		s.log.Warnf("Ошибка обновления прогресса чтения: %v", err)
-		return err
+		return nil, err
 	}
-	return nil
+
+	if userBook.Status != models.StatusCompleted && progress.Status == models.StatusCompleted {
+		s.activityService.RecordUserEvent(userID, models.ActivityBookFinished, bookID, "")
+	}
+
+	updated, err := s.repo.GetUserBook(userID, bookID)
+	if err != nil {
+		s.log.Warnf("Ошибка получения обновленного прогресса: %v", err)
+		return nil, err
+	}
+
+	response := toUserBookResponse(updated)
+	return &response, nil
 }
 
 // RemoveBookFromUser удаляет книгу из списка пользователя
@@ -61,15 +112,220 @@ func (s *UserBookService) GetUserBooks(userID uuid.UUID) ([]dto.UserBookResponse
 
 	bookResponses := make([]dto.UserBookResponse, 0, len(userBooks))
 
-	for _, book := range userBooks {
-		bookResponses = append(bookResponses, dto.UserBookResponse{
-			BookID:    book.BookID,
-			Status:    string(book.Status),
-			PagesRead: book.PagesRead,
-			CreatedAt: book.CreatedAt,
-			UpdatedAt: book.UpdatedAt,
-		})
+	for i := range userBooks {
+		bookResponses = append(bookResponses, toUserBookResponse(&userBooks[i]))
 	}
 
 	return bookResponses, nil
 }
+
+// GetReadingHistory получает историю прочтений книги
+func (s *UserBookService) GetReadingHistory(userID, bookID uuid.UUID) ([]dto.ReadThroughResponse, error) {
+	// Проверяем, что книга вообще есть в списке, чтобы отличать "нет истории" от "нет книги"
+	if _, err := s.repo.GetUserBook(userID, bookID); err != nil {
+		s.log.Warnf("Ошибка получения книги из списка пользователя: %v", err)
+		return nil, err
+	}
+
+	readThroughs, err := s.repo.GetReadThroughs(userID, bookID)
+	if err != nil {
+		s.log.Warnf("Ошибка получения истории прочтений: %v", err)
+		return nil, err
+	}
+
+	history := make([]dto.ReadThroughResponse, len(readThroughs))
+	for i := range readThroughs {
+		history[i] = toReadThroughResponse(&readThroughs[i])
+	}
+
+	return history, nil
+}
+
+// StartReread начинает повторное прочтение книги
+func (s *UserBookService) StartReread(userID, bookID uuid.UUID, startedAt *time.Time) (*dto.ReadThroughResponse, error) {
+	start := time.Now().UTC()
+	if startedAt != nil {
+		start = startedAt.UTC()
+	}
+
+	readThrough, err := s.repo.StartReread(userID, bookID, start)
+	if err != nil {
+		s.log.Warnf("Ошибка начала повторного прочтения: %v", err)
+		return nil, err
+	}
+
+	response := toReadThroughResponse(readThrough)
+	return &response, nil
+}
+
+// UpdateReadThrough правит даты и оценку прочтения из истории
+func (s *UserBookService) UpdateReadThrough(userID, bookID, readThroughID uuid.UUID, req dto.UpdateReadThroughRequest) (*dto.ReadThroughResponse, error) {
+	if err := validateRating(req.Rating); err != nil {
+		return nil, err
+	}
+
+	readThrough, err := s.repo.GetReadThrough(userID, bookID, readThroughID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.StartedAt != nil {
+		readThrough.StartedAt = req.StartedAt.UTC()
+	}
+	// Дату окончания можно поправить только у закрытого прочтения — закрывает его смена статуса
+	if req.FinishedAt != nil && readThrough.FinishedAt != nil {
+		finishedAt := req.FinishedAt.UTC()
+		readThrough.FinishedAt = &finishedAt
+	}
+	if req.Rating != nil {
+		readThrough.Rating = req.Rating
+	}
+
+	if readThrough.FinishedAt != nil && readThrough.FinishedAt.Before(readThrough.StartedAt) {
+		return nil, ErrInvalidReadThroughDates
+	}
+
+	if err := s.repo.UpdateReadThrough(readThrough); err != nil {
+		s.log.Warnf("Ошибка обновления прочтения: %v", err)
+		return nil, err
+	}
+
+	response := toReadThroughResponse(readThrough)
+	return &response, nil
+}
+
+// GetStats получает статистику чтения пользователя
+func (s *UserBookService) GetStats(userID uuid.UUID) (*dto.UserBookStatsResponse, error) {
+	stats, err := s.repo.GetUserBookStats(userID)
+	if err != nil {
+		s.log.Warnf("Ошибка получения статистики чтения: %v", err)
+		return nil, err
+	}
+
+	return &dto.UserBookStatsResponse{
+		TotalBooks:  stats.TotalBooks,
+		Reading:     stats.Reading,
+		Completed:   stats.Completed,
+		Dropped:     stats.Dropped,
+		TotalReads:  stats.TotalReads,
+		Rereads:     stats.Rereads,
+		RereadBooks: stats.RereadBooks,
+	}, nil
+}
+
+func validateRating(rating *int) error {
+	if rating != nil && (*rating < 1 || *rating > 10) {
+		return ErrInvalidRating
+	}
+	return nil
+}
+
+func toReadThroughResponse(readThrough *models.ReadThrough) dto.ReadThroughResponse {
+	return dto.ReadThroughResponse{
+		ID:         readThrough.ID,
+		BookID:     readThrough.BookID,
+		Status:     string(readThrough.Status),
+		StartedAt:  readThrough.StartedAt,
+		FinishedAt: readThrough.FinishedAt,
+		Rating:     readThrough.Rating,
+	}
+}
+
+// resolveReadingProgress переводит прогресс из единиц запроса в страницы и проценты.
+// pageCount == 0 означает, что объем книги неизвестен и процент по страницам не считается
+func resolveReadingProgress(req dto.UpdateReadingProgressRequest, userBook *models.UserBook, pageCount int) (repositories.ReadingProgressUpdate, error) {
+	status := models.ReadingStatus(req.Status)
+	if !status.IsValid() {
+		return repositories.ReadingProgressUpdate{}, ErrInvalidReadingStatus
+	}
+
+	unit := models.ProgressUnit(req.Unit)
+	if unit == "" {
+		unit = models.ProgressUnitPages
+	}
+	if !unit.IsValid() {
+		return repositories.ReadingProgressUpdate{}, ErrInvalidProgressUnit
+	}
+
+	progress := repositories.ReadingProgressUpdate{
+		Status:         status,
+		Unit:           unit,
+		TotalLocations: userBook.TotalLocations,
+		Rating:         req.Rating,
+	}
+
+	var percent *float64
+
+	switch unit {
+	case models.ProgressUnitPages:
+		if req.PagesRead < 0 {
+			return repositories.ReadingProgressUpdate{}, ErrInvalidProgress
+		}
+		progress.PagesRead = req.PagesRead
+		if pageCount > 0 {
+			progress.PagesRead = min(req.PagesRead, pageCount)
+			percent = progressPercent(progress.PagesRead, pageCount)
+		}
+	case models.ProgressUnitPercent:
+		if req.Percent == nil || *req.Percent < 0 || *req.Percent > 100 {
+			return repositories.ReadingProgressUpdate{}, ErrInvalidProgress
+		}
+		value := *req.Percent
+		percent = &value
+	case models.ProgressUnitLocation:
+		if req.TotalLocations != nil {
+			progress.TotalLocations = req.TotalLocations
+		}
+		if req.Location == nil || *req.Location < 0 ||
+			progress.TotalLocations == nil || *progress.TotalLocations <= 0 {
+			return repositories.ReadingProgressUpdate{}, ErrInvalidProgress
+		}
+		location := min(*req.Location, *progress.TotalLocations)
+		progress.Location = &location
+		percent = progressPercent(location, *progress.TotalLocations)
+	}
+
+	// Дошли до последней страницы — книга прочитана
+	if status == models.StatusReading && percent != nil && *percent >= 100 {
+		progress.Status = models.StatusCompleted
+	}
+
+	if progress.Status == models.StatusCompleted {
+		full := 100.0
+		percent = &full
+		if progress.Location != nil {
+			progress.Location = progress.TotalLocations
+		}
+	}
+
+	// Процент в других единицах пересчитываем в страницы, если объем известен
+	if unit != models.ProgressUnitPages || progress.Status == models.StatusCompleted {
+		if pageCount > 0 && percent != nil {
+			progress.PagesRead = int(math.Round(*percent * float64(pageCount) / 100))
+		}
+	}
+
+	progress.ProgressPercent = percent
+	return progress, nil
+}
+
+// progressPercent считает процент прочитанного с точностью до десятых
+func progressPercent(done, total int) *float64 {
+	percent := math.Round(float64(done)*1000/float64(total)) / 10
+	return &percent
+}
+
+func toUserBookResponse(userBook *models.UserBook) dto.UserBookResponse {
+	return dto.UserBookResponse{
+		BookID:          userBook.BookID,
+		Status:          string(userBook.Status),
+		PagesRead:       userBook.PagesRead,
+		ProgressUnit:    string(userBook.ProgressUnit),
+		ProgressPercent: userBook.ProgressPercent,
+		Location:        userBook.Location,
+		TotalLocations:  userBook.TotalLocations,
+		ReadCount:       userBook.ReadCount,
+		CreatedAt:       userBook.CreatedAt,
+		UpdatedAt:       userBook.UpdatedAt,
+	}
+}
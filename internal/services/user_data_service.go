@@ -0,0 +1,350 @@
+package services
+
+import (
+	"archive/zip"
+	"book-management-system/internal/dto"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"book-management-system/pkg/utils"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+	"io"
+	"time"
+)
+
+// UserDataService собирает и стирает персональные данные пользователя из PostgreSQL и MongoDB
+type UserDataService struct {
+	userRepo            *repositories.UserRepository
+	userBookRepo        *repositories.UserBookRepository
+	bookRatingRepo      *repositories.BookRatingRepository
+	bookNoteRepo        *repositories.BookNoteRepository
+	refreshTokenRepo    *repositories.RefreshTokenRepository
+	reviewRepo          *repositories.ReviewRepository
+	reviewCommentRepo   *repositories.ReviewCommentRepository
+	reviewReportRepo    *repositories.ReviewReportRepository
+	moderatorActionRepo *repositories.ModeratorActionRepository
+	feedbackRepo        *repositories.FeedbackRepository
+	userReputationRepo  *repositories.UserReputationRepository
+	followRepo          *repositories.FollowRepository
+	activityEventRepo   *repositories.ActivityEventRepository
+	notificationRepo    *repositories.NotificationRepository
+	outboxRepo          *repositories.OutboxRepository
+	// reputationService снимает с авторов отзывов репутацию, начисленную голосами удаляемого пользователя
+	reputationService *ReputationService
+	log               *logger.Logger
+}
+
+// NewUserDataService создает сервис работы с персональными данными
+func NewUserDataService(
+	userRepo *repositories.UserRepository,
+	userBookRepo *repositories.UserBookRepository,
+	bookRatingRepo *repositories.BookRatingRepository,
+	bookNoteRepo *repositories.BookNoteRepository,
+	refreshTokenRepo *repositories.RefreshTokenRepository,
+	reviewRepo *repositories.ReviewRepository,
+	reviewCommentRepo *repositories.ReviewCommentRepository,
+	reviewReportRepo *repositories.ReviewReportRepository,
+	moderatorActionRepo *repositories.ModeratorActionRepository,
+	feedbackRepo *repositories.FeedbackRepository,
+	userReputationRepo *repositories.UserReputationRepository,
+	followRepo *repositories.FollowRepository,
+	activityEventRepo *repositories.ActivityEventRepository,
+	notificationRepo *repositories.NotificationRepository,
+	outboxRepo *repositories.OutboxRepository,
+	reputationService *ReputationService,
+) *UserDataService {
+	return &UserDataService{
+		userRepo:            userRepo,
+		userBookRepo:        userBookRepo,
+		bookRatingRepo:      bookRatingRepo,
+		bookNoteRepo:        bookNoteRepo,
+		refreshTokenRepo:    refreshTokenRepo,
+		reviewRepo:          reviewRepo,
+		reviewCommentRepo:   reviewCommentRepo,
+		reviewReportRepo:    reviewReportRepo,
+		moderatorActionRepo: moderatorActionRepo,
+		feedbackRepo:        feedbackRepo,
+		userReputationRepo:  userReputationRepo,
+		followRepo:          followRepo,
+		activityEventRepo:   activityEventRepo,
+		notificationRepo:    notificationRepo,
+		outboxRepo:          outboxRepo,
+		reputationService:   reputationService,
+		log:                 logger.GetLogger(),
+	}
+}
+
+// ExportUserData пишет в w ZIP-архив со всеми данными, связанными с пользователем
+func (s *UserDataService) ExportUserData(userID uuid.UUID, w io.Writer) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		s.log.Warnf("Ошибка получения пользователя %s для выгрузки: %v", userID, err)
+		return err
+	}
+
+	stringUserID := utils.ConvertUUIDToString(userID)
+
+	userBooks, err := s.userBookRepo.GetUserBooks(userID)
+	if err != nil {
+		return err
+	}
+
+	readThroughs, err := s.userBookRepo.GetReadThroughsByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	ratings, err := s.bookRatingRepo.GetRatingsByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	notes, err := s.bookNoteRepo.GetNotesByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	noteVotes, err := s.bookNoteRepo.GetNoteVotesByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := s.refreshTokenRepo.GetTokensByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	sessions := make([]dto.RefreshTokenExport, len(tokens))
+	for i, token := range tokens {
+		sessions[i] = dto.RefreshTokenExport{ID: token.ID, ExpiresAt: token.ExpiresAt}
+	}
+
+	reviews, err := s.reviewRepo.GetReviewsByUser(stringUserID)
+	if err != nil {
+		return err
+	}
+
+	votes, err := s.reviewRepo.GetVotesByUser(stringUserID)
+	if err != nil {
+		return err
+	}
+
+	comments, err := s.reviewCommentRepo.GetCommentsByUser(stringUserID)
+	if err != nil {
+		return err
+	}
+
+	reports, err := s.reviewReportRepo.GetReportsByUser(stringUserID)
+	if err != nil {
+		return err
+	}
+
+	moderatorActions, err := s.moderatorActionRepo.GetActionsByModerator(userID)
+	if err != nil {
+		return err
+	}
+
+	feedbacks, err := s.feedbackRepo.GetFeedbacksByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	reputation, err := s.userReputationRepo.GetReputation(userID)
+	if err != nil {
+		return err
+	}
+
+	follows, err := s.followRepo.GetFollowing(userID)
+	if err != nil {
+		return err
+	}
+
+	activityEvents, err := s.activityEventRepo.GetEventsByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	notifications, err := s.notificationRepo.GetNotificationsByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	notificationPreferences, err := s.notificationRepo.GetPreferences(userID)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"user.json", dto.UserProfileExport{
+			ID:        user.ID,
+			Username:  user.Username,
+			Email:     user.Email,
+			Role:      user.Role,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+		}},
+		{"user_books.json", userBooks},
+		{"read_throughs.json", readThroughs},
+		{"book_ratings.json", ratings},
+		{"book_notes.json", notes},
+		{"book_note_votes.json", noteVotes},
+		{"refresh_tokens.json", sessions},
+		{"reviews.json", reviews},
+		{"review_votes.json", votes},
+		{"review_comments.json", comments},
+		{"review_reports.json", reports},
+		{"moderator_actions.json", moderatorActions},
+		{"feedbacks.json", feedbacks},
+		{"user_reputation.json", reputation},
+		{"follows.json", follows},
+		{"activity_events.json", activityEvents},
+		{"notifications.json", notifications},
+		{"notification_preferences.json", notificationPreferences},
+	}
+
+	archive := zip.NewWriter(w)
+	exportedAt := time.Now().UTC()
+
+	for _, file := range files {
+		entry, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: exportedAt,
+		})
+		if err != nil {
+			s.log.Warnf("Ошибка создания файла %s в архиве: %v", file.name, err)
+			return err
+		}
+
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			s.log.Warnf("Ошибка записи файла %s в архив: %v", file.name, err)
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// EraseUser анонимизирует отзывы и комментарии, удаляет голоса, фидбэк, заметки и список книг, отзывает токены
+// и мягко удаляет пользователя
+func (s *UserDataService) EraseUser(userID uuid.UUID) error {
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		s.log.Warnf("Ошибка получения пользователя %s перед удалением: %v", userID, err)
+		return err
+	}
+
+	stringUserID := utils.ConvertUUIDToString(userID)
+
+	// Сначала отзываем сессии, чтобы пользователь не успел ничего создать в процессе удаления
+	if err := s.refreshTokenRepo.DeleteTokensByUser(userID); err != nil {
+		return err
+	}
+
+	// Отзывы остаются (на них держится рейтинг книг), но без привязки к автору
+	if err := s.reviewRepo.AnonymizeReviewsByUser(stringUserID, utils.ConvertUUIDToString(uuid.Nil)); err != nil {
+		return err
+	}
+
+	// Комментарии тоже остаются, иначе развалятся ветки ответов других пользователей
+	if err := s.reviewCommentRepo.AnonymizeCommentsByUser(stringUserID, utils.ConvertUUIDToString(uuid.Nil)); err != nil {
+		return err
+	}
+
+	// Жалобы — доказательства для модерации, поэтому тоже только обезличиваем
+	if err := s.reviewReportRepo.AnonymizeReportsByUser(stringUserID, utils.ConvertUUIDToString(uuid.Nil)); err != nil {
+		return err
+	}
+
+	votes, err := s.reviewRepo.DeleteVotesByUser(stringUserID)
+	if err != nil {
+		return err
+	}
+	if err := s.revertReviewVotes(stringUserID, votes); err != nil {
+		return err
+	}
+
+	if err := s.feedbackRepo.DeleteFeedbacksByUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.bookRatingRepo.DeleteRatingsByUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.bookNoteRepo.DeleteNoteVotesByUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.bookNoteRepo.DeleteNotesByUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.userBookRepo.DeleteUserBooksByUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.userReputationRepo.DeleteReputation(userID); err != nil {
+		return err
+	}
+
+	if err := s.followRepo.DeleteFollowsByUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.activityEventRepo.DeleteEventsByUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.notificationRepo.DeleteNotificationsByUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.AnonymizeUser(userID); err != nil {
+		return err
+	}
+
+	s.log.Infof("Персональные данные пользователя %s удалены", userID)
+
+	return nil
+}
+
+// revertReviewVotes снимает с авторов отзывов репутацию за удаленные голоса и ставит в outbox пересчет рейтинга
+// затронутых книг: голоса влияют на вес отзывов в рейтинге
+func (s *UserDataService) revertReviewVotes(userID string, votes []models.ReviewVote) error {
+	bookIDs := make(map[uuid.UUID]struct{})
+	for _, vote := range votes {
+		review, err := s.reviewRepo.GetReviewById(vote.ReviewID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// Голос за собственный отзыв репутацию не менял, а свои отзывы к этому моменту уже обезличены
+		if review.UserID != userID {
+			if err := s.reputationService.OnReviewVoteChanged(review.UserID, vote.Vote, 0); err != nil {
+				s.log.Warnf("Ошибка отмены репутации за голос по отзыву %s: %v", review.ID.Hex(), err)
+			}
+		}
+
+		if bookID, err := utils.ConvertStringToUUID(review.BookID); err == nil && review.IsVisible() {
+			bookIDs[bookID] = struct{}{}
+		}
+	}
+
+	for bookID := range bookIDs {
+		if err := s.outboxRepo.Enqueue(models.OutboxRecalculateBookRating, bookID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -14,52 +14,114 @@ import (
 )
 
 type UserService struct {
-	userRepo         *repositories.UserRepository
-	refreshTokenRepo *repositories.RefreshTokenRepository
+	userRepo          *repositories.UserRepository
+	refreshTokenRepo  *repositories.RefreshTokenRepository
+	reputationService *ReputationService
 
 	log *logger.Logger
 }
 
 // NewUserService создает новый сервис пользователей
-func NewUserService(userRepo *repositories.UserRepository, refreshTokenRepo *repositories.RefreshTokenRepository) *UserService {
+func NewUserService(userRepo *repositories.UserRepository, refreshTokenRepo *repositories.RefreshTokenRepository, reputationService *ReputationService) *UserService {
 	return &UserService{
-		userRepo:         userRepo,
-		refreshTokenRepo: refreshTokenRepo,
+		userRepo:          userRepo,
+		refreshTokenRepo:  refreshTokenRepo,
+		reputationService: reputationService,
 
 		log: logger.GetLogger(),
 	}
 }
 
+var (
+	ErrUserAlreadyExists = errors.New("пользователь уже зарегистрирован")
+	ErrInvalidRole       = errors.New("неизвестная роль: ожидается user, moderator или admin")
+)
+
 // RegisterUser регистрирует нового пользователя
 func (s *UserService) RegisterUser(req dto.UserRegisterRequest) error {
+	_, err := s.CreateUser(req.Email, req.Username, req.Password, models.RoleUser) // По умолчанию обычный пользователь
+	return err
+}
+
+// CreateUser создает пользователя с указанной ролью. Через API создаются только обычные пользователи,
+// модераторов и админов заводят из bmsctl
+func (s *UserService) CreateUser(email, username, password, role string) (*models.User, error) {
+	if !isValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+
 	// Проверяем, существует ли уже пользователь
-	existingUser, _ := s.userRepo.GetUserByEmail(req.Email)
+	existingUser, _ := s.userRepo.GetUserByEmail(email)
 	if existingUser != nil {
-		return errors.New("пользователь уже зарегистрирован")
+		return nil, ErrUserAlreadyExists
 	}
 
 	// Хешируем пароль
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		s.log.Warnf("Ошибка хеширования пароля: %v", err)
-		return err
+		return nil, err
 	}
 
-	// Создаем пользователя
 	user := models.User{
 		ID:       uuid.New(),
-		Email:    req.Email,
+		Username: username,
+		Email:    email,
 		Password: string(hashedPassword),
-		Role:     models.RoleUser, // По умолчанию обычный пользователь
+		Role:     role,
 	}
 
 	err = s.userRepo.CreateUser(user)
 	if err != nil {
 		s.log.Warnf("Ошибка сохранения пользователя: %v", err)
-		return err
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// SetUserRole меняет роль пользователя и отзывает его refresh-токены: роль зашита в токены,
+// и новая начнет действовать со следующего входа, а не через неделю
+func (s *UserService) SetUserRole(email, role string) (*models.User, error) {
+	if !isValidRole(role) {
+		return nil, ErrInvalidRole
 	}
 
-	return nil
+	user, err := s.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateUserRole(user.ID, role); err != nil {
+		return nil, err
+	}
+	if err := s.refreshTokenRepo.DeleteTokensByUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	user.Role = role
+	return user, nil
+}
+
+// RevokeUserTokens отзывает все refresh-токены пользователя; выданные access-токены доживают свой час
+func (s *UserService) RevokeUserTokens(email string) (*models.User, error) {
+	user, err := s.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshTokenRepo.DeleteTokensByUser(user.ID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func isValidRole(role string) bool {
+	switch role {
+	case models.RoleUser, models.RoleModerator, models.RoleAdmin:
+		return true
+	}
+	return false
 }
 
 // LoginUser проверяет учетные данные и выдает JWT
@@ -158,11 +220,37 @@ func (s *UserService) GetUserByID(userID uuid.UUID) (*dto.UserResponse, error) {
 
 		return nil, err
 	}
+	reputation, err := s.reputationService.GetReputation(userID)
+	if err != nil {
+		return nil, err
+	}
+
 	userResponse := &dto.UserResponse{
-		ID:    utils.ConvertUUIDToString(user.ID),
-		Role:  user.Role,
-		Email: user.Email,
+		ID:         utils.ConvertUUIDToString(user.ID),
+		Role:       user.Role,
+		Email:      user.Email,
+		Reputation: reputation,
 	}
 
 	return userResponse, nil
 }
+
+// GetPublicProfile получает открытый профиль пользователя с репутацией
+func (s *UserService) GetPublicProfile(userID uuid.UUID) (*dto.PublicProfileResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reputation, err := s.reputationService.GetReputation(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PublicProfileResponse{
+		ID:         utils.ConvertUUIDToString(user.ID),
+		Username:   user.Username,
+		Reputation: *reputation,
+		CreatedAt:  user.CreatedAt,
+	}, nil
+}
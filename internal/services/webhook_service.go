@@ -0,0 +1,528 @@
+package services
+
+import (
+	"book-management-system/config"
+	"book-management-system/internal/dto"
+	"book-management-system/internal/events"
+	"book-management-system/internal/models"
+	"book-management-system/internal/repositories"
+	"book-management-system/pkg/logger"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	webhookTestEvent = "webhook.test"
+
+	defaultWebhookMaxAttempts = 8
+	webhookRequestTimeout     = 10 * time.Second
+	webhookBaseBackoff        = 30 * time.Second // после n-й неудачи ждем base * 2^(n-1): 30с, 1м, 2м, ...
+	webhookMaxBackoff         = 6 * time.Hour
+	webhookPollInterval       = 5 * time.Second
+	webhookClaimBatch         = 20
+	webhookClaimLease         = 2 * time.Minute // больше таймаута запроса с запасом на всю пачку
+)
+
+var (
+	ErrInvalidWebhookURL     = errors.New("адрес вебхука должен быть абсолютным http(s) URL")
+	ErrUnknownWebhookEvent   = errors.New("неизвестное событие вебхука")
+	ErrWebhookPrivateAddress = errors.New("адрес вебхука указывает во внутреннюю сеть")
+)
+
+// webhookBlockedPrefixes диапазоны, которых нет среди IsPrivate/IsLoopback/IsLinkLocal*, но которые тоже
+// не ведут в публичный интернет
+var webhookBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // CGNAT
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"), // NAT64 может вести во внутреннюю IPv4-сеть
+}
+
+// webhookEventNames события шины, которые уходят во внешние сервисы, и их имена в API вебхуков
+var webhookEventNames = map[events.Type]string{
+	events.BookCreated:   "book.created",
+	events.BookConfirmed: "book.confirmed",
+	events.BookUpdated:   "book.updated",
+	events.BookDeleted:   "book.deleted",
+	events.ReviewCreated: "review.created",
+}
+
+// WebhookService регистрирует вебхуки, ставит события в очередь доставки и отправляет их с повторами
+type WebhookService struct {
+	repo        *repositories.WebhookRepository
+	client      *http.Client
+	maxAttempts int
+	// allowPrivateNetworks разрешает вебхуки на внутренние адреса, например для локальной разработки
+	allowPrivateNetworks bool
+	// wake будит воркер, когда в очереди появились новые доставки, чтобы не ждать следующего опроса
+	wake chan struct{}
+	log  *logger.Logger
+}
+
+// NewWebhookService создает сервис вебхуков. Число попыток доставки берется из WEBHOOK_MAX_ATTEMPTS,
+// разрешение доставлять на внутренние адреса — из WEBHOOK_ALLOW_PRIVATE_NETWORKS (по умолчанию запрещено)
+func NewWebhookService(repo *repositories.WebhookRepository) *WebhookService {
+	log := logger.GetLogger()
+
+	maxAttempts, err := strconv.Atoi(config.GetEnv("WEBHOOK_MAX_ATTEMPTS", strconv.Itoa(defaultWebhookMaxAttempts)))
+	if err != nil || maxAttempts <= 0 {
+		log.Warnf("Некорректный WEBHOOK_MAX_ATTEMPTS, используем %d", defaultWebhookMaxAttempts)
+		maxAttempts = defaultWebhookMaxAttempts
+	}
+
+	allowPrivateNetworks, err := strconv.ParseBool(config.GetEnv("WEBHOOK_ALLOW_PRIVATE_NETWORKS", "false"))
+	if err != nil {
+		log.Warnf("Некорректный WEBHOOK_ALLOW_PRIVATE_NETWORKS, внутренние адреса запрещены")
+		allowPrivateNetworks = false
+	}
+
+	return &WebhookService{
+		repo:                 repo,
+		client:               newWebhookClient(allowPrivateNetworks),
+		maxAttempts:          maxAttempts,
+		allowPrivateNetworks: allowPrivateNetworks,
+		wake:                 make(chan struct{}, 1),
+		log:                  log,
+	}
+}
+
+// newWebhookClient создает HTTP-клиент доставки. Адрес вебхука задает администратор, но запрос уходит
+// с сервера, поэтому без разрешения внутренние адреса отсекаются уже после разрешения DNS — при каждом
+// соединении, так что их не обойти ни перепривязкой DNS, ни редиректом. Прокси из окружения не используется:
+// иначе проверялся бы адрес прокси, а не получателя
+func newWebhookClient(allowPrivateNetworks bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookRequestTimeout}
+	if !allowPrivateNetworks {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !isPublicAddress(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrWebhookPrivateAddress, address)
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: webhookRequestTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookRequestTimeout,
+			MaxIdleConnsPerHost: 2,
+		},
+		// Редирект — тоже ответ не 2xx: получатель должен принимать события по указанному адресу
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// isPublicAddress проверяет, что адрес ведет в публичный интернет
+func isPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range webhookBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// HandleEvent ставит событие в очередь доставки всем подписанным вебхукам
+func (s *WebhookService) HandleEvent(event events.Event) {
+	name, ok := webhookEventNames[event.Type]
+	if !ok {
+		return
+	}
+
+	webhooks, err := s.repo.GetActiveWebhooksForEvent(name)
+	if err != nil || len(webhooks) == 0 {
+		return
+	}
+
+	data := dto.WebhookEventData{BookID: event.BookID, BookTitle: event.BookTitle, ReviewID: event.ReviewID}
+	if event.ActorID != uuid.Nil {
+		data.ActorID = &event.ActorID
+	}
+	payload, err := json.Marshal(dto.WebhookEventPayload{
+		ID:         uuid.New(),
+		Event:      name,
+		OccurredAt: event.OccurredAt,
+		Data:       data,
+	})
+	if err != nil {
+		s.log.Warnf("Ошибка сериализации события %s для вебхуков: %v", name, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	deliveries := make([]models.WebhookDelivery, len(webhooks))
+	for i, webhook := range webhooks {
+		deliveries[i] = models.WebhookDelivery{
+			ID:            uuid.New(),
+			WebhookID:     webhook.ID,
+			EventType:     name,
+			Payload:       string(payload),
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+		}
+	}
+
+	if err := s.repo.CreateDeliveries(deliveries); err != nil {
+		return
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartWorker запускает фоновую отправку доставок из очереди. Воркер останавливается при отмене ctx,
+// дослав текущую доставку: остальные захваченные вернутся в очередь по истечении аренды
+func (s *WebhookService) StartWorker(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(webhookPollInterval)
+		defer ticker.Stop()
+
+		for {
+			s.deliverDue(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+		}
+	}()
+}
+
+// deliverDue отправляет пачки доставок, пока в очереди есть те, чье время наступило
+func (s *WebhookService) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := s.repo.ClaimDueDeliveries(webhookClaimBatch, webhookClaimLease)
+		if err != nil || len(deliveries) == 0 {
+			return
+		}
+
+		webhooks := make(map[uuid.UUID]*models.Webhook)
+		for i := range deliveries {
+			if ctx.Err() != nil {
+				return
+			}
+			delivery := &deliveries[i]
+
+			webhook, ok := webhooks[delivery.WebhookID]
+			if !ok {
+				if webhook, err = s.repo.GetWebhookByID(delivery.WebhookID); err != nil {
+					continue
+				}
+				webhooks[delivery.WebhookID] = webhook
+			}
+
+			s.attempt(webhook, delivery, s.maxAttempts)
+		}
+	}
+}
+
+// attempt отправляет доставку один раз, сохраняет результат и планирует следующую попытку
+func (s *WebhookService) attempt(webhook *models.Webhook, delivery *models.WebhookDelivery, maxAttempts int) {
+	statusCode, err := s.send(webhook, delivery)
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+	delivery.NextAttemptAt = nil
+
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= maxAttempts || !webhook.Active:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = err.Error()
+	default:
+		next := now.Add(webhookBackoff(delivery.Attempts))
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = &next
+	}
+
+	if err != nil {
+		s.log.Warnf("Доставка %s события %s на %s не удалась (попытка %d): %v", delivery.ID, delivery.EventType, webhook.URL, delivery.Attempts, err)
+	}
+
+	_ = s.repo.SaveDeliveryAttempt(delivery)
+}
+
+// send отправляет подписанный запрос; ошибкой считается все, кроме ответа 2xx
+func (s *WebhookService) send(webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "book-management-system-webhooks")
+	request.Header.Set("X-Webhook-Event", delivery.EventType)
+	request.Header.Set("X-Webhook-Delivery", delivery.ID.String())
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	request.Header.Set("X-Webhook-Signature", "sha256="+signWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	// Тело ответа не сохраняем: журнал доставок виден через API, и он не должен пересказывать чужие ответы
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("получатель ответил %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// signWebhookPayload подписывает "timestamp.body": метка времени в подписи не дает переиграть старый запрос
+func signWebhookPayload(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff задержка перед следующей попыткой после attempts неудачных
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, webhookMaxBackoff)
+}
+
+// CreateWebhook регистрирует вебхук; если секрет не задан, генерирует его
+func (s *WebhookService) CreateWebhook(req dto.CreateWebhookRequest, adminID uuid.UUID) (*dto.WebhookResponse, error) {
+	if err := validateWebhook(req.URL, req.EventTypes, s.allowPrivateNetworks); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated := make([]byte, 32)
+		if _, err := rand.Read(generated); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(generated)
+	}
+
+	webhook := &models.Webhook{
+		ID:         uuid.New(),
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: slices.Compact(slices.Sorted(slices.Values(req.EventTypes))),
+		Active:     true,
+		CreatedBy:  adminID,
+	}
+	if err := s.repo.CreateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	response := toWebhookResponse(webhook)
+	response.Secret = secret
+	return &response, nil
+}
+
+// GetWebhooks получает все вебхуки
+func (s *WebhookService) GetWebhooks() ([]dto.WebhookResponse, error) {
+	webhooks, err := s.repo.GetWebhooks()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.WebhookResponse, len(webhooks))
+	for i := range webhooks {
+		responses[i] = toWebhookResponse(&webhooks[i])
+	}
+	return responses, nil
+}
+
+// GetWebhook получает вебхук по ID
+func (s *WebhookService) GetWebhook(webhookID uuid.UUID) (*dto.WebhookResponse, error) {
+	webhook, err := s.repo.GetWebhookByID(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toWebhookResponse(webhook)
+	return &response, nil
+}
+
+// UpdateWebhook меняет адрес, события и включенность вебхука
+func (s *WebhookService) UpdateWebhook(webhookID uuid.UUID, req dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	if err := validateWebhook(req.URL, req.EventTypes, s.allowPrivateNetworks); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.repo.GetWebhookByID(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.URL = req.URL
+	webhook.EventTypes = slices.Compact(slices.Sorted(slices.Values(req.EventTypes)))
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := s.repo.UpdateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	response := toWebhookResponse(webhook)
+	return &response, nil
+}
+
+// DeleteWebhook удаляет вебхук и его журнал доставок
+func (s *WebhookService) DeleteWebhook(webhookID uuid.UUID) error {
+	return s.repo.DeleteWebhook(webhookID)
+}
+
+// GetDeliveries получает журнал доставок вебхука
+func (s *WebhookService) GetDeliveries(webhookID uuid.UUID, limit int, afterID *uuid.UUID) (*dto.PaginatedWebhookDeliveriesResponse, error) {
+	if _, err := s.repo.GetWebhookByID(webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.GetDeliveriesPaginated(webhookID, limit, afterID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		responses[i] = toWebhookDeliveryResponse(&deliveries[i])
+	}
+
+	var nextCursor *uuid.UUID
+	if len(deliveries) > 0 {
+		nextCursor = &deliveries[len(deliveries)-1].ID
+	}
+
+	return &dto.PaginatedWebhookDeliveriesResponse{
+		Deliveries: responses,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// TestWebhook сразу отправляет на вебхук тестовое событие webhook.test без повторов и возвращает результат
+func (s *WebhookService) TestWebhook(webhookID uuid.UUID) (*dto.WebhookDeliveryResponse, error) {
+	webhook, err := s.repo.GetWebhookByID(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(dto.WebhookEventPayload{
+		ID:         uuid.New(),
+		Event:      webhookTestEvent,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Без next_attempt_at воркер эту доставку не подберет: ее единственная попытка — ниже
+	deliveries := []models.WebhookDelivery{{
+		ID:        uuid.New(),
+		WebhookID: webhook.ID,
+		EventType: webhookTestEvent,
+		Payload:   string(payload),
+		Status:    models.WebhookDeliveryPending,
+	}}
+	if err := s.repo.CreateDeliveries(deliveries); err != nil {
+		return nil, err
+	}
+	delivery := &deliveries[0]
+
+	// Проверяем и выключенный вебхук: так его можно починить перед включением
+	testWebhook := *webhook
+	testWebhook.Active = true
+	s.attempt(&testWebhook, delivery, 1)
+
+	response := toWebhookDeliveryResponse(delivery)
+	return &response, nil
+}
+
+// validateWebhook проверяет адрес и события вебхука. Внутренний IP или localhost в адресе отклоняется сразу,
+// имена хостов проверяет уже клиент доставки после разрешения DNS
+func validateWebhook(rawURL string, eventTypes []string, allowPrivateNetworks bool) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	if !allowPrivateNetworks {
+		host := parsed.Hostname()
+		if addr, err := netip.ParseAddr(host); (err == nil && !isPublicAddress(addr)) || host == "localhost" {
+			return ErrWebhookPrivateAddress
+		}
+	}
+
+	known := slices.Collect(maps.Values(webhookEventNames))
+	for _, eventType := range eventTypes {
+		if !slices.Contains(known, eventType) {
+			return fmt.Errorf("%w: %s", ErrUnknownWebhookEvent, eventType)
+		}
+	}
+	return nil
+}
+
+func toWebhookResponse(webhook *models.Webhook) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		ID:         webhook.ID,
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		Active:     webhook.Active,
+		CreatedAt:  webhook.CreatedAt,
+		UpdatedAt:  webhook.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(delivery *models.WebhookDelivery) dto.WebhookDeliveryResponse {
+	return dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
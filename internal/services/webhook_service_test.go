@@ -0,0 +1,136 @@
+package services
+
+import (
+	"book-management-system/internal/models"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{10, 512 * 30 * time.Second},
+		{11, webhookMaxBackoff},
+		{100, webhookMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	const want = "b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+	got := signWebhookPayload("secret", "1700000000", "{}")
+	if got != want {
+		t.Fatalf("signWebhookPayload = %q, want %q", got, want)
+	}
+	if got == signWebhookPayload("secret", "1700000001", "{}") {
+		t.Error("подпись не зависит от метки времени")
+	}
+	if got == signWebhookPayload("other", "1700000000", "{}") {
+		t.Error("подпись не зависит от секрета")
+	}
+}
+
+func TestIsPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPublicAddress(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("isPublicAddress(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestValidateWebhook(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		events       []string
+		allowPrivate bool
+		want         error
+	}{
+		{"публичный адрес", "https://hooks.example.com/books", []string{"book.created"}, false, nil},
+		{"не http", "ftp://example.com", nil, false, ErrInvalidWebhookURL},
+		{"относительный адрес", "/hooks", nil, false, ErrInvalidWebhookURL},
+		{"неизвестное событие", "https://example.com", []string{"book.read"}, false, ErrUnknownWebhookEvent},
+		{"localhost", "http://localhost:8080/hook", nil, false, ErrWebhookPrivateAddress},
+		{"метаданные облака", "http://169.254.169.254/latest/meta-data", nil, false, ErrWebhookPrivateAddress},
+		{"IPv6 loopback", "http://[::1]/hook", nil, false, ErrWebhookPrivateAddress},
+		{"внутренний адрес разрешен", "http://localhost:8080/hook", nil, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWebhook(tt.url, tt.events, tt.allowPrivate)
+			if (tt.want == nil && err != nil) || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Errorf("validateWebhook(%q) = %v, want %v", tt.url, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Webhook-Signature") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("internal secret"))
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{URL: server.URL, Secret: "secret"}
+	delivery := &models.WebhookDelivery{EventType: webhookTestEvent, Payload: "{}"}
+
+	// Сервер теста слушает 127.0.0.1: без разрешения соединение не устанавливается
+	blocked := &WebhookService{client: newWebhookClient(false)}
+	if _, err := blocked.send(webhook, delivery); !errors.Is(err, ErrWebhookPrivateAddress) {
+		t.Errorf("send на внутренний адрес: %v, want ErrWebhookPrivateAddress", err)
+	}
+
+	allowed := &WebhookService{client: newWebhookClient(true)}
+	statusCode, err := allowed.send(webhook, delivery)
+	if statusCode != http.StatusInternalServerError || err == nil {
+		t.Fatalf("send = %d, %v, want 500 и ошибку", statusCode, err)
+	}
+	if strings.Contains(err.Error(), "internal secret") {
+		t.Errorf("ошибка пересказывает тело ответа: %v", err)
+	}
+}
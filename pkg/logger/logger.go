@@ -14,10 +14,14 @@ var sugarLogger *Logger
 
 // NewLogger создает экземпляр кастомного логгера
 func NewLogger() *Logger {
+	return newLogger("stdout") // INFO в stdout
+}
+
+func newLogger(outputPath string) *Logger {
 	config := zap.NewProductionConfig()
 	config.EncoderConfig.TimeKey = "timestamp"
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	config.OutputPaths = []string{"stdout"}      // INFO в stdout
+	config.OutputPaths = []string{outputPath}
 	config.ErrorOutputPaths = []string{"stderr"} // Ошибки и варнинги в stderr
 
 	logger, err := config.Build()
@@ -33,6 +37,12 @@ func InitLogger() {
 	sugarLogger = NewLogger()
 }
 
+// InitCLILogger инициализирует глобальный логгер для консольных утилит: все пишется в stderr,
+// чтобы stdout оставался под результат команды
+func InitCLILogger() {
+	sugarLogger = newLogger("stderr")
+}
+
 // GetLogger возвращает экз. логгера
 func GetLogger() *Logger {
 	if sugarLogger == nil {
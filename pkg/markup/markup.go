@@ -0,0 +1,165 @@
+// Package markup рендерит ограниченное подмножество Markdown в безопасный HTML.
+//
+// Поддерживается: абзацы и переносы строк, **жирный**, *курсив* / _курсив_, ~~зачеркнутый~~,
+// `код`, цитаты (> ...), списки (- ... / * ...), ссылки [текст](https://...) и спойлеры ||текст||.
+// Исходный текст сначала целиком экранируется, и только потом в него подставляются теги
+// из списка выше, поэтому никакой другой HTML в результат попасть не может.
+package markup
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Result результат рендеринга
+type Result struct {
+	HTML        string
+	HasSpoilers bool
+}
+
+var (
+	codePattern    = regexp.MustCompile("`([^`\n]+)`")
+	spoilerPattern = regexp.MustCompile(`(?s)\|\|(.+?)\|\|`)
+	linkPattern    = regexp.MustCompile(`\[([^\]\n]+)\]\((https?://[^\s()]+)\)`)
+	boldPattern    = regexp.MustCompile(`\*\*([^*\n]+)\*\*`)
+	italicPattern  = regexp.MustCompile(`(^|[^\w*])[*_]([^*_\n]+)[*_]($|[^\w*])`)
+	strikePattern  = regexp.MustCompile(`~~([^~\n]+)~~`)
+	listPattern    = regexp.MustCompile(`^[-*] +`)
+	quotePattern   = regexp.MustCompile(`^&gt; ?`)
+	tokenPattern   = regexp.MustCompile("\x00([0-9]+)\x00")
+)
+
+// Render превращает исходный текст в HTML и сообщает, есть ли в нем спойлеры
+func Render(source string) Result {
+	// \x00 зарезервирован под метки отложенных фрагментов
+	source = strings.NewReplacer("\r\n", "\n", "\x00", "").Replace(strings.TrimSpace(source))
+	if source == "" {
+		return Result{}
+	}
+
+	r := &renderer{}
+
+	// Код вырезаем до форматирования, чтобы внутри него разметка не применялась
+	escaped := codePattern.ReplaceAllStringFunc(html.EscapeString(source), func(match string) string {
+		return r.stash("<code>" + codePattern.FindStringSubmatch(match)[1] + "</code>")
+	})
+
+	// Спойлер через несколько абзацев иначе разрезало бы на блоки, и он остался бы виден. Такой спойлер
+	// рендерим целиком одним элементом, абзацы внутри него становятся переносами строк
+	escaped = spoilerPattern.ReplaceAllStringFunc(escaped, func(match string) string {
+		if !strings.Contains(match, "\n\n") {
+			return match
+		}
+		return r.spoiler(match)
+	})
+
+	var out strings.Builder
+	for _, block := range strings.Split(escaped, "\n\n") {
+		block = strings.Trim(block, "\n")
+		if block == "" {
+			continue
+		}
+		out.WriteString(r.renderBlock(block))
+	}
+
+	return Result{HTML: r.restore(out.String()), HasSpoilers: r.hasSpoilers}
+}
+
+// renderer хранит готовые фрагменты, которые не должна трогать дальнейшая разметка
+type renderer struct {
+	stashed     []string
+	hasSpoilers bool
+}
+
+// stash откладывает готовый HTML-фрагмент и возвращает метку на его место
+func (r *renderer) stash(fragment string) string {
+	r.stashed = append(r.stashed, fragment)
+	return "\x00" + strconv.Itoa(len(r.stashed)-1) + "\x00"
+}
+
+// restore подставляет отложенные фрагменты вместо меток; фрагменты сами могут содержать метки
+func (r *renderer) restore(text string) string {
+	return tokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		index, _ := strconv.Atoi(tokenPattern.FindStringSubmatch(token)[1])
+		return r.restore(r.stashed[index])
+	})
+}
+
+// wrap рендерит содержимое элемента и откладывает элемент целиком, чтобы следующая разметка
+// не захватила его разделители и теги всегда оставались правильно вложенными
+func (r *renderer) wrap(open, content, close string) string {
+	return r.stash(open + r.renderInline(content) + close)
+}
+
+// spoiler рендерит найденный spoilerPattern спойлер
+func (r *renderer) spoiler(match string) string {
+	r.hasSpoilers = true
+	return r.wrap(`<span class="spoiler">`, spoilerPattern.FindStringSubmatch(match)[1], "</span>")
+}
+
+// renderBlock рендерит абзац, список или цитату
+func (r *renderer) renderBlock(block string) string {
+	lines := strings.Split(block, "\n")
+
+	if allLinesMatch(lines, listPattern) {
+		var out strings.Builder
+		out.WriteString("<ul>")
+		for _, line := range lines {
+			out.WriteString("<li>" + r.renderInline(listPattern.ReplaceAllString(line, "")) + "</li>")
+		}
+		out.WriteString("</ul>")
+		return out.String()
+	}
+
+	if allLinesMatch(lines, quotePattern) {
+		for i, line := range lines {
+			lines[i] = quotePattern.ReplaceAllString(line, "")
+		}
+		return "<blockquote><p>" + r.renderInline(strings.Join(lines, "\n")) + "</p></blockquote>"
+	}
+
+	return "<p>" + r.renderInline(block) + "</p>"
+}
+
+// renderInline применяет строчную разметку к уже экранированному тексту. Спойлеры разбираются первыми:
+// разметка внутри спойлера рендерится отдельно и не может вынести его текст наружу
+func (r *renderer) renderInline(text string) string {
+	text = spoilerPattern.ReplaceAllStringFunc(text, r.spoiler)
+
+	// Ссылки откладываем целиком, чтобы подчеркивания в URL не превратились в курсив
+	text = linkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := linkPattern.FindStringSubmatch(match)
+		return r.wrap(`<a href="`+parts[2]+`" rel="nofollow ugc noopener" target="_blank">`, parts[1], "</a>")
+	})
+
+	text = boldPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return r.wrap("<strong>", boldPattern.FindStringSubmatch(match)[1], "</strong>")
+	})
+	// Два прохода: соседние выделения делят разделитель, и первый проход берет только каждое второе
+	for i := 0; i < 2; i++ {
+		text = italicPattern.ReplaceAllStringFunc(text, func(match string) string {
+			parts := italicPattern.FindStringSubmatch(match)
+			return parts[1] + r.wrap("<em>", parts[2], "</em>") + parts[3]
+		})
+	}
+	text = strikePattern.ReplaceAllStringFunc(text, func(match string) string {
+		return r.wrap("<del>", strikePattern.FindStringSubmatch(match)[1], "</del>")
+	})
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+// StripSpoilers убирает содержимое спойлеров из исходного текста, например для превью и лент
+func StripSpoilers(source string) string {
+	return spoilerPattern.ReplaceAllString(source, "[спойлер]")
+}
+
+func allLinesMatch(lines []string, pattern *regexp.Regexp) bool {
+	for _, line := range lines {
+		if !pattern.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package markup
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		html        string
+		hasSpoilers bool
+	}{
+		{"пустой текст", "  \n ", "", false},
+		{"абзацы и переносы", "раз\nдва\n\nтри", "<p>раз<br>два</p><p>три</p>", false},
+		{"жирный и курсив", "**жирный** и *курсив* и _тоже_", "<p><strong>жирный</strong> и <em>курсив</em> и <em>тоже</em></p>", false},
+		{"соседние выделения", "*a* *b*", "<p><em>a</em> <em>b</em></p>", false},
+		{"зачеркнутый", "~~нет~~", "<p><del>нет</del></p>", false},
+		{"список", "- один\n* два", "<ul><li>один</li><li>два</li></ul>", false},
+		{"цитата", "> цитата\n>ещё", "<blockquote><p>цитата<br>ещё</p></blockquote>", false},
+		{"спойлер", "конец: ||все умерли||", `<p>конец: <span class="spoiler">все умерли</span></p>`, true},
+		{"спойлер через абзацы", "итог:\n\n||a\n\n**b**||\n\nконец", `<p>итог:</p><p><span class="spoiler">a<br><br><strong>b</strong></span></p><p>конец</p>`, true},
+		{"спойлер через абзацы внутри абзаца", "до ||a\n\nb|| после ||c||", `<p>до <span class="spoiler">a<br><br>b</span> после <span class="spoiler">c</span></p>`, true},
+		{"разметка внутри спойлера", "||**жирный** спойлер||", `<p><span class="spoiler"><strong>жирный</strong> спойлер</span></p>`, true},
+		{"спойлер внутри жирного", "**a ||b|| c**", `<p><strong>a <span class="spoiler">b</span> c</strong></p>`, true},
+		{"пересекающиеся жирный и спойлер", "**bold ||spoil** er||", `<p>**bold <span class="spoiler">spoil** er</span></p>`, true},
+		{"пересекающиеся зачеркнутый и курсив", "~~a *b~~ c*", `<p>~~a <em>b~~ c</em></p>`, false},
+		{"ссылка", "[сайт](https://example.com/a_b_c)", `<p><a href="https://example.com/a_b_c" rel="nofollow ugc noopener" target="_blank">сайт</a></p>`, false},
+		{"разметка не выходит из ссылки", "[**a](https://example.com) b**", `<p><a href="https://example.com" rel="nofollow ugc noopener" target="_blank">**a</a> b**</p>`, false},
+		{"javascript-ссылка", "[x](javascript:alert(1))", "<p>[x](javascript:alert(1))</p>", false},
+		{"кавычка в адресе ссылки", `[x](https://e.com/"onclick=)`, `<p><a href="https://e.com/&#34;onclick=" rel="nofollow ugc noopener" target="_blank">x</a></p>`, false},
+		{"сырой HTML", `<script>alert("x")</script><b>`, "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;&lt;b&gt;</p>", false},
+		{"код без разметки", "`**не жирный** ||нет||`", "<p><code>**не жирный** ||нет||</code></p>", false},
+		{"HTML в коде", "`<b>`", "<p><code>&lt;b&gt;</code></p>", false},
+		{"служебный символ", "a\x00 0\x00b", "<p>a 0b</p>", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.source)
+			if got.HTML != tt.html {
+				t.Errorf("Render(%q).HTML = %q, want %q", tt.source, got.HTML, tt.html)
+			}
+			if got.HasSpoilers != tt.hasSpoilers {
+				t.Errorf("Render(%q).HasSpoilers = %v, want %v", tt.source, got.HasSpoilers, tt.hasSpoilers)
+			}
+		})
+	}
+}
+
+func TestStripSpoilers(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"без спойлеров", "без спойлеров"},
+		{"убийца — ||дворецкий||", "убийца — [спойлер]"},
+		{"||раз||, ||два\nстроки||", "[спойлер], [спойлер]"},
+		{"||раз\n\nдва||\n\nтри", "[спойлер]\n\nтри"},
+	}
+
+	for _, tt := range tests {
+		if got := StripSpoilers(tt.source); got != tt.want {
+			t.Errorf("StripSpoilers(%q) = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package textdiff строит построчный diff двух текстов по наибольшей общей подпоследовательности.
+package textdiff
+
+import "strings"
+
+// maxTableCells ограничивает таблицу LCS (около 8 МБ): тексты сравнивают по публичному запросу,
+// и квадратичная память на длинных текстах позволила бы любому исчерпать память сервера
+const maxTableCells = 1 << 21
+
+// Op вид изменения строки
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// Line строка diff'а
+type Line struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines сравнивает тексты построчно и возвращает строки старого и нового текста
+// в порядке следования с пометкой, осталась строка, удалена или добавлена.
+// Общие начало и конец отбрасываются до сравнения; если оставшаяся середина слишком велика,
+// она отдается целиком как удаление старых строк и вставка новых — diff верный, но не минимальный
+func Lines(oldText, newText string) []Line {
+	a, b := splitLines(oldText), splitLines(newText)
+	diff := make([]Line, 0, len(a)+len(b))
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		diff = append(diff, Line{Op: OpEqual, Text: a[prefix]})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	diff = appendMiddle(diff, a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+
+	for _, line := range a[len(a)-suffix:] {
+		diff = append(diff, Line{Op: OpEqual, Text: line})
+	}
+	return diff
+}
+
+// appendMiddle добавляет diff строк a и b, у которых уже нет общего начала и конца
+func appendMiddle(diff []Line, a, b []string) []Line {
+	if (len(a)+1)*(len(b)+1) > maxTableCells {
+		for _, line := range a {
+			diff = append(diff, Line{Op: OpDelete, Text: line})
+		}
+		for _, line := range b {
+			diff = append(diff, Line{Op: OpInsert, Text: line})
+		}
+		return diff
+	}
+
+	// lcs[i*width+j] — длина общей подпоследовательности хвостов a[i:] и b[j:]
+	width := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, Line{Op: OpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			diff = append(diff, Line{Op: OpDelete, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, Line{Op: OpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, Line{Op: OpDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, Line{Op: OpInsert, Text: b[j]})
+	}
+	return diff
+}
+
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,85 @@
+package textdiff
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    []Line
+	}{
+		{"оба пустые", "", "", []Line{}},
+		{"новый текст", "", "a\nb", []Line{{OpInsert, "a"}, {OpInsert, "b"}}},
+		{"текст удален", "a\nb", "", []Line{{OpDelete, "a"}, {OpDelete, "b"}}},
+		{"без изменений", "a\nb", "a\r\nb", []Line{{OpEqual, "a"}, {OpEqual, "b"}}},
+		{
+			"замена в середине",
+			"a\nb\nc",
+			"a\nx\nc",
+			[]Line{{OpEqual, "a"}, {OpDelete, "b"}, {OpInsert, "x"}, {OpEqual, "c"}},
+		},
+		{
+			"вставка и удаление",
+			"a\nb\nc\nd",
+			"b\nc\ne\nd",
+			[]Line{{OpDelete, "a"}, {OpEqual, "b"}, {OpEqual, "c"}, {OpInsert, "e"}, {OpEqual, "d"}},
+		},
+		{
+			"повторяющиеся строки",
+			"a\na\nb",
+			"a\nb\nb",
+			[]Line{{OpEqual, "a"}, {OpDelete, "a"}, {OpInsert, "b"}, {OpEqual, "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lines(tt.oldText, tt.newText); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines(%q, %q) = %v, want %v", tt.oldText, tt.newText, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinesLargeTextsStayCorrect(t *testing.T) {
+	oldLines := make([]string, 20000)
+	newLines := make([]string, 20000)
+	for i := range oldLines {
+		oldLines[i] = "old " + strconv.Itoa(i)
+		newLines[i] = "new " + strconv.Itoa(i)
+	}
+	// Общие начало и конец сравниваются точно даже при огромной середине
+	oldLines[0], newLines[0] = "start", "start"
+	oldLines[len(oldLines)-1], newLines[len(newLines)-1] = "end", "end"
+	oldText, newText := strings.Join(oldLines, "\n"), strings.Join(newLines, "\n")
+
+	diff := Lines(oldText, newText)
+
+	if diff[0] != (Line{OpEqual, "start"}) || diff[len(diff)-1] != (Line{OpEqual, "end"}) {
+		t.Errorf("общие начало и конец не сохранились: %v ... %v", diff[0], diff[len(diff)-1])
+	}
+	gotOld, gotNew := apply(diff)
+	if gotOld != oldText || gotNew != newText {
+		t.Error("diff не восстанавливает исходные тексты")
+	}
+}
+
+// apply собирает из diff'а старый и новый тексты
+func apply(diff []Line) (string, string) {
+	var oldLines, newLines []string
+	for _, line := range diff {
+		if line.Op != OpInsert {
+			oldLines = append(oldLines, line.Text)
+		}
+		if line.Op != OpDelete {
+			newLines = append(newLines, line.Text)
+		}
+	}
+	return strings.Join(oldLines, "\n"), strings.Join(newLines, "\n")
+}
@@ -22,3 +22,11 @@ func ConvertStringToUUID(idStr string) (uuid.UUID, error) {
 func ConvertStringToObjectID(idStr string) (primitive.ObjectID, error) {
 	return primitive.ObjectIDFromHex(idStr)
 }
+
+// DerefString возвращает значение строки по указателю или пустую строку для nil
+func DerefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
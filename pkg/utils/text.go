@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeTitle приводит название к виду для сравнения: нижний регистр, каждая последовательность
+// не букв и не цифр заменена одним пробелом. Миграция 0002_book_import заполняет существующие книги
+// тем же правилом в SQL, поэтому при изменении функции нужна миграция, пересчитывающая normalized_title
+func NormalizeTitle(title string) string {
+	var b strings.Builder
+	b.Grow(len(title))
+
+	pendingSpace := false
+	for _, r := range title {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pendingSpace = b.Len() > 0
+			continue
+		}
+		if pendingSpace {
+			b.WriteByte(' ')
+			pendingSpace = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
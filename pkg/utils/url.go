@@ -0,0 +1,12 @@
+package utils
+
+import "strings"
+
+// AbsoluteURL делает адрес на сайте абсолютным относительно site (без завершающего слеша),
+// например путь обложки /uploads/<id>.jpg; абсолютные http(s) адреса возвращаются как есть
+func AbsoluteURL(site, href string) string {
+	if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
+		return href
+	}
+	return site + "/" + strings.TrimPrefix(href, "/")
+}